@@ -22,6 +22,7 @@
     .Values.sync.toHost.storageClasses.enabled
     .Values.sync.toHost.persistentVolumes.enabled
     .Values.sync.toHost.priorityClasses.enabled
+    .Values.sync.toHost.adminNetworkPolicies.enabled
     .Values.sync.toHost.resourceClaims.enabled
     .Values.sync.toHost.resourceClaimTemplates.enabled
     .Values.sync.fromHost.priorityClasses.enabled
@@ -45,6 +46,7 @@
     .Values.sync.fromHost.configMaps.enabled
     .Values.sync.fromHost.secrets.enabled
     .Values.integrations.istio.enabled
+    .Values.integrations.prometheusOperator.enabled
     .Values.sync.toHost.namespaces.enabled
     .Values.sync.fromHost.gatewayClasses.enabled
     .Values.sync.fromHost.gateways.enabled
@@ -220,6 +222,24 @@
 {{- end -}}
 
 
+{{/*
+  Host objects that sync.toHost mappings write to, each with its own role. The syncer lets the role own the host object,
+  so it is garbage collected once the mapping is removed. The role name needs to match ToHostMappingRoleName.
+*/}}
+{{- define "vcluster.rbac.toHostMappings" -}}
+{{- $mappings := list -}}
+{{- range $resource, $config := dict "configmaps" .Values.sync.toHost.configMaps "secrets" .Values.sync.toHost.secrets -}}
+{{- if $config.enabled -}}
+{{- range $virtual, $host := (dig "mappings" "byName" dict $config) -}}
+  {{- $hash := printf "%s/%s/%s/%s" $.Release.Namespace $.Release.Name $resource $host | sha256sum | trunc 10 -}}
+  {{- $roleName := printf "vc-tohost-%s-%s" ($.Release.Name | trunc 40 | trimSuffix "-") $hash -}}
+  {{- $mappings = append $mappings (dict "resource" $resource "namespace" (splitList "/" $host | first) "name" (splitList "/" $host | last) "roleName" $roleName) -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+{{- toJson (dict "mappings" $mappings) -}}
+{{- end -}}
+
 {{- define "vcluster.rbac.platformRoleBindingName" -}}
 {{- printf "vc-%s-v-%s-platform-role-binding" .Release.Name .Release.Namespace | trunc 63 | trimSuffix "-" -}}
 {{- end -}}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/sqlite"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type DBOptions struct {
+	Config string
+
+	Output string
+
+	Log log.Logger
+}
+
+type dbInfo struct {
+	File   string              `json:"file"`
+	Stats  *sqlite.Stats       `json:"stats"`
+	Rows   int64               `json:"rows"`
+	Volume *sqlite.VolumeUsage `json:"volume,omitempty"`
+}
+
+func NewDBCmd() *cobra.Command {
+	options := &DBOptions{
+		Log: log.GetInstance(),
+	}
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Show the health of the vCluster embedded database",
+		Long: `#######################################################
+################# vcluster debug db ###################
+#######################################################
+Shows the size, row count, compaction lag and free pages
+of the embedded SQLite database and the usage of the
+volume it is stored on.
+#######################################################
+		`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCommand *cobra.Command, _ []string) (err error) {
+			return ExecuteDB(cobraCommand.Context(), options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Config, "config", constants.DefaultVClusterConfigLocation, "The path where to find the vCluster config to load")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", "text", "The output format to use, either text or json")
+	return cmd
+}
+
+func ExecuteDB(ctx context.Context, options *DBOptions) error {
+	if options.Output != "text" && options.Output != "json" {
+		return fmt.Errorf("unsupported output format %q, use text or json", options.Output)
+	}
+
+	// parse vCluster config
+	vConfig, err := config.ParseConfig(options.Config, os.Getenv("VCLUSTER_NAME"), nil)
+	if err != nil {
+		return err
+	}
+
+	file := sqlite.DatabaseFile(vConfig)
+	if file == "" {
+		return fmt.Errorf("vCluster uses %s as backing store, but only the embedded SQLite database is supported", vConfig.BackingStoreType())
+	}
+
+	db, err := sqlite.Open(file)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	stats, err := sqlite.GetStats(ctx, db, file)
+	if err != nil {
+		return err
+	}
+	rows, err := sqlite.CountRows(ctx, db)
+	if err != nil {
+		return err
+	}
+	info := &dbInfo{File: file, Stats: stats, Rows: rows}
+	info.Volume, err = sqlite.GetVolumeUsage(file)
+	if err != nil {
+		info.Volume = nil
+	}
+
+	if options.Output == "json" {
+		out, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		options.Log.WriteString(logrus.InfoLevel, string(out)+"\n")
+		return nil
+	}
+
+	lines := []string{
+		fmt.Sprintf("File:              %s", info.File),
+		fmt.Sprintf("Database size:     %d bytes", stats.DatabaseSize),
+		fmt.Sprintf("WAL size:          %d bytes", stats.WALSize),
+		fmt.Sprintf("Rows:              %d", info.Rows),
+		fmt.Sprintf("Current revision:  %d", stats.CurrentRevision),
+		fmt.Sprintf("Compact revision:  %d", stats.CompactRevision),
+		fmt.Sprintf("Compaction lag:    %d revisions", stats.CompactionLag()),
+		fmt.Sprintf("Free pages:        %d of %d (%d bytes)", stats.FreePages, stats.Pages, stats.FreeBytes()),
+	}
+	if info.Volume != nil {
+		lines = append(lines, fmt.Sprintf("Volume usage:      %d%% (%d of %d bytes, %d bytes available)", info.Volume.Percentage(), info.Volume.Used, info.Volume.Capacity, info.Volume.Available))
+	}
+	for _, line := range lines {
+		options.Log.WriteString(logrus.InfoLevel, line+"\n")
+	}
+
+	return nil
+}
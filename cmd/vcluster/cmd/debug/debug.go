@@ -1,6 +1,7 @@
 package debug
 
 import (
+	"github.com/loft-sh/vcluster/cmd/vcluster/cmd/debug/db"
 	"github.com/loft-sh/vcluster/cmd/vcluster/cmd/debug/etcd"
 	"github.com/loft-sh/vcluster/cmd/vcluster/cmd/debug/mappings"
 	"github.com/spf13/cobra"
@@ -19,5 +20,6 @@ func NewDebugCmd() *cobra.Command {
 
 	debugCmd.AddCommand(mappings.NewMappingsCmd())
 	debugCmd.AddCommand(etcd.NewEtcdCmd())
+	debugCmd.AddCommand(db.NewDBCmd())
 	return debugCmd
 }
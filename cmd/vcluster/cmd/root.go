@@ -73,6 +73,7 @@ func BuildRoot() *cobra.Command {
 	rootCmd.AddCommand(NewVersionCommand())
 	rootCmd.AddCommand(snapshot.NewSnapshotCommand())
 	rootCmd.AddCommand(snapshot.NewRestoreCommand())
+	rootCmd.AddCommand(snapshot.NewMigrateBackingStoreCommand())
 	rootCmd.AddCommand(NewPortForwardCommand())
 	rootCmd.AddCommand(debug.NewDebugCmd())
 	rootCmd.AddCommand(node.NewNodeCmd())
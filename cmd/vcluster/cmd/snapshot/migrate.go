@@ -0,0 +1,62 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/snapshot"
+	"github.com/spf13/cobra"
+)
+
+func NewMigrateBackingStoreCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate-backing-store",
+		Short: "Migrate the vCluster backing store",
+		Long: `Copies all keys of the vCluster backing store into the backing store given
+as json in the ` + constants.VClusterMigrateBackingStoreEnv + ` environment variable. The source
+store is read from ` + constants.VClusterMigrateBackingStoreSourceEnv + ` if set. This is an
+internal command: it must only run while the vCluster control plane is down, and
+it is normally invoked by "vcluster migrate-backing-store" through the snapshot pod.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if active, state := isServiceActive(); active {
+				return fmt.Errorf("refusing to migrate: %s.service is %s on this host", constants.VClusterStandaloneSystemdServiceName, state)
+			}
+
+			vConfig, err := config.LoadConfig(os.Getenv("VCLUSTER_NAME"))
+			if err != nil {
+				return err
+			}
+
+			rawBackingStore := os.Getenv(constants.VClusterMigrateBackingStoreEnv)
+			if rawBackingStore == "" {
+				return fmt.Errorf("environment variable %s is not set", constants.VClusterMigrateBackingStoreEnv)
+			}
+
+			backingStore := vclusterconfig.BackingStore{}
+			err = json.Unmarshal([]byte(rawBackingStore), &backingStore)
+			if err != nil {
+				return fmt.Errorf("parse target backing store: %w", err)
+			}
+
+			// the config is already switched to the target if the target store had to be deployed first
+			if rawSourceBackingStore := os.Getenv(constants.VClusterMigrateBackingStoreSourceEnv); rawSourceBackingStore != "" {
+				sourceBackingStore := vclusterconfig.BackingStore{}
+				err = json.Unmarshal([]byte(rawSourceBackingStore), &sourceBackingStore)
+				if err != nil {
+					return fmt.Errorf("parse source backing store: %w", err)
+				}
+
+				vConfig = snapshot.MigrationTargetConfig(vConfig, sourceBackingStore)
+			}
+
+			return snapshot.MigrateBackingStore(cmd.Context(), vConfig, backingStore)
+		},
+	}
+
+	return cmd
+}
@@ -62,6 +62,9 @@ the backing store.`,
 				return fmt.Errorf("failed to parse options from environment: %w", err)
 			}
 			restoreClient := snapshot.NewRestoreClient(*envOptions, newVCluster)
+			restoreClient.FromHostNamespace = os.Getenv(constants.VClusterRestoreFromHostNamespaceEnv)
+			restoreClient.ScrubSecrets = os.Getenv(constants.VClusterRestoreScrubSecretsEnv) == "true"
+			restoreClient.ScaleDownWorkloads = os.Getenv(constants.VClusterRestoreScaleDownWorkloadsEnv) == "true"
 			return restoreClient.Run(cmd.Context(), vConfig)
 		},
 	}
@@ -10,6 +10,7 @@ import (
 )
 
 func NewSnapshotCommand() *cobra.Command {
+	paused := false
 	cmd := &cobra.Command{
 		Use:   "snapshot",
 		Short: "Manage vCluster snapshots",
@@ -19,7 +20,7 @@ func NewSnapshotCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			client := &snapshot.Client{}
+			client := &snapshot.Client{Paused: paused}
 			envOptions, err := snapshot.ParseOptionsFromEnv()
 			if err != nil {
 				return fmt.Errorf("failed to parse options from environment: %w", err)
@@ -30,6 +31,7 @@ func NewSnapshotCommand() *cobra.Command {
 		},
 	}
 
+	cmd.Flags().BoolVar(&paused, "paused", false, "The vCluster is paused, start its embedded backing store to take the snapshot")
 	cmd.AddCommand(NewCreateCmd())
 	cmd.AddCommand(NewGetCmd())
 	cmd.AddCommand(NewListCmd())
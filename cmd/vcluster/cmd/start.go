@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"runtime/debug"
+	"time"
 
 	"github.com/loft-sh/log"
 	"github.com/loft-sh/vcluster/pkg/cli/find"
@@ -19,6 +20,7 @@ import (
 	setupconfig "github.com/loft-sh/vcluster/pkg/setup/config"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	"github.com/loft-sh/vcluster/pkg/telemetry"
+	"github.com/loft-sh/vcluster/pkg/tracing"
 	"github.com/loft-sh/vcluster/pkg/util/osutil"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -88,6 +90,20 @@ func StartInCluster(ctx context.Context, options *StartOptions) error {
 	telemetry.StartControlPlane(vConfig)
 	defer telemetry.CollectorControlPlane.Flush()
 
+	// start tracing
+	err = tracing.Start(ctx, vConfig, telemetry.SyncerVersion)
+	if err != nil {
+		return fmt.Errorf("start tracing: %w", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := tracing.Shutdown(ctx); err != nil {
+			klog.Errorf("error shutting down tracing: %v", err)
+		}
+	}()
+
 	// capture errors
 	defer func() {
 		if r := recover(); r != nil {
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/loft-sh/vcluster/pkg/snapshot/pod"
+	"github.com/spf13/cobra"
+)
+
+// CloneCmd holds the cmd flags
+type CloneCmd struct {
+	*flags.GlobalFlags
+	cli.CloneOptions
+
+	Log log.Logger
+}
+
+// NewCloneCmd creates a new command
+func NewCloneCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &CloneCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	useLine, validator := util.NamedPositionalArgsValidator(true, true, "VCLUSTER_NAME", "CLONE_NAME")
+	cobraCmd := &cobra.Command{
+		Use:   "clone" + useLine,
+		Short: "Clones a running virtual cluster into a new virtual cluster",
+		Long: `#######################################################
+#################### vcluster clone ####################
+#######################################################
+Clone creates a new virtual cluster from a point-in-time
+snapshot of the backing store and config of a running
+virtual cluster. By default the snapshot is copied directly
+from the source to the clone, no snapshot storage is needed.
+Use --snapshot-url to go through a snapshot storage instead.
+Virtual clusters with an external database or etcd cannot
+be cloned, as the clone would share their backing store.
+Kube config secrets exported and objects mapped to other
+host namespaces are moved into the namespace of the clone.
+
+Secrets can be scrubbed and workloads scaled to zero,
+e.g. to debug a production virtual cluster safely.
+
+Example:
+vcluster clone prod prod-debug --namespace prod
+vcluster clone prod prod-debug --namespace prod --scrub-secrets --scale-down-workloads
+vcluster clone prod prod-debug --namespace prod --to-context debug-cluster --to-namespace debug
+vcluster clone prod prod-debug --namespace prod --snapshot-url oci://ghcr.io/my-user/my-repo:my-tag
+#######################################################
+	`,
+		Args:              validator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Driver, "driver", "", "The driver for the virtual cluster, only helm is supported.")
+	cobraCmd.Flags().StringVar(&cmd.ToNamespace, "to-namespace", "", "The host namespace to create the clone in. Defaults to vcluster-CLONE_NAME")
+	cobraCmd.Flags().StringVar(&cmd.ToContext, "to-context", "", "The kube context of the host cluster to create the clone in. Defaults to the context of the virtual cluster")
+	cobraCmd.Flags().StringVar(&cmd.SnapshotURL, "snapshot-url", "", "The snapshot storage to write the clone snapshot to and restore it from, e.g. s3://my-bucket/clone.tar.gz. Defaults to copying the snapshot directly")
+	cobraCmd.Flags().BoolVar(&cmd.ScrubSecrets, "scrub-secrets", false, "If true, empties the values of all user secrets in the clone and drops helm release secrets, service account tokens are kept")
+	cobraCmd.Flags().BoolVar(&cmd.ScaleDownWorkloads, "scale-down-workloads", false, "If true, scales all deployments, statefulsets and replicasets in the clone to zero and suspends jobs and cronjobs")
+	cobraCmd.Flags().DurationVar(&cmd.Timeout, "timeout", time.Minute*10, "How long to wait for the clone to become running")
+	pod.AddFlags(cobraCmd.Flags(), &cmd.Pod, false)
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *CloneCmd) Run(ctx context.Context, args []string) error {
+	cfg := cmd.LoadedConfig(cmd.Log)
+
+	// If driver has been passed as flag use it, otherwise read it from the config file
+	driverType, err := config.ParseDriverType(cmp.Or(cmd.Driver, string(cfg.Driver.Type)))
+	if err != nil {
+		return fmt.Errorf("parse driver type: %w", err)
+	} else if driverType != config.HelmDriver {
+		return fmt.Errorf("cloning a virtual cluster is only supported with the helm driver")
+	}
+
+	return cli.CloneHelm(ctx, &cmd.CloneOptions, cmd.GlobalFlags, args[0], args[1], cmd.Log)
+}
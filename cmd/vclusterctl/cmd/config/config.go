@@ -0,0 +1,21 @@
+package config
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func NewConfigCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "vCluster config subcommand",
+		Long: `#######################################################
+#################### vcluster config ###################
+#######################################################
+		`,
+		Args: cobra.NoArgs,
+	}
+
+	configCmd.AddCommand(NewLintCmd(globalFlags))
+	return configCmd
+}
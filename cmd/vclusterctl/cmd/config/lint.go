@@ -0,0 +1,59 @@
+package config
+
+import (
+	"cmp"
+	"fmt"
+
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+type LintOptions struct {
+	*flags.GlobalFlags
+
+	Options cli.ConfigLintOptions
+}
+
+func NewLintCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	o := &LintOptions{
+		GlobalFlags: globalFlags,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Lint a vCluster config",
+		Long: `#######################################################
+################# vcluster config lint ##################
+#######################################################
+Lint vCluster config files for invalid and deprecated
+options and for settings that are known to cause
+problems. Findings can be printed as text, JSON or
+SARIF. The command fails if a finding is at least as
+severe as --fail-on.
+
+Rules can be disabled with a comment in the values file:
+# vcluster-lint-disable: rule-a, rule-b
+or through the vcluster.loft.sh/lint-disable annotation
+in controlPlane.advanced.globalMetadata.annotations.
+
+Example:
+vcluster config lint -f values.yaml
+vcluster config lint -f values.yaml --output sarif > lint.sarif
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			// validate the config for the host namespace given by --namespace
+			o.Options.Namespace = cmp.Or(o.Namespace, o.Options.Name)
+			return cli.ConfigLint(&o.Options, cobraCmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&o.Options.Values, "values", "f", []string{}, "Path of the vCluster config file to lint, use - to read from stdin")
+	cmd.Flags().StringVarP(&o.Options.Output, "output", "o", "text", fmt.Sprintf("Choose the format of the output. %v", cli.ConfigLintOutputs))
+	cmd.Flags().StringVar(&o.Options.FailOn, "fail-on", "error", "Fail if a finding has at least this severity. [error|warning|info|none]")
+	cmd.Flags().StringVar(&o.Options.Name, "name", "vcluster", "The vCluster name to validate the config for")
+
+	return cmd
+}
@@ -46,6 +46,8 @@ vcluster connect test --namespace test
 # Open a new bash with the vcluster KUBECONFIG defined
 vcluster connect test -n test -- bash
 vcluster connect test -n test -- kubectl get ns
+# Keep port-forwarding in the background, even after network changes
+vcluster connect test -n test --background
 #######################################################
 	`,
 		Args:              nameValidator,
@@ -66,6 +68,7 @@ vcluster connect test -n test -- kubectl get ns
 	}
 
 	cobraCmd.Flags().StringVar(&cmd.Driver, "driver", "", "The driver to use for managing the virtual cluster, can be either helm, platform, or docker.")
+	cobraCmd.Flags().BoolVar(&cmd.Background, "background", false, "If true, port-forwarding is done by a background daemon that reconnects automatically. Use 'vcluster connections ls' to list background connections and 'vcluster disconnect' to stop them. Only works with driver helm")
 
 	connect.AddCommonFlags(cobraCmd, &cmd.ConnectOptions)
 	connect.AddPlatformFlags(cobraCmd, &cmd.ConnectOptions, "[PLATFORM] ")
@@ -95,6 +98,15 @@ func (cmd *ConnectCmd) Run(ctx context.Context, args []string) error {
 		return fmt.Errorf("parse driver type: %w", err)
 	}
 
+	if cmd.ExecCredential && driverType != config.HelmDriver {
+		return fmt.Errorf("--exec-credential is only supported with driver type %s", config.HelmDriver)
+	}
+	if cmd.Background && driverType != config.HelmDriver {
+		return fmt.Errorf("--background is only supported with driver type %s", config.HelmDriver)
+	} else if cmd.Background && len(args) > 1 {
+		return fmt.Errorf("--background can't be used together with a command")
+	}
+
 	if driverType == config.PlatformDriver {
 		return cli.ConnectPlatform(ctx, &cmd.ConnectOptions, cmd.GlobalFlags, vClusterName, args[1:], cmd.Log)
 	}
@@ -123,6 +135,9 @@ func (cmd *ConnectCmd) validateFlags() error {
 	if cmd.ServiceAccountClusterRole != "" && cmd.ServiceAccount == "" {
 		return fmt.Errorf("expected --service-account to be defined as well")
 	}
+	if cmd.ExecCredential && cmd.ServiceAccount == "" {
+		return fmt.Errorf("--exec-credential requires --service-account to be defined as well")
+	}
 
 	return nil
 }
@@ -0,0 +1,25 @@
+package connections
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func NewConnectionsCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	connectionsCmd := &cobra.Command{
+		Use:   "connections",
+		Short: "Manage background virtual cluster connections",
+		Long: `#######################################################
+################# vcluster connections ################
+#######################################################
+Manage the virtual cluster port-forwarding done in the
+background by "vcluster connect --background"
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+	}
+
+	connectionsCmd.AddCommand(list(globalFlags))
+	connectionsCmd.AddCommand(daemon(globalFlags))
+	return connectionsCmd
+}
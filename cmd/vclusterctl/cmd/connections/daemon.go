@@ -0,0 +1,48 @@
+package connections
+
+import (
+	"context"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/connectdaemon"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+type DaemonCmd struct {
+	*flags.GlobalFlags
+
+	log log.Logger
+}
+
+func daemon(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &DaemonCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Runs the connect daemon in the foreground",
+		Long: `#######################################################
+############# vcluster connections daemon #############
+#######################################################
+Runs the daemon that port-forwards virtual clusters in
+the background. It is started automatically by
+"vcluster connect --background" and stops once all
+connections were removed.
+#######################################################
+	`,
+		Args:   cobra.NoArgs,
+		Hidden: true,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return cmd.Run(cobraCmd.Context())
+		},
+	}
+
+	return cobraCmd
+}
+
+func (cmd *DaemonCmd) Run(ctx context.Context) error {
+	return connectdaemon.NewDaemon(cmd.log).Run(ctx, connectdaemon.SocketPath(cmd.Config))
+}
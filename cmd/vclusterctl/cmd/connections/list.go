@@ -0,0 +1,52 @@
+package connections
+
+import (
+	"context"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+type ListCmd struct {
+	*flags.GlobalFlags
+	cli.ListConnectionsOptions
+
+	log log.Logger
+}
+
+func list(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ListCmd{
+		GlobalFlags: globalFlags,
+		log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists all background virtual cluster connections",
+		Long: `#######################################################
+############## vcluster connections list ##############
+#######################################################
+Lists all virtual cluster connections port-forwarded
+in the background
+
+Example:
+vcluster connections ls
+vcluster connections ls --output json
+#######################################################
+	`,
+		Args:    cobra.NoArgs,
+		Aliases: []string{"ls"},
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return cmd.Run(cobraCmd.Context())
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Output, "output", "table", "Choose the format of the output. [table|json]")
+	return cobraCmd
+}
+
+func (cmd *ListCmd) Run(ctx context.Context) error {
+	return cli.ListConnections(ctx, &cmd.ListConnectionsOptions, cmd.GlobalFlags, cmd.log)
+}
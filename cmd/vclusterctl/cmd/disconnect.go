@@ -1,12 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	"github.com/loft-sh/log"
 	"github.com/loft-sh/log/survey"
 	"github.com/loft-sh/log/terminal"
+	"github.com/loft-sh/vcluster/pkg/cli/connectdaemon"
 	"github.com/loft-sh/vcluster/pkg/cli/find"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
 	"github.com/spf13/cobra"
@@ -18,6 +20,8 @@ import (
 type DisconnectCmd struct {
 	*flags.GlobalFlags
 
+	All bool
+
 	log log.Logger
 }
 
@@ -36,24 +40,38 @@ func NewDisconnectCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
 #######################################################
 Disconnect switches back the kube context if
 "vcluster connect --update-current" or "vcluster platform
-connect" was used
+connect" was used and stops the background port-forwarding
+started by "vcluster connect --background"
 
 Example:
 vcluster connect --update-current
 vcluster disconnect
+# Stop all background port-forwarding
+vcluster disconnect --all
 #######################################################
 	`,
 		Args: cobra.NoArgs,
-		RunE: func(_ *cobra.Command, _ []string) error {
-			return cmd.Run()
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return cmd.Run(cobraCmd.Context())
 		},
 	}
 
+	cobraCmd.Flags().BoolVar(&cmd.All, "all", false, "If true, stops all background port-forwarding started by vcluster connect --background")
 	return cobraCmd
 }
 
 // Run executes the functionality
-func (cmd *DisconnectCmd) Run() error {
+func (cmd *DisconnectCmd) Run(ctx context.Context) error {
+	daemonClient := connectdaemon.NewClient(connectdaemon.SocketPath(cmd.Config))
+	if cmd.All && daemonClient.IsRunning(ctx) {
+		err := daemonClient.RemoveAll(ctx)
+		if err != nil {
+			return fmt.Errorf("stop background connections: %w", err)
+		}
+
+		cmd.log.Infof("Successfully stopped all background connections")
+	}
+
 	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{
 		CurrentContext: cmd.Context,
 	})
@@ -80,11 +98,37 @@ func (cmd *DisconnectCmd) Run() error {
 			// get vCluster docker info from context
 			vClusterName, otherContext = find.VClusterDockerFromContext(cmd.Context)
 			if vClusterName == "" {
+				// there is nothing to switch back from
+				if cmd.All {
+					return nil
+				}
+
 				return fmt.Errorf("current selected context %q is not a virtual cluster context. If you've used a custom context name you will need to switch manually using kubectl", otherContext)
 			}
 		}
 	}
 
+	// stop the background port-forwarding of the context, if there is any
+	if !cmd.All && daemonClient.IsRunning(ctx) {
+		connections, err := daemonClient.List(ctx)
+		if err != nil {
+			return fmt.Errorf("list background connections: %w", err)
+		}
+
+		for _, connection := range connections {
+			if connection.ID != cmd.Context {
+				continue
+			}
+
+			err = daemonClient.Remove(ctx, connection.ID)
+			if err != nil {
+				return fmt.Errorf("stop background connection: %w", err)
+			}
+
+			cmd.log.Infof("Successfully stopped background connection to vcluster %s/%s", connection.Namespace, connection.Name)
+		}
+	}
+
 	if cfg.PreviousContext != "" {
 		otherContext = cfg.PreviousContext
 	}
@@ -38,6 +38,7 @@ Example:
 vcluster list
 vcluster list --output json
 vcluster list --namespace test
+vcluster list --all-contexts
 #######################################################
 	`,
 		Args:    cobra.NoArgs,
@@ -49,6 +50,8 @@ vcluster list --namespace test
 
 	cobraCmd.Flags().StringVar(&cmd.Driver, "driver", "", "The driver to use for managing the virtual cluster, can be either helm, platform, or docker.")
 	cobraCmd.Flags().StringVar(&cmd.Output, "output", "table", "Choose the format of the output. [table|json]")
+	cobraCmd.Flags().BoolVar(&cmd.AllContexts, "all-contexts", false, "List the virtual clusters of all kube contexts, the docker driver and the platform")
+	cobraCmd.Flags().DurationVar(&cmd.ContextTimeout, "context-timeout", cli.DefaultListContextTimeout, "The time to wait for a single kube context with --all-contexts")
 
 	return cobraCmd
 }
@@ -56,6 +59,13 @@ vcluster list --namespace test
 // Run executes the functionality
 func (cmd *ListCmd) Run(cobraCmd *cobra.Command) error {
 	cfg := cmd.LoadedConfig(cmd.log)
+	if cmd.AllContexts {
+		if cmd.Context != "" {
+			return fmt.Errorf("--all-contexts cannot be used together with --context")
+		}
+
+		return cli.ListAllContexts(cobraCmd.Context(), &cmd.ListOptions, cmd.GlobalFlags, cmd.log)
+	}
 
 	// If driver has been passed as flag use it, otherwise read it from the config file
 	driverType, err := config.ParseDriverType(cmp.Or(cmd.Driver, string(cfg.Driver.Type)))
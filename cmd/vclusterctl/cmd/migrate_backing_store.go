@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/snapshot/pod"
+	"github.com/spf13/cobra"
+)
+
+// MigrateBackingStoreCmd holds the cmd flags
+type MigrateBackingStoreCmd struct {
+	*flags.GlobalFlags
+	cli.MigrateBackingStoreOptions
+
+	Driver string
+
+	Log log.Logger
+}
+
+// NewMigrateBackingStoreCmd creates a new command
+func NewMigrateBackingStoreCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &MigrateBackingStoreCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "migrate-backing-store" + util.VClusterNameOnlyUseLine,
+		Short: "Migrates a virtual cluster to another backing store",
+		Long: fmt.Sprintf(`#######################################################
+############ vcluster migrate-backing-store ############
+#######################################################
+Migrate the backing store of a virtual cluster, e.g.
+from the embedded SQLite database to embedded etcd or
+an external database.
+
+The virtual cluster is paused, all keys are copied from
+the current into the new backing store and the virtual
+cluster is upgraded to use the new backing store and
+resumed afterwards. The current backing store is not
+modified, the new backing store is emptied before the
+migration. Deployed etcd is installed by the chart before
+the keys are copied, while the virtual cluster stays
+scaled down. Supported backing stores: %v
+
+Example:
+vcluster migrate-backing-store test --to embedded-etcd
+vcluster migrate-backing-store test --to deployed-etcd
+vcluster migrate-backing-store test --to external-database --set controlPlane.backingStore.database.external.dataSource=postgres://...
+#######################################################
+	`, cli.MigrationTargets),
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Driver, "driver", "", "The driver for the virtual cluster, can be either helm, platform, or docker.")
+	cobraCmd.Flags().StringVar(&cmd.To, "to", "", fmt.Sprintf("The backing store to migrate to, one of %v", cli.MigrationTargets))
+	cobraCmd.Flags().StringVar(&cmd.ChartName, "chart-name", "vcluster", "The virtual cluster chart name to use")
+	cobraCmd.Flags().StringVar(&cmd.ChartRepo, "chart-repo", constants.LoftChartRepo, "The virtual cluster chart repo to use")
+	cobraCmd.Flags().StringVar(&cmd.ChartVersion, "chart-version", "", "The virtual cluster chart version to use, defaults to the version of the virtual cluster")
+	cobraCmd.Flags().StringArrayVarP(&cmd.Values, "values", "f", []string{}, "Path where to load extra helm values from, e.g. the settings of the new backing store")
+	cobraCmd.Flags().StringArrayVar(&cmd.SetValues, "set", []string{}, "Set values for the new backing store, e.g. --set controlPlane.backingStore.etcd.external.endpoint=my-etcd:2379")
+	pod.AddFlags(cobraCmd.Flags(), &cmd.Pod, true)
+	_ = cobraCmd.MarkFlagRequired("to")
+
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *MigrateBackingStoreCmd) Run(ctx context.Context, args []string) error {
+	cfg := cmd.LoadedConfig(cmd.Log)
+
+	// If driver has been passed as flag use it, otherwise read it from the config file
+	driverType, err := config.ParseDriverType(cmp.Or(cmd.Driver, string(cfg.Driver.Type)))
+	if err != nil {
+		return fmt.Errorf("parse driver type: %w", err)
+	}
+	if driverType != config.HelmDriver {
+		return fmt.Errorf("migrating the backing store is only supported for the helm driver")
+	}
+
+	return cli.MigrateBackingStoreHelm(ctx, cmd.GlobalFlags, args[0], &cmd.MigrateBackingStoreOptions, cmd.Log)
+}
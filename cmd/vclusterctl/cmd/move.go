@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/loft-sh/vcluster/pkg/platform"
+	"github.com/loft-sh/vcluster/pkg/snapshot/pod"
+	"github.com/spf13/cobra"
+)
+
+// MoveCmd holds the cmd flags
+type MoveCmd struct {
+	*flags.GlobalFlags
+	cli.MoveOptions
+
+	Log log.Logger
+}
+
+// NewMoveCmd creates a new command
+func NewMoveCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &MoveCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	cobraCmd := &cobra.Command{
+		Use:   "move" + util.VClusterNameOnlyUseLine,
+		Short: "Moves a virtual cluster to another host namespace or host cluster",
+		Long: `#######################################################
+#################### vcluster move #####################
+#######################################################
+Move pauses the virtual cluster, takes a snapshot of it
+and restores the snapshot into the target namespace or
+host cluster. Workloads are recreated in the target.
+
+The source virtual cluster is deleted once the target is
+running. If the move fails, the target is removed and the
+source is resumed. Use --keep-source to keep the paused
+source for a manual rollback.
+
+The snapshot url has to be reachable from both host
+clusters.
+
+Example:
+vcluster move test --namespace test --to-namespace test-new --snapshot-url oci://ghcr.io/my-user/my-repo:my-tag
+vcluster move test --namespace test --to-context other-cluster --snapshot-url s3://my-bucket/my-key --keep-source
+#######################################################
+	`,
+		Args:              util.VClusterNameOnlyValidator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Driver, "driver", "", "The driver for the virtual cluster, only helm is supported.")
+	cobraCmd.Flags().StringVar(&cmd.ToNamespace, "to-namespace", "", "The host namespace to move the virtual cluster to. Defaults to the current namespace of the virtual cluster")
+	cobraCmd.Flags().StringVar(&cmd.ToContext, "to-context", "", "The kube context of the host cluster to move the virtual cluster to. Defaults to the current context of the virtual cluster")
+	cobraCmd.Flags().StringVar(&cmd.SnapshotURL, "snapshot-url", "", "Where to store the snapshot used for the move. E.g. oci://ghcr.io/my-user/my-repo:my-tag or s3://my-bucket/my-key")
+	cobraCmd.Flags().StringVar(&cmd.SnapshotTempDir, "snapshot-temp-dir", "", "Temporary directory for snapshot operations. If set to empty string, the OS default directory for temporary files will be used")
+	cobraCmd.Flags().BoolVar(&cmd.KeepSource, "keep-source", false, "If true, keeps the paused source virtual cluster after the move instead of deleting it")
+	cobraCmd.Flags().DurationVar(&cmd.Timeout, "timeout", time.Minute*10, "How long to wait for the moved virtual cluster to become running before rolling back")
+	pod.AddFlags(cobraCmd.Flags(), &cmd.Pod, true)
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *MoveCmd) Run(ctx context.Context, args []string) error {
+	cfg := cmd.LoadedConfig(cmd.Log)
+
+	// If driver has been passed as flag use it, otherwise read it from the config file
+	driverType, err := config.ParseDriverType(cmp.Or(cmd.Driver, string(cfg.Driver.Type)))
+	if err != nil {
+		return fmt.Errorf("parse driver type: %w", err)
+	} else if driverType != config.HelmDriver {
+		return fmt.Errorf("moving a virtual cluster is only supported with the helm driver")
+	}
+
+	// the platform client is optional and only used to remove the source from the platform
+	platformClient, _ := platform.InitClientFromConfig(ctx, cfg)
+	return cli.MoveHelm(ctx, platformClient, &cmd.MoveOptions, cmd.GlobalFlags, args[0], cmd.Log)
+}
@@ -0,0 +1,86 @@
+package pvc
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/completion"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/cli/util"
+	"github.com/spf13/cobra"
+)
+
+// MigrateCmd holds the cmd flags
+type MigrateCmd struct {
+	*flags.GlobalFlags
+	cli.PVCMigrateOptions
+
+	Driver string
+
+	Log log.Logger
+}
+
+// NewMigrateCmd creates a new command
+func NewMigrateCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &MigrateCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	useLine, validator := util.NamedPositionalArgsValidator(true, true, "VCLUSTER_NAME", "NAMESPACE/PVC")
+	cobraCmd := &cobra.Command{
+		Use:   "migrate" + useLine,
+		Short: "Migrates a persistent volume claim to another storage class",
+		Long: `#######################################################
+################# vcluster pvc migrate #################
+#######################################################
+Migrate moves the data of a persistent volume claim
+within the virtual cluster to a new volume of another
+storage class.
+
+Deployments and statefulsets using the claim are scaled
+down, the data is copied by a job in the host namespace
+and the claim is recreated with the same name bound to
+the new volume. The workloads are scaled up afterwards.
+
+Example:
+vcluster pvc migrate test default/data --storage-class fast
+vcluster pvc migrate test default/data --storage-class fast --keep-old-volume
+#######################################################
+	`,
+		Args:              validator,
+		ValidArgsFunction: completion.NewValidVClusterNameFunc(globalFlags),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	cobraCmd.Flags().StringVar(&cmd.Driver, "driver", "", "The driver for the virtual cluster, only helm is supported.")
+	cobraCmd.Flags().StringVar(&cmd.StorageClass, "storage-class", "", "The storage class within the virtual cluster to migrate the persistent volume claim to")
+	cobraCmd.Flags().StringVar(&cmd.Image, "image", cli.DefaultPVCMigrateImage, "The image of the job that copies the data in the host cluster")
+	cobraCmd.Flags().BoolVar(&cmd.KeepOldVolume, "keep-old-volume", false, "If true, the old persistent volume is retained in the host cluster instead of being reclaimed")
+	cobraCmd.Flags().DurationVar(&cmd.Timeout, "timeout", time.Hour, "How long to wait for each step of the migration, e.g. copying the data")
+	_ = cobraCmd.MarkFlagRequired("storage-class")
+
+	return cobraCmd
+}
+
+// Run executes the functionality
+func (cmd *MigrateCmd) Run(ctx context.Context, args []string) error {
+	cfg := cmd.LoadedConfig(cmd.Log)
+
+	// If driver has been passed as flag use it, otherwise read it from the config file
+	driverType, err := config.ParseDriverType(cmp.Or(cmd.Driver, string(cfg.Driver.Type)))
+	if err != nil {
+		return fmt.Errorf("parse driver type: %w", err)
+	} else if driverType != config.HelmDriver {
+		return fmt.Errorf("migrating persistent volume claims is only supported with the helm driver")
+	}
+
+	return cli.PVCMigrateHelm(ctx, &cmd.PVCMigrateOptions, cmd.GlobalFlags, args[0], args[1], cmd.Log)
+}
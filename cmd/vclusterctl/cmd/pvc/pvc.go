@@ -0,0 +1,22 @@
+package pvc
+
+import (
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+// NewPVCCmd creates a new command
+func NewPVCCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	pvcCmd := &cobra.Command{
+		Use:   "pvc",
+		Short: "Manage persistent volume claims of a virtual cluster",
+		Long: `#######################################################
+##################### vcluster pvc ####################
+#######################################################
+		`,
+		Args: cobra.NoArgs,
+	}
+
+	pvcCmd.AddCommand(NewMigrateCmd(globalFlags))
+	return pvcCmd
+}
@@ -0,0 +1,192 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type GcOptions struct {
+	*flags.GlobalFlags
+
+	KeepInUse bool
+	Keep      []string
+	DryRun    bool
+
+	Log log.Logger
+}
+
+func NewGcCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	o := &GcOptions{
+		GlobalFlags: globalFlags,
+
+		Log: log.GetInstance(),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Delete unused images from the vCluster registry",
+		Long: `#######################################################
+################# vcluster registry gc ##################
+#######################################################
+Delete the images from the vCluster registry that are not
+used by any pod in the virtual cluster and unlink their
+blobs.
+
+This command only deletes images through the registry API,
+it doesn't run the registry garbage collection and doesn't
+free the storage of the unlinked blobs. Untagged manifests,
+e.g. of overwritten tags, cannot be listed through the
+registry API and are not deleted.
+
+Example:
+vcluster registry gc --dry-run
+vcluster registry gc --keep 'library/nginx:*' --keep 'charts/*'
+#######################################################
+	`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return o.Run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.KeepInUse, "keep-in-use", true, "If enabled, images referenced by pods in the virtual cluster are not deleted")
+	cmd.Flags().StringSliceVar(&o.Keep, "keep", []string{}, "Glob patterns of repository:tag to keep, e.g. 'library/nginx:*'")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "If enabled, only prints the images that would be deleted")
+
+	return cmd
+}
+
+func (o *GcOptions) Run(ctx context.Context) error {
+	for _, pattern := range o.Keep {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid --keep pattern %s: %w", pattern, err)
+		}
+	}
+
+	client, restConfig, err := newRegistryClient(ctx, o.GlobalFlags, o.Log)
+	if err != nil {
+		return err
+	}
+
+	// get the images used by pods in the virtual cluster
+	podImages := []string{}
+	if o.KeepInUse {
+		kubeClient, err := kubernetes.NewForConfig(restConfig)
+		if err != nil {
+			return fmt.Errorf("create kube client: %w", err)
+		}
+
+		podList, err := kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("list pods: %w", err)
+		}
+
+		podImages = imagesInUse(podList.Items)
+	}
+
+	images, err := client.listImages(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list images: %w", err)
+	}
+
+	deleted := unusedImages(images, podImages, o.Keep)
+	if len(deleted) == 0 {
+		o.Log.Donef("No unused images found")
+		return nil
+	}
+
+	if o.DryRun {
+		for _, image := range deleted {
+			o.Log.Infof("Would delete %s@%s (%s)", image.Repository, image.Digest, units.HumanSize(float64(image.Size)))
+		}
+
+		o.Log.Infof("Would delete %d images", len(deleted))
+		return nil
+	}
+
+	err = client.deleteImages(ctx, deleted, images, o.Log)
+	if err != nil {
+		return err
+	}
+
+	o.Log.Donef("Deleted %d images, the storage of their blobs is not freed until the registry garbage collection runs", len(deleted))
+	return nil
+}
+
+// unusedImages returns the images that are neither used by a pod nor match one of the keep patterns
+func unusedImages(images []*registryImage, podImages []string, keep []string) []*registryImage {
+	unused := []*registryImage{}
+	for _, image := range images {
+		if isKept(image, keep) {
+			continue
+		}
+
+		inUse := false
+		for _, podImage := range podImages {
+			if imageMatches(image, podImage) {
+				inUse = true
+				break
+			}
+		}
+		if !inUse {
+			unused = append(unused, image)
+		}
+	}
+
+	return unused
+}
+
+// isKept checks if any tag of the image matches one of the patterns. Images are deleted by digest, so keeping one tag keeps them all.
+func isKept(image *registryImage, keep []string) bool {
+	for _, pattern := range keep {
+		for _, tag := range image.Tags {
+			if matched, _ := path.Match(pattern, image.Repository+":"+tag); matched {
+				return true
+			}
+		}
+		if matched, _ := path.Match(pattern, image.Repository+"@"+image.Digest); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// imagesInUse returns the images and image ids of all pods that have not terminated yet
+func imagesInUse(pods []corev1.Pod) []string {
+	images := []string{}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		for _, container := range pod.Spec.InitContainers {
+			images = append(images, container.Image)
+		}
+		for _, container := range pod.Spec.Containers {
+			images = append(images, container.Image)
+		}
+		for _, container := range pod.Spec.EphemeralContainers {
+			images = append(images, container.Image)
+		}
+		for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses, pod.Status.EphemeralContainerStatuses} {
+			for _, status := range statuses {
+				if status.ImageID != "" {
+					images = append(images, strings.TrimPrefix(status.ImageID, "docker-pullable://"))
+				}
+			}
+		}
+	}
+
+	return images
+}
@@ -0,0 +1,360 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/util/clihelper"
+	"k8s.io/client-go/rest"
+)
+
+var errBlobDeleteUnsupported = errors.New("the vCluster registry does not allow deleting blobs, please set storage.delete.enabled to true in the registry config")
+
+// registryImage is a single manifest within a repository of the vCluster registry
+type registryImage struct {
+	Repository string   `json:"repository"`
+	Tags       []string `json:"tags,omitempty"`
+	Digest     string   `json:"digest"`
+	MediaType  string   `json:"mediaType,omitempty"`
+	Size       int64    `json:"size"`
+
+	// manifests are the digests of the manifests referenced by an index
+	manifests []string
+	// blobs are the digests of the config and layer blobs referenced by this image
+	blobs map[string]int64
+}
+
+// registryClient talks to the vCluster registry through a local reverse proxy
+type registryClient struct {
+	registry name.Registry
+	baseURL  string
+	options  []remote.Option
+}
+
+func newRegistryClient(ctx context.Context, globalFlags *flags.GlobalFlags, log log.Logger) (*registryClient, *rest.Config, error) {
+	restConfig, err := getConfig(ctx, globalFlags)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get client config: %w", err)
+	}
+
+	// start the reverse proxy
+	localPort := clihelper.RandomPort()
+	if err := startReverseProxy(restConfig, localPort, log); err != nil {
+		return nil, nil, fmt.Errorf("failed to start reverse proxy: %w", err)
+	}
+
+	host := fmt.Sprintf("127.0.0.1:%d", localPort)
+	registry, err := name.NewRegistry(host, name.Insecure)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &registryClient{
+		registry: registry,
+		baseURL:  "http://" + host,
+		options:  []remote.Option{remote.WithContext(ctx)},
+	}, restConfig, nil
+}
+
+// listImages returns all tagged images of the given repositories or of all repositories if none are given
+func (r *registryClient) listImages(ctx context.Context, repositories []string) ([]*registryImage, error) {
+	if len(repositories) == 0 {
+		var err error
+		repositories, err = remote.Catalog(ctx, r.registry, r.options...)
+		if err != nil {
+			return nil, fmt.Errorf("list repositories: %w", err)
+		}
+	}
+
+	images := []*registryImage{}
+	for _, repository := range repositories {
+		repositoryImages, err := r.listRepositoryImages(repository)
+		if err != nil {
+			return nil, err
+		}
+
+		images = append(images, repositoryImages...)
+	}
+
+	return images, nil
+}
+
+func (r *registryClient) listRepositoryImages(repository string) ([]*registryImage, error) {
+	repo := r.registry.Repo(repository)
+	tags, err := remote.List(repo, r.options...)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("list tags of %s: %w", repository, err)
+	}
+	sort.Strings(tags)
+
+	// group the tags by digest
+	byDigest := map[string]*registryImage{}
+	images := []*registryImage{}
+	for _, tag := range tags {
+		descriptor, err := remote.Get(repo.Tag(tag), r.options...)
+		if err != nil {
+			return nil, fmt.Errorf("get %s:%s: %w", repository, tag, err)
+		}
+
+		digest := descriptor.Digest.String()
+		if image, ok := byDigest[digest]; ok {
+			image.Tags = append(image.Tags, tag)
+			continue
+		}
+
+		image, err := r.inspect(repo, descriptor)
+		if err != nil {
+			return nil, fmt.Errorf("inspect %s:%s: %w", repository, tag, err)
+		}
+
+		image.Tags = []string{tag}
+		byDigest[digest] = image
+		images = append(images, image)
+	}
+
+	return images, nil
+}
+
+// inspect resolves the blobs an image references and calculates its size
+func (r *registryClient) inspect(repo name.Repository, descriptor *remote.Descriptor) (*registryImage, error) {
+	image := &registryImage{
+		Repository: repo.RepositoryStr(),
+		Digest:     descriptor.Digest.String(),
+		MediaType:  string(descriptor.MediaType),
+		Size:       descriptor.Size,
+		blobs:      map[string]int64{},
+	}
+
+	if !descriptor.MediaType.IsIndex() {
+		err := addManifestBlobs(image, descriptor.Manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		image.Size += sumBlobs(image.blobs)
+		return image, nil
+	}
+
+	index, err := descriptor.ImageIndex()
+	if err != nil {
+		return nil, err
+	}
+	indexManifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range indexManifest.Manifests {
+		childDescriptor, err := remote.Get(repo.Digest(child.Digest.String()), r.options...)
+		if err != nil {
+			if isNotFound(err) {
+				// indexes pushed for a single architecture only contain that one manifest
+				continue
+			}
+
+			return nil, err
+		} else if childDescriptor.MediaType.IsIndex() {
+			// nested indexes are rare, only count the manifest itself
+			image.manifests = append(image.manifests, child.Digest.String())
+			image.Size += child.Size
+			continue
+		}
+
+		err = addManifestBlobs(image, childDescriptor.Manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		image.manifests = append(image.manifests, child.Digest.String())
+		image.Size += child.Size
+	}
+
+	image.Size += sumBlobs(image.blobs)
+	return image, nil
+}
+
+// deleteImages deletes the given images and unlinks all blobs that are not referenced by any of the remaining images
+// anymore. Unlinking doesn't free the storage of the blobs in the registry.
+func (r *registryClient) deleteImages(ctx context.Context, deleted, remaining []*registryImage, log log.Logger) error {
+	for _, image := range deleted {
+		ref := r.registry.Repo(image.Repository).Digest(image.Digest)
+		log.Infof("Deleting %s@%s", image.Repository, image.Digest)
+		err := remote.Delete(ref, r.options...)
+		if err != nil {
+			return fmt.Errorf("delete %s@%s: %w", image.Repository, image.Digest, err)
+		}
+	}
+
+	// delete the manifests and blobs that are no longer referenced
+	manifests, blobs := unreferenced(deleted, remaining)
+	for repository, digests := range manifests {
+		for _, digest := range digests {
+			err := remote.Delete(r.registry.Repo(repository).Digest(digest), r.options...)
+			if err != nil && !isNotFound(err) {
+				return fmt.Errorf("delete %s@%s: %w", repository, digest, err)
+			}
+		}
+	}
+
+	for repository, repositoryBlobs := range blobs {
+		for digest := range repositoryBlobs {
+			err := r.deleteBlob(ctx, repository, digest)
+			if errors.Is(err, errBlobDeleteUnsupported) {
+				log.Warn(err.Error())
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("delete blob %s@%s: %w", repository, digest, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *registryClient) deleteBlob(ctx context.Context, repository, digest string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/v2/%s/blobs/%s", r.baseURL, repository, digest), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusAccepted, http.StatusOK, http.StatusNotFound:
+		return nil
+	case http.StatusMethodNotAllowed:
+		return errBlobDeleteUnsupported
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+}
+
+// unreferenced returns the child manifests and blobs per repository that are only referenced by the deleted images.
+// Blobs are linked per repository in the registry, so a blob shared across repositories is unlinked in each one separately.
+func unreferenced(deleted, remaining []*registryImage) (map[string][]string, map[string]map[string]int64) {
+	deletedDigests := map[string]bool{}
+	for _, image := range deleted {
+		deletedDigests[image.Repository+"@"+image.Digest] = true
+	}
+
+	referencedManifests := map[string]bool{}
+	referencedBlobs := map[string]bool{}
+	for _, image := range remaining {
+		if deletedDigests[image.Repository+"@"+image.Digest] {
+			continue
+		}
+
+		referencedManifests[image.Repository+"@"+image.Digest] = true
+		for _, manifest := range image.manifests {
+			referencedManifests[image.Repository+"@"+manifest] = true
+		}
+		for blob := range image.blobs {
+			referencedBlobs[image.Repository+"@"+blob] = true
+		}
+	}
+
+	manifests := map[string][]string{}
+	blobs := map[string]map[string]int64{}
+	for _, image := range deleted {
+		for _, manifest := range image.manifests {
+			key := image.Repository + "@" + manifest
+			if referencedManifests[key] {
+				continue
+			}
+
+			referencedManifests[key] = true
+			manifests[image.Repository] = append(manifests[image.Repository], manifest)
+		}
+		for blob, size := range image.blobs {
+			if referencedBlobs[image.Repository+"@"+blob] {
+				continue
+			}
+
+			if blobs[image.Repository] == nil {
+				blobs[image.Repository] = map[string]int64{}
+			}
+			blobs[image.Repository][blob] = size
+		}
+	}
+
+	return manifests, blobs
+}
+
+func addManifestBlobs(image *registryImage, rawManifest []byte) error {
+	manifest, err := v1.ParseManifest(bytes.NewReader(rawManifest))
+	if err != nil {
+		return fmt.Errorf("parse manifest: %w", err)
+	}
+
+	if manifest.Config.Digest.Hex != "" {
+		image.blobs[manifest.Config.Digest.String()] = manifest.Config.Size
+	}
+	for _, layer := range manifest.Layers {
+		image.blobs[layer.Digest.String()] = layer.Size
+	}
+
+	return nil
+}
+
+func sumBlobs(blobs map[string]int64) int64 {
+	size := int64(0)
+	for _, blobSize := range blobs {
+		size += blobSize
+	}
+
+	return size
+}
+
+func isNotFound(err error) bool {
+	var transportErr *transport.Error
+	return errors.As(err, &transportErr) && transportErr.StatusCode == http.StatusNotFound
+}
+
+// imageMatches checks if the image reference (e.g. nginx, library/nginx:1.25 or nginx@sha256:...) points to the given image.
+// The registry part of the reference is ignored, since pods reference the images by the registry they were pulled from.
+func imageMatches(image *registryImage, reference string) bool {
+	// runtimes sometimes report the plain digest as image id
+	if strings.HasPrefix(reference, "sha256:") {
+		return image.Digest == reference || slices.Contains(image.manifests, reference)
+	}
+
+	ref, err := name.ParseReference(reference, name.WeakValidation)
+	if err != nil {
+		return false
+	}
+
+	repository := ref.Context().RepositoryStr()
+	if repository != image.Repository && !(strings.HasPrefix(repository, "library/") && strings.TrimPrefix(repository, "library/") == image.Repository) {
+		return false
+	}
+
+	switch ref := ref.(type) {
+	case name.Digest:
+		return image.Digest == ref.DigestStr() || slices.Contains(image.manifests, ref.DigestStr())
+	case name.Tag:
+		return slices.Contains(image.Tags, ref.TagStr())
+	}
+
+	return false
+}
@@ -0,0 +1,102 @@
+package registry
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestImageMatches(t *testing.T) {
+	image := &registryImage{
+		Repository: "library/nginx",
+		Tags:       []string{"1.25", "latest"},
+		Digest:     "sha256:1111111111111111111111111111111111111111111111111111111111111111",
+		manifests:  []string{"sha256:2222222222222222222222222222222222222222222222222222222222222222"},
+	}
+	pushedImage := &registryImage{
+		Repository: "my-app",
+		Tags:       []string{"v1"},
+		Digest:     "sha256:3333333333333333333333333333333333333333333333333333333333333333",
+	}
+
+	testCases := []struct {
+		name      string
+		image     *registryImage
+		reference string
+		expected  bool
+	}{
+		{name: "short name", image: image, reference: "nginx", expected: true},
+		{name: "tag", image: image, reference: "nginx:1.25", expected: true},
+		{name: "other registry", image: image, reference: "my-registry.example.com:5000/library/nginx:1.25", expected: true},
+		{name: "unknown tag", image: image, reference: "nginx:1.26", expected: false},
+		{name: "index digest", image: image, reference: "docker.io/library/nginx@" + image.Digest, expected: true},
+		{name: "platform digest", image: image, reference: "nginx@" + image.manifests[0], expected: true},
+		{name: "plain digest", image: image, reference: image.Digest, expected: true},
+		{name: "other repository", image: image, reference: "library/redis:latest", expected: false},
+		{name: "repository without library", image: pushedImage, reference: "my-app:v1", expected: true},
+		{name: "invalid reference", image: image, reference: "NGINX::", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, imageMatches(tc.image, tc.reference), tc.expected)
+		})
+	}
+}
+
+func TestUnusedImages(t *testing.T) {
+	nginx := &registryImage{Repository: "library/nginx", Tags: []string{"1.25"}, Digest: "sha256:aaaa"}
+	oldNginx := &registryImage{Repository: "library/nginx", Tags: []string{"1.24"}, Digest: "sha256:bbbb"}
+	chart := &registryImage{Repository: "charts/my-chart", Tags: []string{"0.1.0"}, Digest: "sha256:cccc"}
+
+	pods := []corev1.Pod{
+		{
+			Spec:   corev1.PodSpec{Containers: []corev1.Container{{Image: "nginx:1.25"}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		{
+			Spec:   corev1.PodSpec{Containers: []corev1.Container{{Image: "nginx:1.24"}}},
+			Status: corev1.PodStatus{Phase: corev1.PodSucceeded},
+		},
+	}
+
+	unused := unusedImages([]*registryImage{nginx, oldNginx, chart}, imagesInUse(pods), nil)
+	assert.DeepEqual(t, digests(unused), []string{oldNginx.Digest, chart.Digest})
+
+	unused = unusedImages([]*registryImage{nginx, oldNginx, chart}, imagesInUse(pods), []string{"charts/*"})
+	assert.DeepEqual(t, digests(unused), []string{oldNginx.Digest})
+}
+
+func TestUnreferenced(t *testing.T) {
+	deleted := &registryImage{
+		Repository: "library/nginx",
+		Digest:     "sha256:index",
+		manifests:  []string{"sha256:amd64", "sha256:arm64"},
+		blobs:      map[string]int64{"sha256:config": 1, "sha256:shared": 10, "sha256:only": 100},
+	}
+	remaining := &registryImage{
+		Repository: "library/nginx",
+		Digest:     "sha256:other",
+		manifests:  []string{"sha256:arm64"},
+		blobs:      map[string]int64{"sha256:shared": 10},
+	}
+	otherRepository := &registryImage{
+		Repository: "library/redis",
+		Digest:     "sha256:redis",
+		blobs:      map[string]int64{"sha256:only": 100},
+	}
+
+	manifests, blobs := unreferenced([]*registryImage{deleted}, []*registryImage{deleted, remaining, otherRepository})
+	assert.DeepEqual(t, manifests, map[string][]string{"library/nginx": {"sha256:amd64"}})
+	assert.DeepEqual(t, blobs, map[string]map[string]int64{"library/nginx": {"sha256:config": 1, "sha256:only": 100}})
+}
+
+func digests(images []*registryImage) []string {
+	digests := []string{}
+	for _, image := range images {
+		digests = append(digests, image.Digest)
+	}
+
+	return digests
+}
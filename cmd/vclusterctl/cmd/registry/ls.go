@@ -0,0 +1,106 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type LsOptions struct {
+	*flags.GlobalFlags
+
+	Output string
+
+	Log log.Logger
+}
+
+func NewLsCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	o := &LsOptions{
+		GlobalFlags: globalFlags,
+
+		Log: log.GetInstance(),
+	}
+
+	cmd := &cobra.Command{
+		Use:     "ls [repository...]",
+		Aliases: []string{"list"},
+		Short:   "List the images stored in the vCluster registry",
+		Long: `#######################################################
+################# vcluster registry ls ##################
+#######################################################
+List the repositories, tags and sizes of the images
+stored in the vCluster registry.
+
+Example:
+vcluster registry ls
+vcluster registry ls library/nginx --output json
+#######################################################
+	`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run(cmd.Context(), args)
+		},
+	}
+
+	cmd.Flags().StringVar(&o.Output, "output", "table", "Choose the format of the output. [table|json]")
+
+	return cmd
+}
+
+func (o *LsOptions) Run(ctx context.Context, repositories []string) error {
+	if o.Output != "table" && o.Output != "json" {
+		return fmt.Errorf("unsupported output format %s, must be either table or json", o.Output)
+	}
+
+	client, _, err := newRegistryClient(ctx, o.GlobalFlags, o.Log)
+	if err != nil {
+		return err
+	}
+
+	images, err := client.listImages(ctx, repositories)
+	if err != nil {
+		return fmt.Errorf("failed to list images: %w", err)
+	}
+
+	if o.Output == "json" {
+		bytes, err := json.MarshalIndent(images, "", "    ")
+		if err != nil {
+			return fmt.Errorf("json marshal images: %w", err)
+		}
+
+		o.Log.WriteString(logrus.InfoLevel, string(bytes)+"\n")
+		return nil
+	}
+
+	header := []string{"REPOSITORY", "TAG", "DIGEST", "SIZE"}
+	values := [][]string{}
+	total := int64(0)
+	for _, image := range images {
+		values = append(values, []string{
+			image.Repository,
+			strings.Join(image.Tags, ", "),
+			shortDigest(image.Digest),
+			units.HumanSize(float64(image.Size)),
+		})
+		total += image.Size
+	}
+
+	table.PrintTable(o.Log, header, values)
+	o.Log.Infof("%d images using %s", len(images), units.HumanSize(float64(total)))
+	return nil
+}
+
+func shortDigest(digest string) string {
+	if len(digest) > len("sha256:")+12 {
+		return digest[:len("sha256:")+12]
+	}
+
+	return digest
+}
@@ -19,5 +19,8 @@ func NewRegistryCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
 	registryCmd.AddCommand(NewPushCmd(globalFlags))
 	registryCmd.AddCommand(NewPullCmd(globalFlags))
 	registryCmd.AddCommand(NewProxyCmd(globalFlags))
+	registryCmd.AddCommand(NewLsCmd(globalFlags))
+	registryCmd.AddCommand(NewRmCmd(globalFlags))
+	registryCmd.AddCommand(NewGcCmd(globalFlags))
 	return registryCmd
 }
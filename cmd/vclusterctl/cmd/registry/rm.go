@@ -0,0 +1,126 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+type RmOptions struct {
+	*flags.GlobalFlags
+
+	Log log.Logger
+}
+
+func NewRmCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	o := &RmOptions{
+		GlobalFlags: globalFlags,
+
+		Log: log.GetInstance(),
+	}
+
+	cmd := &cobra.Command{
+		Use:     "rm image...",
+		Aliases: []string{"delete"},
+		Short:   "Delete images from the vCluster registry",
+		Long: `#######################################################
+################# vcluster registry rm ##################
+#######################################################
+Delete images from the vCluster registry and unlink their
+blobs that are not used by any other image anymore. This
+command doesn't run the registry garbage collection and
+doesn't free the storage of the unlinked blobs.
+
+Images are deleted by digest, so all tags pointing to the
+same image are removed as well.
+
+Example:
+vcluster registry rm nginx:1.25
+vcluster registry rm library/nginx@sha256:...
+#######################################################
+	`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.Run(cmd.Context(), args)
+		},
+	}
+
+	return cmd
+}
+
+func (o *RmOptions) Run(ctx context.Context, references []string) error {
+	client, _, err := newRegistryClient(ctx, o.GlobalFlags, o.Log)
+	if err != nil {
+		return err
+	}
+
+	// only list the repositories of the given images
+	repositories := []string{}
+	for _, reference := range references {
+		ref, err := name.ParseReference(reference, name.WeakValidation)
+		if err != nil {
+			return fmt.Errorf("parse image %s: %w", reference, err)
+		}
+
+		candidates := []string{ref.Context().RepositoryStr()}
+		if strings.HasPrefix(candidates[0], "library/") {
+			candidates = append(candidates, strings.TrimPrefix(candidates[0], "library/"))
+		}
+		for _, candidate := range candidates {
+			if !slices.Contains(repositories, candidate) {
+				repositories = append(repositories, candidate)
+			}
+		}
+	}
+
+	images, err := client.listImages(ctx, repositories)
+	if err != nil {
+		return fmt.Errorf("failed to list images: %w", err)
+	}
+
+	// find the images to delete
+	deleted := []*registryImage{}
+	for _, reference := range references {
+		found := false
+		for _, image := range images {
+			if !imageMatches(image, reference) {
+				continue
+			}
+
+			found = true
+			if len(image.Tags) > 1 {
+				o.Log.Warnf("Image %s is also tagged as %s, these tags will be removed as well", reference, strings.Join(image.Tags, ", "))
+			}
+			if !containsImage(deleted, image) {
+				deleted = append(deleted, image)
+			}
+		}
+		if !found {
+			return fmt.Errorf("image %s not found in the vCluster registry", reference)
+		}
+	}
+
+	err = client.deleteImages(ctx, deleted, images, o.Log)
+	if err != nil {
+		return err
+	}
+
+	o.Log.Donef("Deleted %d images, the storage of their blobs is not freed until the registry garbage collection runs", len(deleted))
+	return nil
+}
+
+func containsImage(images []*registryImage, image *registryImage) bool {
+	for _, other := range images {
+		if other.Repository == image.Repository && other.Digest == image.Digest {
+			return true
+		}
+	}
+
+	return false
+}
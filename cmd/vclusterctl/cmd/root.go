@@ -9,11 +9,14 @@ import (
 
 	"github.com/loft-sh/log"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/certs"
+	cmdconfig "github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/config"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/connections"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/credits"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/debug"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/node"
 	cmdplatform "github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/platform"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/platform/set"
+	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/pvc"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/registry"
 	"github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/snapshot"
 	cmdtelemetry "github.com/loft-sh/vcluster/cmd/vclusterctl/cmd/telemetry"
@@ -129,9 +132,14 @@ func BuildRoot(log log.Logger) (*cobra.Command, *flags.GlobalFlags, error) {
 	rootCmd.AddCommand(NewPauseCmd(globalFlags))
 	rootCmd.AddCommand(NewResumeCmd(globalFlags))
 	rootCmd.AddCommand(NewDisconnectCmd(globalFlags))
+	rootCmd.AddCommand(connections.NewConnectionsCmd(globalFlags))
 	rootCmd.AddCommand(NewUpgradeCmd())
 	rootCmd.AddCommand(snapshot.NewSnapshot(globalFlags))
 	rootCmd.AddCommand(NewRestore(globalFlags))
+	rootCmd.AddCommand(NewMoveCmd(globalFlags))
+	rootCmd.AddCommand(NewCloneCmd(globalFlags))
+	rootCmd.AddCommand(NewMigrateBackingStoreCmd(globalFlags))
+	rootCmd.AddCommand(pvc.NewPVCCmd(globalFlags))
 	rootCmd.AddCommand(use.NewUseCmd(globalFlags))
 	rootCmd.AddCommand(debug.NewDebugCommand(globalFlags))
 	rootCmd.AddCommand(cmdtelemetry.NewTelemetryCmd(globalFlags))
@@ -142,6 +150,7 @@ func BuildRoot(log log.Logger) (*cobra.Command, *flags.GlobalFlags, error) {
 	rootCmd.AddCommand(node.NewNodeCmd(globalFlags))
 	rootCmd.AddCommand(registry.NewRegistryCmd(globalFlags))
 	rootCmd.AddCommand(certs.NewCertsCmd(globalFlags))
+	rootCmd.AddCommand(cmdconfig.NewConfigCmd(globalFlags))
 
 	// add platform commands
 	platformCmd, err := cmdplatform.NewPlatformCmd(globalFlags)
@@ -0,0 +1,58 @@
+package token
+
+import (
+	"context"
+	"os"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type ExecCmd struct {
+	*flags.GlobalFlags
+	cli.ExecCredentialOptions
+
+	Log log.Logger
+}
+
+func NewExecCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &ExecCmd{
+		GlobalFlags: globalFlags,
+		// stdout is reserved for the exec credential, so log to stderr only
+		Log: log.NewStdoutLogger(os.Stdin, os.Stderr, os.Stderr, logrus.InfoLevel),
+	}
+
+	description := `########################################################
+################## vcluster token exec ##################
+########################################################
+Kube config exec credential plugin that retrieves a short-lived
+service account token for a virtual cluster. Tokens are cached
+locally until shortly before they expire.
+
+This command is used by kube configs written via:
+vcluster connect test -n test --service-account admin --exec-credential
+#######################################################
+	`
+
+	execCmd := &cobra.Command{
+		Use:    "exec <vcluster-name>",
+		Short:  "Kube config exec credential plugin for short-lived service account tokens.",
+		Long:   description,
+		Args:   cobra.ExactArgs(1),
+		Hidden: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.Run(cobraCmd.Context(), args)
+		},
+	}
+
+	execCmd.Flags().StringVar(&cmd.ServiceAccount, "service-account", "", "The service account to create the token for. Can be used as namespace/name.")
+	execCmd.Flags().IntVar(&cmd.Expiration, "token-expiration", cli.DefaultExecCredentialExpiration, "The duration in seconds the created token will be valid for")
+	return execCmd
+}
+
+func (cmd *ExecCmd) Run(ctx context.Context, args []string) error {
+	return cli.ExecCredential(ctx, &cmd.ExecCredentialOptions, cmd.GlobalFlags, args[0], os.Stdout, cmd.Log)
+}
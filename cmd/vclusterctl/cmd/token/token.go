@@ -19,5 +19,6 @@ func NewTokenCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
 	tokenCmd.AddCommand(NewCreateCmd(globalFlags))
 	tokenCmd.AddCommand(NewListCmd(globalFlags))
 	tokenCmd.AddCommand(NewDeleteCmd(globalFlags))
+	tokenCmd.AddCommand(NewExecCmd(globalFlags))
 	return tokenCmd
 }
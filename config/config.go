@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
@@ -827,6 +828,12 @@ type Integrations struct {
 	// MetricsServer reuses the metrics server from the host cluster within the vCluster.
 	MetricsServer MetricsServer `json:"metricsServer,omitempty"`
 
+	// CustomMetrics reuses a custom metrics adapter (e.g. prometheus-adapter) from the host cluster within the vCluster.
+	CustomMetrics MetricsAdapter `json:"customMetrics,omitempty"`
+
+	// ExternalMetrics reuses an external metrics adapter (e.g. KEDA) from the host cluster within the vCluster.
+	ExternalMetrics MetricsAdapter `json:"externalMetrics,omitempty"`
+
 	// KubeVirt reuses a host kubevirt and makes certain CRDs from it available inside the vCluster
 	KubeVirt KubeVirt `json:"kubeVirt,omitempty"`
 
@@ -844,6 +851,10 @@ type Integrations struct {
 	// Istio syncs DestinationRules, Gateways and VirtualServices from virtual cluster to the host.
 	Istio Istio `json:"istio,omitempty"`
 
+	// PrometheusOperator reuses a host prometheus-operator and makes its CRDs from it available inside the vCluster.
+	// - ServiceMonitors, PodMonitors and PrometheusRules will be synced from the virtual cluster to the host cluster.
+	PrometheusOperator PrometheusOperator `json:"prometheusOperator,omitempty"`
+
 	// Netris integration helps configuring netris networking for vCluster.
 	Netris vclusterconfig.NetrisIntegration `json:"netris,omitempty"`
 
@@ -900,6 +911,34 @@ type IstioSyncToHost struct {
 	VirtualServices EnableSwitch `json:"virtualServices,omitempty"`
 }
 
+// PrometheusOperator reuses a host prometheus-operator and makes its CRDs from it available inside the vCluster
+type PrometheusOperator struct {
+	EnableSwitch
+
+	// EnforcedLabels are added to all ServiceMonitors, PodMonitors and PrometheusRules synced to the host cluster and can't be
+	// overwritten from within the vCluster. Use them in the serviceMonitorSelector, podMonitorSelector and ruleSelector of the
+	// host Prometheus to only pick up resources of permitted virtual clusters.
+	EnforcedLabels map[string]string `json:"enforcedLabels,omitempty"`
+
+	// Sync contains advanced configuration for syncing prometheus-operator resources.
+	Sync PrometheusOperatorSync `json:"sync,omitempty"`
+}
+
+type PrometheusOperatorSync struct {
+	ToHost PrometheusOperatorSyncToHost `json:"toHost,omitempty"`
+}
+
+type PrometheusOperatorSyncToHost struct {
+	// ServiceMonitors defines if service monitors should get synced from the virtual cluster to the host cluster.
+	ServiceMonitors EnableSwitch `json:"serviceMonitors,omitempty"`
+
+	// PodMonitors defines if pod monitors should get synced from the virtual cluster to the host cluster.
+	PodMonitors EnableSwitch `json:"podMonitors,omitempty"`
+
+	// PrometheusRules defines if prometheus rules should get synced from the virtual cluster to the host cluster.
+	PrometheusRules EnableSwitch `json:"prometheusRules,omitempty"`
+}
+
 // ExternalSecrets reuses a host external secret operator and makes certain CRDs from it available inside the vCluster
 type ExternalSecrets struct {
 	// Enabled defines whether the external secret integration is enabled or not
@@ -988,6 +1027,16 @@ type MetricsServer struct {
 	Pods bool `json:"pods,omitempty"`
 }
 
+// MetricsAdapter reuses a metrics adapter from the host cluster within the vCluster.
+type MetricsAdapter struct {
+	// Enabled signals the metrics adapter integration should be enabled.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// APIService holds information about where to find the metrics adapter service. Defaults to prometheus-adapter/monitoring
+	// for custom metrics and keda-operator-metrics-apiserver/keda for external metrics.
+	APIService APIService `json:"apiService,omitempty"`
+}
+
 // APIService holds configuration related to the api server
 type APIService struct {
 	// Service is a reference to the service for the API server.
@@ -1051,6 +1100,49 @@ func (c *Config) IsDockerRegistryDaemonEnabled() bool {
 	return c.Experimental.Docker.Enabled && c.Experimental.Docker.RegistryProxy.Enabled
 }
 
+// ContainerdRegistryMirrors returns the containerd registry mirrors for nodes joining with the given join configuration. If the
+// pull-through cache of the embedded registry should be used as mirror, every upstream without an explicitly configured mirror
+// is mirrored to the pull-through cache at the given vCluster endpoint.
+func (c *Config) ContainerdRegistryMirrors(joinConfig JoinConfiguration, endpoint string) map[string]ContainerdMirror {
+	mirrors := map[string]ContainerdMirror{}
+	for name, mirror := range joinConfig.Containerd.Registry.Mirrors {
+		mirrors[name] = mirror
+	}
+
+	registry := c.ControlPlane.Advanced.Registry
+	if !registry.Enabled || !registry.PullThrough.Enabled || !registry.PullThrough.ContainerdMirrors || endpoint == "" {
+		return mirrors
+	}
+
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	caCertPath := joinConfig.CACertPath
+	if caCertPath == "" {
+		caCertPath = "/etc/kubernetes/pki/ca.crt"
+	}
+	for _, upstream := range registry.PullThrough.Upstreams {
+		if _, ok := mirrors[upstream.Name]; ok {
+			continue
+		}
+
+		mirrors[upstream.Name] = ContainerdMirror{
+			Server: upstream.GetURL(),
+			Hosts: []ContainerdMirrorHost{
+				{
+					Server:       endpoint + "/v2/" + upstream.Name,
+					CACert:       []string{caCertPath},
+					Capabilities: []string{"pull", "resolve"},
+					OverridePath: true,
+				},
+			},
+		}
+	}
+
+	return mirrors
+}
+
 func (c *Config) IsVirtualSchedulerEnabled() bool {
 	return c.ControlPlane.Distro.K8S.Scheduler.Enabled || c.ControlPlane.Advanced.VirtualScheduler.Enabled
 }
@@ -1348,7 +1440,7 @@ type SyncToHost struct {
 	ConfigMaps SyncAllResource `json:"configMaps,omitempty"`
 
 	// Ingresses defines if ingresses created within the virtual cluster should get synced to the host cluster.
-	Ingresses EnableSwitchWithPatches `json:"ingresses,omitempty"`
+	Ingresses SyncToHostIngresses `json:"ingresses,omitempty"`
 
 	// GatewayAPI defines Gateway API resources created within the tenant cluster that should get synced to the control plane cluster.
 	// Setting enabled: true turns on Gateway and HTTPRoute sync, imports control plane cluster GatewayClasses so tenant Gateways can resolve them, and serves tenant ReferenceGrants for validation; TLSRoutes and BackendTLSPolicies must be enabled individually.
@@ -1366,6 +1458,10 @@ type SyncToHost struct {
 	// NetworkPolicies defines if network policies created within the virtual cluster should get synced to the host cluster.
 	NetworkPolicies EnableSwitchWithPatches `json:"networkPolicies,omitempty"`
 
+	// AdminNetworkPolicies defines if AdminNetworkPolicies and the BaselineAdminNetworkPolicy (policy.networking.k8s.io) created within the virtual cluster
+	// should get synced to the host cluster. Requires the AdminNetworkPolicy CRDs in the host cluster.
+	AdminNetworkPolicies SyncToHostAdminNetworkPolicies `json:"adminNetworkPolicies,omitempty"`
+
 	// PersistentVolumeClaims defines if persistent volume claims created within the virtual cluster should get synced to the host cluster.
 	PersistentVolumeClaims EnableSwitchWithPatches `json:"persistentVolumeClaims,omitempty"`
 
@@ -1404,6 +1500,27 @@ type SyncToHost struct {
 
 	// ResourceClaimTemplates defines if resourceClaimTemplates created within the virtual cluster should get synced to the host cluster.
 	ResourceClaimTemplates EnableSwitchWithPatches `json:"resourceClaimTemplates,omitempty"`
+
+	// Naming defines how the names of objects synced to the host cluster are generated.
+	Naming SyncToHostNaming `json:"naming,omitempty"`
+}
+
+type SyncToHostAdminNetworkPolicies struct {
+	// Enabled defines if this option should be enabled.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// MinPriority is the lowest host priority AdminNetworkPolicies of this virtual cluster get. Virtual priorities 0-1000
+	// are mapped into the range MinPriority-1000, so host policies with a priority below MinPriority always take precedence.
+	MinPriority int32 `json:"minPriority,omitempty"`
+}
+
+type SyncToHostNaming struct {
+	// Template is a Go template for the host names of namespaced objects, e.g. "{{.VCluster}}-{{.Namespace}}-{{.Name}}".
+	// It has to use .Name, .Namespace and .VCluster exactly once. Names are always suffixed with a hash of these fields, so
+	// different objects can't get the same name, and truncated to 63 characters. Objects that already exist in the host
+	// cluster keep their names when the template changes.
+	// If empty, names are generated as NAME-x-NAMESPACE-x-VCLUSTER. Not supported if namespaces are synced to the host cluster.
+	Template string `json:"template,omitempty"`
 }
 
 type EnableSwitchWithPatches struct {
@@ -1414,6 +1531,45 @@ type EnableSwitchWithPatches struct {
 	Patches []TranslatePatch `json:"patches,omitempty"`
 }
 
+type SyncToHostIngresses struct {
+	EnableSwitchWithPatches
+
+	// AnnotationTranslation rewrites references to services and secrets within ingress annotations to the host object names.
+	AnnotationTranslation IngressAnnotationTranslation `json:"annotationTranslation,omitempty"`
+}
+
+type IngressAnnotationTranslation struct {
+	// Presets enable the built-in rules for the annotations of an ingress controller. Supported presets are "nginx", "haproxy" and "traefik".
+	// The "nginx" preset also translates the service referenced by nginx.ingress.kubernetes.io/default-backend. Contour has no preset, as its ingress annotations don't reference other objects.
+	Presets []string `json:"presets,omitempty"`
+
+	// Rules are additional annotations to translate.
+	Rules []IngressAnnotationRule `json:"rules,omitempty"`
+}
+
+// IngressAnnotationRule translates the references to services or secrets within an ingress annotation
+type IngressAnnotationRule struct {
+	// Annotation is the key of the annotation to translate.
+	Annotation string `json:"annotation,omitempty" jsonschema:"required"`
+
+	// Type is the type of the annotation value. Either "service" or "secret" for a "name" or "namespace/name" reference,
+	// "traefikMiddleware" for a comma separated list of "<namespace>-<name>@kubernetescrd" references to middlewares in the
+	// ingress namespace, or "json" for a JSON value with references at the given paths.
+	Type string `json:"type,omitempty" jsonschema:"required"`
+
+	// Paths are the references within a JSON annotation value.
+	Paths []IngressAnnotationPath `json:"paths,omitempty"`
+}
+
+type IngressAnnotationPath struct {
+	// Path is the dot separated path to a reference within the JSON value, "[*]" selects all items of a list,
+	// e.g. forwardConfig.targetGroups[*].serviceName
+	Path string `json:"path,omitempty" jsonschema:"required"`
+
+	// Type is the type of the reference, either "service" or "secret".
+	Type string `json:"type,omitempty" jsonschema:"required"`
+}
+
 type GatewayAPIEnableSwitchWithPatches struct {
 	EnableSwitchWithPatches
 
@@ -1719,6 +1875,57 @@ type SyncAllResource struct {
 
 	// Patches patch the resource according to the provided specification.
 	Patches []TranslatePatch `json:"patches,omitempty"`
+
+	// Mappings sync specific objects to a fixed name in any host namespace instead of the vCluster namespace.
+	Mappings ToHostMappings `json:"mappings,omitempty"`
+}
+
+type ToHostMappings struct {
+	// ByName is a map of virtual-object-namespace/virtual-object-name: host-object-namespace/host-object-name, e.g.
+	// byName:
+	//   "team-a/registry-credentials": "shared-registry/team-a-credentials"
+	// Mapped objects are always synced and changes in the host cluster are synced back. Wildcards are not supported.
+	// Host objects that were not created by this vCluster are never overwritten. Objects that were synced before they
+	// were mapped keep their host name until they are recreated. Host objects are deleted together with the role the chart
+	// creates for their mapping once the mapping is removed.
+	ByName map[string]string `json:"byName,omitempty"`
+}
+
+// HostName returns the host namespace and name the given virtual object is mapped to
+func (m ToHostMappings) HostName(vNamespace, vName string) (string, string, bool) {
+	target, ok := m.ByName[vNamespace+"/"+vName]
+	if !ok {
+		return "", "", false
+	}
+
+	namespace, name, _ := strings.Cut(target, "/")
+	return namespace, name, true
+}
+
+// VirtualName returns the virtual namespace and name of the object that is mapped to the given host object
+func (m ToHostMappings) VirtualName(hNamespace, hName string) (string, string, bool) {
+	for source, target := range m.ByName {
+		if target == hNamespace+"/"+hName {
+			namespace, name, _ := strings.Cut(source, "/")
+			return namespace, name, true
+		}
+	}
+
+	return "", "", false
+}
+
+// HostNamespaces returns the host namespaces objects are mapped to
+func (m ToHostMappings) HostNamespaces() []string {
+	namespaces := []string{}
+	for _, target := range m.ByName {
+		namespace, _, _ := strings.Cut(target, "/")
+		if namespace != "" && !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+
+	slices.Sort(namespaces)
+	return namespaces
 }
 
 type SyncPods struct {
@@ -1905,6 +2112,11 @@ type NetworkProxyKubelets struct {
 	// ByIP will create a separate service in the host cluster for every node that will point to virtual cluster and will be used to
 	// route traffic.
 	ByIP bool `json:"byIP,omitempty"`
+
+	// SyntheticStats serves the kubelet /stats/summary and /metrics/resource endpoints of fake nodes from the host metrics API
+	// usage of the virtual cluster pods on the node, the other metrics endpoints return no metrics. Requires a metrics server
+	// in the host cluster. Not used if real nodes are synced.
+	SyntheticStats bool `json:"syntheticStats,omitempty"`
 }
 
 type Plugin struct {
@@ -2287,6 +2499,40 @@ type Database struct {
 
 	// External defines that an external database should be used as the backend for the virtual cluster
 	External ExternalDatabaseKine `json:"external,omitempty"`
+
+	// Maintenance defines the health monitoring and compaction of the embedded database.
+	Maintenance DatabaseMaintenance `json:"maintenance,omitempty"`
+}
+
+type DatabaseMaintenance struct {
+	// CompactInterval is the interval in which kine compacts the embedded database, e.g. 5m. Set to 0s to disable the
+	// compaction.
+	CompactInterval string `json:"compactInterval,omitempty"`
+
+	// RetainRevisions is the number of latest revisions kine keeps when compacting the embedded database.
+	RetainRevisions int64 `json:"retainRevisions,omitempty"`
+
+	// VolumeUsageWarningPercentage is the usage of the data volume in percent above which a warning event is emitted
+	// on the vCluster pod. Set to 0 to disable the warning.
+	VolumeUsageWarningPercentage int `json:"volumeUsageWarningPercentage,omitempty"`
+
+	// Vacuum defines the maintenance window in which the embedded database is vacuumed to return its unused pages to the
+	// file system.
+	Vacuum DatabaseVacuum `json:"vacuum,omitempty"`
+}
+
+type DatabaseVacuum struct {
+	// Enabled defines if the embedded database should be vacuumed within the maintenance window. Writes to the database
+	// are blocked while it is vacuumed.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Schedule specifies when the maintenance window begins, following
+	// the upstream cronjob syntax. Timezones are not supported.
+	Schedule string `json:"schedule,omitempty"`
+
+	// Duration determines how long the maintenance window is open since each Schedule hit. The vacuum is only started
+	// within the window.
+	Duration string `json:"duration,omitempty"`
 }
 
 type ExternalDatabaseKine struct {
@@ -2495,6 +2741,53 @@ type CoreDNS struct {
 
 	// PriorityClassName specifies the priority class name for the CoreDNS pods.
 	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// StubDomains forward queries for the given domains to dedicated upstream DNS servers, e.g. to resolve on-prem zones.
+	StubDomains []CoreDNSStubDomain `json:"stubDomains,omitempty"`
+
+	// Forward configures the upstream DNS servers queries outside the cluster domain and the stub domains are forwarded to.
+	// Defaults to the nameservers of /etc/resolv.conf.
+	Forward CoreDNSForward `json:"forward,omitempty"`
+
+	// Hosts are static host entries that CoreDNS resolves in addition to the node hosts.
+	Hosts []CoreDNSHost `json:"hosts,omitempty"`
+
+	// Rewrites rewrite the names of queries before they are resolved.
+	Rewrites []CoreDNSRewrite `json:"rewrites,omitempty"`
+}
+
+type CoreDNSStubDomain struct {
+	// Domain is the DNS zone, e.g. corp.internal, whose queries are forwarded to Upstreams.
+	Domain string `json:"domain,omitempty"`
+
+	CoreDNSForward `json:",inline"`
+}
+
+type CoreDNSForward struct {
+	// Upstreams are the DNS servers to forward to, e.g. 10.0.0.10, 10.0.0.10:5353 or tls://10.0.0.10.
+	Upstreams []string `json:"upstreams,omitempty"`
+
+	// Policy is the policy used to select an upstream, either random, round_robin or sequential. Defaults to random.
+	Policy string `json:"policy,omitempty"`
+}
+
+type CoreDNSHost struct {
+	// IP is the address the hostnames resolve to.
+	IP string `json:"ip,omitempty"`
+
+	// Hostnames are the fully qualified names that resolve to IP.
+	Hostnames []string `json:"hostnames,omitempty"`
+}
+
+type CoreDNSRewrite struct {
+	// Match defines how From is matched against the queried name, either exact, prefix, suffix, substring or regex. Defaults to exact.
+	Match string `json:"match,omitempty"`
+
+	// From is the name, name part or regular expression to rewrite.
+	From string `json:"from,omitempty"`
+
+	// To is the replacement. Regex rewrites can reference capture groups, e.g. {1}.
+	To string `json:"to,omitempty"`
 }
 
 func (c CoreDNS) JSONSchemaExtend(base *jsonschema.Schema) {
@@ -2545,6 +2838,36 @@ type ControlPlaneProxy struct {
 
 	// ExtraSANs are extra hostnames to sign the vCluster proxy certificate for.
 	ExtraSANs []string `json:"extraSANs,omitempty"`
+
+	// OIDC defines an OpenID Connect provider the vCluster proxy should authenticate bearer tokens against.
+	OIDC ControlPlaneProxyOIDC `json:"oidc,omitempty"`
+}
+
+type ControlPlaneProxyOIDC struct {
+	// Enabled defines if the vCluster proxy should authenticate OIDC id tokens.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// IssuerURL is the URL of the OpenID issuer, only https is accepted. The discovery document is expected under /.well-known/openid-configuration.
+	IssuerURL string `json:"issuerURL,omitempty"`
+
+	// ClientID is the client ID that all tokens must be issued for.
+	ClientID string `json:"clientID,omitempty"`
+
+	// UsernameClaim is the JWT claim to use as the user name. Defaults to sub.
+	UsernameClaim string `json:"usernameClaim,omitempty"`
+
+	// UsernamePrefix is prepended to the username claim. If empty, claims other than email are prefixed with the issuer URL followed by #.
+	// Use - to disable prefixing.
+	UsernamePrefix string `json:"usernamePrefix,omitempty"`
+
+	// GroupsClaim is the JWT claim to use as the user's groups. The claim can be a string or an array of strings.
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+
+	// GroupsPrefix is prepended to all group names.
+	GroupsPrefix string `json:"groupsPrefix,omitempty"`
+
+	// CA is a PEM encoded certificate bundle used to verify the issuer. If empty, the system roots are used.
+	CA string `json:"ca,omitempty"`
 }
 
 type ControlPlaneService struct {
@@ -2630,6 +2953,22 @@ type ControlPlaneAdvanced struct {
 	// PodDisruptionBudget limits how many pods of an application can be voluntarily disrupted at once
 	// to ensure availability during maintenance or scaling operations.
 	PodDisruptionBudget PodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+
+	// Tracing configures OpenTelemetry tracing of proxied requests, admission webhooks, plugin calls and syncer reconciles.
+	Tracing ControlPlaneTracing `json:"tracing,omitempty"`
+}
+
+type ControlPlaneTracing struct {
+	// Enabled defines if vCluster should export traces to an OTLP collector.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Endpoint is the OTLP gRPC endpoint of the collector, e.g. otel-collector.monitoring:4317. The connection is not encrypted.
+	// Defaults to localhost:4317.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// SamplingRatePerMillion is the number of requests and reconciles to trace per million. Requests that carry a
+	// sampled trace context are always traced.
+	SamplingRatePerMillion int32 `json:"samplingRatePerMillion,omitempty"`
 }
 
 type Registry struct {
@@ -2641,6 +2980,52 @@ type Registry struct {
 
 	// Config is the regular docker registry config. See https://distribution.github.io/distribution/about/configuration/ for more details.
 	Config interface{} `json:"config,omitempty"`
+
+	// PullThrough configures the embedded registry as a pull-through cache for upstream registries.
+	PullThrough RegistryPullThrough `json:"pullThrough,omitempty"`
+}
+
+type RegistryPullThrough struct {
+	// Enabled defines if the embedded registry should proxy and cache the configured upstream registries.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Upstreams are the registries to proxy. Images of an upstream are pulled through the vCluster registry by prefixing the
+	// repository with the upstream name, e.g. my-vcluster.example.com/docker.io/library/nginx:latest.
+	Upstreams []RegistryUpstream `json:"upstreams,omitempty"`
+
+	// MaxCacheSize is the maximum size of the cache, e.g. 20Gi. If the cache grows beyond this size, the least recently used blobs are removed. Empty means unlimited.
+	MaxCacheSize string `json:"maxCacheSize,omitempty"`
+
+	// TagTTL is how long a resolved tag is served from the cache before it is resolved against the upstream again, e.g. 5m. Cached tags are also served if the upstream is unavailable.
+	TagTTL string `json:"tagTTL,omitempty"`
+
+	// ContainerdMirrors defines if private nodes should be configured to use the pull-through cache as containerd mirror for the upstreams.
+	// Mirrors configured in privateNodes.joinNode.containerd.registry.mirrors take precedence. Requires controlPlane.endpoint and
+	// controlPlane.advanced.registry.anonymousPull, as containerd pulls from the mirror without credentials.
+	ContainerdMirrors bool `json:"containerdMirrors,omitempty"`
+}
+
+type RegistryUpstream struct {
+	// Name is the name of the upstream and the path prefix it is served under, e.g. docker.io or ghcr.io.
+	Name string `json:"name,omitempty"`
+
+	// URL is the url of the upstream registry, e.g. https://registry-1.docker.io. Defaults to the name with https as scheme.
+	URL string `json:"url,omitempty"`
+
+	// CredentialsSecret is the name of a secret in the vCluster namespace on the host cluster that holds the credentials for the upstream.
+	// The secret either needs a username and password key or needs to be of type kubernetes.io/dockerconfigjson.
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// GetURL returns the url of the upstream registry
+func (r RegistryUpstream) GetURL() string {
+	if r.URL != "" {
+		return strings.TrimSuffix(r.URL, "/")
+	} else if r.Name == "docker.io" {
+		return "https://registry-1.docker.io"
+	}
+
+	return "https://" + r.Name
 }
 
 type ControlPlaneHeadlessService struct {
@@ -2950,6 +3335,9 @@ type Policies struct {
 	// LimitRange specifies limit range options.
 	LimitRange LimitRange `json:"limitRange,omitempty"`
 
+	// Hostnames restricts the hostnames that synced Ingresses, Gateways, Gateway API routes, ExternalDNS annotations and ingress-nginx server aliases can claim.
+	Hostnames HostnamePolicy `json:"hostnames,omitempty"`
+
 	// CentralAdmission defines what validating or mutating webhooks should be enforced within the virtual cluster.
 	CentralAdmission CentralAdmission `json:"centralAdmission,omitempty" product:"pro"`
 }
@@ -2958,6 +3346,20 @@ func (p Policies) JSONSchemaExtend(base *jsonschema.Schema) {
 	addProToJSONSchema(base, reflect.TypeOf(p))
 }
 
+type HostnamePolicy struct {
+	// Enabled defines if the hostname policy should be enforced when syncing objects to the host cluster.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// AllowedDomains are the domains hostnames must be equal to or a subdomain of, e.g. apps.example.com
+	AllowedDomains []string `json:"allowedDomains,omitempty"`
+
+	// Prefix the first label below the allowed domain has to start with, e.g. team-a- allows team-a-shop.apps.example.com
+	Prefix string `json:"prefix,omitempty"`
+
+	// Suffix the first label below the allowed domain has to end with, e.g. -team-a allows shop-team-a.apps.example.com
+	Suffix string `json:"suffix,omitempty"`
+}
+
 type ResourceQuota struct {
 	// Enabled defines if the resource quota should be enabled. "auto" means that if limitRange is enabled,
 	// the resourceQuota will be enabled as well.
@@ -3358,6 +3760,30 @@ type Telemetry struct {
 	MachineID          string `json:"machineID,omitempty"`
 	PlatformUserID     string `json:"platformUserID,omitempty"`
 	PlatformInstanceID string `json:"platformInstanceID,omitempty"`
+
+	// Sink writes the control plane telemetry events to a local file, stdout or an OTLP collector instead of the hosted backend.
+	// If a sink type is set, no events are sent to the hosted backend, even if enabled is true.
+	Sink TelemetrySink `json:"sink,omitempty"`
+}
+
+type TelemetrySinkType string
+
+const (
+	TelemetrySinkTypeFile   TelemetrySinkType = "file"
+	TelemetrySinkTypeStdout TelemetrySinkType = "stdout"
+	TelemetrySinkTypeOTLP   TelemetrySinkType = "otlp"
+)
+
+type TelemetrySink struct {
+	// Type is the sink the events are written to. Can be file, stdout or otlp. If empty, events are sent to the hosted backend.
+	Type TelemetrySinkType `json:"type,omitempty"`
+
+	// Path is the file the events are appended to as JSON lines if type is file.
+	Path string `json:"path,omitempty"`
+
+	// Endpoint is the OTLP gRPC endpoint the events are exported to as spans if type is otlp, e.g. otel-collector.monitoring:4317.
+	// The connection is not encrypted. Defaults to localhost:4317.
+	Endpoint string `json:"endpoint,omitempty"`
 }
 
 type Experimental struct {
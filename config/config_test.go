@@ -489,3 +489,44 @@ func TestImage_String(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_ContainerdRegistryMirrors(t *testing.T) {
+	c := &Config{}
+	c.ControlPlane.Advanced.Registry.Enabled = true
+	c.ControlPlane.Advanced.Registry.PullThrough = RegistryPullThrough{
+		Enabled:           true,
+		ContainerdMirrors: true,
+		Upstreams: []RegistryUpstream{
+			{Name: "docker.io"},
+			{Name: "ghcr.io"},
+			{Name: "mirror.example.com", URL: "http://mirror.example.com:5000/"},
+		},
+	}
+	joinConfig := JoinConfiguration{
+		Containerd: ContainerdJoin{
+			Registry: ContainerdRegistry{
+				Mirrors: map[string]ContainerdMirror{
+					"ghcr.io": {Server: "https://ghcr.io", Hosts: []ContainerdMirrorHost{{Server: "https://ghcr-mirror.example.com"}}},
+				},
+			},
+		},
+	}
+
+	mirrors := c.ContainerdRegistryMirrors(joinConfig, "my-vcluster.example.com")
+	assert.Equal(t, len(mirrors), 3)
+	assert.DeepEqual(t, mirrors["docker.io"], ContainerdMirror{
+		Server: "https://registry-1.docker.io",
+		Hosts: []ContainerdMirrorHost{{
+			Server:       "https://my-vcluster.example.com/v2/docker.io",
+			CACert:       []string{"/etc/kubernetes/pki/ca.crt"},
+			Capabilities: []string{"pull", "resolve"},
+			OverridePath: true,
+		}},
+	})
+	assert.Equal(t, mirrors["ghcr.io"].Hosts[0].Server, "https://ghcr-mirror.example.com")
+	assert.Equal(t, mirrors["mirror.example.com"].Server, "http://mirror.example.com:5000")
+
+	// pull-through cache not used as mirror
+	c.ControlPlane.Advanced.Registry.PullThrough.ContainerdMirrors = false
+	assert.Equal(t, len(c.ContainerdRegistryMirrors(joinConfig, "my-vcluster.example.com")), 1)
+}
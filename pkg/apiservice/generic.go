@@ -293,3 +293,17 @@ func DeregisterAPIService(ctx *synccontext.ControllerContext, groupVersion schem
 
 	return nil
 }
+
+// DeregisterAPIServiceForService deregisters the api service for the group version only if it is served by the given
+// service, so api services installed by users within the vCluster are kept.
+func DeregisterAPIServiceForService(ctx *synccontext.ControllerContext, serviceName string, groupVersion schema.GroupVersion) error {
+	apiService := &apiregistrationv1.APIService{}
+	err := ctx.VirtualManager.GetClient().Get(ctx, types.NamespacedName{Name: groupVersion.Version + "." + groupVersion.Group}, apiService)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	} else if apiService.Spec.Service == nil || apiService.Spec.Service.Name != serviceName || apiService.Spec.Service.Namespace != "kube-system" {
+		return nil
+	}
+
+	return applyOperation(ctx, deleteOperation(ctx, groupVersion))
+}
@@ -0,0 +1,315 @@
+package oidcauthenticator
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/authentication/bearertoken"
+	"golang.org/x/sync/singleflight"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/klog/v2"
+)
+
+var (
+	// keysRefreshInterval is how long fetched signing keys are cached
+	keysRefreshInterval = 10 * time.Minute
+
+	// keysMinRefreshInterval rate limits refreshes triggered by unknown key ids
+	keysMinRefreshInterval = 10 * time.Second
+
+	// clockSkew is the leeway allowed when validating exp and nbf
+	clockSkew = time.Minute
+
+	supportedAlgorithms = []jose.SignatureAlgorithm{
+		jose.RS256, jose.RS384, jose.RS512,
+		jose.ES256, jose.ES384, jose.ES512,
+		jose.PS256, jose.PS384, jose.PS512,
+	}
+)
+
+func New(options config.ControlPlaneProxyOIDC) (authenticator.Request, error) {
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	if options.CA != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(options.CA)) {
+			return nil, errors.New("could not parse oidc ca")
+		}
+
+		httpClient.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		}
+	}
+
+	return bearertoken.New(&oidcAuthenticator{
+		options:    options,
+		issuerURL:  strings.TrimSuffix(options.IssuerURL, "/"),
+		httpClient: httpClient,
+	}), nil
+}
+
+type oidcAuthenticator struct {
+	options    config.ControlPlaneProxyOIDC
+	issuerURL  string
+	httpClient *http.Client
+
+	m           sync.Mutex
+	keys        *jose.JSONWebKeySet
+	keysFetched time.Time
+
+	// fetches makes sure concurrent requests with unknown key ids fetch the keys only once
+	fetches singleflight.Group
+}
+
+type discoveryDocument struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+func (o *oidcAuthenticator) AuthenticateToken(ctx context.Context, token string) (*authenticator.Response, bool, error) {
+	signature, err := jose.ParseSigned(token, supportedAlgorithms)
+	if err != nil || len(signature.Signatures) != 1 {
+		// not a jwt we understand, let the other authenticators handle it
+		return nil, false, nil
+	}
+
+	// check if the token was issued by our issuer before doing any expensive verification
+	claims := map[string]interface{}{}
+	err = json.Unmarshal(signature.UnsafePayloadWithoutVerification(), &claims)
+	if err != nil {
+		return nil, false, nil
+	}
+	issuer, _ := claims["iss"].(string)
+	if strings.TrimSuffix(issuer, "/") != o.issuerURL {
+		return nil, false, nil
+	}
+
+	// verify the signature
+	payload, err := o.verify(ctx, signature)
+	if err != nil {
+		return nil, false, fmt.Errorf("oidc: verify token: %w", err)
+	}
+	claims = map[string]interface{}{}
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return nil, false, fmt.Errorf("oidc: parse claims: %w", err)
+	}
+
+	// validate the standard claims
+	err = o.validateClaims(claims, time.Now())
+	if err != nil {
+		return nil, false, fmt.Errorf("oidc: %w", err)
+	}
+
+	userInfo, err := o.userInfo(claims)
+	if err != nil {
+		return nil, false, fmt.Errorf("oidc: %w", err)
+	}
+
+	return &authenticator.Response{User: userInfo}, true, nil
+}
+
+func (o *oidcAuthenticator) validateClaims(claims map[string]interface{}, now time.Time) error {
+	if !audienceContains(claims["aud"], o.options.ClientID) {
+		return fmt.Errorf("token audience does not contain client id %s", o.options.ClientID)
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return errors.New("token is missing exp claim")
+	} else if now.Add(-clockSkew).After(time.Unix(int64(exp), 0)) {
+		return errors.New("token is expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(clockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return errors.New("token is not valid yet")
+	}
+
+	return nil
+}
+
+func (o *oidcAuthenticator) userInfo(claims map[string]interface{}) (*user.DefaultInfo, error) {
+	usernameClaim := o.options.UsernameClaim
+	if usernameClaim == "" {
+		usernameClaim = "sub"
+	}
+
+	username, ok := claims[usernameClaim].(string)
+	if !ok || username == "" {
+		return nil, fmt.Errorf("token is missing claim %s", usernameClaim)
+	}
+	if usernameClaim == "email" {
+		if verified, ok := claims["email_verified"]; ok {
+			if verified, ok := verified.(bool); !ok || !verified {
+				return nil, errors.New("email not verified")
+			}
+		}
+	}
+
+	switch {
+	case o.options.UsernamePrefix == "-":
+	case o.options.UsernamePrefix != "":
+		username = o.options.UsernamePrefix + username
+	case usernameClaim != "email":
+		username = o.issuerURL + "#" + username
+	}
+
+	userInfo := &user.DefaultInfo{Name: username}
+	if sub, ok := claims["sub"].(string); ok {
+		userInfo.UID = sub
+	}
+	if o.options.GroupsClaim != "" {
+		switch groups := claims[o.options.GroupsClaim].(type) {
+		case nil:
+		case string:
+			userInfo.Groups = []string{o.options.GroupsPrefix + groups}
+		case []interface{}:
+			for _, group := range groups {
+				groupName, ok := group.(string)
+				if !ok {
+					return nil, fmt.Errorf("claim %s contains a non string value", o.options.GroupsClaim)
+				}
+
+				userInfo.Groups = append(userInfo.Groups, o.options.GroupsPrefix+groupName)
+			}
+		default:
+			return nil, fmt.Errorf("claim %s is neither a string nor an array of strings", o.options.GroupsClaim)
+		}
+	}
+
+	return userInfo, nil
+}
+
+func (o *oidcAuthenticator) verify(ctx context.Context, signature *jose.JSONWebSignature) ([]byte, error) {
+	keyID := signature.Signatures[0].Header.KeyID
+	keys, err := o.getKeys(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		payload, err := signature.Verify(key)
+		if err == nil {
+			return payload, nil
+		}
+	}
+
+	return nil, errors.New("failed to verify signature: no matching key found")
+}
+
+// getKeys returns the issuer's keys with the given key id. If the key id is unknown, the keys are refreshed
+func (o *oidcAuthenticator) getKeys(ctx context.Context, keyID string) ([]jose.JSONWebKey, error) {
+	o.m.Lock()
+	cachedKeys, keysFetched := o.keys, o.keysFetched
+	o.m.Unlock()
+
+	now := time.Now()
+	if cachedKeys != nil && now.Sub(keysFetched) < keysRefreshInterval {
+		keys := filterKeys(cachedKeys, keyID)
+		if len(keys) > 0 || now.Sub(keysFetched) < keysMinRefreshInterval {
+			return keys, nil
+		}
+	}
+
+	// the keys are fetched without holding the lock, so a slow issuer doesn't block requests with known key ids. The
+	// fetch is shared, so it must not be cancelled with the request that started it, the http client has a timeout.
+	keySet, err, _ := o.fetches.Do("keys", func() (interface{}, error) {
+		keySet, err := o.fetchKeys(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		o.m.Lock()
+		defer o.m.Unlock()
+		o.keys = keySet
+		o.keysFetched = time.Now()
+		return keySet, nil
+	})
+	if err != nil {
+		// fallback to the old keys if we have some
+		if cachedKeys != nil {
+			klog.FromContext(ctx).Error(err, "error refreshing oidc keys")
+			return filterKeys(cachedKeys, keyID), nil
+		}
+
+		return nil, err
+	}
+
+	return filterKeys(keySet.(*jose.JSONWebKeySet), keyID), nil
+}
+
+func (o *oidcAuthenticator) fetchKeys(ctx context.Context) (*jose.JSONWebKeySet, error) {
+	discovery := &discoveryDocument{}
+	err := o.getJSON(ctx, o.issuerURL+"/.well-known/openid-configuration", discovery)
+	if err != nil {
+		return nil, fmt.Errorf("get discovery document: %w", err)
+	} else if strings.TrimSuffix(discovery.Issuer, "/") != o.issuerURL {
+		return nil, fmt.Errorf("discovery document issuer %s does not match issuer %s", discovery.Issuer, o.issuerURL)
+	} else if discovery.JWKSURI == "" {
+		return nil, errors.New("discovery document is missing jwks_uri")
+	}
+
+	keySet := &jose.JSONWebKeySet{}
+	err = o.getJSON(ctx, discovery.JWKSURI, keySet)
+	if err != nil {
+		return nil, fmt.Errorf("get keys: %w", err)
+	}
+
+	return keySet, nil
+}
+
+func (o *oidcAuthenticator) getJSON(ctx context.Context, url string, into interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	} else if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	return json.Unmarshal(body, into)
+}
+
+func filterKeys(keySet *jose.JSONWebKeySet, keyID string) []jose.JSONWebKey {
+	if keyID == "" {
+		return keySet.Keys
+	}
+
+	return keySet.Key(keyID)
+}
+
+func audienceContains(audience interface{}, clientID string) bool {
+	switch audience := audience.(type) {
+	case string:
+		return audience == clientID
+	case []interface{}:
+		for _, aud := range audience {
+			if aud == clientID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,246 @@
+package oidcauthenticator
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/loft-sh/vcluster/config"
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+)
+
+type mockIssuer struct {
+	server  *httptest.Server
+	key     *rsa.PrivateKey
+	keyID   string
+	fetches atomic.Int32
+
+	// block delays serving the keys until it is closed, if set
+	block chan struct{}
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NilError(t, err)
+
+	issuer := &mockIssuer{key: key, keyID: "key-1"}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   issuer.server.URL,
+			"jwks_uri": issuer.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		issuer.fetches.Add(1)
+		if issuer.block != nil {
+			<-issuer.block
+		}
+		_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{
+			Key:       &issuer.key.PublicKey,
+			KeyID:     issuer.keyID,
+			Algorithm: string(jose.RS256),
+			Use:       "sig",
+		}}})
+	})
+	issuer.server = httptest.NewTLSServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (m *mockIssuer) ca() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: m.server.Certificate().Raw}))
+}
+
+func (m *mockIssuer) token(t *testing.T, claims map[string]interface{}) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: m.key}, (&jose.SignerOptions{}).WithHeader("kid", m.keyID))
+	assert.NilError(t, err)
+
+	payload, err := json.Marshal(claims)
+	assert.NilError(t, err)
+
+	signature, err := signer.Sign(payload)
+	assert.NilError(t, err)
+
+	token, err := signature.CompactSerialize()
+	assert.NilError(t, err)
+	return token
+}
+
+func authenticate(t *testing.T, auth authenticator.Request, token string) (*authenticator.Response, bool, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "/", nil)
+	assert.NilError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return auth.AuthenticateRequest(req)
+}
+
+func TestAuthenticate(t *testing.T) {
+	issuer := newMockIssuer(t)
+	auth, err := New(config.ControlPlaneProxyOIDC{
+		Enabled:      true,
+		IssuerURL:    issuer.server.URL,
+		ClientID:     "vcluster",
+		GroupsClaim:  "groups",
+		GroupsPrefix: "oidc:",
+		CA:           issuer.ca(),
+	})
+	assert.NilError(t, err)
+
+	validClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":    issuer.server.URL,
+			"aud":    []string{"other", "vcluster"},
+			"sub":    "user-1",
+			"exp":    time.Now().Add(time.Hour).Unix(),
+			"groups": []string{"admins", "devs"},
+		}
+	}
+
+	// valid token
+	response, ok, err := authenticate(t, auth, issuer.token(t, validClaims()))
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+	assert.Equal(t, response.User.GetName(), issuer.server.URL+"#user-1")
+	assert.Equal(t, response.User.GetUID(), "user-1")
+	assert.DeepEqual(t, response.User.GetGroups(), []string{"oidc:admins", "oidc:devs"})
+
+	// keys are cached
+	_, ok, err = authenticate(t, auth, issuer.token(t, validClaims()))
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+	assert.Equal(t, issuer.fetches.Load(), int32(1))
+
+	// token of another issuer is left to the other authenticators
+	claims := validClaims()
+	claims["iss"] = "https://other.example.com"
+	_, ok, err = authenticate(t, auth, issuer.token(t, claims))
+	assert.NilError(t, err)
+	assert.Assert(t, !ok)
+
+	// not a jwt at all
+	_, ok, err = authenticate(t, auth, "not-a-jwt")
+	assert.NilError(t, err)
+	assert.Assert(t, !ok)
+
+	// wrong audience
+	claims = validClaims()
+	claims["aud"] = "other"
+	_, ok, err = authenticate(t, auth, issuer.token(t, claims))
+	assert.ErrorContains(t, err, "token audience does not contain client id vcluster")
+	assert.Assert(t, !ok)
+
+	// expired
+	claims = validClaims()
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+	_, ok, err = authenticate(t, auth, issuer.token(t, claims))
+	assert.ErrorContains(t, err, "token is expired")
+	assert.Assert(t, !ok)
+
+	// signed by an unknown key
+	otherIssuer := newMockIssuer(t)
+	_, ok, err = authenticate(t, auth, otherIssuer.token(t, validClaims()))
+	assert.ErrorContains(t, err, "no matching key found")
+	assert.Assert(t, !ok)
+}
+
+func TestUserInfo(t *testing.T) {
+	testCases := []struct {
+		name         string
+		options      config.ControlPlaneProxyOIDC
+		claims       map[string]interface{}
+		expectedName string
+		expectedErr  string
+	}{
+		{
+			name:         "email claim is not prefixed",
+			options:      config.ControlPlaneProxyOIDC{UsernameClaim: "email"},
+			claims:       map[string]interface{}{"email": "jane@example.com", "email_verified": true},
+			expectedName: "jane@example.com",
+		},
+		{
+			name:        "unverified email",
+			options:     config.ControlPlaneProxyOIDC{UsernameClaim: "email"},
+			claims:      map[string]interface{}{"email": "jane@example.com", "email_verified": false},
+			expectedErr: "email not verified",
+		},
+		{
+			name:         "custom prefix",
+			options:      config.ControlPlaneProxyOIDC{UsernamePrefix: "oidc:"},
+			claims:       map[string]interface{}{"sub": "jane"},
+			expectedName: "oidc:jane",
+		},
+		{
+			name:         "disabled prefix",
+			options:      config.ControlPlaneProxyOIDC{UsernamePrefix: "-"},
+			claims:       map[string]interface{}{"sub": "jane"},
+			expectedName: "jane",
+		},
+		{
+			name:        "missing claim",
+			options:     config.ControlPlaneProxyOIDC{UsernameClaim: "preferred_username"},
+			claims:      map[string]interface{}{"sub": "jane"},
+			expectedErr: "token is missing claim preferred_username",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &oidcAuthenticator{options: tc.options, issuerURL: "https://issuer.example.com"}
+			userInfo, err := o.userInfo(tc.claims)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, userInfo.Name, tc.expectedName)
+		})
+	}
+}
+
+func TestGetKeysRefreshDoesNotBlock(t *testing.T) {
+	issuer := newMockIssuer(t)
+	o := &oidcAuthenticator{issuerURL: issuer.server.URL, httpClient: issuer.server.Client()}
+
+	keys, err := o.getKeys(context.Background(), issuer.keyID)
+	assert.NilError(t, err)
+	assert.Equal(t, len(keys), 1)
+
+	// unknown key ids refresh the keys once the min refresh interval passed
+	issuer.block = make(chan struct{})
+	o.m.Lock()
+	o.keysFetched = time.Now().Add(-keysMinRefreshInterval)
+	o.m.Unlock()
+
+	refreshed := make(chan []jose.JSONWebKey, 2)
+	for range 2 {
+		go func() {
+			keys, _ := o.getKeys(context.Background(), "unknown")
+			refreshed <- keys
+		}()
+	}
+	assert.NilError(t, wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+		return issuer.fetches.Load() == 2, nil
+	}))
+
+	// known key ids are served from the cache while the refresh is in progress
+	keys, err = o.getKeys(context.Background(), issuer.keyID)
+	assert.NilError(t, err)
+	assert.Equal(t, len(keys), 1)
+
+	close(issuer.block)
+	for range 2 {
+		assert.Equal(t, len(<-refreshed), 0)
+	}
+	assert.Equal(t, issuer.fetches.Load(), int32(2))
+}
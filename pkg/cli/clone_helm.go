@@ -0,0 +1,365 @@
+package cli
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	snapshotapi "github.com/loft-sh/api/v4/pkg/snapshot"
+	"github.com/loft-sh/log"
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"github.com/loft-sh/vcluster/pkg/platform/random"
+	"github.com/loft-sh/vcluster/pkg/snapshot/pod"
+	"github.com/loft-sh/vcluster/pkg/upgrade"
+	"github.com/loft-sh/vcluster/pkg/util/podhelper"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// cloneSourceSnapshotDir is the temporary directory of the source syncer container the clone snapshot is written to,
+	// so the snapshot doesn't take space on the data volume of the source
+	cloneSourceSnapshotDir = "/tmp"
+	// cloneTargetSnapshotDir is the data volume mount of the clone, the restore pod can only read snapshots from there
+	cloneTargetSnapshotDir = "/data"
+)
+
+// CloneOptions holds the clone cmd options
+type CloneOptions struct {
+	Driver string
+
+	ToNamespace string
+	ToContext   string
+
+	// SnapshotURL is the optional snapshot storage the clone snapshot is written to and restored from, instead of
+	// copying it through the CLI
+	SnapshotURL string
+
+	ScrubSecrets       bool
+	ScaleDownWorkloads bool
+
+	Timeout time.Duration
+
+	Pod pod.Options
+}
+
+// CloneHelm creates a new virtual cluster from a point-in-time copy of the backing store and config of a running
+// virtual cluster. Without a snapshot URL the snapshot is written to the temporary directory of the source and streamed
+// through the CLI into the data volume of the clone, so no external snapshot storage is needed.
+func CloneHelm(ctx context.Context, options *CloneOptions, globalFlags *flags.GlobalFlags, sourceName, targetName string, log log.Logger) error {
+	source, err := find.GetVCluster(ctx, globalFlags.Context, sourceName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	} else if source.IsStandalone {
+		return fmt.Errorf("cannot clone standalone vCluster %s", sourceName)
+	} else if source.StatefulSet == nil {
+		return fmt.Errorf("cannot clone vCluster %s, cloning requires a vCluster with a persistent data volume", sourceName)
+	} else if !source.IsRunning() {
+		return fmt.Errorf("vCluster %s is %s, it has to be running to be cloned", sourceName, source.Status)
+	}
+
+	targetContext := cmp.Or(options.ToContext, source.Context)
+	targetNamespace := cmp.Or(options.ToNamespace, "vcluster-"+targetName)
+	if targetContext == source.Context && targetNamespace == source.Namespace {
+		return fmt.Errorf("cannot clone vCluster %s into its own namespace %s, please specify a different --to-namespace", sourceName, source.Namespace)
+	}
+
+	sourceFlags := *globalFlags
+	sourceFlags.Context = source.Context
+	sourceFlags.Namespace = source.Namespace
+	targetFlags := *globalFlags
+	targetFlags.Context = targetContext
+	targetFlags.Namespace = targetNamespace
+
+	sourceRestConfig, err := source.ClientFactory.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("load kube config of context %s: %w", source.Context, err)
+	}
+	sourceClient, err := kubernetes.NewForConfig(sourceRestConfig)
+	if err != nil {
+		return err
+	}
+
+	// the clone uses the config of the source
+	release, err := helm.NewSecrets(sourceClient).Get(ctx, source.Name, source.Namespace)
+	if err != nil {
+		return fmt.Errorf("get helm release of vCluster %s: %w", sourceName, err)
+	}
+	values, err := yaml.Marshal(release.Config)
+	if err != nil {
+		return fmt.Errorf("marshal vCluster config: %w", err)
+	}
+	err = validateCloneSource(values)
+	if err != nil {
+		return fmt.Errorf("cannot clone vCluster %s: %w", sourceName, err)
+	}
+	values, rewritten, err := rewriteCloneHostNames(values, targetNamespace)
+	if err != nil {
+		return fmt.Errorf("cannot clone vCluster %s: %w", sourceName, err)
+	}
+	for _, message := range rewritten {
+		log.Warnf("The clone %s", message)
+	}
+	chartVersion := upgrade.GetVersion()
+	if release.Chart != nil && release.Chart.Metadata != nil && release.Chart.Metadata.Version != "" {
+		chartVersion = release.Chart.Metadata.Version
+	}
+
+	// take the snapshot into the snapshot storage or the temporary directory of the source
+	snapshotName := "vcluster-clone-" + random.String(6) + ".tar.gz"
+	sourceSnapshotPath := path.Join(cloneSourceSnapshotDir, snapshotName)
+	snapshotURL := options.SnapshotURL
+	sourcePodOptions := options.Pod
+	if snapshotURL == "" {
+		// the temporary directory is only reachable from within the syncer container
+		snapshotURL = "container://" + sourceSnapshotPath
+		sourcePodOptions.Exec = true
+	}
+	log.Infof("Taking snapshot of vCluster %s/%s...", source.Namespace, sourceName)
+	err = CreateSnapshot(ctx, []string{sourceName, snapshotURL}, &sourceFlags, &snapshotapi.Options{}, &sourcePodOptions, log, false, false)
+	if err != nil {
+		return fmt.Errorf("snapshot vCluster %s: %w", sourceName, err)
+	}
+	if options.SnapshotURL == "" {
+		defer removeCloneSnapshot(ctx, sourceRestConfig, source.Namespace, source.Name, sourceSnapshotPath, log)
+	}
+
+	// create the clone with the config of the source
+	log.Infof("Creating vCluster %s in namespace %s of context %s...", targetName, targetNamespace, targetContext)
+	err = CreateHelm(ctx, &CreateOptions{
+		ChartName:       "vcluster",
+		ChartRepo:       constants.LoftChartRepo,
+		ChartVersion:    chartVersion,
+		Values:          []string{base64.StdEncoding.EncodeToString(values)},
+		CreateNamespace: true,
+	}, &targetFlags, targetName, log)
+	if err == nil {
+		err = restoreClone(ctx, options, sourceRestConfig, source, &targetFlags, targetName, snapshotName, log)
+	}
+	if err != nil {
+		log.Warnf("Cloning vCluster %s failed, deleting vCluster %s in namespace %s...", sourceName, targetName, targetNamespace)
+		deleteErr := DeleteHelm(ctx, nil, &DeleteOptions{Wait: true, IgnoreNotFound: true}, &targetFlags, targetName, log)
+		if deleteErr != nil {
+			log.Errorf("Failed to delete vCluster %s in namespace %s of context %s: %v", targetName, targetNamespace, targetContext, deleteErr)
+		}
+
+		return fmt.Errorf("clone vCluster %s: %w", sourceName, err)
+	}
+
+	log.Donef("Successfully cloned vCluster %s into vCluster %s in namespace %s of context %s. Use 'vcluster connect %s --namespace %s --context %s' to access it", sourceName, targetName, targetNamespace, targetContext, targetName, targetNamespace, targetContext)
+	return nil
+}
+
+// restoreClone streams the snapshot into the data volume of the clone if no snapshot URL is used and restores it
+func restoreClone(ctx context.Context, options *CloneOptions, sourceRestConfig *rest.Config, source *find.VCluster, targetFlags *flags.GlobalFlags, targetName, snapshotName string, log log.Logger) error {
+	err := waitForVClusterRunning(ctx, targetFlags.Context, targetName, targetFlags.Namespace, options.Timeout)
+	if err != nil {
+		return err
+	}
+	target, err := find.GetVCluster(ctx, targetFlags.Context, targetName, targetFlags.Namespace, log)
+	if err != nil {
+		return err
+	}
+	targetRestConfig, err := target.ClientFactory.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("load kube config of context %s: %w", target.Context, err)
+	}
+
+	snapshotURL := options.SnapshotURL
+	targetSnapshotPath := path.Join(cloneTargetSnapshotDir, snapshotName)
+	if snapshotURL == "" {
+		log.Infof("Copying snapshot from vCluster %s to vCluster %s...", source.Name, targetName)
+		err = streamCloneSnapshot(ctx, sourceRestConfig, source.Namespace, source.Name+"-0", path.Join(cloneSourceSnapshotDir, snapshotName), targetRestConfig, target.Namespace, targetName+"-0", targetSnapshotPath)
+		if err != nil {
+			return err
+		}
+
+		snapshotURL = "container://" + targetSnapshotPath
+	}
+
+	// the restore pod can't exec into the paused vCluster
+	podOptions := options.Pod
+	podOptions.Exec = false
+	podOptions.Env = append([]string{}, podOptions.Env...)
+	if options.ScrubSecrets {
+		podOptions.Env = append(podOptions.Env, constants.VClusterRestoreScrubSecretsEnv+"=true")
+	}
+	if options.ScaleDownWorkloads {
+		podOptions.Env = append(podOptions.Env, constants.VClusterRestoreScaleDownWorkloadsEnv+"=true")
+	}
+
+	log.Infof("Restore vCluster %s...", targetName)
+	err = Restore(ctx, []string{targetName, snapshotURL}, targetFlags, &snapshotapi.Options{}, &podOptions, true, false, log)
+	if err != nil {
+		return fmt.Errorf("restore vCluster %s: %w", targetName, err)
+	}
+	err = waitForVClusterRunning(ctx, target.Context, targetName, target.Namespace, options.Timeout)
+	if err != nil {
+		return err
+	}
+
+	// the snapshot isn't scrubbed, so make sure it doesn't stay around in the clone
+	if options.SnapshotURL == "" {
+		removeCloneSnapshot(ctx, targetRestConfig, target.Namespace, targetName, targetSnapshotPath, log)
+	}
+	return nil
+}
+
+// streamCloneSnapshot copies the snapshot from the source pod into the target pod without storing it locally
+func streamCloneSnapshot(ctx context.Context, sourceRestConfig *rest.Config, sourceNamespace, sourcePod, sourcePath string, targetRestConfig *rest.Config, targetNamespace, targetPod, targetPath string) error {
+	reader, writer := io.Pipe()
+	sourceStderr := &bytes.Buffer{}
+	sourceErr := make(chan error, 1)
+	go func() {
+		err := podhelper.ExecStream(ctx, sourceRestConfig, &podhelper.ExecStreamOptions{
+			Pod:       sourcePod,
+			Namespace: sourceNamespace,
+			Container: "syncer",
+			Command:   []string{"cat", sourcePath},
+			Stdout:    writer,
+			Stderr:    sourceStderr,
+		})
+		_ = writer.CloseWithError(err)
+		sourceErr <- err
+	}()
+
+	targetStderr := &bytes.Buffer{}
+	err := podhelper.ExecStream(ctx, targetRestConfig, &podhelper.ExecStreamOptions{
+		Pod:       targetPod,
+		Namespace: targetNamespace,
+		Container: "syncer",
+		Command:   []string{"sh", "-c", "cat > " + targetPath},
+		Stdin:     reader,
+		Stderr:    targetStderr,
+	})
+	// unblock the source if the target stopped reading
+	_ = reader.CloseWithError(err)
+	if readErr := <-sourceErr; readErr != nil {
+		return fmt.Errorf("read snapshot from pod %s/%s: %w %s", sourceNamespace, sourcePod, readErr, strings.TrimSpace(sourceStderr.String()))
+	} else if err != nil {
+		return fmt.Errorf("write snapshot to pod %s/%s: %w %s", targetNamespace, targetPod, err, strings.TrimSpace(targetStderr.String()))
+	}
+
+	return nil
+}
+
+func removeCloneSnapshot(ctx context.Context, restConfig *rest.Config, namespace, vClusterName, snapshotPath string, log log.Logger) {
+	_, stderr, err := podhelper.ExecBuffered(ctx, restConfig, namespace, vClusterName+"-0", "syncer", []string{"rm", "-f", snapshotPath}, nil)
+	if err != nil {
+		log.Warnf("Couldn't remove snapshot %s from vCluster %s/%s: %v %s", snapshotPath, namespace, vClusterName, err, strings.TrimSpace(string(stderr)))
+	}
+}
+
+// validateCloneSource rejects sources whose backing store would be shared with the clone. Restoring the snapshot into
+// a shared external database or etcd overwrites the live data of the source.
+func validateCloneSource(values []byte) error {
+	sourceConfig := &vclusterconfig.Config{}
+	err := yaml.Unmarshal(values, sourceConfig)
+	if err != nil {
+		return fmt.Errorf("parse vCluster config: %w", err)
+	}
+
+	backingStore := sourceConfig.ControlPlane.BackingStore
+	if backingStore.Database.External.Enabled {
+		return fmt.Errorf("the clone would share the external database of the source, only vClusters with an embedded or deployed backing store can be cloned")
+	} else if backingStore.Etcd.External.Enabled {
+		return fmt.Errorf("the clone would share the external etcd of the source, only vClusters with an embedded or deployed backing store can be cloned")
+	}
+
+	return nil
+}
+
+// rewriteCloneHostNames moves the objects the source writes to fixed names in other host namespaces into the namespace
+// of the clone, otherwise the clone would overwrite the exported kube configs of the source or compete with it for the
+// mapped host objects. It returns a message for each rewritten name.
+func rewriteCloneHostNames(values []byte, targetNamespace string) ([]byte, []string, error) {
+	config := map[string]interface{}{}
+	err := yaml.Unmarshal(values, &config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse vCluster config: %w", err)
+	}
+
+	messages := []string{}
+	for _, kind := range []string{"configMaps", "secrets"} {
+		byName, ok := nestedMap(config, "sync", "toHost", kind, "mappings", "byName")
+		if !ok {
+			continue
+		}
+
+		hostNames := map[string]string{}
+		for _, virtualName := range slices.Sorted(maps.Keys(byName)) {
+			hostName, ok := byName[virtualName].(string)
+			if !ok {
+				continue
+			}
+
+			_, name, _ := strings.Cut(hostName, "/")
+			rewrittenName := targetNamespace + "/" + name
+			if other, ok := hostNames[rewrittenName]; ok {
+				return nil, nil, fmt.Errorf("sync.toHost.%s.mappings.byName maps %s and %s to the same name %s in the namespace of the clone", kind, other, virtualName, name)
+			}
+			hostNames[rewrittenName] = virtualName
+			if rewrittenName != hostName {
+				byName[virtualName] = rewrittenName
+				messages = append(messages, fmt.Sprintf("syncs %s %s to %s instead of %s", kind, virtualName, rewrittenName, hostName))
+			}
+		}
+	}
+
+	if secret, ok := nestedMap(config, "exportKubeConfig", "secret"); ok {
+		messages = append(messages, rewriteCloneKubeConfigNamespace(secret, targetNamespace)...)
+	}
+	if exportKubeConfig, ok := nestedMap(config, "exportKubeConfig"); ok {
+		additionalSecrets, _ := exportKubeConfig["additionalSecrets"].([]interface{})
+		for _, additionalSecret := range additionalSecrets {
+			if secret, ok := additionalSecret.(map[string]interface{}); ok {
+				messages = append(messages, rewriteCloneKubeConfigNamespace(secret, targetNamespace)...)
+			}
+		}
+	}
+
+	values, err = yaml.Marshal(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal vCluster config: %w", err)
+	}
+
+	return values, messages, nil
+}
+
+// rewriteCloneKubeConfigNamespace exports the kube config secret into the namespace of the clone
+func rewriteCloneKubeConfigNamespace(secret map[string]interface{}, targetNamespace string) []string {
+	namespace, _ := secret["namespace"].(string)
+	if namespace == "" || namespace == targetNamespace {
+		return nil
+	}
+
+	secret["namespace"] = targetNamespace
+	return []string{fmt.Sprintf("exports its kube config secret %s to namespace %s instead of %s", secret["name"], targetNamespace, namespace)}
+}
+
+// nestedMap returns the map at the given path of the values
+func nestedMap(values map[string]interface{}, path ...string) (map[string]interface{}, bool) {
+	for _, key := range path {
+		next, ok := values[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		values = next
+	}
+
+	return values, true
+}
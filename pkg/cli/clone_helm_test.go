@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"gotest.tools/v3/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func TestValidateCloneSource(t *testing.T) {
+	for values, expectedErr := range map[string]string{
+		"": "",
+		"controlPlane:\n  backingStore:\n    etcd:\n      deploy:\n        enabled: true\n":                                                                  "",
+		"controlPlane:\n  backingStore:\n    database:\n      embedded:\n        enabled: true\n":                                                            "",
+		"controlPlane:\n  backingStore:\n    database:\n      external:\n        enabled: true\n        dataSource: mysql://root@tcp(mysql:3306)/vcluster\n": "the clone would share the external database of the source",
+		"controlPlane:\n  backingStore:\n    etcd:\n      external:\n        enabled: true\n        endpoint: etcd:2379\n":                                   "the clone would share the external etcd of the source",
+	} {
+		err := validateCloneSource([]byte(values))
+		if expectedErr == "" {
+			assert.NilError(t, err, values)
+		} else {
+			assert.ErrorContains(t, err, expectedErr, values)
+		}
+	}
+}
+
+func TestRewriteCloneHostNames(t *testing.T) {
+	values := `exportKubeConfig:
+  secret:
+    name: kubeconfig
+    namespace: vcluster-source
+  additionalSecrets:
+  - name: admin
+    namespace: platform
+  - name: local
+sync:
+  toHost:
+    secrets:
+      mappings:
+        byName:
+          team-a/registry: shared/team-a-registry
+          team-b/registry: vcluster-clone/team-b-registry
+`
+
+	rewritten, messages, err := rewriteCloneHostNames([]byte(values), "vcluster-clone")
+	assert.NilError(t, err)
+	assert.Equal(t, len(messages), 3)
+
+	config := &vclusterconfig.Config{}
+	assert.NilError(t, yaml.Unmarshal(rewritten, config))
+	assert.DeepEqual(t, config.Sync.ToHost.Secrets.Mappings.ByName, map[string]string{
+		"team-a/registry": "vcluster-clone/team-a-registry",
+		"team-b/registry": "vcluster-clone/team-b-registry",
+	})
+	assert.Equal(t, config.ExportKubeConfig.Secret.Namespace, "vcluster-clone")
+	assert.Equal(t, config.ExportKubeConfig.AdditionalSecrets[0].Namespace, "vcluster-clone")
+	assert.Equal(t, config.ExportKubeConfig.AdditionalSecrets[1].Namespace, "")
+
+	// mappings to the same name in different namespaces can't be moved into the namespace of the clone
+	_, _, err = rewriteCloneHostNames([]byte("sync:\n  toHost:\n    configMaps:\n      mappings:\n        byName:\n          a/config: ns-a/config\n          b/config: ns-b/config\n"), "vcluster-clone")
+	assert.ErrorContains(t, err, "maps a/config and b/config to the same name config in the namespace of the clone")
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
 	"github.com/loft-sh/vcluster/pkg/cli/find"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
 	"github.com/spf13/cobra"
@@ -28,6 +29,15 @@ type completionResult struct {
 // It takes into account the namespace if specified by the --namespace flag.
 func NewValidVClusterNameFunc(globalFlags *flags.GlobalFlags) Func {
 	fn := func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		// reuse the results of a recent vcluster list --all-contexts if available
+		kubeContext := globalFlags.Context
+		if kubeContext == "" {
+			kubeContext, _, _ = find.CurrentContext()
+		}
+		if names, ok := cli.CachedVClusterNames(globalFlags, kubeContext, globalFlags.Namespace); ok && len(names) > 0 {
+			return names, cobra.ShellCompDirectiveNoFileComp
+		}
+
 		vclusters, err := find.ListVClusters(cmd.Context(), globalFlags.Context, "", globalFlags.Namespace, log.Default.ErrorStreamOnly())
 		if err != nil {
 			return []string{}, cobra.ShellCompDirectiveError | cobra.ShellCompDirectiveNoFileComp
@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	pkgconfig "github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/config/advisors"
+	"github.com/loft-sh/vcluster/pkg/upgrade"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// ConfigLintOutputs are the supported output formats of ConfigLint
+var ConfigLintOutputs = []string{"text", "json", "sarif"}
+
+type ConfigLintOptions struct {
+	Values []string
+
+	Output string
+	FailOn string
+
+	Name      string
+	Namespace string
+}
+
+// ConfigLintFileResult are the lint findings of a single values file
+type ConfigLintFileResult struct {
+	File     string                  `json:"file"`
+	Findings []pkgconfig.LintFinding `json:"findings"`
+}
+
+// ConfigLint lints the given values files, writes the findings in the requested format to out and returns an
+// error if a finding is at least as severe as options.FailOn.
+func ConfigLint(options *ConfigLintOptions, out io.Writer) error {
+	if !slices.Contains(ConfigLintOutputs, options.Output) {
+		return fmt.Errorf("unsupported output format %q, choose one of %v", options.Output, ConfigLintOutputs)
+	} else if options.FailOn != "none" && severityRank(pkgconfig.LintSeverity(options.FailOn)) == 0 {
+		return fmt.Errorf("unsupported --fail-on %q, choose one of error, warning, info or none", options.FailOn)
+	} else if len(options.Values) == 0 {
+		return fmt.Errorf("please specify at least one values file with -f")
+	}
+
+	results := make([]ConfigLintFileResult, 0, len(options.Values))
+	for _, file := range options.Values {
+		var (
+			values []byte
+			err    error
+		)
+		if file == "-" {
+			values, err = io.ReadAll(os.Stdin)
+		} else {
+			values, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return fmt.Errorf("read values file %s: %w", file, err)
+		}
+
+		findings := pkgconfig.LintValues(values, pkgconfig.LintOptions{
+			Name:               options.Name,
+			HostNamespace:      options.Namespace,
+			DeprecationWarning: advisors.DeprecationWarning,
+		})
+		results = append(results, ConfigLintFileResult{File: file, Findings: findings})
+	}
+
+	err := writeConfigLintResults(out, options.Output, results)
+	if err != nil {
+		return err
+	}
+
+	if options.FailOn == "none" {
+		return nil
+	}
+	failing := 0
+	for _, result := range results {
+		for _, finding := range result.Findings {
+			if severityRank(finding.Severity) >= severityRank(pkgconfig.LintSeverity(options.FailOn)) {
+				failing++
+			}
+		}
+	}
+	if failing > 0 {
+		return fmt.Errorf("found %d finding(s) with severity %s or higher", failing, options.FailOn)
+	}
+
+	return nil
+}
+
+func writeConfigLintResults(out io.Writer, output string, results []ConfigLintFileResult) error {
+	switch output {
+	case "json":
+		raw, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(out, string(raw))
+		return err
+	case "sarif":
+		raw, err := json.MarshalIndent(toSarif(results), "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(out, string(raw))
+		return err
+	default:
+		total := 0
+		for _, result := range results {
+			for _, finding := range result.Findings {
+				location := result.File
+				if finding.Line > 0 {
+					location = fmt.Sprintf("%s:%d", location, finding.Line)
+				}
+
+				_, err := fmt.Fprintf(out, "%s: %s [%s] %s\n", location, finding.Severity, finding.RuleID, finding.Message)
+				if err != nil {
+					return err
+				}
+				total++
+			}
+		}
+
+		_, err := fmt.Fprintf(out, "%d finding(s) in %d file(s)\n", total, len(results))
+		return err
+	}
+}
+
+func severityRank(severity pkgconfig.LintSeverity) int {
+	switch severity {
+	case pkgconfig.LintSeverityError:
+		return 3
+	case pkgconfig.LintSeverityWarning:
+		return 2
+	case pkgconfig.LintSeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+func toSarif(results []ConfigLintFileResult) *sarifLog {
+	driver := sarifDriver{
+		Name:           "vcluster config lint",
+		Version:        upgrade.GetVersion(),
+		InformationURI: "https://www.vcluster.com/docs",
+	}
+	for _, rule := range pkgconfig.LintRules() {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(rule.Severity)},
+		})
+	}
+
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: []sarifResult{}}
+	for _, result := range results {
+		for _, finding := range result.Findings {
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: result.File}}}
+			if finding.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: finding.Line}
+			}
+
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    finding.RuleID,
+				Level:     sarifLevel(finding.Severity),
+				Message:   sarifMessage{Text: finding.Message},
+				Locations: []sarifLocation{location},
+			})
+		}
+	}
+
+	return &sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []sarifRun{run}}
+}
+
+func sarifLevel(severity pkgconfig.LintSeverity) string {
+	switch severity {
+	case pkgconfig.LintSeverityError:
+		return "error"
+	case pkgconfig.LintSeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestConfigLint(t *testing.T) {
+	valuesFile := filepath.Join(t.TempDir(), "values.yaml")
+	err := os.WriteFile(valuesFile, []byte("controlPlane:\n  statefulSet:\n    persistence:\n      volumeClaim:\n        enabled: false\n"), 0o600)
+	assert.NilError(t, err)
+
+	options := &ConfigLintOptions{Values: []string{valuesFile}, Output: "sarif", FailOn: "error", Name: "vcluster", Namespace: "vcluster"}
+	out := &bytes.Buffer{}
+	assert.NilError(t, ConfigLint(options, out))
+
+	sarif := &sarifLog{}
+	assert.NilError(t, json.Unmarshal(out.Bytes(), sarif))
+	assert.Equal(t, sarif.Version, "2.1.0")
+	assert.Equal(t, len(sarif.Runs[0].Results), 1)
+	result := sarif.Runs[0].Results[0]
+	assert.Equal(t, result.RuleID, "embedded-store-without-persistence")
+	assert.Equal(t, result.Level, "warning")
+	assert.Equal(t, result.Locations[0].PhysicalLocation.ArtifactLocation.URI, valuesFile)
+	assert.Equal(t, result.Locations[0].PhysicalLocation.Region.StartLine, 5)
+
+	options.Output = "text"
+	options.FailOn = "warning"
+	assert.ErrorContains(t, ConfigLint(options, &bytes.Buffer{}), "found 1 finding(s) with severity warning or higher")
+
+	options.FailOn = "critical"
+	assert.ErrorContains(t, ConfigLint(options, &bytes.Buffer{}), "unsupported --fail-on")
+}
@@ -3,12 +3,16 @@ package cli
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/connectdaemon"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -108,6 +112,14 @@ func (cmd *connectDocker) connect(ctx context.Context, vClusterName string, comm
 		}
 	}
 
+	// publish the load balancer services locally if the docker network isn't reachable
+	if !cmd.ConnectOptions.Print {
+		err = startLoadBalancerForwarding(ctx, cmd.GlobalFlags, vClusterName, kubeConfig, cmd.log)
+		if err != nil {
+			return err
+		}
+	}
+
 	// check if we should execute command
 	if len(command) > 0 {
 		return executeCommand(*kubeConfig, command, nil, cmd.log)
@@ -298,3 +310,88 @@ func isVClusterFailed(ctx context.Context, vClusterName string) bool {
 	out, _ := exec.CommandContext(ctx, "docker", args...).Output()
 	return strings.TrimSpace(string(out)) == "0"
 }
+
+// loadBalancerAddressesFile holds the local addresses the load balancer services of a docker vCluster are published
+// on. It only exists if the docker network isn't reachable and vCluster can't publish them itself.
+const loadBalancerAddressesFile = ".load-balancer-addresses"
+
+func writeLoadBalancerAddresses(vClusterConfigDir string, addresses []string) error {
+	addressesPath := filepath.Join(vClusterConfigDir, loadBalancerAddressesFile)
+	if len(addresses) == 0 {
+		err := os.Remove(addressesPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove load balancer addresses: %w", err)
+		}
+
+		return nil
+	}
+
+	err := os.WriteFile(addressesPath, []byte(strings.Join(addresses, "\n")), 0644)
+	if err != nil {
+		return fmt.Errorf("write load balancer addresses: %w", err)
+	}
+
+	return nil
+}
+
+func loadBalancerConnectionID(vClusterName string) string {
+	return "vcluster-docker_" + vClusterName + "_load-balancers"
+}
+
+// startLoadBalancerForwarding lets the connect daemon publish the load balancer services on the local addresses of
+// the vCluster, so they stay reachable after this command
+func startLoadBalancerForwarding(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName string, kubeConfig *clientcmdapi.Config, log log.Logger) error {
+	out, err := os.ReadFile(filepath.Join(filepath.Dir(globalFlags.Config), "docker", "vclusters", vClusterName, loadBalancerAddressesFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("read load balancer addresses: %w", err)
+	}
+
+	rawKubeConfig, err := clientcmd.Write(*kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	daemonClient, err := connectdaemon.StartDaemon(ctx, globalFlags.Config, log)
+	if err != nil {
+		return err
+	}
+
+	_, err = daemonClient.Add(ctx, &connectdaemon.AddRequest{
+		Connection: connectdaemon.Connection{
+			ID:        loadBalancerConnectionID(vClusterName),
+			Type:      connectdaemon.TypeLoadBalancer,
+			Name:      vClusterName,
+			Context:   "docker",
+			Addresses: strings.Fields(string(out)),
+		},
+		KubeConfig: rawKubeConfig,
+	})
+	if err != nil {
+		return fmt.Errorf("start load balancer forwarding: %w", err)
+	}
+
+	log.Debugf("Publishing load balancer services of vCluster %s locally in the background", vClusterName)
+	return nil
+}
+
+// stopLoadBalancerForwarding stops publishing the load balancer services of the vCluster if the connect daemon is running
+func stopLoadBalancerForwarding(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName string) error {
+	daemonClient := connectdaemon.NewClient(connectdaemon.SocketPath(globalFlags.Config))
+	if !daemonClient.IsRunning(ctx) {
+		return nil
+	}
+
+	connections, err := daemonClient.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list background connections: %w", err)
+	}
+	for _, connection := range connections {
+		if connection.ID == loadBalancerConnectionID(vClusterName) {
+			return daemonClient.Remove(ctx, connection.ID)
+		}
+	}
+
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/connectdaemon"
 	"github.com/loft-sh/vcluster/pkg/cli/find"
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
 	"github.com/loft-sh/vcluster/pkg/cli/localkubernetes"
@@ -51,7 +52,9 @@ type ConnectOptions struct {
 	Print                     bool
 	UpdateCurrent             bool
 	BackgroundProxy           bool
+	Background                bool
 	Insecure                  bool
+	ExecCredential            bool
 
 	Project string
 }
@@ -359,8 +362,8 @@ func (cmd *connectHelm) getVClusterKubeConfig(ctx context.Context, vclusterName
 			return nil, err
 		}
 
-		// check if we should start a background proxy
-		if cmd.Server == "" && cmd.BackgroundProxy {
+		// check if we should start a background proxy, the connect daemon replaces it
+		if cmd.Server == "" && cmd.BackgroundProxy && !cmd.Background {
 			if localkubernetes.IsDockerInstalledAndUpAndRunning() {
 				// start background container
 				cmd.Server, err = localkubernetes.CreateBackgroundProxyContainer(ctx, vclusterName, cmd.Namespace, cmd.BackgroundProxyImage, cmd.kubeClientConfig, cmd.LocalPort, cmd.Log)
@@ -409,7 +412,12 @@ func (cmd *connectHelm) getVClusterKubeConfig(ctx context.Context, vclusterName
 	// * we want to have a service account token
 	// * we still don't have a server (means background proxy has failed or is disabled)
 	// * we have a command to execute
-	if cmd.ServiceAccount != "" || cmd.Server == "" || len(command) > 0 {
+	if cmd.Background && (cmd.ServiceAccount != "" || cmd.Server == "") {
+		err = cmd.startBackgroundPortForwarding(ctx, vclusterName, port)
+		if err != nil {
+			return nil, err
+		}
+	} else if cmd.ServiceAccount != "" || cmd.Server == "" || len(command) > 0 {
 		cmd.portForwarding = true
 		cmd.interruptChan = make(chan struct{})
 		cmd.errorChan = make(chan error)
@@ -442,6 +450,26 @@ func (cmd *connectHelm) getVClusterKubeConfig(ctx context.Context, vclusterName
 			return nil, err
 		}
 
+		// use the exec credential plugin to retrieve short-lived tokens instead of embedding a token, so only make
+		// sure the service account exists
+		if cmd.ExecCredential {
+			err = serviceaccount.EnsureServiceAccount(ctx, vKubeClient, serviceAccount, serviceAccountNamespace, cmd.ServiceAccountClusterRole, cmd.Log)
+			if err != nil {
+				return nil, err
+			}
+
+			authInfo, err := execCredentialAuthInfo(vclusterName, cmd.ConnectOptions, cmd.GlobalFlags)
+			if err != nil {
+				return nil, err
+			}
+
+			for k := range kubeConfig.AuthInfos {
+				kubeConfig.AuthInfos[k] = authInfo
+			}
+
+			return kubeConfig, nil
+		}
+
 		token, err := serviceaccount.CreateServiceAccountToken(ctx, vKubeClient, serviceAccount, serviceAccountNamespace, cmd.ServiceAccountClusterRole, int64(cmd.ServiceAccountExpiration), cmd.Log)
 		if err != nil {
 			return nil, err
@@ -460,24 +488,64 @@ func (cmd *connectHelm) getVClusterKubeConfig(ctx context.Context, vclusterName
 	return kubeConfig, nil
 }
 
+// startBackgroundPortForwarding lets the connect daemon forward the virtual cluster, so the port-forward survives
+// this command and is restarted after network changes
+func (cmd *connectHelm) startBackgroundPortForwarding(ctx context.Context, vClusterName, remotePort string) error {
+	port, err := strconv.Atoi(remotePort)
+	if err != nil {
+		return fmt.Errorf("parse vcluster server port %q: %w", remotePort, err)
+	}
+
+	// the daemon gets its own copy of the host kube config, so it doesn't depend on the environment it was started with
+	hostKubeConfig := cmd.rawConfig.DeepCopy()
+	err = clientcmdapi.MinifyConfig(hostKubeConfig)
+	if err != nil {
+		return fmt.Errorf("minify kube config: %w", err)
+	}
+	err = clientcmdapi.FlattenConfig(hostKubeConfig)
+	if err != nil {
+		return fmt.Errorf("flatten kube config: %w", err)
+	}
+	rawHostKubeConfig, err := clientcmd.Write(*hostKubeConfig)
+	if err != nil {
+		return err
+	}
+
+	daemonClient, err := connectdaemon.StartDaemon(ctx, cmd.Config, cmd.Log)
+	if err != nil {
+		return err
+	}
+
+	_, err = daemonClient.Add(ctx, &connectdaemon.AddRequest{
+		Connection: connectdaemon.Connection{
+			ID:         cmd.KubeConfigContextName,
+			Name:       vClusterName,
+			Namespace:  cmd.Namespace,
+			Context:    cmd.Context,
+			Pod:        cmd.PodName,
+			Address:    cmd.Address,
+			LocalPort:  cmd.LocalPort,
+			RemotePort: port,
+		},
+		KubeConfig: rawHostKubeConfig,
+	})
+	if err != nil {
+		return fmt.Errorf("start background port-forwarding: %w", err)
+	}
+
+	cmd.Log.Donef("Port-forwarding vcluster %s/%s in the background on port %d, use `vcluster connections ls` to list background connections", cmd.Namespace, vClusterName, cmd.LocalPort)
+	return nil
+}
+
 func getServiceAccountClientAndName(kubeConfig clientcmdapi.Config, options *ConnectOptions) (kubernetes.Interface, string, string, error) {
 	vKubeClient, err := getLocalVClusterClient(kubeConfig, options)
 	if err != nil {
 		return nil, "", "", err
 	}
 
-	var (
-		serviceAccount          = options.ServiceAccount
-		serviceAccountNamespace = "kube-system"
-	)
-	if strings.Contains(options.ServiceAccount, "/") {
-		splitted := strings.Split(options.ServiceAccount, "/")
-		if len(splitted) != 2 {
-			return nil, "", "", fmt.Errorf("unexpected service account reference, expected ServiceAccountNamespace/ServiceAccountName")
-		}
-
-		serviceAccountNamespace = splitted[0]
-		serviceAccount = splitted[1]
+	serviceAccountNamespace, serviceAccount, err := parseServiceAccount(options.ServiceAccount)
+	if err != nil {
+		return nil, "", "", err
 	}
 
 	return vKubeClient, serviceAccount, serviceAccountNamespace, nil
@@ -0,0 +1,150 @@
+package connectdaemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/loft-sh/log"
+)
+
+const startTimeout = time.Second * 10
+
+// Client talks to the connect daemon over its unix socket
+type Client struct {
+	httpClient *http.Client
+}
+
+func NewClient(socketPath string) *Client {
+	return &Client{
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+// StartDaemon starts the connect daemon in the background via "vcluster connections daemon" if it isn't running
+// yet and waits until it accepts requests
+func StartDaemon(ctx context.Context, configPath string, log log.Logger) (*Client, error) {
+	client := NewClient(SocketPath(configPath))
+	if client.IsRunning(ctx) {
+		return client, nil
+	}
+
+	binary, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("find vcluster binary: %w", err)
+	}
+	logFile, err := os.OpenFile(LogPath(configPath), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open connect daemon log: %w", err)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(binary, "connections", "daemon", "--config", configPath)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	detach(cmd)
+	err = cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("start connect daemon: %w", err)
+	}
+	_ = cmd.Process.Release()
+	log.Debugf("Started connect daemon, logs are written to %s", LogPath(configPath))
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, startTimeout)
+	defer cancel()
+	for !client.IsRunning(timeoutCtx) {
+		select {
+		case <-timeoutCtx.Done():
+			return nil, fmt.Errorf("connect daemon didn't start within %s, check %s", startTimeout, LogPath(configPath))
+		case <-time.After(time.Millisecond * 100):
+		}
+	}
+
+	return client, nil
+}
+
+// IsRunning returns true if the daemon accepts requests
+func (c *Client) IsRunning(ctx context.Context) bool {
+	_, err := c.List(ctx)
+	return err == nil
+}
+
+func (c *Client) List(ctx context.Context) ([]Connection, error) {
+	connections := []Connection{}
+	err := c.do(ctx, http.MethodGet, "/connections", nil, &connections)
+	if err != nil {
+		return nil, err
+	}
+
+	return connections, nil
+}
+
+// Add starts forwarding the connection and returns once the port-forward is ready
+func (c *Client) Add(ctx context.Context, req *AddRequest) (*Connection, error) {
+	conn := &Connection{}
+	err := c.do(ctx, http.MethodPost, "/connections", req, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (c *Client) Remove(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/connections/"+url.PathEscape(id), nil, nil)
+}
+
+// RemoveAll stops all port-forwards, which also stops the daemon
+func (c *Client) RemoveAll(ctx context.Context) error {
+	return c.do(ctx, http.MethodDelete, "/connections", nil, nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reqBody bytes.Buffer
+	if body != nil {
+		err := json.NewEncoder(&reqBody).Encode(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the host is ignored, requests are always sent to the socket
+	req, err := http.NewRequestWithContext(ctx, method, "http://connect-daemon"+path, &reqBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		errResp := &errorResponse{}
+		err = json.NewDecoder(resp.Body).Decode(errResp)
+		if err != nil || errResp.Message == "" {
+			return fmt.Errorf("unexpected status code %d from connect daemon", resp.StatusCode)
+		}
+
+		return errors.New(errResp.Message)
+	} else if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
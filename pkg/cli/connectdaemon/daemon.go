@@ -0,0 +1,364 @@
+package connectdaemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/loft-sh/log"
+)
+
+const (
+	// firstConnectTimeout is how long an add request waits for the first port-forward to become ready
+	firstConnectTimeout = time.Minute
+
+	minReconnectBackoff = time.Second
+	maxReconnectBackoff = time.Second * 30
+)
+
+// forwardFunc forwards a single connection until it fails or ctx is done. It calls ready once the
+// port-forward accepts connections.
+type forwardFunc func(ctx context.Context, conn Connection, kubeConfig []byte, ready func()) error
+
+// Daemon manages the port-forwards of many virtual clusters and serves the connections API on a unix socket.
+// It stops once the last connection was removed.
+type Daemon struct {
+	log log.Logger
+
+	m           sync.Mutex
+	connections map[string]*connection
+
+	forward forwardFunc
+
+	idle     chan struct{}
+	idleOnce sync.Once
+}
+
+type connection struct {
+	m sync.Mutex
+	Connection
+
+	kubeConfig []byte
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+func NewDaemon(log log.Logger) *Daemon {
+	return &Daemon{
+		log:         log,
+		connections: map[string]*connection{},
+		forward:     forwardConnection,
+		idle:        make(chan struct{}),
+	}
+}
+
+// Run serves the connections API on socketPath until ctx is done or no connections are left
+func (d *Daemon) Run(ctx context.Context, socketPath string) error {
+	if NewClient(socketPath).IsRunning(ctx) {
+		return fmt.Errorf("connect daemon is already running on %s", socketPath)
+	}
+
+	// remove a stale socket of a daemon that didn't shut down cleanly
+	err := os.Remove(socketPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove stale socket: %w", err)
+	}
+	err = os.MkdirAll(filepath.Dir(socketPath), 0700)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", socketPath, err)
+	}
+	defer func() {
+		_ = listener.Close()
+		_ = os.Remove(socketPath)
+	}()
+	err = os.Chmod(socketPath, 0600)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{Handler: d.handler(), ReadHeaderTimeout: time.Second * 10}
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.Serve(listener)
+	}()
+
+	d.log.Infof("Connect daemon listening on %s", socketPath)
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+	case <-d.idle:
+		d.log.Infof("No connections left, stopping connect daemon")
+	}
+
+	d.removeAll()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+func (d *Daemon) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /connections", d.handleList)
+	mux.HandleFunc("POST /connections", d.handleAdd)
+	mux.HandleFunc("DELETE /connections", d.handleRemoveAll)
+	mux.HandleFunc("DELETE /connections/{id}", d.handleRemove)
+	return mux
+}
+
+func (d *Daemon) handleList(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, d.list())
+}
+
+func (d *Daemon) handleAdd(w http.ResponseWriter, req *http.Request) {
+	addRequest := &AddRequest{}
+	err := json.NewDecoder(req.Body).Decode(addRequest)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("decode request: %w", err))
+		return
+	}
+
+	conn := addRequest.Connection
+	err = validateConnection(conn)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	added, err := d.add(req.Context(), conn, addRequest.KubeConfig)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, added)
+}
+
+func validateConnection(conn Connection) error {
+	switch conn.Type {
+	case TypePortForward:
+		if conn.ID == "" || conn.Name == "" || conn.Namespace == "" {
+			return fmt.Errorf("id, name and namespace are required")
+		} else if conn.LocalPort <= 0 || conn.RemotePort <= 0 {
+			return fmt.Errorf("local and remote port are required")
+		}
+	case TypeLoadBalancer:
+		if conn.ID == "" || conn.Name == "" {
+			return fmt.Errorf("id and name are required")
+		} else if len(conn.Addresses) == 0 {
+			return fmt.Errorf("addresses are required")
+		}
+	default:
+		return fmt.Errorf("unknown connection type %q", conn.Type)
+	}
+
+	return nil
+}
+
+func (d *Daemon) handleRemove(w http.ResponseWriter, req *http.Request) {
+	if !d.remove(req.PathValue("id")) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("connection %s not found", req.PathValue("id")))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (d *Daemon) handleRemoveAll(w http.ResponseWriter, _ *http.Request) {
+	d.removeAll()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (d *Daemon) list() []Connection {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	connections := []Connection{}
+	for _, conn := range d.connections {
+		connections = append(connections, conn.snapshot())
+	}
+	sort.Slice(connections, func(i, j int) bool {
+		return connections[i].ID < connections[j].ID
+	})
+
+	return connections
+}
+
+// add starts forwarding the connection and waits until the first port-forward is ready. An existing connection
+// with the same id is replaced.
+func (d *Daemon) add(ctx context.Context, info Connection, kubeConfig []byte) (Connection, error) {
+	info.Status = StatusConnecting
+	info.LastError = ""
+	info.Reconnects = 0
+	info.Created = time.Now()
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	conn := &connection{
+		Connection: info,
+		kubeConfig: kubeConfig,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+
+	d.m.Lock()
+	existing := d.connections[info.ID]
+	d.connections[info.ID] = conn
+	d.m.Unlock()
+
+	// the replaced connection has to release the local port first
+	if existing != nil {
+		existing.stop()
+	}
+
+	firstResult := make(chan error, 1)
+	go d.run(runCtx, conn, firstResult)
+
+	select {
+	case err := <-firstResult:
+		if err != nil {
+			d.removeConnection(conn)
+			return Connection{}, err
+		}
+	case <-time.After(firstConnectTimeout):
+		// keep trying in the background
+	case <-ctx.Done():
+	}
+
+	return conn.snapshot(), nil
+}
+
+// run forwards the connection and restarts the port-forward with a backoff whenever it fails, e.g. after
+// the network changed. If the first port-forward fails, the error is sent to firstResult and run returns.
+func (d *Daemon) run(ctx context.Context, conn *connection, firstResult chan<- error) {
+	defer close(conn.done)
+
+	everReady := false
+	backoff := minReconnectBackoff
+	for {
+		ready := false
+		err := d.forward(ctx, conn.snapshot(), conn.kubeConfig, func() {
+			ready = true
+			conn.setStatus(StatusConnected, nil)
+			if !everReady {
+				everReady = true
+				firstResult <- nil
+			}
+		})
+		if ctx.Err() != nil {
+			return
+		} else if !everReady {
+			firstResult <- err
+			return
+		}
+
+		if err == nil {
+			err = fmt.Errorf("port-forward stopped")
+		}
+		if ready {
+			backoff = minReconnectBackoff
+		}
+		conn.setStatus(StatusReconnecting, err)
+		d.log.Warnf("Lost connection to vcluster %s/%s, reconnecting in %s: %v", conn.Namespace, conn.Name, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxReconnectBackoff)
+	}
+}
+
+func (d *Daemon) remove(id string) bool {
+	d.m.Lock()
+	conn := d.connections[id]
+	d.m.Unlock()
+	if conn == nil {
+		return false
+	}
+
+	conn.stop()
+	d.removeConnection(conn)
+	return true
+}
+
+func (d *Daemon) removeAll() {
+	d.m.Lock()
+	connections := make([]*connection, 0, len(d.connections))
+	for _, conn := range d.connections {
+		connections = append(connections, conn)
+	}
+	d.m.Unlock()
+
+	for _, conn := range connections {
+		conn.stop()
+		d.removeConnection(conn)
+	}
+
+	// make sure we stop, even if there were no connections
+	d.idleOnce.Do(func() { close(d.idle) })
+}
+
+// removeConnection removes the connection unless it was replaced in the meantime and signals that the daemon
+// is idle if it was the last one
+func (d *Daemon) removeConnection(conn *connection) {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	if d.connections[conn.ID] == conn {
+		delete(d.connections, conn.ID)
+	}
+	if len(d.connections) == 0 {
+		d.idleOnce.Do(func() { close(d.idle) })
+	}
+}
+
+func (c *connection) stop() {
+	c.cancel()
+	<-c.done
+}
+
+func (c *connection) snapshot() Connection {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return c.Connection
+}
+
+func (c *connection) setStatus(status Status, err error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if status == StatusReconnecting {
+		c.Reconnects++
+	}
+	c.Status = status
+	c.LastError = ""
+	if err != nil {
+		c.LastError = err.Error()
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(obj)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, &errorResponse{Message: err.Error()})
+}
@@ -0,0 +1,92 @@
+package connectdaemon
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/log"
+	"gotest.tools/v3/assert"
+)
+
+func TestDaemon(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "daemon.sock")
+	attempts := atomic.Int32{}
+
+	daemon := NewDaemon(log.Discard)
+	daemon.forward = func(ctx context.Context, conn Connection, _ []byte, ready func()) error {
+		if conn.Name == "broken" {
+			return errors.New("can't find a running vcluster pod")
+		}
+
+		// the first port-forward of "flaky" is lost right after it was ready
+		ready()
+		if conn.Name == "flaky" && attempts.Add(1) == 1 {
+			return errors.New("lost connection to pod")
+		}
+
+		<-ctx.Done()
+		return nil
+	}
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- daemon.Run(context.Background(), socketPath)
+	}()
+
+	ctx := context.Background()
+	client := NewClient(socketPath)
+	assert.NilError(t, waitFor(func() bool { return client.IsRunning(ctx) }))
+
+	// add connections
+	_, err := client.Add(ctx, &AddRequest{Connection: Connection{ID: "vcluster_flaky", Name: "flaky", Namespace: "vcluster-flaky", LocalPort: 10443, RemotePort: 8443}})
+	assert.NilError(t, err)
+	conn, err := client.Add(ctx, &AddRequest{Connection: Connection{ID: "vcluster_stable", Name: "stable", Namespace: "vcluster-stable", LocalPort: 10444, RemotePort: 8443}})
+	assert.NilError(t, err)
+	assert.Equal(t, conn.Status, StatusConnected)
+	_, err = client.Add(ctx, &AddRequest{Connection: Connection{ID: "vcluster_broken", Name: "broken", Namespace: "vcluster-broken", LocalPort: 10445, RemotePort: 8443}})
+	assert.ErrorContains(t, err, "can't find a running vcluster pod")
+	_, err = client.Add(ctx, &AddRequest{Connection: Connection{ID: "vcluster_invalid", Name: "invalid", Namespace: "vcluster-invalid"}})
+	assert.ErrorContains(t, err, "local and remote port are required")
+	_, err = client.Add(ctx, &AddRequest{Connection: Connection{ID: "vcluster_lb", Type: TypeLoadBalancer, Name: "lb"}})
+	assert.ErrorContains(t, err, "addresses are required")
+
+	// the lost port-forward is restarted
+	assert.NilError(t, waitFor(func() bool {
+		connections, err := client.List(ctx)
+		return err == nil && len(connections) == 2 && connections[0].Reconnects == 1 && connections[0].Status == StatusConnected
+	}))
+
+	// remove connections
+	assert.NilError(t, client.Remove(ctx, "vcluster_stable"))
+	assert.ErrorContains(t, client.Remove(ctx, "vcluster_stable"), "connection vcluster_stable not found")
+	connections, err := client.List(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, len(connections), 1)
+	assert.Equal(t, connections[0].ID, "vcluster_flaky")
+
+	// the daemon stops once all connections are removed
+	assert.NilError(t, client.RemoveAll(ctx))
+	select {
+	case err := <-runErr:
+		assert.NilError(t, err)
+	case <-time.After(time.Second * 10):
+		t.Fatal("daemon didn't stop")
+	}
+	assert.Assert(t, !client.IsRunning(ctx))
+}
+
+func waitFor(condition func() bool) error {
+	for range 50 {
+		if condition() {
+			return nil
+		}
+
+		time.Sleep(time.Millisecond * 100)
+	}
+
+	return errors.New("timed out")
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package connectdaemon
+
+import "os/exec"
+
+// detach isn't supported on this platform, the daemon might stop with the terminal that started it.
+func detach(*exec.Cmd) {}
@@ -0,0 +1,13 @@
+//go:build unix
+
+package connectdaemon
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the daemon in a new session, so it keeps running after the terminal was closed
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
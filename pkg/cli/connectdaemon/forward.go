@@ -0,0 +1,173 @@
+package connectdaemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	utilhttp "github.com/loft-sh/vcluster/pkg/util/http"
+	"github.com/loft-sh/vcluster/pkg/util/portforward"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	healthCheckInterval    = time.Second * 10
+	healthCheckTimeout     = time.Second * 5
+	maxHealthCheckFailures = 2
+)
+
+// healthCheckClient sends requests through the port-forward to the virtual cluster api server. Keep-alives are
+// disabled, so every check opens a new stream and detects a dead connection to the host cluster.
+var healthCheckClient = func() *http.Client {
+	transport := utilhttp.InsecureTransport()
+	transport.DisableKeepAlives = true
+	return &http.Client{Transport: transport, Timeout: healthCheckTimeout}
+}()
+
+func forwardConnection(ctx context.Context, conn Connection, kubeConfig []byte, ready func()) error {
+	if conn.Type == TypeLoadBalancer {
+		return forwardLoadBalancers(ctx, conn, kubeConfig, ready)
+	}
+
+	return forwardPod(ctx, conn, kubeConfig, ready)
+}
+
+// forwardPod forwards the local port to the virtual cluster pod. A port-forward over a connection that died
+// silently, e.g. after the laptop was asleep, doesn't always fail on its own, so the forwarded port is health
+// checked periodically.
+func forwardPod(ctx context.Context, conn Connection, kubeConfig []byte, ready func()) error {
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeConfig)
+	if err != nil {
+		return fmt.Errorf("load kube config: %w", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("create kube client: %w", err)
+	}
+
+	// without a pinned pod the newest vCluster pod is looked up on every reconnect, as the pod might have been
+	// recreated under another name since the last port-forward. A pinned pod is always used as is, so the port-forward
+	// only recovers once a pod with that name is running again.
+	pod := conn.Pod
+	if pod == "" {
+		pod, err = findPod(ctx, kubeClient, conn.Name, conn.Namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	dialer, err := portforward.NewPodDialer(restConfig, kubeClient, pod, conn.Namespace)
+	if err != nil {
+		return err
+	}
+
+	address := conn.Address
+	if address == "" {
+		address = "localhost"
+	}
+
+	stopChan := make(chan struct{})
+	readyChan := make(chan struct{})
+	// the forwarder might raise more than one error
+	errChan := make(chan error, 4)
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{address}, []string{strconv.Itoa(conn.LocalPort) + ":" + strconv.Itoa(conn.RemotePort)}, stopChan, readyChan, errChan, io.Discard, io.Discard)
+	if err != nil {
+		return err
+	}
+
+	forwardDone := make(chan struct{})
+	go func() {
+		defer close(forwardDone)
+
+		err := forwarder.ForwardPorts(ctx)
+		if err != nil {
+			errChan <- err
+		}
+	}()
+	defer func() {
+		close(stopChan)
+
+		// wait until the local port is released, so it can be reused by the next port-forward
+		select {
+		case <-forwardDone:
+		case <-time.After(time.Second * 5):
+		}
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-ctx.Done():
+		return nil
+	case <-readyChan:
+	}
+	ready()
+
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		select {
+		case err := <-errChan:
+			return err
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			err := checkHealth(ctx, net.JoinHostPort(address, strconv.Itoa(conn.LocalPort)))
+			if err == nil {
+				failures = 0
+				continue
+			}
+
+			failures++
+			if failures >= maxHealthCheckFailures {
+				return fmt.Errorf("health check: %w", err)
+			}
+		}
+	}
+}
+
+// checkHealth succeeds if the virtual cluster api server responds at all, the response status doesn't matter
+func checkHealth(ctx context.Context, hostPort string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+hostPort+"/readyz", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := healthCheckClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+func findPod(ctx context.Context, kubeClient kubernetes.Interface, name, namespace string) (string, error) {
+	pods, err := kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=vcluster,release=" + name,
+	})
+	if err != nil {
+		return "", fmt.Errorf("list vcluster pods: %w", err)
+	}
+
+	// sort by newest
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].CreationTimestamp.Unix() > pods.Items[j].CreationTimestamp.Unix()
+	})
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp == nil && pod.Status.Phase == corev1.PodRunning {
+			return pod.Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("can't find a running vcluster pod in namespace %s", namespace)
+}
@@ -0,0 +1,309 @@
+package connectdaemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/util/portforward"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/ptr"
+)
+
+// loadBalancerSyncInterval is how often the LoadBalancer services and their endpoints are reconciled
+const loadBalancerSyncInterval = time.Second * 5
+
+// forwardLoadBalancers publishes the ports of the LoadBalancer services of a docker virtual cluster on the addresses
+// of the connection and writes the address into the service status. The docker network of rootless docker, podman
+// and WSL2 isn't reachable from the host, so the ports are forwarded through the virtual cluster api server to a
+// ready pod of the service.
+func forwardLoadBalancers(ctx context.Context, conn Connection, kubeConfig []byte, ready func()) error {
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeConfig)
+	if err != nil {
+		return fmt.Errorf("load kube config: %w", err)
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("create kube client: %w", err)
+	}
+
+	forwarder := &loadBalancerForwarder{
+		restConfig: restConfig,
+		kubeClient: kubeClient,
+		addresses:  conn.Addresses,
+		forwards:   map[forwardKey]*servicePortForward{},
+	}
+	defer forwarder.stopAll()
+
+	ticker := time.NewTicker(loadBalancerSyncInterval)
+	defer ticker.Stop()
+
+	isReady := false
+	for {
+		err := forwarder.sync(ctx)
+		if err != nil {
+			return err
+		} else if !isReady {
+			isReady = true
+			ready()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// forwardKey is the local address and port a service port is published on
+type forwardKey struct {
+	address string
+	port    int32
+}
+
+// forwardTarget is the pod port a published service port is forwarded to
+type forwardTarget struct {
+	namespace string
+	pod       string
+	port      int32
+}
+
+type servicePortForward struct {
+	target forwardTarget
+
+	stopChan chan struct{}
+	done     chan struct{}
+}
+
+func (f *servicePortForward) stop() {
+	close(f.stopChan)
+	<-f.done
+}
+
+// stopped returns true if the port-forward ended on its own, e.g. because the pod was deleted
+func (f *servicePortForward) stopped() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+		return false
+	}
+}
+
+type loadBalancerForwarder struct {
+	restConfig *rest.Config
+	kubeClient kubernetes.Interface
+	addresses  []string
+
+	forwards map[forwardKey]*servicePortForward
+}
+
+func (l *loadBalancerForwarder) sync(ctx context.Context) error {
+	services, err := l.kubeClient.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("list services: %w", err)
+	}
+	endpointSlices, err := l.kubeClient.DiscoveryV1().EndpointSlices("").List(ctx, metav1.ListOptions{LabelSelector: discoveryv1.LabelServiceName})
+	if err != nil {
+		return fmt.Errorf("list endpoint slices: %w", err)
+	}
+
+	assigned := assignAddresses(services.Items, l.addresses)
+	desired := desiredForwards(services.Items, endpointSlices.Items, assigned)
+	for i := range services.Items {
+		service := &services.Items[i]
+		address, ok := assigned[types.NamespacedName{Namespace: service.Namespace, Name: service.Name}]
+		if !ok || statusAddress(service) == address {
+			continue
+		}
+
+		service.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: address}}
+		_, err = l.kubeClient.CoreV1().Services(service.Namespace).UpdateStatus(ctx, service, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("update status of service %s/%s: %w", service.Namespace, service.Name, err)
+		}
+	}
+
+	// restart port-forwards whose pod changed or that stopped on their own
+	for key, forward := range l.forwards {
+		if target, ok := desired[key]; !ok || target != forward.target || forward.stopped() {
+			forward.stop()
+			delete(l.forwards, key)
+		}
+	}
+	for key, target := range desired {
+		if _, ok := l.forwards[key]; ok {
+			continue
+		}
+
+		// ports that can't be published, e.g. privileged ports, are retried on the next sync
+		forward, err := l.start(ctx, key, target)
+		if err == nil {
+			l.forwards[key] = forward
+		}
+	}
+
+	return nil
+}
+
+func (l *loadBalancerForwarder) start(ctx context.Context, key forwardKey, target forwardTarget) (*servicePortForward, error) {
+	dialer, err := portforward.NewPodDialer(l.restConfig, l.kubeClient, target.pod, target.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	forward := &servicePortForward{
+		target:   target,
+		stopChan: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	readyChan := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{key.address}, []string{strconv.Itoa(int(key.port)) + ":" + strconv.Itoa(int(target.port))}, forward.stopChan, readyChan, nil, io.Discard, io.Discard)
+	if err != nil {
+		return nil, err
+	}
+
+	var forwardErr error
+	go func() {
+		defer close(forward.done)
+
+		forwardErr = forwarder.ForwardPorts(ctx)
+	}()
+
+	select {
+	case <-readyChan:
+		return forward, nil
+	case <-forward.done:
+		if forwardErr == nil {
+			forwardErr = fmt.Errorf("port-forward stopped")
+		}
+		return nil, forwardErr
+	case <-ctx.Done():
+		forward.stop()
+		return nil, ctx.Err()
+	}
+}
+
+func (l *loadBalancerForwarder) stopAll() {
+	for key, forward := range l.forwards {
+		forward.stop()
+		delete(l.forwards, key)
+	}
+}
+
+// assignAddresses assigns one of the addresses to each LoadBalancer service. Services keep the address of their
+// status, the others get the first free address in the order of their creation. Services are left pending if there
+// are no free addresses left.
+func assignAddresses(services []corev1.Service, addresses []string) map[types.NamespacedName]string {
+	loadBalancers := []corev1.Service{}
+	for _, service := range services {
+		if service.Spec.Type == corev1.ServiceTypeLoadBalancer && service.DeletionTimestamp == nil {
+			loadBalancers = append(loadBalancers, service)
+		}
+	}
+	sort.SliceStable(loadBalancers, func(i, j int) bool {
+		return loadBalancers[i].CreationTimestamp.Before(&loadBalancers[j].CreationTimestamp)
+	})
+
+	assigned := map[types.NamespacedName]string{}
+	used := map[string]bool{}
+	for _, service := range loadBalancers {
+		address := statusAddress(&service)
+		if slices.Contains(addresses, address) && !used[address] {
+			assigned[types.NamespacedName{Namespace: service.Namespace, Name: service.Name}] = address
+			used[address] = true
+		}
+	}
+	for _, service := range loadBalancers {
+		name := types.NamespacedName{Namespace: service.Namespace, Name: service.Name}
+		if _, ok := assigned[name]; ok {
+			continue
+		}
+
+		for _, address := range addresses {
+			if !used[address] {
+				assigned[name] = address
+				used[address] = true
+				break
+			}
+		}
+	}
+
+	return assigned
+}
+
+// desiredForwards returns the pod each TCP port of the assigned services is forwarded to. Ports without a ready pod
+// are not published.
+func desiredForwards(services []corev1.Service, endpointSlices []discoveryv1.EndpointSlice, assigned map[types.NamespacedName]string) map[forwardKey]forwardTarget {
+	desired := map[forwardKey]forwardTarget{}
+	for _, service := range services {
+		address, ok := assigned[types.NamespacedName{Namespace: service.Namespace, Name: service.Name}]
+		if !ok {
+			continue
+		}
+
+		for _, servicePort := range service.Spec.Ports {
+			if servicePort.Protocol != "" && servicePort.Protocol != corev1.ProtocolTCP {
+				continue
+			}
+
+			target, ok := readyEndpoint(service, servicePort, endpointSlices)
+			if ok {
+				desired[forwardKey{address: address, port: servicePort.Port}] = target
+			}
+		}
+	}
+
+	return desired
+}
+
+// readyEndpoint returns the first ready pod of the service port by name, so the same pod is used across syncs
+func readyEndpoint(service corev1.Service, servicePort corev1.ServicePort, endpointSlices []discoveryv1.EndpointSlice) (forwardTarget, bool) {
+	targets := []forwardTarget{}
+	for _, endpointSlice := range endpointSlices {
+		if endpointSlice.Namespace != service.Namespace || endpointSlice.Labels[discoveryv1.LabelServiceName] != service.Name {
+			continue
+		}
+
+		for _, port := range endpointSlice.Ports {
+			if port.Port == nil || ptr.Deref(port.Name, "") != servicePort.Name {
+				continue
+			}
+
+			for _, endpoint := range endpointSlice.Endpoints {
+				if endpoint.TargetRef == nil || endpoint.TargetRef.Kind != "Pod" || (endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready) {
+					continue
+				}
+
+				targets = append(targets, forwardTarget{namespace: service.Namespace, pod: endpoint.TargetRef.Name, port: *port.Port})
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return forwardTarget{}, false
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].pod < targets[j].pod
+	})
+	return targets[0], true
+}
+
+func statusAddress(service *corev1.Service) string {
+	if len(service.Status.LoadBalancer.Ingress) != 1 {
+		return ""
+	}
+
+	return service.Status.LoadBalancer.Ingress[0].IP
+}
@@ -0,0 +1,101 @@
+package connectdaemon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+)
+
+var (
+	loadBalancerAddresses = []string{"127.1.2.254", "127.1.2.253"}
+
+	cmpForwards = cmp.AllowUnexported(forwardKey{}, forwardTarget{})
+)
+
+func loadBalancerService(name string, created time.Time, statusIP string, ports ...corev1.ServicePort) corev1.Service {
+	service := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.NewTime(created)},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, Ports: ports},
+	}
+	if statusIP != "" {
+		service.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: statusIP}}
+	}
+
+	return service
+}
+
+func TestAssignAddresses(t *testing.T) {
+	now := time.Now()
+	services := []corev1.Service{
+		loadBalancerService("first", now, ""),
+		loadBalancerService("second", now.Add(time.Second), ""),
+		loadBalancerService("assigned", now.Add(time.Minute), "127.1.2.254"),
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-ip", Namespace: "default"}, Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP}},
+	}
+
+	// services keep their address, the others are assigned in the order they were created until none is left
+	assert.DeepEqual(t, assignAddresses(services, loadBalancerAddresses), map[types.NamespacedName]string{
+		{Namespace: "default", Name: "assigned"}: "127.1.2.254",
+		{Namespace: "default", Name: "first"}:    "127.1.2.253",
+	})
+}
+
+func TestDesiredForwards(t *testing.T) {
+	service := loadBalancerService("web", time.Now(), "",
+		corev1.ServicePort{Name: "http", Port: 80},
+		corev1.ServicePort{Name: "https", Port: 443},
+		corev1.ServicePort{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP},
+	)
+	endpointSlices := []discoveryv1.EndpointSlice{{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-abc", Namespace: "default", Labels: map[string]string{discoveryv1.LabelServiceName: "web"}},
+		Ports: []discoveryv1.EndpointPort{
+			{Name: ptr.To("http"), Port: ptr.To[int32](8080)},
+			{Name: ptr.To("https"), Port: ptr.To[int32](8443)},
+			{Name: ptr.To("dns"), Port: ptr.To[int32](5353)},
+		},
+		Endpoints: []discoveryv1.Endpoint{
+			{TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "web-b"}},
+			{TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "web-a"}, Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(false)}},
+			{TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "web-c"}, Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)}},
+		},
+	}}
+
+	desired := desiredForwards([]corev1.Service{service}, endpointSlices, map[types.NamespacedName]string{
+		{Namespace: "default", Name: "web"}: "127.1.2.254",
+	})
+	assert.DeepEqual(t, desired, map[forwardKey]forwardTarget{
+		{address: "127.1.2.254", port: 80}:  {namespace: "default", pod: "web-b", port: 8080},
+		{address: "127.1.2.254", port: 443}: {namespace: "default", pod: "web-b", port: 8443},
+	}, cmpForwards)
+
+	// ports without ready pods are not published
+	assert.Equal(t, len(desiredForwards([]corev1.Service{service}, nil, map[types.NamespacedName]string{{Namespace: "default", Name: "web"}: "127.1.2.254"})), 0)
+}
+
+func TestLoadBalancerForwarderStatus(t *testing.T) {
+	service := loadBalancerService("web", time.Now(), "", corev1.ServicePort{Name: "http", Port: 80})
+	kubeClient := fake.NewSimpleClientset(&service)
+
+	forwarder := &loadBalancerForwarder{
+		restConfig: &rest.Config{},
+		kubeClient: kubeClient,
+		addresses:  loadBalancerAddresses,
+		forwards:   map[forwardKey]*servicePortForward{},
+	}
+	assert.NilError(t, forwarder.sync(context.Background()))
+
+	updated, err := kubeClient.CoreV1().Services("default").Get(context.Background(), "web", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, updated.Status.LoadBalancer.Ingress, []corev1.LoadBalancerIngress{{IP: "127.1.2.254"}})
+	assert.Equal(t, len(forwarder.forwards), 0)
+}
@@ -0,0 +1,89 @@
+package connectdaemon
+
+import (
+	"path/filepath"
+	"time"
+)
+
+const (
+	socketFileName = "connect-daemon.sock"
+	logFileName    = "connect-daemon.log"
+)
+
+type Type string
+
+const (
+	// TypePortForward forwards a local port to the virtual cluster api server
+	TypePortForward Type = ""
+	// TypeLoadBalancer publishes the LoadBalancer services of a docker virtual cluster on local addresses
+	TypeLoadBalancer Type = "LoadBalancer"
+)
+
+type Status string
+
+const (
+	// StatusConnecting means the first port-forward of the connection is being established
+	StatusConnecting Status = "Connecting"
+	// StatusConnected means the port-forward is up and healthy
+	StatusConnected Status = "Connected"
+	// StatusReconnecting means the port-forward was lost, e.g. after a network change, and is being restarted
+	StatusReconnecting Status = "Reconnecting"
+)
+
+// Connection is a virtual cluster port-forward managed by the connect daemon
+type Connection struct {
+	// ID identifies the connection, it is the name of the kube context that points to the forwarded port
+	ID string `json:"id"`
+
+	// Type of the connection, defaults to a port-forward to the virtual cluster api server
+	Type Type `json:"type,omitempty"`
+
+	// Name and Namespace of the virtual cluster
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+
+	// Context is the host cluster kube context the virtual cluster runs in
+	Context string `json:"context"`
+
+	// Pod is the virtual cluster pod to forward to. If empty, the newest running virtual cluster pod is looked up on
+	// every reconnect. A pod set here is never replaced by another pod of the virtual cluster.
+	Pod string `json:"pod,omitempty"`
+
+	// Address and LocalPort the virtual cluster is forwarded to
+	Address   string `json:"address"`
+	LocalPort int    `json:"localPort"`
+
+	// RemotePort is the virtual cluster api server port within the pod
+	RemotePort int `json:"remotePort"`
+
+	// Addresses are the local addresses LoadBalancer services are published on, only used by load balancer connections
+	Addresses []string `json:"addresses,omitempty"`
+
+	Status     Status    `json:"status"`
+	LastError  string    `json:"lastError,omitempty"`
+	Reconnects int       `json:"reconnects"`
+	Created    time.Time `json:"created"`
+}
+
+// AddRequest asks the daemon to start forwarding the given connection
+type AddRequest struct {
+	Connection Connection `json:"connection"`
+
+	// KubeConfig is the minified and flattened host cluster kube config, so the daemon doesn't depend on the
+	// environment it was started with. Load balancer connections use the virtual cluster kube config instead.
+	KubeConfig []byte `json:"kubeConfig"`
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+// SocketPath returns the path of the daemon socket next to the vCluster CLI config
+func SocketPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), socketFileName)
+}
+
+// LogPath returns the path of the daemon log file next to the vCluster CLI config
+func LogPath(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), logFileName)
+}
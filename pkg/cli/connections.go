@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/pkg/cli/connectdaemon"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+type ListConnectionsOptions struct {
+	Output string
+}
+
+// ListConnections prints the virtual cluster port-forwards of the connect daemon
+func ListConnections(ctx context.Context, options *ListConnectionsOptions, globalFlags *flags.GlobalFlags, logger log.Logger) error {
+	connections := []connectdaemon.Connection{}
+	daemonClient := connectdaemon.NewClient(connectdaemon.SocketPath(globalFlags.Config))
+	if daemonClient.IsRunning(ctx) {
+		var err error
+		connections, err = daemonClient.List(ctx)
+		if err != nil {
+			return fmt.Errorf("list background connections: %w", err)
+		}
+	}
+
+	if options.Output == "json" {
+		bytes, err := json.MarshalIndent(connections, "", "    ")
+		if err != nil {
+			return fmt.Errorf("json marshal connections: %w", err)
+		}
+
+		logger.WriteString(logrus.InfoLevel, string(bytes)+"\n")
+		return nil
+	}
+
+	header := []string{"NAME", "NAMESPACE", "CONTEXT", "LOCAL ADDRESS", "STATUS", "RECONNECTS", "AGE", "LAST ERROR"}
+	table.PrintTable(logger, header, connectionsToValues(connections))
+	return nil
+}
+
+func connectionsToValues(connections []connectdaemon.Connection) [][]string {
+	var values [][]string
+	for _, connection := range connections {
+		address := connection.Address
+		if address == "" {
+			address = "localhost"
+		}
+		address = net.JoinHostPort(address, strconv.Itoa(connection.LocalPort))
+		if connection.Type == connectdaemon.TypeLoadBalancer && len(connection.Addresses) > 0 {
+			address = connection.Addresses[0] + "-" + connection.Addresses[len(connection.Addresses)-1]
+		}
+
+		lastError := ""
+		if connection.Status != connectdaemon.StatusConnected {
+			lastError = connection.LastError
+		}
+
+		values = append(values, []string{
+			connection.Name,
+			connection.Namespace,
+			connection.Context,
+			address,
+			string(connection.Status),
+			strconv.Itoa(connection.Reconnects),
+			duration.HumanDuration(time.Since(connection.Created)),
+			lastError,
+		})
+	}
+	return values
+}
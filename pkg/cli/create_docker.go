@@ -13,6 +13,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -133,7 +134,7 @@ func CreateDocker(ctx context.Context, options *CreateOptions, globalFlags *flag
 	}
 
 	// configure the network and update user values if needed
-	networkName, extraDockerArgs, err := configureNetwork(ctx, userValuesRaw, vClusterName, log)
+	networkName, extraDockerArgs, loadBalancerAddresses, err := configureNetwork(ctx, userValuesRaw, vClusterName, log)
 	if err != nil {
 		return fmt.Errorf("failed to configure network: %w", err)
 	}
@@ -187,6 +188,12 @@ func CreateDocker(ctx context.Context, options *CreateOptions, globalFlags *flag
 		return fmt.Errorf("write version: %w", err)
 	}
 
+	// Persist the local load balancer addresses so connect can publish the load balancer services on them.
+	err = writeLoadBalancerAddresses(vClusterConfigDir, loadBalancerAddresses)
+	if err != nil {
+		return err
+	}
+
 	// ensure the k8s resolv conf file
 	err = ensureK8sResolvConf(ctx, globalFlags, vClusterName, log)
 	if err != nil {
@@ -536,6 +543,39 @@ func canMountPrivilegedPort(ctx context.Context, log log.Logger) bool {
 	return true
 }
 
+// capNetBindService is the capability that allows binding ports below net.ipv4.ip_unprivileged_port_start
+const capNetBindService = 10
+
+// unprivilegedPortStart returns the first port this process can listen on without privileges or 0 if it can
+// listen on all ports. It reads the capabilities of the process and the sysctl instead of trying to bind a port.
+func unprivilegedPortStart() int {
+	status, err := os.ReadFile("/proc/self/status")
+	if err == nil {
+		for _, line := range strings.Split(string(status), "\n") {
+			capabilities, ok := strings.CutPrefix(line, "CapEff:")
+			if !ok {
+				continue
+			}
+
+			effective, err := strconv.ParseUint(strings.TrimSpace(capabilities), 16, 64)
+			if err == nil && effective&(1<<capNetBindService) != 0 {
+				return 0
+			}
+		}
+	}
+
+	out, err := os.ReadFile("/proc/sys/net/ipv4/ip_unprivileged_port_start")
+	if err != nil {
+		return 1024
+	}
+	port, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		return 1024
+	}
+
+	return port
+}
+
 func runControlPlaneContainer(ctx context.Context, kubernetesDir, vClusterBinaryDir, vClusterConfigDir, vClusterName, cpHostname, networkName string, config *config.Config, extraArgs []string, log log.Logger) error {
 	args := []string{
 		"run",
@@ -922,11 +962,13 @@ func loadUserValues(options *CreateOptions, globalFlags *flags.GlobalFlags, log
 	return userValuesMap, nil
 }
 
-func configureNetwork(ctx context.Context, fullConfigRaw map[string]interface{}, vClusterName string, log log.Logger) (string, []string, error) {
+// configureNetwork creates the docker network and returns its name, the extra docker args of the control plane and
+// the local addresses the connect daemon publishes the load balancer services on if vCluster can't publish them itself
+func configureNetwork(ctx context.Context, fullConfigRaw map[string]interface{}, vClusterName string, log log.Logger) (string, []string, []string, error) {
 	// convert the config to a config object
 	fullConfig, _, err := convertConfig(fullConfigRaw)
 	if err != nil {
-		return "", nil, fmt.Errorf("convert config: %w", err)
+		return "", nil, nil, fmt.Errorf("convert config: %w", err)
 	}
 
 	// get the network name
@@ -938,7 +980,7 @@ func configureNetwork(ctx context.Context, fullConfigRaw map[string]interface{},
 	// create the docker network
 	err = createNetwork(ctx, networkName, log)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to create network: %w", err)
+		return "", nil, nil, fmt.Errorf("failed to create network: %w", err)
 	}
 
 	// if the registry proxy is disabled, we don't need to mount the containerd socket
@@ -948,7 +990,7 @@ func configureNetwork(ctx context.Context, fullConfigRaw map[string]interface{},
 			log.Infof("Docker is using the non-containerd image store, please use containerd image store to use the docker daemon registry proxy. For more information, see https://docs.docker.com/engine/storage/containerd/")
 			err = unstructured.SetNestedField(fullConfigRaw, false, "experimental", "docker", "registryProxy", "enabled")
 			if err != nil {
-				return "", nil, fmt.Errorf("failed to set nested field: %w", err)
+				return "", nil, nil, fmt.Errorf("failed to set nested field: %w", err)
 			}
 		} else {
 			containerdSocketPath, err := getContainerdSocketPath(ctx)
@@ -956,7 +998,7 @@ func configureNetwork(ctx context.Context, fullConfigRaw map[string]interface{},
 				log.Infof("Containerd socket couldn't be found, disabling docker daemon registry proxy (%s)", err.Error())
 				err = unstructured.SetNestedField(fullConfigRaw, false, "experimental", "docker", "registryProxy", "enabled")
 				if err != nil {
-					return "", nil, fmt.Errorf("failed to set nested field: %w", err)
+					return "", nil, nil, fmt.Errorf("failed to set nested field: %w", err)
 				}
 			} else {
 				extraArgs = append(extraArgs, "--mount", fmt.Sprintf("type=bind,src=%s,dst=%s,ro", containerdSocketPath, constants.DockerContainerdSocketPath))
@@ -965,40 +1007,58 @@ func configureNetwork(ctx context.Context, fullConfigRaw map[string]interface{},
 	}
 
 	// if the load balancer is disabled, we don't need to mount the docker socket
+	var loadBalancerAddresses []string
 	if fullConfig.Experimental.Docker.LoadBalancer.Enabled {
-		loadBalancerArgs, err := configureLoadBalancer(ctx, fullConfigRaw, networkName, log)
+		var loadBalancerArgs []string
+		loadBalancerArgs, loadBalancerAddresses, err = configureLoadBalancer(ctx, fullConfigRaw, networkName, log)
 		if err != nil {
-			return "", nil, fmt.Errorf("failed to configure load balancer: %w", err)
+			return "", nil, nil, fmt.Errorf("failed to configure load balancer: %w", err)
 		}
 		extraArgs = append(extraArgs, loadBalancerArgs...)
 	}
 
-	return networkName, extraArgs, nil
+	return networkName, extraArgs, loadBalancerAddresses, nil
 }
 
-func configureLoadBalancer(ctx context.Context, fullConfigRaw map[string]interface{}, networkName string, log log.Logger) ([]string, error) {
+func configureLoadBalancer(ctx context.Context, fullConfigRaw map[string]interface{}, networkName string, log log.Logger) ([]string, []string, error) {
 	extraArgs := []string{}
 	reachable, err := isDockerNetworkReachable(ctx, networkName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check if docker network is reachable: %w", err)
+		return nil, nil, fmt.Errorf("failed to check if docker network is reachable: %w", err)
 	}
 
 	// if the docker network is reachable, we don't need to forward the ports
 	if reachable {
 		err = unstructured.SetNestedField(fullConfigRaw, false, "experimental", "docker", "loadBalancer", "forwardPorts")
 		if err != nil {
-			return nil, fmt.Errorf("failed to set nested field: %w", err)
+			return nil, nil, fmt.Errorf("failed to set nested field: %w", err)
+		}
+	} else if runtime.GOOS == "linux" {
+		// on linux the whole 127.0.0.0/8 range is routed to the loopback device, so the connect daemon publishes
+		// the load balancer services there without aliasing anything. This covers rootless docker, podman and wsl2.
+		// The load balancer containers of vCluster are disabled, as their ports couldn't be reached.
+		err = unstructured.SetNestedField(fullConfigRaw, false, "experimental", "docker", "loadBalancer", "enabled")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to set nested field: %w", err)
+		}
+
+		// rootless runtimes usually cannot bind privileged ports
+		if port := unprivilegedPortStart(); port > 0 {
+			log.Warnf("Load balancer services with ports below %d will not be reachable locally. To allow them, set net.ipv4.ip_unprivileged_port_start=0", port)
 		}
+
+		log.Infof("Load balancer services will be published locally on 127.0.0.0/8 addresses by vcluster connect, use `vcluster connections ls` to check the status")
+		return extraArgs, findLoopbackIPs(networkName, 10), nil
 	} else {
 		// this method only works on macos, where we can bind ip addresses to the loopback device
 		if runtime.GOOS != "darwin" {
 			err = unstructured.SetNestedField(fullConfigRaw, false, "experimental", "docker", "loadBalancer", "enabled")
 			if err != nil {
-				return nil, fmt.Errorf("failed to set nested field: %w", err)
+				return nil, nil, fmt.Errorf("failed to set nested field: %w", err)
 			}
 
-			log.Warnf("Load balancer type services are not supported inside the vCluster because the docker network is not reachable. Port-forwarding will not work. This is only supported on macOS")
-			return extraArgs, nil
+			log.Warnf("Load balancer type services are not supported inside the vCluster because the docker network is not reachable. Port-forwarding will not work. This is only supported on macOS and Linux")
+			return extraArgs, nil, nil
 		}
 
 		// check if privileged port helper is available
@@ -1006,17 +1066,17 @@ func configureLoadBalancer(ctx context.Context, fullConfigRaw map[string]interfa
 		if !canMountPrivilegedPort {
 			err = unstructured.SetNestedField(fullConfigRaw, false, "experimental", "docker", "loadBalancer", "enabled")
 			if err != nil {
-				return nil, fmt.Errorf("failed to set nested field: %w", err)
+				return nil, nil, fmt.Errorf("failed to set nested field: %w", err)
 			}
 
 			log.Warnf("Load balancer type services are not supported inside the vCluster because privileged port mapping is not allowed. If you are using Docker Desktop, please enable it in the Docker Desktop settings")
-			return extraArgs, nil
+			return extraArgs, nil, nil
 		}
 
 		// check if we can configure the loopback device to forward the ports
 		ips, err := findTailIPs(ctx, networkName, 10)
 		if err != nil {
-			return nil, fmt.Errorf("failed to find tail ips: %w", err)
+			return nil, nil, fmt.Errorf("failed to find tail ips: %w", err)
 		}
 		for _, ip := range ips {
 			out, err := exec.CommandContext(ctx, "ifconfig", "lo0", "alias", ip).CombinedOutput()
@@ -1024,14 +1084,14 @@ func configureLoadBalancer(ctx context.Context, fullConfigRaw map[string]interfa
 				if strings.Contains(string(out), "permission denied") {
 					err = unstructured.SetNestedField(fullConfigRaw, false, "experimental", "docker", "loadBalancer", "enabled")
 					if err != nil {
-						return nil, fmt.Errorf("failed to set nested field: %w", err)
+						return nil, nil, fmt.Errorf("failed to set nested field: %w", err)
 					}
 
 					log.Warnf("Load balancer type services are not supported inside the vCluster because this command was executed with insufficient privileges. To enable load balancer type services, run this command with sudo")
-					return extraArgs, nil
+					return extraArgs, nil, nil
 				}
 
-				return nil, fmt.Errorf("failed to add loopback alias: %s: %w", string(out), err)
+				return nil, nil, fmt.Errorf("failed to add loopback alias: %s: %w", string(out), err)
 			}
 		}
 	}
@@ -1039,10 +1099,10 @@ func configureLoadBalancer(ctx context.Context, fullConfigRaw map[string]interfa
 	// mount the docker socket
 	dockerSocketPath, err := getDockerSocketPath(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get docker socket path: %w", err)
+		return nil, nil, fmt.Errorf("failed to get docker socket path: %w", err)
 	}
 	extraArgs = append(extraArgs, "--mount", fmt.Sprintf("type=bind,src=%s,dst=%s,ro", dockerSocketPath, constants.DockerSocketPath))
-	return extraArgs, nil
+	return extraArgs, nil, nil
 }
 
 func convertConfig(userConfigRaw map[string]interface{}) (*config.Config, string, error) {
@@ -1274,6 +1334,21 @@ func findTailIPs(ctx context.Context, networkName string, tailSize int) ([]strin
 	return ips, nil
 }
 
+// findLoopbackIPs returns tailSize loopback ips for the given network. The /24 inside 127.0.0.0/8 is derived
+// from the network name, so multiple vClusters don't publish their load balancers on the same addresses.
+func findLoopbackIPs(networkName string, tailSize int) []string {
+	sum := hash.StringToNumber(networkName)
+
+	// avoid 127.0.0.0/24, which is commonly used by local services
+	second, third := byte(sum>>8)%254+1, byte(sum)
+	ips := []string{}
+	for i := 1; i <= tailSize && i < 254; i++ {
+		ips = append(ips, net.IPv4(127, second, third, byte(255-i)).String())
+	}
+
+	return ips
+}
+
 func getDockerSocketPath(ctx context.Context) (string, error) {
 	// Updated awk regex: /\/docker\.sock(\.real)?$/
 	// This matches paths ending in "/docker.sock" OR "/docker.sock.real"
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"net"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestFindLoopbackIPs(t *testing.T) {
+	ips := findLoopbackIPs("vcluster.my-cluster", 10)
+	assert.Equal(t, len(ips), 10)
+
+	seen := map[string]bool{}
+	for _, ip := range ips {
+		parsed := net.ParseIP(ip)
+		assert.Assert(t, parsed != nil, "invalid ip %s", ip)
+		assert.Assert(t, parsed.IsLoopback(), "ip %s is not a loopback ip", ip)
+		assert.Assert(t, !parsed.Equal(net.IPv4(127, 0, 0, 1)), "ip %s collides with localhost", ip)
+		assert.Assert(t, !seen[ip], "duplicate ip %s", ip)
+		seen[ip] = true
+	}
+
+	// same network should always result in the same ips
+	assert.DeepEqual(t, ips, findLoopbackIPs("vcluster.my-cluster", 10))
+
+	// other networks should use a different range
+	assert.Assert(t, ips[0] != findLoopbackIPs("vcluster.other-cluster", 10)[0])
+}
@@ -37,13 +37,13 @@ import (
 	"github.com/loft-sh/vcluster/pkg/cli/flags"
 	"github.com/loft-sh/vcluster/pkg/cli/localkubernetes"
 	pkgconfig "github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/config/advisors"
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/embed"
 	"github.com/loft-sh/vcluster/pkg/helm"
 	"github.com/loft-sh/vcluster/pkg/lifecycle"
 	"github.com/loft-sh/vcluster/pkg/platform"
 	platformclihelper "github.com/loft-sh/vcluster/pkg/platform/clihelper"
-	"github.com/loft-sh/vcluster/pkg/platform/sleepmode"
 	"github.com/loft-sh/vcluster/pkg/snapshot"
 	"github.com/loft-sh/vcluster/pkg/snapshot/pod"
 	"github.com/loft-sh/vcluster/pkg/telemetry"
@@ -81,6 +81,10 @@ type CreateOptions struct {
 	Connect              bool
 	Upgrade              bool
 
+	// RestoreFromHostNamespace is the host namespace the restored snapshot was taken in, if it differs from the
+	// namespace the virtual cluster is created in. It's set when moving a virtual cluster.
+	RestoreFromHostNamespace string
+
 	// Platform
 	Project         string
 	Cluster         string
@@ -358,17 +362,9 @@ func CreateHelm(ctx context.Context, options *CreateOptions, globalFlags *flags.
 	return nil
 }
 
-var advisors = map[string]func() (warning string){
-	"sleepMode":    sleepmode.Warning,
-	"platform":     config.WarningPlatform,
-	"autoDelete":   config.WarningAutoDelete,
-	"autoSleep":    config.WarningAutoSleep,
-	"autoSnapshot": config.WarningAutoSnapshot,
-}
-
 func confirmConfigIncompatibility(currentVClusterConfig *config.Config, currentValues string, log log.Logger) error {
 	if err := currentVClusterConfig.UnmarshalYAMLStrict([]byte(currentValues)); err != nil {
-		warning := config.ConfigStructureWarning(log, []byte(currentValues), advisors)
+		warning := config.ConfigStructureWarning(log, []byte(currentValues), advisors.Deprecation)
 		if warning == "" {
 			warning = "The current configuration is not compatible with the version you're upgrading to."
 		}
@@ -650,7 +646,11 @@ func (cmd *createHelm) deployChart(ctx context.Context, vClusterName, chartValue
 	// now restore if wanted
 	if cmd.Restore != "" {
 		cmd.log.Infof("Restore vCluster %s...", vClusterName)
-		err = Restore(ctx, []string{vClusterName, cmd.Restore}, cmd.GlobalFlags, &snapshotapi.Options{SnapshotTempDir: cmd.SnapshotTempDir}, &pod.Options{}, true, false, cmd.log)
+		podOptions := &pod.Options{}
+		if cmd.RestoreFromHostNamespace != "" {
+			podOptions.Env = []string{constants.VClusterRestoreFromHostNamespaceEnv + "=" + cmd.RestoreFromHostNamespace}
+		}
+		err = Restore(ctx, []string{vClusterName, cmd.Restore}, cmd.GlobalFlags, &snapshotapi.Options{SnapshotTempDir: cmd.SnapshotTempDir}, podOptions, true, false, cmd.log)
 		if err != nil {
 			// delete the vcluster if the restore failed
 			deleteErr := helmClient.Delete(vClusterName, cmd.Namespace)
@@ -92,6 +92,10 @@ func (cmd *deleteDocker) delete(ctx context.Context, platformClient platform.Cli
 	}
 
 	// delete the load balancers
+	err = stopLoadBalancerForwarding(ctx, cmd.GlobalFlags, vClusterName)
+	if err != nil {
+		return fmt.Errorf("failed to stop load balancer forwarding: %w", err)
+	}
 	loadBalancers, err := findDockerContainer(ctx, constants.DockerLoadBalancerPrefix+vClusterName+".")
 	if err != nil {
 		return fmt.Errorf("failed to find vCluster load balancers: %w", err)
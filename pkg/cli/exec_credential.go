@@ -0,0 +1,270 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/hash"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	utilkubeconfig "github.com/loft-sh/vcluster/pkg/util/kubeconfig"
+	"github.com/loft-sh/vcluster/pkg/util/serviceaccount"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	clientauthv1 "k8s.io/client-go/pkg/apis/clientauthentication/v1"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	execCredentialAPIVersion = "client.authentication.k8s.io/v1"
+	execCredentialKind       = "ExecCredential"
+	execInfoEnv              = "KUBERNETES_EXEC_INFO"
+
+	// DefaultExecCredentialExpiration is the token lifetime in seconds used by the exec credential plugin
+	// if no explicit expiration was configured.
+	DefaultExecCredentialExpiration = 3600
+
+	// execCredentialRefreshBefore defines how long before its expiry a cached token is renewed.
+	execCredentialRefreshBefore = time.Minute
+)
+
+// ExecCredentialOptions holds the options for the vcluster token exec credential plugin
+type ExecCredentialOptions struct {
+	ServiceAccount string
+	Expiration     int
+}
+
+// ExecCredential mints a short-lived service account token for the given virtual cluster and writes it as
+// client.authentication.k8s.io/v1 ExecCredential to out. Tokens are cached locally until shortly before they expire,
+// the admin credentials used to mint them are read from the host cluster each time and never written to disk.
+func ExecCredential(ctx context.Context, options *ExecCredentialOptions, globalFlags *flags.GlobalFlags, vClusterName string, out io.Writer, log log.Logger) error {
+	if options.ServiceAccount == "" {
+		return fmt.Errorf("please specify a service account via --service-account")
+	}
+	serviceAccountNamespace, serviceAccount, err := parseServiceAccount(options.ServiceAccount)
+	if err != nil {
+		return err
+	}
+
+	// check if we have a cached token that is still valid
+	cachePath := execCredentialCachePath(globalFlags, vClusterName, serviceAccountNamespace, serviceAccount)
+	status := readCachedExecCredential(cachePath, time.Now())
+	if status == nil {
+		status, err = mintExecCredential(ctx, options, globalFlags, vClusterName, serviceAccountNamespace, serviceAccount, log)
+		if err != nil {
+			return err
+		}
+
+		err = writeCachedExecCredential(cachePath, status)
+		if err != nil {
+			log.Debugf("Error caching token: %v", err)
+		}
+	}
+
+	return json.NewEncoder(out).Encode(&clientauthv1.ExecCredential{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: execCredentialAPIVersion,
+			Kind:       execCredentialKind,
+		},
+		Status: status,
+	})
+}
+
+func mintExecCredential(ctx context.Context, options *ExecCredentialOptions, globalFlags *flags.GlobalFlags, vClusterName, serviceAccountNamespace, serviceAccount string, log log.Logger) (*clientauthv1.ExecCredentialStatus, error) {
+	// the server we should talk to is passed by kubectl
+	cluster, err := execInfoCluster()
+	if err != nil {
+		return nil, err
+	}
+
+	// retrieve the vcluster admin credentials from the host cluster
+	authInfo, err := readExecCredentialAdmin(ctx, globalFlags, vClusterName, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return createExecCredentialToken(ctx, options, cluster, authInfo, serviceAccountNamespace, serviceAccount)
+}
+
+// readExecCredentialAdmin retrieves the admin credentials of the virtual cluster from the host cluster
+func readExecCredentialAdmin(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName string, log log.Logger) (*clientcmdapi.AuthInfo, error) {
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return nil, err
+	}
+	hostRestConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("load kube config: %w", err)
+	}
+	hostClient, err := kubernetes.NewForConfig(hostRestConfig)
+	if err != nil {
+		return nil, fmt.Errorf("create kube client: %w", err)
+	}
+	kubeConfig, err := utilkubeconfig.ReadKubeConfig(ctx, hostClient, vCluster.Name, vCluster.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	var authInfo *clientcmdapi.AuthInfo
+	for _, a := range kubeConfig.AuthInfos {
+		authInfo = a
+	}
+	if authInfo == nil {
+		return nil, errors.New("nil authConfig")
+	}
+
+	return &clientcmdapi.AuthInfo{
+		ClientCertificateData: authInfo.ClientCertificateData,
+		ClientKeyData:         authInfo.ClientKeyData,
+		Token:                 authInfo.Token,
+	}, nil
+}
+
+// createExecCredentialToken creates the service account token within the virtual cluster with the admin credentials
+func createExecCredentialToken(ctx context.Context, options *ExecCredentialOptions, cluster *clientauthv1.Cluster, authInfo *clientcmdapi.AuthInfo, serviceAccountNamespace, serviceAccount string) (*clientauthv1.ExecCredentialStatus, error) {
+	vKubeClient, err := kubernetes.NewForConfig(&rest.Config{
+		Host: cluster.Server,
+		TLSClientConfig: rest.TLSClientConfig{
+			Insecure:   cluster.InsecureSkipTLSVerify,
+			ServerName: cluster.TLSServerName,
+			CAData:     cluster.CertificateAuthorityData,
+			CertData:   authInfo.ClientCertificateData,
+			KeyData:    authInfo.ClientKeyData,
+		},
+		BearerToken: authInfo.Token,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create virtual cluster client: %w", err)
+	}
+
+	expirationSeconds := int64(DefaultExecCredentialExpiration)
+	if options.Expiration > 0 {
+		expirationSeconds = int64(options.Expiration)
+	}
+	result, err := vKubeClient.CoreV1().ServiceAccounts(serviceAccountNamespace).CreateToken(ctx, serviceAccount, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         serviceaccount.Audiences,
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("create service account token for %s/%s: %w", serviceAccountNamespace, serviceAccount, err)
+	}
+
+	return &clientauthv1.ExecCredentialStatus{
+		Token:               result.Status.Token,
+		ExpirationTimestamp: &result.Status.ExpirationTimestamp,
+	}, nil
+}
+
+// execInfoCluster returns the cluster information kubectl passes to the exec credential plugin
+func execInfoCluster() (*clientauthv1.Cluster, error) {
+	execInfo := os.Getenv(execInfoEnv)
+	if execInfo == "" {
+		return nil, fmt.Errorf("%s is not set, this command is meant to be used as kube config exec credential plugin", execInfoEnv)
+	}
+
+	execCredential := &clientauthv1.ExecCredential{}
+	err := json.Unmarshal([]byte(execInfo), execCredential)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", execInfoEnv, err)
+	} else if execCredential.Spec.Cluster == nil || execCredential.Spec.Cluster.Server == "" {
+		return nil, fmt.Errorf("%s is missing cluster information, make sure provideClusterInfo is enabled in the kube config", execInfoEnv)
+	}
+
+	return execCredential.Spec.Cluster, nil
+}
+
+func execCredentialCachePath(globalFlags *flags.GlobalFlags, vClusterName, serviceAccountNamespace, serviceAccount string) string {
+	key := strings.Join([]string{globalFlags.Context, globalFlags.Namespace, vClusterName, serviceAccountNamespace, serviceAccount}, "/")
+	return filepath.Join(filepath.Dir(globalFlags.Config), "tokens", hash.String(key)[:32]+".json")
+}
+
+// readCachedExecCredential returns the cached credential if it exists and is valid for at least execCredentialRefreshBefore
+func readCachedExecCredential(cachePath string, now time.Time) *clientauthv1.ExecCredentialStatus {
+	out, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil
+	}
+
+	status := &clientauthv1.ExecCredentialStatus{}
+	err = json.Unmarshal(out, status)
+	if err != nil || status.Token == "" || status.ExpirationTimestamp == nil {
+		return nil
+	} else if status.ExpirationTimestamp.Time.Before(now.Add(execCredentialRefreshBefore)) {
+		return nil
+	}
+
+	return status
+}
+
+func writeCachedExecCredential(cachePath string, status *clientauthv1.ExecCredentialStatus) error {
+	out, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(cachePath), 0700)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(cachePath, out, 0600)
+}
+
+// execCredentialAuthInfo returns an auth info that retrieves short-lived service account tokens
+// through vcluster token exec instead of embedding credentials into the kube config.
+func execCredentialAuthInfo(vClusterName string, options *ConnectOptions, globalFlags *flags.GlobalFlags) (*clientcmdapi.AuthInfo, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("find vcluster executable: %w", err)
+	}
+
+	expiration := options.ServiceAccountExpiration
+	if expiration <= 0 {
+		expiration = DefaultExecCredentialExpiration
+	}
+
+	args := []string{
+		"token", "exec", vClusterName,
+		"--namespace", globalFlags.Namespace,
+		"--service-account", options.ServiceAccount,
+		"--token-expiration", strconv.Itoa(expiration),
+	}
+	if globalFlags.Context != "" {
+		args = append(args, "--context", globalFlags.Context)
+	}
+
+	return &clientcmdapi.AuthInfo{
+		Exec: &clientcmdapi.ExecConfig{
+			APIVersion:         execCredentialAPIVersion,
+			Command:            executable,
+			Args:               args,
+			ProvideClusterInfo: true,
+			InteractiveMode:    clientcmdapi.NeverExecInteractiveMode,
+		},
+	}, nil
+}
+
+func parseServiceAccount(serviceAccountRef string) (string, string, error) {
+	if !strings.Contains(serviceAccountRef, "/") {
+		return "kube-system", serviceAccountRef, nil
+	}
+
+	splitted := strings.Split(serviceAccountRef, "/")
+	if len(splitted) != 2 {
+		return "", "", fmt.Errorf("unexpected service account reference, expected ServiceAccountNamespace/ServiceAccountName")
+	}
+
+	return splitted[0], splitted[1], nil
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientauthv1 "k8s.io/client-go/pkg/apis/clientauthentication/v1"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestExecCredentialCache(t *testing.T) {
+	now := time.Now()
+	cachePath := filepath.Join(t.TempDir(), "tokens", "test.json")
+
+	// nothing cached yet
+	assert.Assert(t, readCachedExecCredential(cachePath, now) == nil)
+
+	// valid token
+	err := writeCachedExecCredential(cachePath, &clientauthv1.ExecCredentialStatus{
+		Token:               "token",
+		ExpirationTimestamp: &metav1.Time{Time: now.Add(time.Hour)},
+	})
+	assert.NilError(t, err)
+	status := readCachedExecCredential(cachePath, now)
+	assert.Assert(t, status != nil)
+	assert.Equal(t, status.Token, "token")
+
+	// token about to expire
+	assert.Assert(t, readCachedExecCredential(cachePath, now.Add(time.Hour-execCredentialRefreshBefore/2)) == nil)
+}
+
+func TestExecCredentialCachePath(t *testing.T) {
+	globalFlags := &flags.GlobalFlags{Config: "/home/test/.vcluster/config.json", Context: "kind", Namespace: "vcluster-test"}
+
+	path := execCredentialCachePath(globalFlags, "test", "kube-system", "admin")
+	assert.Equal(t, filepath.Dir(path), "/home/test/.vcluster/tokens")
+	assert.Equal(t, path, execCredentialCachePath(globalFlags, "test", "kube-system", "admin"))
+	assert.Assert(t, path != execCredentialCachePath(globalFlags, "test", "default", "admin"))
+}
+
+func TestExecCredentialAuthInfo(t *testing.T) {
+	authInfo, err := execCredentialAuthInfo("test", &ConnectOptions{ServiceAccount: "default/admin"}, &flags.GlobalFlags{Context: "kind", Namespace: "vcluster-test"})
+	assert.NilError(t, err)
+	assert.Assert(t, authInfo.Exec != nil)
+	assert.Equal(t, authInfo.Exec.APIVersion, execCredentialAPIVersion)
+	assert.Equal(t, authInfo.Exec.InteractiveMode, clientcmdapi.NeverExecInteractiveMode)
+	assert.Assert(t, authInfo.Exec.ProvideClusterInfo)
+	assert.DeepEqual(t, authInfo.Exec.Args, []string{
+		"token", "exec", "test",
+		"--namespace", "vcluster-test",
+		"--service-account", "default/admin",
+		"--token-expiration", "3600",
+		"--context", "kind",
+	})
+}
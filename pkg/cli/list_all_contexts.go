@@ -0,0 +1,386 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/table"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/platform"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// DefaultListContextTimeout is the time vcluster list --all-contexts waits for a single kube context to respond.
+	DefaultListContextTimeout = 5 * time.Second
+
+	// listAllContextsCacheTTL defines how long the results of vcluster list --all-contexts are reused for shell completion.
+	listAllContextsCacheTTL = 30 * time.Second
+)
+
+// listAllContextsDrivers are the drivers searched by vcluster list --all-contexts in the order they are printed
+var listAllContextsDrivers = []config.DriverType{config.HelmDriver, config.DockerDriver, config.PlatformDriver}
+
+// ListAllContextsVCluster holds information about a virtual cluster found by vcluster list --all-contexts
+type ListAllContextsVCluster struct {
+	ListVCluster
+	Context string
+	Driver  config.DriverType
+	Project string `json:",omitempty"`
+}
+
+// listAllContextsCache is the on-disk representation of the last vcluster list --all-contexts results
+type listAllContextsCache struct {
+	Created   time.Time
+	VClusters []ListAllContextsVCluster
+}
+
+// ListAllContexts lists the virtual clusters of all kube contexts, the docker driver and the platform in parallel
+// and prints them as a single table or json list. Contexts that cannot be reached are printed as warnings.
+func ListAllContexts(ctx context.Context, options *ListOptions, globalFlags *flags.GlobalFlags, logger log.Logger) error {
+	rawConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return err
+	}
+
+	drivers := listAllContextsDrivers
+	if options.Driver != "" {
+		driverType, err := config.ParseDriverType(options.Driver)
+		if err != nil {
+			return fmt.Errorf("parse driver type: %w", err)
+		}
+		drivers = []config.DriverType{driverType}
+	}
+
+	timeout := options.ContextTimeout
+	if timeout <= 0 {
+		timeout = DefaultListContextTimeout
+	}
+
+	namespace := metav1.NamespaceAll
+	if globalFlags.Namespace != "" {
+		namespace = globalFlags.Namespace
+	}
+
+	var (
+		m         sync.Mutex
+		wg        sync.WaitGroup
+		vClusters []ListAllContextsVCluster
+		warnings  []string
+	)
+	collect := func(found []ListAllContextsVCluster, warning string) {
+		m.Lock()
+		defer m.Unlock()
+
+		vClusters = append(vClusters, found...)
+		if warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	if slices.Contains(drivers, config.HelmDriver) {
+		for _, kubeContext := range hostContexts(&rawConfig) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				found, err := listHelmContext(ctx, kubeContext, namespace, rawConfig.CurrentContext, timeout)
+				if err != nil {
+					collect(nil, fmt.Sprintf("Context %s is unreachable: %v", kubeContext, err))
+					return
+				}
+				collect(found, "")
+			}()
+		}
+	}
+	if slices.Contains(drivers, config.DockerDriver) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			found, err := listDockerContext(ctx, rawConfig.CurrentContext, timeout)
+			if err != nil {
+				logger.Debugf("Skipping docker driver: %v", err)
+				return
+			}
+			collect(found, "")
+		}()
+	}
+	if slices.Contains(drivers, config.PlatformDriver) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			platformClient, err := platform.InitClientFromConfig(ctx, globalFlags.LoadedConfig(logger))
+			if err != nil {
+				logger.Debugf("Skipping platform driver: %v", err)
+				return
+			}
+
+			found, err := listPlatformContext(ctx, platformClient, rawConfig.CurrentContext, timeout)
+			if err != nil {
+				collect(nil, fmt.Sprintf("Platform %s is unreachable: %v", platformClient.Config().Platform.Host, err))
+				return
+			}
+			collect(found, "")
+		}()
+	}
+	wg.Wait()
+
+	// helm contexts are already listed in the namespace, the docker driver and the platform list all virtual clusters
+	vClusters = filterListAllContextsNamespace(vClusters, namespace)
+	sortListAllContextsVClusters(vClusters)
+	sort.Strings(warnings)
+
+	// only cache complete results, so shell completion never misses virtual clusters
+	if options.Driver == "" && namespace == metav1.NamespaceAll && len(warnings) == 0 {
+		err = writeListAllContextsCache(listAllContextsCachePath(globalFlags), vClusters, time.Now())
+		if err != nil {
+			logger.Debugf("Error caching virtual clusters: %v", err)
+		}
+	}
+
+	if options.Output == "json" {
+		if vClusters == nil {
+			vClusters = []ListAllContextsVCluster{}
+		}
+		bytes, err := json.MarshalIndent(vClusters, "", "    ")
+		if err != nil {
+			return fmt.Errorf("json marshal vClusters: %w", err)
+		}
+
+		logger.WriteString(logrus.InfoLevel, string(bytes)+"\n")
+	} else {
+		header := []string{"NAME", "NAMESPACE", "CONTEXT", "DRIVER", "PROJECT", "STATUS", "VERSION", "CONNECTED", "AGE"}
+		table.PrintTable(logger, header, listAllContextsToValues(vClusters))
+	}
+
+	// warnings go to stderr, so they don't break the json output
+	for _, warning := range warnings {
+		logger.ErrorStreamOnly().Warn(warning)
+	}
+
+	return nil
+}
+
+// CachedVClusterNames returns the names of the helm virtual clusters in the given kube context and namespace that were
+// found by a recent vcluster list --all-contexts. It returns false if there are no recent results.
+func CachedVClusterNames(globalFlags *flags.GlobalFlags, kubeContext, namespace string) ([]string, bool) {
+	vClusters, ok := readListAllContextsCache(listAllContextsCachePath(globalFlags), time.Now())
+	if !ok {
+		return nil, false
+	}
+
+	names := []string{}
+	for _, vCluster := range vClusters {
+		if vCluster.Driver != config.HelmDriver || vCluster.Context != kubeContext {
+			continue
+		} else if namespace != "" && vCluster.Namespace != namespace {
+			continue
+		}
+
+		names = append(names, vCluster.Name)
+	}
+
+	return names, true
+}
+
+// hostContexts returns the sorted kube contexts to search. Contexts created by vcluster connect are skipped, as
+// the virtual clusters are already found through their parent context.
+func hostContexts(rawConfig *clientcmdapi.Config) []string {
+	kubeContexts := []string{}
+	for kubeContext := range rawConfig.Contexts {
+		if strings.HasPrefix(kubeContext, "vcluster_") || strings.HasPrefix(kubeContext, "vcluster-platform_") || strings.HasPrefix(kubeContext, "vcluster-docker_") {
+			continue
+		}
+
+		kubeContexts = append(kubeContexts, kubeContext)
+	}
+
+	sort.Strings(kubeContexts)
+	return kubeContexts
+}
+
+func listHelmContext(ctx context.Context, kubeContext, namespace, currentContext string, timeout time.Duration) ([]ListAllContextsVCluster, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	kubeClient, err := find.CreateKubeClient(kubeContext)
+	if err != nil {
+		return nil, err
+	}
+
+	vClusters, err := find.ListOSSVClusters(ctx, kubeClient, kubeContext, "", namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var output []ListAllContextsVCluster
+	for _, vCluster := range ossToVClusters(vClusters, currentContext) {
+		output = append(output, ListAllContextsVCluster{
+			ListVCluster: vCluster,
+			Context:      kubeContext,
+			Driver:       config.HelmDriver,
+		})
+	}
+	return output, nil
+}
+
+func listDockerContext(ctx context.Context, currentContext string, timeout time.Duration) ([]ListAllContextsVCluster, error) {
+	_, err := exec.LookPath("docker")
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	vClusters, err := findDockerContainer(ctx, constants.DockerControlPlanePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var output []ListAllContextsVCluster
+	for _, vCluster := range vClusters {
+		output = append(output, ListAllContextsVCluster{
+			ListVCluster: ListVCluster{
+				Name:       vCluster.Name,
+				Namespace:  "docker",
+				Status:     vCluster.Status,
+				Created:    vCluster.Created,
+				AgeSeconds: int(time.Since(vCluster.Created).Round(time.Second).Seconds()),
+				Connected:  currentContext == "vcluster-docker_"+vCluster.Name,
+			},
+			Driver: config.DockerDriver,
+		})
+	}
+	return output, nil
+}
+
+func listPlatformContext(ctx context.Context, platformClient platform.Client, currentContext string, timeout time.Duration) ([]ListAllContextsVCluster, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	proVClusters, err := platform.ListVClusters(ctx, platformClient, "", "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	var output []ListAllContextsVCluster
+	for _, vCluster := range proToVClusters(proVClusters, currentContext) {
+		output = append(output, ListAllContextsVCluster{
+			ListVCluster: vCluster.ListVCluster,
+			Driver:       config.PlatformDriver,
+			Project:      vCluster.Project,
+		})
+	}
+	return output, nil
+}
+
+// filterListAllContextsNamespace returns the virtual clusters in the given namespace. Virtual clusters of the docker
+// driver are listed in the namespace docker.
+func filterListAllContextsNamespace(vClusters []ListAllContextsVCluster, namespace string) []ListAllContextsVCluster {
+	if namespace == metav1.NamespaceAll {
+		return vClusters
+	}
+
+	return slices.DeleteFunc(vClusters, func(vCluster ListAllContextsVCluster) bool {
+		return vCluster.Namespace != namespace
+	})
+}
+
+func sortListAllContextsVClusters(vClusters []ListAllContextsVCluster) {
+	sort.SliceStable(vClusters, func(i, j int) bool {
+		a, b := vClusters[i], vClusters[j]
+		if a.Driver != b.Driver {
+			return slices.Index(listAllContextsDrivers, a.Driver) < slices.Index(listAllContextsDrivers, b.Driver)
+		} else if a.Context != b.Context {
+			return a.Context < b.Context
+		} else if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+}
+
+func listAllContextsToValues(vClusters []ListAllContextsVCluster) [][]string {
+	var values [][]string
+	for _, vCluster := range vClusters {
+		isConnected := ""
+		if vCluster.Connected {
+			isConnected = "True"
+		}
+
+		age := ""
+		if !vCluster.Created.IsZero() {
+			age = duration.HumanDuration(time.Since(vCluster.Created))
+		}
+
+		values = append(values, []string{
+			vCluster.Name,
+			vCluster.Namespace,
+			vCluster.Context,
+			string(vCluster.Driver),
+			vCluster.Project,
+			vCluster.Status,
+			vCluster.Version,
+			isConnected,
+			age,
+		})
+	}
+	return values
+}
+
+func listAllContextsCachePath(globalFlags *flags.GlobalFlags) string {
+	return filepath.Join(filepath.Dir(globalFlags.Config), "cache", "list-all-contexts.json")
+}
+
+// readListAllContextsCache returns the cached virtual clusters if they exist and are younger than listAllContextsCacheTTL
+func readListAllContextsCache(cachePath string, now time.Time) ([]ListAllContextsVCluster, bool) {
+	out, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil, false
+	}
+
+	cache := &listAllContextsCache{}
+	err = json.Unmarshal(out, cache)
+	if err != nil || cache.Created.Add(listAllContextsCacheTTL).Before(now) {
+		return nil, false
+	}
+
+	return cache.VClusters, true
+}
+
+func writeListAllContextsCache(cachePath string, vClusters []ListAllContextsVCluster, now time.Time) error {
+	out, err := json.Marshal(&listAllContextsCache{
+		Created:   now,
+		VClusters: vClusters,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(cachePath), 0700)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(cachePath, out, 0600)
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"gotest.tools/v3/assert"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestHostContexts(t *testing.T) {
+	rawConfig := &clientcmdapi.Config{
+		Contexts: map[string]*clientcmdapi.Context{
+			"prod-eu":                           {},
+			"kind":                              {},
+			"vcluster_test_vcluster-test_kind":  {},
+			"vcluster-platform_test_default_pf": {},
+			"vcluster-docker_test":              {},
+		},
+	}
+
+	assert.DeepEqual(t, hostContexts(rawConfig), []string{"kind", "prod-eu"})
+}
+
+func TestSortListAllContextsVClusters(t *testing.T) {
+	vClusters := []ListAllContextsVCluster{
+		{ListVCluster: ListVCluster{Name: "a"}, Driver: config.PlatformDriver, Project: "default"},
+		{ListVCluster: ListVCluster{Name: "b", Namespace: "docker"}, Driver: config.DockerDriver},
+		{ListVCluster: ListVCluster{Name: "c", Namespace: "test"}, Context: "prod-eu", Driver: config.HelmDriver},
+		{ListVCluster: ListVCluster{Name: "d", Namespace: "test"}, Context: "kind", Driver: config.HelmDriver},
+		{ListVCluster: ListVCluster{Name: "a", Namespace: "test"}, Context: "kind", Driver: config.HelmDriver},
+	}
+
+	sortListAllContextsVClusters(vClusters)
+	names := []string{}
+	for _, vCluster := range vClusters {
+		names = append(names, vCluster.Context+"/"+vCluster.Name)
+	}
+	assert.DeepEqual(t, names, []string{"kind/a", "kind/d", "prod-eu/c", "/b", "/a"})
+}
+
+func TestFilterListAllContextsNamespace(t *testing.T) {
+	vClusters := []ListAllContextsVCluster{
+		{ListVCluster: ListVCluster{Name: "a", Namespace: "team-a"}, Driver: config.PlatformDriver, Project: "default"},
+		{ListVCluster: ListVCluster{Name: "b", Namespace: "docker"}, Driver: config.DockerDriver},
+		{ListVCluster: ListVCluster{Name: "c", Namespace: "team-a"}, Context: "kind", Driver: config.HelmDriver},
+	}
+
+	assert.Equal(t, len(filterListAllContextsNamespace(slices.Clone(vClusters), "")), 3)
+
+	names := []string{}
+	for _, vCluster := range filterListAllContextsNamespace(slices.Clone(vClusters), "team-a") {
+		names = append(names, vCluster.Name)
+	}
+	assert.DeepEqual(t, names, []string{"a", "c"})
+}
+
+func TestListAllContextsCache(t *testing.T) {
+	now := time.Now()
+	globalFlags := &flags.GlobalFlags{Config: filepath.Join(t.TempDir(), "config.json")}
+	cachePath := listAllContextsCachePath(globalFlags)
+
+	// nothing cached yet
+	_, ok := CachedVClusterNames(globalFlags, "kind", "")
+	assert.Assert(t, !ok)
+
+	err := writeListAllContextsCache(cachePath, []ListAllContextsVCluster{
+		{ListVCluster: ListVCluster{Name: "a", Namespace: "team-a"}, Context: "kind", Driver: config.HelmDriver},
+		{ListVCluster: ListVCluster{Name: "b", Namespace: "team-b"}, Context: "kind", Driver: config.HelmDriver},
+		{ListVCluster: ListVCluster{Name: "c", Namespace: "team-a"}, Context: "prod-eu", Driver: config.HelmDriver},
+		{ListVCluster: ListVCluster{Name: "d", Namespace: "docker"}, Driver: config.DockerDriver},
+	}, now)
+	assert.NilError(t, err)
+
+	names, ok := CachedVClusterNames(globalFlags, "kind", "")
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, names, []string{"a", "b"})
+
+	names, ok = CachedVClusterNames(globalFlags, "kind", "team-b")
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, names, []string{"b"})
+
+	// cache expired
+	_, ok = readListAllContextsCache(cachePath, now.Add(listAllContextsCacheTTL+time.Second))
+	assert.Assert(t, !ok)
+}
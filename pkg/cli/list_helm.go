@@ -39,6 +39,9 @@ type ListOptions struct {
 	Driver string
 
 	Output string
+
+	AllContexts    bool
+	ContextTimeout time.Duration
 }
 
 func ListHelm(ctx context.Context, options *ListOptions, globalFlags *flags.GlobalFlags, log log.Logger) error {
@@ -0,0 +1,364 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	snapshotapi "github.com/loft-sh/api/v4/pkg/snapshot"
+	"github.com/loft-sh/log"
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"github.com/loft-sh/vcluster/pkg/lifecycle"
+	setupconfig "github.com/loft-sh/vcluster/pkg/setup/config"
+	"github.com/loft-sh/vcluster/pkg/snapshot/pod"
+	"github.com/loft-sh/vcluster/pkg/util/helmdownloader"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+type MigrateBackingStoreOptions struct {
+	To string
+
+	ChartName    string
+	ChartRepo    string
+	ChartVersion string
+
+	Values    []string
+	SetValues []string
+
+	Pod pod.Options
+}
+
+// MigrationTargets are the backing store types a vCluster can be migrated to
+var MigrationTargets = []vclusterconfig.StoreType{
+	vclusterconfig.StoreTypeEmbeddedDatabase,
+	vclusterconfig.StoreTypeExternalDatabase,
+	vclusterconfig.StoreTypeEmbeddedEtcd,
+	vclusterconfig.StoreTypeExternalEtcd,
+	vclusterconfig.StoreTypeDeployedEtcd,
+}
+
+var backingStoreValuePaths = map[vclusterconfig.StoreType][]string{
+	vclusterconfig.StoreTypeEmbeddedDatabase: {"controlPlane", "backingStore", "database", "embedded", "enabled"},
+	vclusterconfig.StoreTypeExternalDatabase: {"controlPlane", "backingStore", "database", "external", "enabled"},
+	vclusterconfig.StoreTypeEmbeddedEtcd:     {"controlPlane", "backingStore", "etcd", "embedded", "enabled"},
+	vclusterconfig.StoreTypeExternalEtcd:     {"controlPlane", "backingStore", "etcd", "external", "enabled"},
+	vclusterconfig.StoreTypeDeployedEtcd:     {"controlPlane", "backingStore", "etcd", "deploy", "enabled"},
+}
+
+func MigrateBackingStoreHelm(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterName string, options *MigrateBackingStoreOptions, log log.Logger) error {
+	targetType := vclusterconfig.StoreType(options.To)
+	if !slices.Contains(MigrationTargets, targetType) {
+		return fmt.Errorf("unsupported backing store %q, choose one of %v", options.To, MigrationTargets)
+	}
+
+	// find vcluster
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	} else if vCluster.IsStandalone {
+		return fmt.Errorf("migrating the backing store of a standalone vCluster is not supported")
+	}
+
+	// build kubernetes client
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	rawConfig, err := vCluster.ClientFactory.RawConfig()
+	if err != nil {
+		return err
+	}
+
+	// get the current values of the release
+	helmBinaryPath, err := helmdownloader.GetHelmBinaryPath(ctx, log)
+	if err != nil {
+		return err
+	}
+	helmClient := helm.NewClient(&rawConfig, log, helmBinaryPath)
+	userValues, err := helmClient.GetValues(ctx, vCluster.Name, vCluster.Namespace, false)
+	if err != nil {
+		return err
+	}
+	allValues, err := helmClient.GetValues(ctx, vCluster.Name, vCluster.Namespace, true)
+	if err != nil {
+		return err
+	}
+	userValues, allValues = trimValuesHeader(userValues), trimValuesHeader(allValues)
+
+	// determine the source store and the values to upgrade with
+	sourceConfig := &vclusterconfig.Config{}
+	err = yaml.Unmarshal(allValues, sourceConfig)
+	if err != nil {
+		return fmt.Errorf("parse vCluster config: %w", err)
+	}
+	sourceType := sourceConfig.BackingStoreType()
+	if sourceType == targetType {
+		return fmt.Errorf("vCluster %s already uses %s as backing store", vCluster.Name, sourceType)
+	} else if (targetType == vclusterconfig.StoreTypeEmbeddedDatabase || targetType == vclusterconfig.StoreTypeEmbeddedEtcd) && (vCluster.StatefulSet == nil || len(vCluster.StatefulSet.Spec.VolumeClaimTemplates) == 0) {
+		return fmt.Errorf("migrating to %s requires a vCluster with a persistent volume, but vCluster %s has none", targetType, vCluster.Name)
+	}
+
+	upgradeValues, _, err := migrationValues(string(userValues), options, sourceType, targetType)
+	if err != nil {
+		return err
+	}
+	_, targetConfig, err := migrationValues(string(allValues), options, sourceType, targetType)
+	if err != nil {
+		return err
+	}
+	err = validateMigrationTarget(targetConfig.ControlPlane.BackingStore, targetType)
+	if err != nil {
+		return err
+	}
+	rawBackingStore, err := json.Marshal(targetConfig.ControlPlane.BackingStore)
+	if err != nil {
+		return err
+	}
+	rawSourceBackingStore, err := json.Marshal(sourceConfig.ControlPlane.BackingStore)
+	if err != nil {
+		return err
+	}
+	chartVersion := options.ChartVersion
+	if chartVersion == "" {
+		chartVersion = vCluster.Version
+	}
+	upgradeOptions := helm.UpgradeOptions{
+		Chart:   options.ChartName,
+		Repo:    options.ChartRepo,
+		Version: chartVersion,
+		Values:  upgradeValues,
+		Debug:   globalFlags.Debug,
+	}
+
+	// deploying etcd already upgrades the release, which has to be reverted to the current values if the migration fails
+	var revertOptions *helm.UpgradeOptions
+	if targetType == vclusterconfig.StoreTypeDeployedEtcd {
+		revertOptions = &helm.UpgradeOptions{
+			Chart:   options.ChartName,
+			Repo:    options.ChartRepo,
+			Version: vCluster.Version,
+			Values:  string(userValues),
+			Debug:   globalFlags.Debug,
+		}
+	}
+
+	// pause vCluster
+	log.Infof("Pausing vCluster %s", vCluster.Name)
+	err = pauseVCluster(ctx, kubeClient, vCluster, log)
+	if err != nil {
+		return fmt.Errorf("pause vCluster %s: %w", vCluster.Name, err)
+	}
+
+	// try to scale up the vCluster again
+	defer func() {
+		log.Infof("Resuming vCluster %s after it was paused", vCluster.Name)
+		err := lifecycle.ResumeVCluster(ctx, kubeClient, vCluster.Name, vCluster.Namespace, true, log)
+		if err != nil {
+			log.Warnf("Error resuming vCluster %s: %v", vCluster.Name, err)
+		}
+	}()
+
+	// deployed etcd is created by the chart, so it has to be installed before the keys can be copied
+	if targetType == vclusterconfig.StoreTypeDeployedEtcd {
+		log.Infof("Deploying etcd for vCluster %s", vCluster.Name)
+		err = deployEtcd(ctx, helmClient, kubeClient, vCluster, upgradeOptions)
+		if err != nil {
+			rollbackMigration(ctx, helmClient, kubeClient, vCluster, sourceType, revertOptions, log)
+			return err
+		}
+	}
+
+	// copy the keys from the current into the new store. The backing stores may contain credentials, so they are
+	// passed to the pod through a secret.
+	log.Infof("Migrating backing store of vCluster %s from %s to %s", vCluster.Name, sourceType, targetType)
+	podOptions := options.Pod
+	podOptions.SecretEnv = map[string]string{
+		constants.VClusterMigrateBackingStoreEnv:       string(rawBackingStore),
+		constants.VClusterMigrateBackingStoreSourceEnv: string(rawSourceBackingStore),
+	}
+	err = pod.RunSnapshotPod(ctx, restConfig, kubeClient, []string{"/vcluster", "migrate-backing-store"}, vCluster, &podOptions, &snapshotapi.Options{}, log)
+	if err != nil {
+		rollbackMigration(ctx, helmClient, kubeClient, vCluster, sourceType, revertOptions, log)
+		return fmt.Errorf("migrate backing store: %w", err)
+	}
+
+	// the syncer refuses to start if the store changes without the annotation being updated
+	err = setupconfig.UpdateSecretAnnotations(ctx, kubeClient, vCluster.Name, vCluster.Namespace, targetType)
+	if err != nil {
+		rollbackMigration(ctx, helmClient, kubeClient, vCluster, sourceType, revertOptions, log)
+		return fmt.Errorf("update backing store annotation: %w", err)
+	}
+
+	// switch the config of the vCluster to the new store
+	log.Infof("Upgrading vCluster %s to use %s", vCluster.Name, targetType)
+	err = helmClient.Upgrade(ctx, vCluster.Name, vCluster.Namespace, upgradeOptions)
+	if err != nil {
+		// the source store was not modified, so the vCluster can resume with it
+		rollbackMigration(ctx, helmClient, kubeClient, vCluster, sourceType, revertOptions, log)
+		return fmt.Errorf("upgrade vCluster %s: %w", vCluster.Name, err)
+	}
+
+	log.Donef("Successfully migrated the backing store of vCluster %s from %s to %s", vCluster.Name, sourceType, targetType)
+	return nil
+}
+
+// deployEtcd upgrades the vCluster to the given values with its control plane scaled to zero replicas, so the chart
+// creates the etcd statefulset without starting the vCluster on the still empty store, and waits until etcd is ready.
+func deployEtcd(ctx context.Context, helmClient helm.Client, kubeClient kubernetes.Interface, vCluster *find.VCluster, upgradeOptions helm.UpgradeOptions) error {
+	valuesMap, err := parseString(upgradeOptions.Values)
+	if err != nil {
+		return err
+	}
+	if valuesMap == nil {
+		valuesMap = map[string]interface{}{}
+	}
+	err = unstructured.SetNestedField(valuesMap, int64(0), "controlPlane", "statefulSet", "highAvailability", "replicas")
+	if err != nil {
+		return fmt.Errorf("set replicas: %w", err)
+	}
+	values, err := yaml.Marshal(valuesMap)
+	if err != nil {
+		return err
+	}
+
+	upgradeOptions.Values = string(values)
+	err = helmClient.Upgrade(ctx, vCluster.Name, vCluster.Namespace, upgradeOptions)
+	if err != nil {
+		return fmt.Errorf("deploy etcd for vCluster %s: %w", vCluster.Name, err)
+	}
+
+	etcdName := vCluster.Name + "-etcd"
+	err = wait.PollUntilContextTimeout(ctx, time.Second, time.Minute*5, true, func(ctx context.Context) (bool, error) {
+		statefulSet, err := kubeClient.AppsV1().StatefulSets(vCluster.Namespace).Get(ctx, etcdName, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+
+		return statefulSet.Spec.Replicas != nil && statefulSet.Status.ReadyReplicas == *statefulSet.Spec.Replicas, nil
+	})
+	if err != nil {
+		return fmt.Errorf("wait for etcd statefulset %s: %w", etcdName, err)
+	}
+
+	return nil
+}
+
+// rollbackMigration reverts the store annotation and, if revertOptions are given, upgrades the release back to its
+// previous values, so the vCluster resumes with its unmodified source store.
+func rollbackMigration(ctx context.Context, helmClient helm.Client, kubeClient kubernetes.Interface, vCluster *find.VCluster, sourceType vclusterconfig.StoreType, revertOptions *helm.UpgradeOptions, log log.Logger) {
+	ctx = context.WithoutCancel(ctx)
+	err := setupconfig.UpdateSecretAnnotations(ctx, kubeClient, vCluster.Name, vCluster.Namespace, sourceType)
+	if err != nil {
+		log.Warnf("Error reverting backing store annotation: %v", err)
+	}
+
+	if revertOptions != nil {
+		log.Infof("Reverting vCluster %s to %s", vCluster.Name, sourceType)
+		err = helmClient.Upgrade(ctx, vCluster.Name, vCluster.Namespace, *revertOptions)
+		if err != nil {
+			log.Warnf("Error reverting vCluster %s: %v", vCluster.Name, err)
+		}
+	}
+}
+
+// migrationValues merges the given values with the values files and set values of the options and switches the
+// backing store to targetType. Returns the values as yaml and the resulting config.
+func migrationValues(values string, options *MigrateBackingStoreOptions, sourceType, targetType vclusterconfig.StoreType) (string, *vclusterconfig.Config, error) {
+	mergedValues, err := mergeAllValues(options.SetValues, options.Values, values)
+	if err != nil {
+		return "", nil, err
+	}
+
+	valuesMap, err := parseString(mergedValues)
+	if err != nil {
+		return "", nil, err
+	}
+	if valuesMap == nil {
+		valuesMap = map[string]interface{}{}
+	}
+	err = setBackingStoreValues(valuesMap, sourceType, targetType)
+	if err != nil {
+		return "", nil, err
+	}
+
+	out, err := yaml.Marshal(valuesMap)
+	if err != nil {
+		return "", nil, err
+	}
+
+	config := &vclusterconfig.Config{}
+	err = yaml.Unmarshal(out, config)
+	if err != nil {
+		return "", nil, fmt.Errorf("parse vCluster config: %w", err)
+	}
+
+	return string(out), config, nil
+}
+
+// setBackingStoreValues enables targetType as the only backing store in the given helm values. The persistent
+// volume of the vCluster is kept if the source store needed it, so the old data stays available.
+func setBackingStoreValues(values map[string]interface{}, sourceType, targetType vclusterconfig.StoreType) error {
+	for storeType, path := range backingStoreValuePaths {
+		err := unstructured.SetNestedField(values, storeType == targetType, path...)
+		if err != nil {
+			return fmt.Errorf("set %v: %w", path, err)
+		}
+	}
+
+	if sourceType == vclusterconfig.StoreTypeEmbeddedDatabase || sourceType == vclusterconfig.StoreTypeEmbeddedEtcd {
+		persistencePath := []string{"controlPlane", "statefulSet", "persistence", "volumeClaim", "enabled"}
+		enabled, found, _ := unstructured.NestedFieldNoCopy(values, persistencePath...)
+		if !found || fmt.Sprint(enabled) == "auto" {
+			err := unstructured.SetNestedField(values, true, persistencePath...)
+			if err != nil {
+				return fmt.Errorf("set %v: %w", persistencePath, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// trimValuesHeader removes the header helm get values prints before the values
+func trimValuesHeader(values []byte) []byte {
+	firstLine, rest, _ := strings.Cut(string(values), "\n")
+	if strings.HasSuffix(strings.TrimSpace(firstLine), "VALUES:") {
+		return []byte(rest)
+	}
+
+	return values
+}
+
+func validateMigrationTarget(backingStore vclusterconfig.BackingStore, targetType vclusterconfig.StoreType) error {
+	switch targetType {
+	case vclusterconfig.StoreTypeExternalDatabase:
+		if backingStore.Database.External.DataSource == "" && backingStore.Database.External.Connector == "" {
+			return fmt.Errorf("controlPlane.backingStore.database.external.dataSource or connector is required to migrate to %s", targetType)
+		}
+	case vclusterconfig.StoreTypeExternalEtcd:
+		if backingStore.Etcd.External.Endpoint == "" {
+			return fmt.Errorf("controlPlane.backingStore.etcd.external.endpoint is required to migrate to %s", targetType)
+		}
+	default:
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/helm"
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+)
+
+type fakeHelmClient struct {
+	helm.Client
+
+	upgrades []helm.UpgradeOptions
+}
+
+func (f *fakeHelmClient) Upgrade(_ context.Context, _, _ string, options helm.UpgradeOptions) error {
+	f.upgrades = append(f.upgrades, options)
+	return nil
+}
+
+func TestMigrationValues(t *testing.T) {
+	testCases := []struct {
+		name       string
+		values     string
+		options    MigrateBackingStoreOptions
+		sourceType vclusterconfig.StoreType
+		targetType vclusterconfig.StoreType
+
+		expectedPersistence interface{}
+		expectedDataSource  string
+	}{
+		{
+			name:                "sqlite to embedded etcd",
+			values:              "USER-SUPPLIED VALUES:\nnull\n",
+			sourceType:          vclusterconfig.StoreTypeEmbeddedDatabase,
+			targetType:          vclusterconfig.StoreTypeEmbeddedEtcd,
+			expectedPersistence: true,
+		},
+		{
+			name: "sqlite to external database",
+			values: `controlPlane:
+  backingStore:
+    database:
+      embedded:
+        enabled: true
+`,
+			options:             MigrateBackingStoreOptions{SetValues: []string{"controlPlane.backingStore.database.external.dataSource=postgres://db"}},
+			sourceType:          vclusterconfig.StoreTypeEmbeddedDatabase,
+			targetType:          vclusterconfig.StoreTypeExternalDatabase,
+			expectedPersistence: true,
+			expectedDataSource:  "postgres://db",
+		},
+		{
+			name: "explicit persistence is kept",
+			values: `controlPlane:
+  statefulSet:
+    persistence:
+      volumeClaim:
+        enabled: false
+`,
+			sourceType:          vclusterconfig.StoreTypeEmbeddedEtcd,
+			targetType:          vclusterconfig.StoreTypeExternalEtcd,
+			expectedPersistence: false,
+		},
+		{
+			name: "external etcd to sqlite",
+			values: `controlPlane:
+  backingStore:
+    etcd:
+      external:
+        enabled: true
+        endpoint: my-etcd:2379
+`,
+			sourceType: vclusterconfig.StoreTypeExternalEtcd,
+			targetType: vclusterconfig.StoreTypeEmbeddedDatabase,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, config, err := migrationValues(string(trimValuesHeader([]byte(tc.values))), &tc.options, tc.sourceType, tc.targetType)
+			assert.NilError(t, err)
+			assert.Equal(t, config.BackingStoreType(), tc.targetType)
+			assert.Equal(t, config.ControlPlane.BackingStore.Database.External.DataSource, tc.expectedDataSource)
+
+			valuesMap, err := parseString(values)
+			assert.NilError(t, err)
+			persistence := valuesMap["controlPlane"].(map[string]interface{})["statefulSet"]
+			if tc.expectedPersistence == nil {
+				assert.Assert(t, persistence == nil)
+			} else {
+				assert.Equal(t, persistence.(map[string]interface{})["persistence"].(map[string]interface{})["volumeClaim"].(map[string]interface{})["enabled"], tc.expectedPersistence)
+			}
+		})
+	}
+}
+
+func TestValidateMigrationTarget(t *testing.T) {
+	backingStore := vclusterconfig.BackingStore{}
+	assert.ErrorContains(t, validateMigrationTarget(backingStore, vclusterconfig.StoreTypeExternalDatabase), "dataSource or connector is required")
+	assert.ErrorContains(t, validateMigrationTarget(backingStore, vclusterconfig.StoreTypeExternalEtcd), "endpoint is required")
+	assert.NilError(t, validateMigrationTarget(backingStore, vclusterconfig.StoreTypeEmbeddedEtcd))
+
+	backingStore.Etcd.External.Endpoint = "my-etcd:2379"
+	assert.NilError(t, validateMigrationTarget(backingStore, vclusterconfig.StoreTypeExternalEtcd))
+}
+
+func TestDeployEtcd(t *testing.T) {
+	etcdStatefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-etcd", Namespace: "vcluster-test"},
+		Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To(int32(1))},
+		Status:     appsv1.StatefulSetStatus{ReadyReplicas: 1},
+	}
+	kubeClient := fake.NewSimpleClientset(etcdStatefulSet)
+	helmClient := &fakeHelmClient{}
+	vCluster := &find.VCluster{Name: "test", Namespace: "vcluster-test"}
+
+	values, _, err := migrationValues("", &MigrateBackingStoreOptions{}, vclusterconfig.StoreTypeEmbeddedDatabase, vclusterconfig.StoreTypeDeployedEtcd)
+	assert.NilError(t, err)
+	err = deployEtcd(context.Background(), helmClient, kubeClient, vCluster, helm.UpgradeOptions{Chart: "vcluster", Values: values})
+	assert.NilError(t, err)
+	assert.Equal(t, len(helmClient.upgrades), 1)
+
+	// etcd is deployed, but the vCluster must not start on the empty store
+	upgradeConfig := &vclusterconfig.Config{}
+	assert.NilError(t, yaml.Unmarshal([]byte(helmClient.upgrades[0].Values), upgradeConfig))
+	assert.Equal(t, upgradeConfig.BackingStoreType(), vclusterconfig.StoreTypeDeployedEtcd)
+	valuesMap, err := parseString(helmClient.upgrades[0].Values)
+	assert.NilError(t, err)
+	replicas, found, _ := unstructured.NestedFieldNoCopy(valuesMap, "controlPlane", "statefulSet", "highAvailability", "replicas")
+	assert.Assert(t, found)
+	assert.Equal(t, fmt.Sprint(replicas), "0")
+}
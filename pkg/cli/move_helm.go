@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"time"
+
+	snapshotapi "github.com/loft-sh/api/v4/pkg/snapshot"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/lifecycle"
+	"github.com/loft-sh/vcluster/pkg/platform"
+	"github.com/loft-sh/vcluster/pkg/snapshot"
+	"github.com/loft-sh/vcluster/pkg/snapshot/pod"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+// MoveOptions holds the move cmd options
+type MoveOptions struct {
+	Driver string
+
+	ToNamespace string
+	ToContext   string
+
+	// SnapshotURL is where the snapshot used to move the virtual cluster is stored. The target host cluster has
+	// to be able to read it.
+	SnapshotURL     string
+	SnapshotTempDir string
+
+	// KeepSource keeps the paused source virtual cluster after the move instead of deleting it
+	KeepSource bool
+	Timeout    time.Duration
+
+	Pod pod.Options
+}
+
+// MoveHelm moves a virtual cluster to another host namespace or host cluster. The virtual cluster is paused,
+// snapshotted and restored into the target. The source is only deleted once the target is running, if anything fails
+// before that the target is removed and the source is resumed.
+func MoveHelm(ctx context.Context, platformClient platform.Client, options *MoveOptions, globalFlags *flags.GlobalFlags, vClusterName string, log log.Logger) error {
+	source, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	} else if source.IsStandalone {
+		return fmt.Errorf("cannot move standalone vCluster %s", vClusterName)
+	}
+
+	targetContext := cmp.Or(options.ToContext, source.Context)
+	targetNamespace := cmp.Or(options.ToNamespace, source.Namespace)
+	if targetContext == source.Context && targetNamespace == source.Namespace {
+		return fmt.Errorf("vCluster %s already runs in namespace %s of context %s, please specify a different --to-namespace or --to-context", vClusterName, source.Namespace, source.Context)
+	}
+
+	err = validateMoveSnapshotURL(options.SnapshotURL)
+	if err != nil {
+		return err
+	}
+
+	// make sure the target namespace is free
+	targetVClusters, err := find.ListVClusters(ctx, targetContext, "", targetNamespace, log.ErrorStreamOnly())
+	if err != nil {
+		return fmt.Errorf("list virtual clusters in context %s: %w", targetContext, err)
+	} else if len(targetVClusters) > 0 {
+		return fmt.Errorf("there is already a virtual cluster in namespace %s of context %s", targetNamespace, targetContext)
+	}
+
+	sourceFlags := *globalFlags
+	sourceFlags.Context = source.Context
+	sourceFlags.Namespace = source.Namespace
+	targetFlags := *globalFlags
+	targetFlags.Context = targetContext
+	targetFlags.Namespace = targetNamespace
+
+	// pause the source first, so nothing changes after the snapshot is taken
+	sourceClient, err := preparePause(source, &sourceFlags)
+	if err != nil {
+		return err
+	}
+	log.Infof("Pausing vCluster %s/%s...", source.Namespace, vClusterName)
+	err = PauseVCluster(ctx, sourceClient, source, log)
+	if err != nil {
+		return fmt.Errorf("pause vCluster %s: %w", vClusterName, err)
+	}
+
+	// take the snapshot
+	log.Infof("Taking snapshot of vCluster %s/%s to %s...", source.Namespace, vClusterName, options.SnapshotURL)
+	err = createPausedSnapshot(ctx, []string{vClusterName, options.SnapshotURL}, &sourceFlags, &snapshotapi.Options{SnapshotTempDir: options.SnapshotTempDir}, &options.Pod, log)
+	if err != nil {
+		resumeMoveSource(ctx, source, sourceClient, log)
+		return fmt.Errorf("snapshot vCluster %s: %w", vClusterName, err)
+	}
+
+	// create the target from the snapshot
+	log.Infof("Creating vCluster %s in namespace %s of context %s from snapshot...", vClusterName, targetNamespace, targetContext)
+	err = CreateHelm(ctx, &CreateOptions{
+		ChartName:                "vcluster",
+		ChartRepo:                constants.LoftChartRepo,
+		CreateNamespace:          true,
+		Restore:                  options.SnapshotURL,
+		SnapshotTempDir:          options.SnapshotTempDir,
+		RestoreFromHostNamespace: source.Namespace,
+	}, &targetFlags, vClusterName, log)
+	if err == nil {
+		err = waitForVClusterRunning(ctx, targetContext, vClusterName, targetNamespace, options.Timeout)
+	}
+	if err != nil {
+		rollbackMove(ctx, source, sourceClient, &targetFlags, log)
+		return fmt.Errorf("move vCluster %s: %w", vClusterName, err)
+	}
+
+	if options.KeepSource {
+		log.Donef("Successfully moved vCluster %s to namespace %s of context %s. The source vCluster is kept paused, delete it with 'vcluster delete %s --namespace %s --context %s'", vClusterName, targetNamespace, targetContext, vClusterName, source.Namespace, source.Context)
+		return nil
+	}
+
+	log.Infof("Deleting source vCluster %s/%s...", source.Namespace, vClusterName)
+	err = DeleteHelm(ctx, platformClient, &DeleteOptions{Wait: true, IgnoreNotFound: true}, &sourceFlags, vClusterName, log)
+	if err != nil {
+		return fmt.Errorf("delete source vCluster %s/%s, the vCluster was moved successfully: %w", source.Namespace, vClusterName, err)
+	}
+
+	log.Donef("Successfully moved vCluster %s to namespace %s of context %s", vClusterName, targetNamespace, targetContext)
+	return nil
+}
+
+// validateMoveSnapshotURL makes sure the snapshot can be read from the target. A container snapshot lives in the
+// source vCluster's volume and isn't reachable from another namespace or host cluster.
+func validateMoveSnapshotURL(snapshotURL string) error {
+	if snapshotURL == "" {
+		return fmt.Errorf("please specify where to store the snapshot with --snapshot-url, e.g. oci://ghcr.io/my-user/my-repo:my-tag or s3://my-bucket/my-key")
+	}
+
+	snapshotOptions := &snapshotapi.Options{}
+	err := snapshot.Parse(snapshotURL, snapshotOptions)
+	if err != nil {
+		return fmt.Errorf("parse snapshot url: %w", err)
+	} else if snapshotOptions.Type == "container" {
+		return fmt.Errorf("cannot move a vCluster through a container snapshot, please use an oci, s3 or azure snapshot url")
+	}
+
+	return nil
+}
+
+func waitForVClusterRunning(ctx context.Context, kubeContext, vClusterName, namespace string, timeout time.Duration) error {
+	var lastStatus find.Status
+	err := wait.PollUntilContextTimeout(ctx, time.Second*2, timeout, true, func(ctx context.Context) (bool, error) {
+		vCluster, err := find.GetVCluster(ctx, kubeContext, vClusterName, namespace, log.Discard)
+		if err != nil {
+			return false, nil
+		}
+
+		lastStatus = vCluster.Status
+		return vCluster.IsRunning(), nil
+	})
+	if err != nil {
+		return fmt.Errorf("wait for vCluster %s in namespace %s to become running (last status %q): %w", vClusterName, namespace, lastStatus, err)
+	}
+
+	return nil
+}
+
+// rollbackMove deletes the partially created target and resumes the source vCluster
+func rollbackMove(ctx context.Context, source *find.VCluster, sourceClient *kubernetes.Clientset, targetFlags *flags.GlobalFlags, log log.Logger) {
+	log.Warnf("Moving vCluster %s failed, rolling back...", source.Name)
+	err := DeleteHelm(ctx, nil, &DeleteOptions{Wait: true, IgnoreNotFound: true}, targetFlags, source.Name, log)
+	if err != nil {
+		log.Errorf("Failed to delete vCluster %s in namespace %s of context %s: %v", source.Name, targetFlags.Namespace, targetFlags.Context, err)
+	}
+
+	resumeMoveSource(ctx, source, sourceClient, log)
+}
+
+// resumeMoveSource resumes the paused source vCluster
+func resumeMoveSource(ctx context.Context, source *find.VCluster, sourceClient *kubernetes.Clientset, log log.Logger) {
+	err := lifecycle.ResumeVCluster(ctx, sourceClient, source.Name, source.Namespace, false, log)
+	if err != nil {
+		log.Errorf("Failed to resume vCluster %s/%s, please resume it with 'vcluster resume %s --namespace %s --context %s': %v", source.Namespace, source.Name, source.Name, source.Namespace, source.Context, err)
+	}
+}
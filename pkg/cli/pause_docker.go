@@ -49,6 +49,10 @@ func PauseDocker(ctx context.Context, globalFlags *flags.GlobalFlags, vClusterNa
 	}
 
 	// stop the load balancers
+	err = stopLoadBalancerForwarding(ctx, globalFlags, vClusterName)
+	if err != nil {
+		return fmt.Errorf("failed to stop load balancer forwarding: %w", err)
+	}
 	loadBalancers, err := findDockerContainer(ctx, constants.DockerLoadBalancerPrefix+vClusterName+".")
 	if err != nil {
 		return fmt.Errorf("failed to find vCluster load balancers: %w", err)
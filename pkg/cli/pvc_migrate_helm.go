@@ -0,0 +1,676 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	snapshotapi "github.com/loft-sh/api/v4/pkg/snapshot"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/find"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/util/clihelper"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+)
+
+// DefaultPVCMigrateImage is the image of the job that copies the data between the host volumes
+const DefaultPVCMigrateImage = "alpine:3.20"
+
+// pvcBindAnnotations are set by the host cluster while binding a claim and must not be carried over to the new claim
+var pvcBindAnnotations = []string{
+	"pv.kubernetes.io/bind-completed",
+	"pv.kubernetes.io/bound-by-controller",
+	"volume.beta.kubernetes.io/storage-provisioner",
+	"volume.kubernetes.io/storage-provisioner",
+	"volume.kubernetes.io/selected-node",
+	"volume.beta.kubernetes.io/storage-class",
+}
+
+// PVCMigrateOptions holds the pvc migrate cmd options
+type PVCMigrateOptions struct {
+	StorageClass string
+	Image        string
+
+	KeepOldVolume bool
+
+	Timeout time.Duration
+}
+
+// pvcMigration holds the state of a single claim migration
+type pvcMigration struct {
+	options *PVCMigrateOptions
+	log     log.Logger
+
+	hostClient    kubernetes.Interface
+	virtualClient kubernetes.Interface
+
+	hostNamespace    string
+	hostStorageClass string
+	skipTranslation  bool
+	vClusterName     string
+
+	virtualClaim *corev1.PersistentVolumeClaim
+	hostClaim    *corev1.PersistentVolumeClaim
+	scaledDown   []scaledWorkload
+}
+
+// scaledWorkload is a workload that was scaled down to release the claim
+type scaledWorkload struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Replicas  int32
+}
+
+// PVCMigrateHelm moves the data of a virtual persistent volume claim to a volume of another storage class. Workloads
+// using the claim are scaled down, the data is copied by a job in the host namespace into a new volume and the virtual
+// claim is recreated with the same name bound to the new volume, so the host claim keeps its name as well. Workloads
+// are scaled up again afterwards.
+func PVCMigrateHelm(ctx context.Context, options *PVCMigrateOptions, globalFlags *flags.GlobalFlags, vClusterName, claim string, log log.Logger) error {
+	vNamespace, vName, ok := strings.Cut(claim, "/")
+	if !ok || vNamespace == "" || vName == "" {
+		return fmt.Errorf("invalid persistent volume claim %q, expected NAMESPACE/NAME", claim)
+	} else if options.StorageClass == "" {
+		return fmt.Errorf("please specify the storage class to migrate to via --storage-class")
+	}
+
+	vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+	if err != nil {
+		return err
+	} else if vCluster.IsStandalone {
+		return fmt.Errorf("cannot migrate persistent volume claims of standalone vCluster %s", vClusterName)
+	} else if !vCluster.IsRunning() {
+		return fmt.Errorf("vCluster %s is %s, it has to be running to migrate persistent volume claims", vClusterName, vCluster.Status)
+	}
+
+	restConfig, err := vCluster.ClientFactory.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("load kube config of context %s: %w", vCluster.Context, err)
+	}
+	hostClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	vClusterConfig, err := getVClusterConfig(ctx, vCluster, hostClient, &snapshotapi.Options{})
+	if err != nil {
+		return err
+	} else if vClusterConfig.Sync.ToHost.Namespaces.Enabled {
+		return fmt.Errorf("migrating persistent volume claims is not supported if namespaces are synced to the host cluster")
+	}
+
+	vRestConfig, err := clihelper.GetVClusterKubeConfig(ctx, restConfig, hostClient, vCluster, log, clihelper.PortForwardingOptions{StdOut: io.Discard, StdErr: io.Discard})
+	if err != nil {
+		return fmt.Errorf("get virtual cluster config: %w", err)
+	}
+	virtualClient, err := kubernetes.NewForConfig(vRestConfig)
+	if err != nil {
+		return fmt.Errorf("create virtual cluster client: %w", err)
+	}
+
+	// storage classes synced to the host are renamed like all other cluster scoped objects
+	hostStorageClass := options.StorageClass
+	if vClusterConfig.Sync.ToHost.StorageClasses.Enabled {
+		hostStorageClass = translate.SafeConcatName("vcluster", options.StorageClass, "x", vCluster.Namespace, "x", vCluster.Name)
+	}
+
+	migration := &pvcMigration{
+		options:          options,
+		log:              log,
+		hostClient:       hostClient,
+		virtualClient:    virtualClient,
+		hostNamespace:    vCluster.Namespace,
+		hostStorageClass: hostStorageClass,
+		// real persistent volumes are renamed in the host cluster, so the new volume name has to be used as is
+		skipTranslation: vClusterConfig.Sync.ToHost.PersistentVolumes.Enabled,
+		vClusterName:    vCluster.Name,
+	}
+	err = migration.run(ctx, vNamespace, vName)
+	if err != nil {
+		return fmt.Errorf("migrate persistent volume claim %s: %w", claim, err)
+	}
+
+	log.Donef("Successfully migrated persistent volume claim %s to storage class %s", claim, options.StorageClass)
+	return nil
+}
+
+func (m *pvcMigration) run(ctx context.Context, vNamespace, vName string) (retErr error) {
+	var err error
+	m.virtualClaim, err = m.virtualClient.CoreV1().PersistentVolumeClaims(vNamespace).Get(ctx, vName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	} else if m.virtualClaim.Spec.VolumeMode != nil && *m.virtualClaim.Spec.VolumeMode == corev1.PersistentVolumeBlock {
+		return fmt.Errorf("block volumes are not supported")
+	} else if ptr.Deref(m.virtualClaim.Spec.StorageClassName, "") == m.options.StorageClass {
+		return fmt.Errorf("persistent volume claim already uses storage class %s", m.options.StorageClass)
+	}
+
+	_, err = m.hostClient.StorageV1().StorageClasses().Get(ctx, m.hostStorageClass, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get storage class %s in host cluster: %w", m.hostStorageClass, err)
+	}
+
+	m.hostClaim, err = m.findHostClaim(ctx)
+	if err != nil {
+		return err
+	} else if m.hostClaim.Status.Phase != corev1.ClaimBound || m.hostClaim.Spec.VolumeName == "" {
+		return fmt.Errorf("host persistent volume claim %s/%s is not bound", m.hostClaim.Namespace, m.hostClaim.Name)
+	}
+
+	// release the claim, so the data doesn't change while it is copied
+	workloads, err := claimWorkloads(ctx, m.virtualClient, vNamespace, vName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// scale up even if the migration was interrupted, otherwise the workloads stay down
+		scaleErr := m.scaleUp(context.WithoutCancel(ctx))
+		if retErr == nil {
+			retErr = scaleErr
+		}
+	}()
+	err = m.scaleDown(ctx, workloads)
+	if err != nil {
+		return err
+	}
+
+	newVolume, reclaimPolicy, err := m.copyData(ctx)
+	if err != nil {
+		return err
+	}
+
+	return m.swap(ctx, newVolume, reclaimPolicy)
+}
+
+// findHostClaim returns the host claim of the virtual claim
+func (m *pvcMigration) findHostClaim(ctx context.Context) (*corev1.PersistentVolumeClaim, error) {
+	claims, err := m.hostClient.CoreV1().PersistentVolumeClaims(m.hostNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: translate.MarkerLabel + "=" + m.vClusterName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list host persistent volume claims: %w", err)
+	}
+
+	for i := range claims.Items {
+		annotations := claims.Items[i].Annotations
+		if annotations[translate.NameAnnotation] == m.virtualClaim.Name && annotations[translate.NamespaceAnnotation] == m.virtualClaim.Namespace {
+			return &claims.Items[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("couldn't find the host persistent volume claim of %s/%s in namespace %s", m.virtualClaim.Namespace, m.virtualClaim.Name, m.hostNamespace)
+}
+
+// claimWorkloads returns the deployments and statefulsets of the pods using the claim. Pods that are not managed by
+// one of these can't be stopped and started again safely, so they have to be removed by the user first.
+func claimWorkloads(ctx context.Context, client kubernetes.Interface, namespace, claimName string) ([]scaledWorkload, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list pods: %w", err)
+	}
+
+	workloads := []scaledWorkload{}
+	seen := map[string]bool{}
+	for _, pod := range pods.Items {
+		if !usesClaim(&pod, claimName) {
+			continue
+		}
+
+		workload, err := podWorkload(ctx, client, &pod)
+		if err != nil {
+			return nil, err
+		} else if seen[workload.Kind+"/"+workload.Name] {
+			continue
+		}
+
+		seen[workload.Kind+"/"+workload.Name] = true
+		workloads = append(workloads, workload)
+	}
+
+	return workloads, nil
+}
+
+func podWorkload(ctx context.Context, client kubernetes.Interface, pod *corev1.Pod) (scaledWorkload, error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner != nil && owner.Kind == "StatefulSet" {
+		return scaledWorkload{Kind: owner.Kind, Namespace: pod.Namespace, Name: owner.Name}, nil
+	} else if owner != nil && owner.Kind == "ReplicaSet" {
+		replicaSet, err := client.AppsV1().ReplicaSets(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+		if err != nil {
+			return scaledWorkload{}, fmt.Errorf("get replica set %s/%s: %w", pod.Namespace, owner.Name, err)
+		}
+
+		deployment := metav1.GetControllerOf(replicaSet)
+		if deployment != nil && deployment.Kind == "Deployment" {
+			return scaledWorkload{Kind: deployment.Kind, Namespace: pod.Namespace, Name: deployment.Name}, nil
+		}
+	}
+
+	return scaledWorkload{}, fmt.Errorf("pod %s/%s uses the persistent volume claim and is not managed by a deployment or statefulset, please delete it first", pod.Namespace, pod.Name)
+}
+
+func usesClaim(pod *corev1.Pod, claimName string) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == claimName {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *pvcMigration) scaleDown(ctx context.Context, workloads []scaledWorkload) error {
+	for _, workload := range workloads {
+		m.log.Infof("Scale down %s %s/%s...", workload.Kind, workload.Namespace, workload.Name)
+		replicas, err := scaleWorkload(ctx, m.virtualClient, workload, 0)
+		if err != nil {
+			return err
+		}
+
+		workload.Replicas = replicas
+		m.scaledDown = append(m.scaledDown, workload)
+	}
+
+	err := wait.PollUntilContextTimeout(ctx, time.Second*2, m.options.Timeout, true, func(ctx context.Context) (bool, error) {
+		pods, err := m.virtualClient.CoreV1().Pods(m.virtualClaim.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		for i := range pods.Items {
+			if usesClaim(&pods.Items[i], m.virtualClaim.Name) {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("wait for pods using the persistent volume claim to terminate: %w", err)
+	}
+
+	return nil
+}
+
+func (m *pvcMigration) scaleUp(ctx context.Context) error {
+	var errs []error
+	for _, workload := range m.scaledDown {
+		m.log.Infof("Scale up %s %s/%s to %d replicas...", workload.Kind, workload.Namespace, workload.Name, workload.Replicas)
+		_, err := scaleWorkload(ctx, m.virtualClient, workload, workload.Replicas)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("scale %s %s/%s back to %d replicas: %w", workload.Kind, workload.Namespace, workload.Name, workload.Replicas, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// scaleWorkload sets the replicas of the workload and returns the previous replicas
+func scaleWorkload(ctx context.Context, client kubernetes.Interface, workload scaledWorkload, replicas int32) (int32, error) {
+	var (
+		scale *autoscalingv1.Scale
+		err   error
+	)
+	switch workload.Kind {
+	case "Deployment":
+		scale, err = client.AppsV1().Deployments(workload.Namespace).GetScale(ctx, workload.Name, metav1.GetOptions{})
+	case "StatefulSet":
+		scale, err = client.AppsV1().StatefulSets(workload.Namespace).GetScale(ctx, workload.Name, metav1.GetOptions{})
+	default:
+		return 0, fmt.Errorf("unsupported workload kind %s", workload.Kind)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	previous := scale.Spec.Replicas
+	scale.Spec.Replicas = replicas
+	if workload.Kind == "Deployment" {
+		_, err = client.AppsV1().Deployments(workload.Namespace).UpdateScale(ctx, workload.Name, scale, metav1.UpdateOptions{})
+	} else {
+		_, err = client.AppsV1().StatefulSets(workload.Namespace).UpdateScale(ctx, workload.Name, scale, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return previous, nil
+}
+
+// copyData copies the data of the host claim into a new volume of the target storage class and returns the name and
+// the original reclaim policy of the new volume. If the copy fails, the temporary claim, its volume and the copy job are
+// removed again.
+func (m *pvcMigration) copyData(ctx context.Context) (_ string, _ corev1.PersistentVolumeReclaimPolicy, retErr error) {
+	size := m.hostClaim.Spec.Resources.Requests[corev1.ResourceStorage]
+	if capacity, ok := m.hostClaim.Status.Capacity[corev1.ResourceStorage]; ok && capacity.Cmp(size) > 0 {
+		size = capacity
+	}
+
+	// the target claim isn't marked as managed by the vCluster, so the syncer leaves it alone
+	target := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      translate.SafeConcatName(m.hostClaim.Name, "migrate"),
+			Namespace: m.hostNamespace,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      m.hostClaim.Spec.AccessModes,
+			StorageClassName: &m.hostStorageClass,
+			VolumeMode:       m.hostClaim.Spec.VolumeMode,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+			},
+		},
+	}
+	m.log.Infof("Create persistent volume claim %s/%s with storage class %s...", target.Namespace, target.Name, m.hostStorageClass)
+	_, err := m.hostClient.CoreV1().PersistentVolumeClaims(target.Namespace).Create(ctx, target, metav1.CreateOptions{})
+	if err != nil {
+		return "", "", fmt.Errorf("create persistent volume claim %s/%s: %w", target.Namespace, target.Name, err)
+	}
+
+	job := copyJob(m.hostClaim.Name, target.Name, m.hostNamespace, m.options.Image)
+	targetName, volumeName := target.Name, ""
+	reclaimPolicy := corev1.PersistentVolumeReclaimPolicy("")
+	defer func() {
+		if retErr != nil {
+			m.cleanupCopy(context.WithoutCancel(ctx), job, targetName, volumeName, reclaimPolicy)
+		}
+	}()
+
+	m.log.Infof("Copy data with job %s/%s...", job.Namespace, job.Name)
+	_, err = m.hostClient.BatchV1().Jobs(job.Namespace).Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return "", "", fmt.Errorf("create copy job %s/%s: %w", job.Namespace, job.Name, err)
+	}
+	err = waitForJob(ctx, m.hostClient, job.Namespace, job.Name, m.options.Timeout)
+	if err != nil {
+		// the job is removed again, so print its logs before
+		logs := jobLogs(context.WithoutCancel(ctx), m.hostClient, job.Namespace, job.Name)
+		if logs != "" {
+			m.log.Warnf("Logs of copy job %s/%s:\n%s", job.Namespace, job.Name, logs)
+		}
+		return "", "", fmt.Errorf("%w, the original volume wasn't changed", err)
+	}
+	deleteJob(ctx, m.hostClient, job, m.log)
+
+	target, err = m.hostClient.CoreV1().PersistentVolumeClaims(target.Namespace).Get(ctx, target.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", "", err
+	} else if target.Spec.VolumeName == "" {
+		return "", "", fmt.Errorf("persistent volume claim %s/%s is not bound", target.Namespace, target.Name)
+	}
+
+	// keep the new volume when the temporary claim is deleted
+	volume, err := m.hostClient.CoreV1().PersistentVolumes().Get(ctx, target.Spec.VolumeName, metav1.GetOptions{})
+	if err != nil {
+		return "", "", err
+	}
+	volumeName, reclaimPolicy = volume.Name, volume.Spec.PersistentVolumeReclaimPolicy
+	err = setReclaimPolicy(ctx, m.hostClient, volume.Name, corev1.PersistentVolumeReclaimRetain)
+	if err != nil {
+		return "", "", err
+	}
+	err = m.hostClient.CoreV1().PersistentVolumeClaims(target.Namespace).Delete(ctx, target.Name, metav1.DeleteOptions{})
+	if err != nil {
+		return "", "", fmt.Errorf("delete persistent volume claim %s/%s: %w", target.Namespace, target.Name, err)
+	}
+	err = waitForClaimDeleted(ctx, m.hostClient, target.Namespace, target.Name, m.options.Timeout)
+	if err != nil {
+		return "", "", err
+	}
+
+	return volume.Name, reclaimPolicy, nil
+}
+
+// cleanupCopy removes the copy job and the temporary claim. If the reclaim policy of the new volume was already changed,
+// it is restored, so the volume is removed together with the claim.
+func (m *pvcMigration) cleanupCopy(ctx context.Context, job *batchv1.Job, claimName, volumeName string, reclaimPolicy corev1.PersistentVolumeReclaimPolicy) {
+	deleteJob(ctx, m.hostClient, job, m.log)
+
+	if volumeName != "" && reclaimPolicy != "" {
+		err := setReclaimPolicy(ctx, m.hostClient, volumeName, reclaimPolicy)
+		if err != nil && !kerrors.IsNotFound(err) {
+			m.log.Warnf("Couldn't restore reclaim policy of persistent volume %s: %v", volumeName, err)
+		}
+	}
+
+	m.log.Infof("Delete persistent volume claim %s/%s...", job.Namespace, claimName)
+	err := m.hostClient.CoreV1().PersistentVolumeClaims(job.Namespace).Delete(ctx, claimName, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		m.log.Warnf("Couldn't delete persistent volume claim %s/%s: %v", job.Namespace, claimName, err)
+	}
+}
+
+func deleteJob(ctx context.Context, client kubernetes.Interface, job *batchv1.Job, log log.Logger) {
+	err := client.BatchV1().Jobs(job.Namespace).Delete(ctx, job.Name, metav1.DeleteOptions{PropagationPolicy: ptr.To(metav1.DeletePropagationBackground)})
+	if err != nil && !kerrors.IsNotFound(err) {
+		log.Warnf("Couldn't delete copy job %s/%s: %v", job.Namespace, job.Name, err)
+	}
+}
+
+// jobLogs returns the logs of the pods of the job or an empty string if they can't be retrieved
+func jobLogs(ctx context.Context, client kubernetes.Interface, namespace, name string) string {
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: batchv1.JobNameLabel + "=" + name})
+	if err != nil {
+		return ""
+	}
+
+	logs := []string{}
+	for _, pod := range pods.Items {
+		out, err := client.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).Do(ctx).Raw()
+		if err != nil || len(out) == 0 {
+			continue
+		}
+
+		logs = append(logs, strings.TrimSpace(string(out)))
+	}
+
+	return strings.Join(logs, "\n")
+}
+
+func copyJob(sourceClaim, targetClaim, namespace, image string) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      translate.SafeConcatName(sourceClaim, "migrate"),
+			Namespace: namespace,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To[int32](2),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    "copy",
+							Image:   image,
+							Command: []string{"sh", "-c", "cp -a /source/. /target/"},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "source", MountPath: "/source", ReadOnly: true},
+								{Name: "target", MountPath: "/target"},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "source",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: sourceClaim, ReadOnly: true},
+							},
+						},
+						{
+							Name: "target",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: targetClaim},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// swap recreates the virtual claim bound to the new volume. The host claim is recreated by the syncer with the same
+// name, because the name of the virtual claim doesn't change.
+func (m *pvcMigration) swap(ctx context.Context, newVolume string, reclaimPolicy corev1.PersistentVolumeReclaimPolicy) error {
+	volume, err := m.hostClient.CoreV1().PersistentVolumes().Get(ctx, newVolume, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	// reserve the released volume for the recreated host claim, so no other claim can bind it
+	volume.Spec.ClaimRef = &corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "PersistentVolumeClaim",
+		Namespace:  m.hostClaim.Namespace,
+		Name:       m.hostClaim.Name,
+	}
+	_, err = m.hostClient.CoreV1().PersistentVolumes().Update(ctx, volume, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("reserve persistent volume %s: %w", newVolume, err)
+	}
+
+	if m.options.KeepOldVolume {
+		err = setReclaimPolicy(ctx, m.hostClient, m.hostClaim.Spec.VolumeName, corev1.PersistentVolumeReclaimRetain)
+		if err != nil {
+			return err
+		}
+	}
+
+	m.log.Infof("Recreate persistent volume claim %s/%s with storage class %s...", m.virtualClaim.Namespace, m.virtualClaim.Name, m.options.StorageClass)
+	err = m.virtualClient.CoreV1().PersistentVolumeClaims(m.virtualClaim.Namespace).Delete(ctx, m.virtualClaim.Name, metav1.DeleteOptions{})
+	if err != nil {
+		return fmt.Errorf("delete persistent volume claim: %w", err)
+	}
+	err = waitForClaimDeleted(ctx, m.virtualClient, m.virtualClaim.Namespace, m.virtualClaim.Name, m.options.Timeout)
+	if err != nil {
+		return err
+	}
+	err = waitForClaimDeleted(ctx, m.hostClient, m.hostClaim.Namespace, m.hostClaim.Name, m.options.Timeout)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.virtualClient.CoreV1().PersistentVolumeClaims(m.virtualClaim.Namespace).Create(ctx, recreatedClaim(m.virtualClaim, m.options.StorageClass, newVolume, m.skipTranslation), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("recreate persistent volume claim, the data is kept in host persistent volume %s: %w", newVolume, err)
+	}
+
+	err = wait.PollUntilContextTimeout(ctx, time.Second*2, m.options.Timeout, true, func(ctx context.Context) (bool, error) {
+		hostClaim, err := m.hostClient.CoreV1().PersistentVolumeClaims(m.hostClaim.Namespace).Get(ctx, m.hostClaim.Name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+
+		return hostClaim.Status.Phase == corev1.ClaimBound && hostClaim.Spec.VolumeName == newVolume, nil
+	})
+	if err != nil {
+		return fmt.Errorf("wait for host persistent volume claim %s/%s to bind to persistent volume %s: %w", m.hostClaim.Namespace, m.hostClaim.Name, newVolume, err)
+	}
+
+	return setReclaimPolicy(ctx, m.hostClient, newVolume, reclaimPolicy)
+}
+
+// recreatedClaim returns a copy of the virtual claim that uses the given storage class and is bound to the given
+// volume. Everything the cluster set while binding the original claim is removed.
+func recreatedClaim(vPVC *corev1.PersistentVolumeClaim, storageClass, volumeName string, skipTranslation bool) *corev1.PersistentVolumeClaim {
+	newPVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            vPVC.Name,
+			Namespace:       vPVC.Namespace,
+			Labels:          vPVC.Labels,
+			Annotations:     map[string]string{},
+			OwnerReferences: vPVC.OwnerReferences,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      vPVC.Spec.AccessModes,
+			Resources:        vPVC.Spec.Resources,
+			VolumeMode:       vPVC.Spec.VolumeMode,
+			StorageClassName: &storageClass,
+			VolumeName:       volumeName,
+		},
+	}
+	for k, v := range vPVC.Annotations {
+		newPVC.Annotations[k] = v
+	}
+	for _, annotation := range pvcBindAnnotations {
+		delete(newPVC.Annotations, annotation)
+	}
+	if skipTranslation {
+		newPVC.Annotations[constants.SkipTranslationAnnotation] = "true"
+	}
+
+	return newPVC
+}
+
+func setReclaimPolicy(ctx context.Context, client kubernetes.Interface, volumeName string, policy corev1.PersistentVolumeReclaimPolicy) error {
+	volume, err := client.CoreV1().PersistentVolumes().Get(ctx, volumeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	} else if volume.Spec.PersistentVolumeReclaimPolicy == policy {
+		return nil
+	}
+
+	volume.Spec.PersistentVolumeReclaimPolicy = policy
+	_, err = client.CoreV1().PersistentVolumes().Update(ctx, volume, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("set reclaim policy of persistent volume %s to %s: %w", volumeName, policy, err)
+	}
+
+	return nil
+}
+
+func waitForJob(ctx context.Context, client kubernetes.Interface, namespace, name string, timeout time.Duration) error {
+	err := wait.PollUntilContextTimeout(ctx, time.Second*2, timeout, true, func(ctx context.Context) (bool, error) {
+		job, err := client.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		for _, condition := range job.Status.Conditions {
+			if condition.Status != corev1.ConditionTrue {
+				continue
+			} else if condition.Type == batchv1.JobComplete {
+				return true, nil
+			} else if condition.Type == batchv1.JobFailed {
+				return false, fmt.Errorf("job failed: %s", condition.Message)
+			}
+		}
+
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("wait for job %s/%s: %w", namespace, name, err)
+	}
+
+	return nil
+}
+
+func waitForClaimDeleted(ctx context.Context, client kubernetes.Interface, namespace, name string, timeout time.Duration) error {
+	err := wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		_, err := client.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("wait for persistent volume claim %s/%s to be deleted: %w", namespace, name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+)
+
+func TestClaimWorkloads(t *testing.T) {
+	controller := func(kind, name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: ptr.To(true)}}
+	}
+	claimPod := func(name string, owners []metav1.OwnerReference) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", OwnerReferences: owners},
+			Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+				Name:         "data",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}},
+			}}},
+		}
+	}
+	replicaSet := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-abc", Namespace: "default", OwnerReferences: controller("Deployment", "web")}}
+
+	client := fake.NewSimpleClientset(
+		replicaSet,
+		claimPod("web-abc-1", controller("ReplicaSet", "web-abc")),
+		claimPod("web-abc-2", controller("ReplicaSet", "web-abc")),
+		claimPod("db-0", controller("StatefulSet", "db")),
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"}},
+	)
+	workloads, err := claimWorkloads(context.Background(), client, "default", "data")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, workloads, []scaledWorkload{
+		{Kind: "StatefulSet", Namespace: "default", Name: "db"},
+		{Kind: "Deployment", Namespace: "default", Name: "web"},
+	})
+
+	client = fake.NewSimpleClientset(claimPod("debug", nil))
+	_, err = claimWorkloads(context.Background(), client, "default", "data")
+	assert.ErrorContains(t, err, "pod default/debug uses the persistent volume claim and is not managed by a deployment or statefulset")
+}
+
+func TestRecreatedClaim(t *testing.T) {
+	vPVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "data",
+			Namespace:       "default",
+			UID:             "123",
+			ResourceVersion: "5",
+			Labels:          map[string]string{"app": "db"},
+			Annotations: map[string]string{
+				"pv.kubernetes.io/bind-completed":          "yes",
+				"volume.kubernetes.io/selected-node":       "node-1",
+				"volume.kubernetes.io/storage-provisioner": "ebs.csi.aws.com",
+				"team": "a",
+			},
+			Finalizers: []string{"kubernetes.io/pvc-protection"},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: ptr.To("standard"),
+			VolumeName:       "pvc-old",
+			DataSource:       &corev1.TypedLocalObjectReference{Kind: "PersistentVolumeClaim", Name: "seed"},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+	}
+
+	newPVC := recreatedClaim(vPVC, "fast", "pvc-new", true)
+	assert.DeepEqual(t, newPVC, &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "data",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "db"},
+			Annotations: map[string]string{
+				"team":                              "a",
+				constants.SkipTranslationAnnotation: "true",
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: ptr.To("fast"),
+			VolumeName:       "pvc-new",
+		},
+	})
+	assert.Equal(t, vPVC.Annotations["team"], "a")
+	assert.Equal(t, vPVC.Annotations["pv.kubernetes.io/bind-completed"], "yes")
+}
+
+func TestCopyDataCleanup(t *testing.T) {
+	hostClaim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data-x-default-x-vcluster", Namespace: "vcluster"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			VolumeName: "pv-old",
+			Resources:  corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")}},
+		},
+	}
+	newMigration := func(client *fake.Clientset) *pvcMigration {
+		return &pvcMigration{
+			options:          &PVCMigrateOptions{StorageClass: "fast", Image: "alpine", Timeout: time.Second * 5},
+			log:              log.Discard,
+			hostClient:       client,
+			hostNamespace:    "vcluster",
+			hostStorageClass: "fast",
+			hostClaim:        hostClaim,
+		}
+	}
+	assertRemoved := func(t *testing.T, client *fake.Clientset) {
+		_, err := client.BatchV1().Jobs("vcluster").Get(context.Background(), "data-x-default-x-vcluster-migrate", metav1.GetOptions{})
+		assert.Assert(t, kerrors.IsNotFound(err), "copy job wasn't deleted: %v", err)
+		_, err = client.CoreV1().PersistentVolumeClaims("vcluster").Get(context.Background(), "data-x-default-x-vcluster-migrate", metav1.GetOptions{})
+		assert.Assert(t, kerrors.IsNotFound(err), "persistent volume claim wasn't deleted: %v", err)
+	}
+	// bind the temporary claim and finish the copy job as soon as they are created
+	bindClaim := func(action clienttesting.Action) (bool, runtime.Object, error) {
+		claim := action.(clienttesting.CreateAction).GetObject().(*corev1.PersistentVolumeClaim)
+		claim.Spec.VolumeName = "pv-new"
+		return false, nil, nil
+	}
+	finishJob := func(jobCondition batchv1.JobConditionType) clienttesting.ReactionFunc {
+		return func(action clienttesting.Action) (bool, runtime.Object, error) {
+			job := action.(clienttesting.CreateAction).GetObject().(*batchv1.Job)
+			job.Status.Conditions = []batchv1.JobCondition{{Type: jobCondition, Status: corev1.ConditionTrue, Message: "copy failed"}}
+			return false, nil, nil
+		}
+	}
+
+	// failed copy job
+	client := fake.NewSimpleClientset(hostClaim.DeepCopy())
+	client.PrependReactor("create", "jobs", finishJob(batchv1.JobFailed))
+	_, _, err := newMigration(client).copyData(context.Background())
+	assert.ErrorContains(t, err, "job failed: copy failed, the original volume wasn't changed")
+	assertRemoved(t, client)
+
+	// failure after the reclaim policy of the new volume was changed
+	client = fake.NewSimpleClientset(hostClaim.DeepCopy(), &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-new"},
+		Spec:       corev1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete},
+	})
+	client.PrependReactor("create", "persistentvolumeclaims", bindClaim)
+	client.PrependReactor("create", "jobs", finishJob(batchv1.JobComplete))
+	deleteFailed := false
+	client.PrependReactor("delete", "persistentvolumeclaims", func(clienttesting.Action) (bool, runtime.Object, error) {
+		if deleteFailed {
+			return false, nil, nil
+		}
+
+		deleteFailed = true
+		return true, nil, fmt.Errorf("delete failed")
+	})
+	_, _, err = newMigration(client).copyData(context.Background())
+	assert.ErrorContains(t, err, "delete failed")
+	assertRemoved(t, client)
+	volume, err := client.CoreV1().PersistentVolumes().Get(context.Background(), "pv-new", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, volume.Spec.PersistentVolumeReclaimPolicy, corev1.PersistentVolumeReclaimDelete)
+
+	// successful copy keeps the new volume
+	client = fake.NewSimpleClientset(hostClaim.DeepCopy(), &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-new"},
+		Spec:       corev1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete},
+	})
+	client.PrependReactor("create", "persistentvolumeclaims", bindClaim)
+	client.PrependReactor("create", "jobs", finishJob(batchv1.JobComplete))
+	newVolume, reclaimPolicy, err := newMigration(client).copyData(context.Background())
+	assert.NilError(t, err)
+	assert.Equal(t, newVolume, "pv-new")
+	assert.Equal(t, reclaimPolicy, corev1.PersistentVolumeReclaimDelete)
+	assertRemoved(t, client)
+	volume, err = client.CoreV1().PersistentVolumes().Get(context.Background(), "pv-new", metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, volume.Spec.PersistentVolumeReclaimPolicy, corev1.PersistentVolumeReclaimRetain)
+}
@@ -28,11 +28,25 @@ const (
 )
 
 func CreateSnapshot(ctx context.Context, args []string, globalFlags *flags.GlobalFlags, snapshotOpts *snapshotapi.Options, podOptions *pod.Options, log log.Logger, delegateFromCLIToCluster, standalone bool) error {
+	return createSnapshot(ctx, args, globalFlags, snapshotOpts, podOptions, log, delegateFromCLIToCluster, standalone, false)
+}
+
+// createPausedSnapshot takes a snapshot of a paused vCluster. The snapshot pod starts the embedded backing store
+// itself, so nothing can change while the snapshot is taken.
+func createPausedSnapshot(ctx context.Context, args []string, globalFlags *flags.GlobalFlags, snapshotOpts *snapshotapi.Options, podOptions *pod.Options, log log.Logger) error {
+	if podOptions.Exec {
+		return fmt.Errorf("cannot exec into a paused vCluster to take a snapshot")
+	}
+
+	return createSnapshot(ctx, args, globalFlags, snapshotOpts, podOptions, log, false, false, true)
+}
+
+func createSnapshot(ctx context.Context, args []string, globalFlags *flags.GlobalFlags, snapshotOpts *snapshotapi.Options, podOptions *pod.Options, log log.Logger, delegateFromCLIToCluster, standalone, paused bool) error {
 	// init kube client and vCluster
 	vCluster, kubeClient, restConfig, err := initSnapshotCommand(ctx, args, globalFlags, snapshotOpts, log, initSnapshotOptions{
 		CredentialsRequiredInCluster: delegateFromCLIToCluster,
 		Standalone:                   standalone,
-		RequireRunning:               true,
+		RequireRunning:               !paused,
 	})
 	if err != nil {
 		return err
@@ -62,7 +76,11 @@ func CreateSnapshot(ctx context.Context, args []string, globalFlags *flags.Globa
 
 	if !delegateFromCLIToCluster {
 		// run the snapshot pod which takes the snapshot synchronously
-		return pod.RunSnapshotPod(ctx, restConfig, kubeClient, []string{"/vcluster", "snapshot"}, vCluster, podOptions, snapshotOpts, log)
+		command := []string{"/vcluster", "snapshot"}
+		if paused {
+			command = append(command, "--paused")
+		}
+		return pod.RunSnapshotPod(ctx, restConfig, kubeClient, command, vCluster, podOptions, snapshotOpts, log)
 	}
 
 	// create the snapshot request which will be reconciled by the vCluster controller
@@ -0,0 +1,22 @@
+package advisors
+
+import (
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/platform/sleepmode"
+)
+
+// Deprecation return migration advice for config options that moved in newer vCluster versions
+var Deprecation = map[string]func() (warning string){
+	"sleepMode":    sleepmode.Warning,
+	"platform":     config.WarningPlatform,
+	"autoDelete":   config.WarningAutoDelete,
+	"autoSleep":    config.WarningAutoSleep,
+	"autoSnapshot": config.WarningAutoSnapshot,
+}
+
+// DeprecationWarning returns the migration advice for the moved config options within the values or an empty string
+// if there are none
+func DeprecationWarning(values []byte) string {
+	return config.ConfigStructureWarning(log.Discard, values, Deprecation)
+}
@@ -2,9 +2,13 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"regexp"
+	"slices"
 	"strings"
 
 	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 func validateMappings(resolveDNS []vclusterconfig.ResolveDNS) error {
@@ -101,3 +105,103 @@ func validateTarget(target vclusterconfig.ResolveDNSTarget) error {
 
 	return nil
 }
+
+var corednsForwardPolicies = []string{"random", "round_robin", "sequential"}
+
+var corednsRewriteMatches = []string{"exact", "prefix", "suffix", "substring", "regex"}
+
+func validateCoreDNSZones(coreDNS vclusterconfig.CoreDNS, networking vclusterconfig.Networking) error {
+	customized := len(coreDNS.StubDomains) > 0 || len(coreDNS.Forward.Upstreams) > 0 || len(coreDNS.Hosts) > 0 || len(coreDNS.Rewrites) > 0
+	if customized && coreDNS.OverwriteConfig != "" {
+		return fmt.Errorf("controlPlane.coredns.overwriteConfig cannot be combined with controlPlane.coredns.stubDomains, forward, hosts or rewrites")
+	}
+
+	clusterDomain := networking.Advanced.ClusterDomain
+	if clusterDomain == "" {
+		clusterDomain = "cluster.local"
+	}
+	domains := map[string]bool{}
+	for i, stubDomain := range coreDNS.StubDomains {
+		domain := strings.TrimSuffix(stubDomain.Domain, ".")
+		if errs := validation.IsDNS1123Subdomain(domain); len(errs) > 0 {
+			return fmt.Errorf("error validating controlPlane.coredns.stubDomains[%d].domain: %s", i, strings.Join(errs, ", "))
+		} else if domain == clusterDomain || strings.HasSuffix(domain, "."+clusterDomain) {
+			return fmt.Errorf("error validating controlPlane.coredns.stubDomains[%d].domain: %s is part of the cluster domain %s", i, domain, clusterDomain)
+		} else if domains[domain] {
+			return fmt.Errorf("error validating controlPlane.coredns.stubDomains[%d].domain: %s is defined twice", i, domain)
+		}
+		domains[domain] = true
+
+		if len(stubDomain.Upstreams) == 0 {
+			return fmt.Errorf("error validating controlPlane.coredns.stubDomains[%d].upstreams: at least one upstream is required", i)
+		}
+		err := validateCoreDNSForward(stubDomain.CoreDNSForward)
+		if err != nil {
+			return fmt.Errorf("error validating controlPlane.coredns.stubDomains[%d]: %w", i, err)
+		}
+	}
+
+	if len(coreDNS.Forward.Upstreams) > 0 && networking.Advanced.FallbackHostCluster {
+		return fmt.Errorf("controlPlane.coredns.forward cannot be combined with networking.advanced.fallbackHostCluster")
+	} else if len(coreDNS.Forward.Upstreams) == 0 && coreDNS.Forward.Policy != "" {
+		return fmt.Errorf("error validating controlPlane.coredns.forward.policy: requires controlPlane.coredns.forward.upstreams")
+	}
+	err := validateCoreDNSForward(coreDNS.Forward)
+	if err != nil {
+		return fmt.Errorf("error validating controlPlane.coredns.forward: %w", err)
+	}
+
+	for i, host := range coreDNS.Hosts {
+		if net.ParseIP(host.IP) == nil {
+			return fmt.Errorf("error validating controlPlane.coredns.hosts[%d].ip: %q is not a valid IP address", i, host.IP)
+		} else if len(host.Hostnames) == 0 {
+			return fmt.Errorf("error validating controlPlane.coredns.hosts[%d].hostnames: at least one hostname is required", i)
+		}
+		for _, hostname := range host.Hostnames {
+			if errs := validation.IsDNS1123Subdomain(strings.TrimSuffix(hostname, ".")); len(errs) > 0 {
+				return fmt.Errorf("error validating controlPlane.coredns.hosts[%d].hostnames: %s", i, strings.Join(errs, ", "))
+			}
+		}
+	}
+
+	for i, rewrite := range coreDNS.Rewrites {
+		if rewrite.Match != "" && !slices.Contains(corednsRewriteMatches, rewrite.Match) {
+			return fmt.Errorf("error validating controlPlane.coredns.rewrites[%d].match: has to be one of %v, but got %s", i, corednsRewriteMatches, rewrite.Match)
+		} else if rewrite.From == "" || rewrite.To == "" {
+			return fmt.Errorf("error validating controlPlane.coredns.rewrites[%d]: from and to are required", i)
+		} else if strings.ContainsAny(rewrite.From+rewrite.To, " \t\n") {
+			return fmt.Errorf("error validating controlPlane.coredns.rewrites[%d]: from and to must not contain whitespace", i)
+		}
+
+		if rewrite.Match == "regex" {
+			if _, err := regexp.Compile(rewrite.From); err != nil {
+				return fmt.Errorf("error validating controlPlane.coredns.rewrites[%d].from: %w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateCoreDNSForward(forward vclusterconfig.CoreDNSForward) error {
+	if forward.Policy != "" && !slices.Contains(corednsForwardPolicies, forward.Policy) {
+		return fmt.Errorf("policy has to be one of %v, but got %s", corednsForwardPolicies, forward.Policy)
+	}
+
+	for _, upstream := range forward.Upstreams {
+		address := upstream
+		for _, scheme := range []string{"dns://", "tls://"} {
+			address = strings.TrimPrefix(address, scheme)
+		}
+		if net.ParseIP(address) != nil {
+			continue
+		}
+
+		host, port, err := net.SplitHostPort(address)
+		if err != nil || net.ParseIP(host) == nil || port == "" {
+			return fmt.Errorf("upstream has to be an IP address with an optional port and dns:// or tls:// scheme, but got %s", upstream)
+		}
+	}
+
+	return nil
+}
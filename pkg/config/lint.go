@@ -1,7 +1,13 @@
 package config
 
 import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
 	"github.com/loft-sh/vcluster/config"
+	yamlv3 "gopkg.in/yaml.v3"
 )
 
 const (
@@ -12,17 +18,317 @@ const (
 		"so all the pods will be scheduled by the default scheduler in the virtual cluster. Enabling " +
 		"the hybrid scheduling does not have any effect here. Consider either adding at least one host " +
 		"scheduler to sync.toHost.pods.hybridScheduling.hostSchedulers, or disable the hybrid scheduling."
+
+	// LintDisableAnnotation is the controlPlane.advanced.globalMetadata annotation that holds a comma separated
+	// list of lint rule IDs that should not be reported for the config.
+	LintDisableAnnotation = "vcluster.loft.sh/lint-disable"
+
+	// LintRuleInvalidConfig reports configs that cannot be parsed or fail validation.
+	LintRuleInvalidConfig = "invalid-config"
+	// LintRuleDeprecatedConfig reports config options that moved or were removed.
+	LintRuleDeprecatedConfig = "deprecated-config"
 )
 
+// lintDisableComment matches comments such as "# vcluster-lint-disable: rule-a, rule-b" that disable rules for the
+// whole values file.
+var lintDisableComment = regexp.MustCompile(`#[ \t]*vcluster-lint-disable:?[ \t]+([a-z0-9, \t-]+)`)
+
+// LintSeverity is the severity of a lint finding
+type LintSeverity string
+
+const (
+	LintSeverityError   LintSeverity = "error"
+	LintSeverityWarning LintSeverity = "warning"
+	LintSeverityInfo    LintSeverity = "info"
+)
+
+// LintRule is a check of the lint engine
+type LintRule struct {
+	// ID uniquely identifies the rule and is used to disable it
+	ID string
+	// Severity is the severity of the findings of the rule
+	Severity LintSeverity
+	// Description describes what the rule checks
+	Description string
+
+	// Check returns the findings of the rule for the given config. The engine fills in the rule ID and severity.
+	// Rules without a check are reported by the engine itself.
+	Check func(cfg *config.Config) []LintFinding
+}
+
+// LintFinding is a problem the lint engine found in a config
+type LintFinding struct {
+	// RuleID is the ID of the rule that reported the finding
+	RuleID string `json:"ruleId"`
+	// Severity is the severity of the finding
+	Severity LintSeverity `json:"severity"`
+	// Path is the config path the finding refers to, e.g. sync.toHost.secrets.all
+	Path string `json:"path,omitempty"`
+	// Line is the line of Path in the linted values, if known
+	Line int `json:"line,omitempty"`
+	// Message describes the finding
+	Message string `json:"message"`
+}
+
+// LintOptions are the options for LintValues
+type LintOptions struct {
+	// Name is the vCluster name the config is validated for
+	Name string
+	// HostNamespace is the host namespace the config is validated for
+	HostNamespace string
+	// DeprecationWarning returns migration advice for values that cannot be parsed, e.g. advisors.DeprecationWarning.
+	// The deprecated-config rule is skipped if it is not set.
+	DeprecationWarning func(values []byte) string
+}
+
+var lintRules = []LintRule{
+	{
+		ID:          LintRuleInvalidConfig,
+		Severity:    LintSeverityError,
+		Description: "The config cannot be parsed or fails validation.",
+	},
+	{
+		ID:          LintRuleDeprecatedConfig,
+		Severity:    LintSeverityWarning,
+		Description: "The config uses options that moved or were removed.",
+	},
+	{
+		ID:          "hybrid-scheduling-no-effect",
+		Severity:    LintSeverityWarning,
+		Description: "Hybrid scheduling is enabled together with the virtual scheduler, but without host schedulers.",
+		Check: func(cfg *config.Config) []LintFinding {
+			if hybridSchedulingNoEffect(cfg) {
+				return []LintFinding{{Path: "sync.toHost.pods.hybridScheduling.enabled", Message: HybridSchedulingNoEffectWarning}}
+			}
+
+			return nil
+		},
+	},
+	{
+		ID:          "secrets-all-multi-namespace",
+		Severity:    LintSeverityWarning,
+		Description: "All secrets are synced to the host in multi-namespace mode.",
+		Check: func(cfg *config.Config) []LintFinding {
+			if cfg.Sync.ToHost.Namespaces.Enabled && cfg.Sync.ToHost.Secrets.Enabled && cfg.Sync.ToHost.Secrets.All {
+				return []LintFinding{{
+					Path:    "sync.toHost.secrets.all",
+					Message: "sync.toHost.secrets.all copies every secret of every virtual namespace, including service account tokens, into the synced host namespaces. Consider syncing only the secrets pods need.",
+				}}
+			}
+
+			return nil
+		},
+	},
+	{
+		ID:          "network-policy-unsupported-cni",
+		Severity:    LintSeverityWarning,
+		Description: "Network policies are enabled, but the deployed CNI doesn't enforce them.",
+		Check: func(cfg *config.Config) []LintFinding {
+			if cfg.PrivateNodes.Enabled && cfg.Deploy.CNI.Flannel.Enabled && cfg.Policies.NetworkPolicy.Enabled {
+				return []LintFinding{{
+					Path:    "policies.networkPolicy.enabled",
+					Message: "policies.networkPolicy.enabled has no effect with the Flannel CNI deployed through deploy.cni.flannel, as Flannel doesn't enforce network policies. Use a CNI that supports network policies instead.",
+				}}
+			}
+
+			return nil
+		},
+	},
+	{
+		ID:          "embedded-etcd-even-replicas",
+		Severity:    LintSeverityWarning,
+		Description: "Embedded etcd runs with an even number of replicas.",
+		Check: func(cfg *config.Config) []LintFinding {
+			replicas := cfg.ControlPlane.StatefulSet.HighAvailability.Replicas
+			if cfg.ControlPlane.BackingStore.Etcd.Embedded.Enabled && replicas > 1 && replicas%2 == 0 {
+				return []LintFinding{{
+					Path:    "controlPlane.statefulSet.highAvailability.replicas",
+					Message: fmt.Sprintf("Embedded etcd with %d replicas tolerates as many failures as with %d replicas. Use an odd number of replicas.", replicas, replicas-1),
+				}}
+			}
+
+			return nil
+		},
+	},
+	{
+		ID:          "embedded-store-without-persistence",
+		Severity:    LintSeverityWarning,
+		Description: "An embedded backing store is used without a persistent volume.",
+		Check: func(cfg *config.Config) []LintFinding {
+			persistence := cfg.ControlPlane.StatefulSet.Persistence
+			storeType := cfg.BackingStoreType()
+			if (storeType == config.StoreTypeEmbeddedDatabase || storeType == config.StoreTypeEmbeddedEtcd) &&
+				!cfg.ControlPlane.Standalone.Enabled &&
+				strings.EqualFold(string(persistence.VolumeClaim.Enabled), "false") &&
+				len(persistence.DataVolume) == 0 {
+				return []LintFinding{{
+					Path:    "controlPlane.statefulSet.persistence.volumeClaim.enabled",
+					Message: fmt.Sprintf("The %s backing store is stored on an ephemeral volume, so all data of the virtual cluster is lost when the control plane pod restarts.", storeType),
+				}}
+			}
+
+			return nil
+		},
+	},
+}
+
+// RegisterLintRule adds a rule to the lint engine. It returns an error if a rule with the same ID is already registered.
+func RegisterLintRule(rule LintRule) error {
+	if slices.ContainsFunc(lintRules, func(existing LintRule) bool { return existing.ID == rule.ID }) {
+		return fmt.Errorf("lint rule %s is already registered", rule.ID)
+	}
+
+	lintRules = append(lintRules, rule)
+	return nil
+}
+
+// LintRules returns all registered lint rules
+func LintRules() []LintRule {
+	return slices.Clone(lintRules)
+}
+
 // Lint checks the virtual cluster config and returns warnings for the parts of the config
 // that should be probably corrected, but are not breaking any functionality in the cluster.
+// The other lint rules are only reported by LintConfig and LintValues.
 func Lint(config config.Config) []string {
 	var warnings []string
-	if config.IsVirtualSchedulerEnabled() &&
-		config.Sync.ToHost.Pods.HybridScheduling.Enabled &&
-		len(config.Sync.ToHost.Pods.HybridScheduling.HostSchedulers) == 0 {
+	if hybridSchedulingNoEffect(&config) {
 		warnings = append(warnings, HybridSchedulingNoEffectWarning)
 	}
 
 	return warnings
 }
+
+func hybridSchedulingNoEffect(config *config.Config) bool {
+	return config.IsVirtualSchedulerEnabled() &&
+		config.Sync.ToHost.Pods.HybridScheduling.Enabled &&
+		len(config.Sync.ToHost.Pods.HybridScheduling.HostSchedulers) == 0
+}
+
+// LintConfig runs all lint rules against the given config. Suppressions configured through the
+// LintDisableAnnotation are respected.
+func LintConfig(config *config.Config) []LintFinding {
+	disabled := disabledLintRules(config, nil)
+
+	var findings []LintFinding
+	for _, rule := range lintRules {
+		if rule.Check == nil || disabled[rule.ID] {
+			continue
+		}
+
+		for _, finding := range rule.Check(config) {
+			finding.RuleID = rule.ID
+			finding.Severity = rule.Severity
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
+
+// LintValues lints the given helm values. The values are parsed, checked for deprecated options and validated
+// before all lint rules run. Rules can be disabled through the LintDisableAnnotation or a
+// "# vcluster-lint-disable: rule-a, rule-b" comment within the values.
+func LintValues(values []byte, options LintOptions) []LintFinding {
+	var findings []LintFinding
+	report := func(ruleID string, severity LintSeverity, message string) {
+		findings = append(findings, LintFinding{RuleID: ruleID, Severity: severity, Message: message})
+	}
+
+	vConfig := &VirtualClusterConfig{Name: options.Name, HostNamespace: options.HostNamespace}
+	defaultConfig, err := config.NewDefaultConfig()
+	if err != nil {
+		report(LintRuleInvalidConfig, LintSeverityError, fmt.Sprintf("load default config: %v", err))
+		return findings
+	}
+	vConfig.Config = *defaultConfig
+
+	// parse the values on top of the defaults
+	err = vConfig.UnmarshalYAMLStrict(values)
+	if err != nil {
+		if options.DeprecationWarning != nil {
+			warning := options.DeprecationWarning(values)
+			if warning != "" {
+				report(LintRuleDeprecatedConfig, LintSeverityWarning, strings.TrimSpace(warning))
+			}
+		}
+
+		report(LintRuleInvalidConfig, LintSeverityError, fmt.Sprintf("parse config: %v", err))
+		return filterLintFindings(findings, disabledLintRules(nil, values))
+	}
+
+	err = ValidateConfigAndSetDefaults(vConfig)
+	if err != nil {
+		report(LintRuleInvalidConfig, LintSeverityError, err.Error())
+	}
+
+	findings = append(findings, LintConfig(&vConfig.Config)...)
+	findings = filterLintFindings(findings, disabledLintRules(&vConfig.Config, values))
+
+	// find the lines of the findings
+	root := &yamlv3.Node{}
+	if yamlv3.Unmarshal(values, root) == nil {
+		for idx := range findings {
+			findings[idx].Line = lineOfPath(root, findings[idx].Path)
+		}
+	}
+
+	return findings
+}
+
+func filterLintFindings(findings []LintFinding, disabled map[string]bool) []LintFinding {
+	return slices.DeleteFunc(findings, func(finding LintFinding) bool {
+		return disabled[finding.RuleID]
+	})
+}
+
+// disabledLintRules returns the rule IDs disabled through the annotation of the config or comments in the values
+func disabledLintRules(config *config.Config, values []byte) map[string]bool {
+	var ruleIDs []string
+	if config != nil {
+		ruleIDs = append(ruleIDs, strings.Split(config.ControlPlane.Advanced.GlobalMetadata.Annotations[LintDisableAnnotation], ",")...)
+	}
+	for _, match := range lintDisableComment.FindAllSubmatch(values, -1) {
+		ruleIDs = append(ruleIDs, strings.Split(string(match[1]), ",")...)
+	}
+
+	disabled := map[string]bool{}
+	for _, ruleID := range ruleIDs {
+		ruleID = strings.TrimSpace(ruleID)
+		if ruleID != "" {
+			disabled[ruleID] = true
+		}
+	}
+
+	return disabled
+}
+
+// lineOfPath returns the line of the deepest key of the dotted path that exists in the given yaml document
+func lineOfPath(root *yamlv3.Node, path string) int {
+	if path == "" || root.Kind != yamlv3.DocumentNode || len(root.Content) == 0 {
+		return 0
+	}
+
+	line := 0
+	node := root.Content[0]
+	for _, key := range strings.Split(path, ".") {
+		if node.Kind != yamlv3.MappingNode {
+			return line
+		}
+
+		found := false
+		for idx := 0; idx+1 < len(node.Content); idx += 2 {
+			if node.Content[idx].Value == key {
+				line = node.Content[idx].Line
+				node = node.Content[idx+1]
+				found = true
+				break
+			}
+		}
+		if !found {
+			return line
+		}
+	}
+
+	return line
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/loft-sh/vcluster/config"
+)
+
+func TestLintValues(t *testing.T) {
+	cases := []struct {
+		name     string
+		values   string
+		expected []string
+		line     int
+	}{
+		{
+			name: "default config",
+		},
+		{
+			name:     "invalid config",
+			values:   "sync:\n  toHost:\n    unknown: true\n",
+			expected: []string{LintRuleInvalidConfig},
+		},
+		{
+			name:     "secrets all in multi-namespace mode",
+			values:   "sync:\n  toHost:\n    namespaces:\n      enabled: true\n      mappings:\n        byName:\n          \"*\": \"vc-${name}-*\"\n    secrets:\n      all: true\n",
+			expected: []string{"secrets-all-multi-namespace"},
+			line:     9,
+		},
+		{
+			name:   "disabled by comment",
+			values: "# vcluster-lint-disable: embedded-etcd-even-replicas\ncontrolPlane:\n  statefulSet:\n    highAvailability:\n      replicas: 2\n  backingStore:\n    etcd:\n      embedded:\n        enabled: true\n",
+		},
+		{
+			name:     "even embedded etcd replicas",
+			values:   "controlPlane:\n  statefulSet:\n    highAvailability:\n      replicas: 2\n  backingStore:\n    etcd:\n      embedded:\n        enabled: true\n",
+			expected: []string{"embedded-etcd-even-replicas"},
+			line:     4,
+		},
+		{
+			name:   "disabled by annotation",
+			values: "controlPlane:\n  advanced:\n    globalMetadata:\n      annotations:\n        vcluster.loft.sh/lint-disable: embedded-store-without-persistence\n  statefulSet:\n    persistence:\n      volumeClaim:\n        enabled: false\n",
+		},
+		{
+			name:     "embedded store without persistence",
+			values:   "controlPlane:\n  statefulSet:\n    persistence:\n      volumeClaim:\n        enabled: false\n",
+			expected: []string{"embedded-store-without-persistence"},
+			line:     5,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			findings := LintValues([]byte(tc.values), LintOptions{Name: "vcluster", HostNamespace: "vcluster"})
+
+			var ruleIDs []string
+			for _, finding := range findings {
+				ruleIDs = append(ruleIDs, finding.RuleID)
+			}
+			if !slices.Equal(ruleIDs, tc.expected) {
+				t.Fatalf("expected findings %v, got %v", tc.expected, findings)
+			}
+			if tc.line > 0 && findings[0].Line != tc.line {
+				t.Errorf("expected line %d, got %d", tc.line, findings[0].Line)
+			}
+		})
+	}
+}
+
+func TestRegisterLintRule(t *testing.T) {
+	err := RegisterLintRule(LintRule{ID: LintRuleInvalidConfig})
+	if err == nil || err.Error() != "lint rule invalid-config is already registered" {
+		t.Errorf("expected registering a rule twice to fail, got %v", err)
+	}
+}
+
+func TestLint(t *testing.T) {
+	// rules other than the hybrid scheduling check are not reported by Lint
+	cfg := config.Config{}
+	cfg.Sync.ToHost.Namespaces.Enabled = true
+	cfg.Sync.ToHost.Secrets.Enabled = true
+	cfg.Sync.ToHost.Secrets.All = true
+	if warnings := Lint(cfg); len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", warnings)
+	}
+
+	cfg.ControlPlane.Distro.K8S.Scheduler.Enabled = true
+	cfg.Sync.ToHost.Pods.HybridScheduling.Enabled = true
+	if warnings := Lint(cfg); !slices.Equal(warnings, []string{HybridSchedulingNoEffectWarning}) {
+		t.Errorf("expected the hybrid scheduling warning, got %v", warnings)
+	}
+}
@@ -5,16 +5,20 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"maps"
 	"net"
 	"net/url"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/ghodss/yaml"
+	"github.com/robfig/cron/v3"
 	"github.com/samber/lo"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -25,7 +29,9 @@ import (
 	cliconfig "github.com/loft-sh/vcluster/pkg/cli/config"
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/platform"
+	"github.com/loft-sh/vcluster/pkg/util/ingressannotations"
 	"github.com/loft-sh/vcluster/pkg/util/namespaces"
+	"github.com/loft-sh/vcluster/pkg/util/nametemplate"
 	"github.com/loft-sh/vcluster/pkg/util/toleration"
 )
 
@@ -115,6 +121,22 @@ func ValidateConfigAndSetDefaults(vConfig *VirtualClusterConfig) error {
 		return err
 	}
 
+	if err := validateHostnamePolicy(vConfig.Policies.Hostnames); err != nil {
+		return err
+	}
+
+	if err := validateNamingTemplate(vConfig.Sync.ToHost); err != nil {
+		return err
+	}
+
+	if err := validateAdminNetworkPolicies(vConfig.Sync.ToHost); err != nil {
+		return err
+	}
+
+	if err := validateIngressAnnotationTranslation(vConfig.Sync.ToHost.Ingresses.AnnotationTranslation); err != nil {
+		return err
+	}
+
 	// validate sync patches
 	err := ValidateAllSyncPatches(vConfig.Sync)
 	if err != nil {
@@ -155,6 +177,12 @@ func ValidateConfigAndSetDefaults(vConfig *VirtualClusterConfig) error {
 		return err
 	}
 
+	// check coredns zones
+	err = validateCoreDNSZones(vConfig.ControlPlane.CoreDNS, vConfig.Networking)
+	if err != nil {
+		return err
+	}
+
 	// check sync.fromHost.configMaps.selector.mappings
 	err = validateFromHostSyncMappings(vConfig.Sync.FromHost.ConfigMaps, "configMaps")
 	if err != nil {
@@ -166,6 +194,17 @@ func ValidateConfigAndSetDefaults(vConfig *VirtualClusterConfig) error {
 		return err
 	}
 
+	// check sync.toHost.configMaps.mappings
+	err = validateToHostSyncMappings(vConfig.Sync.ToHost.ConfigMaps, vConfig.Sync.FromHost.ConfigMaps, vConfig.Sync.ToHost.Namespaces.Enabled, vConfig.HostNamespace, "configMaps")
+	if err != nil {
+		return err
+	}
+
+	err = validateToHostSyncMappings(vConfig.Sync.ToHost.Secrets, vConfig.Sync.FromHost.Secrets, vConfig.Sync.ToHost.Namespaces.Enabled, vConfig.HostNamespace, "secrets")
+	if err != nil {
+		return err
+	}
+
 	// sync.toHost.namespaces validation
 	err = namespaces.ValidateNamespaceSyncConfig(&vConfig.Config, vConfig.Name, vConfig.HostNamespace)
 	if err != nil {
@@ -189,6 +228,38 @@ func ValidateConfigAndSetDefaults(vConfig *VirtualClusterConfig) error {
 		return err
 	}
 
+	// check the proxy oidc config
+	err = validateProxyOIDC(vConfig.ControlPlane.Proxy.OIDC)
+	if err != nil {
+		return err
+	}
+
+	err = validateRegistryPullThrough(vConfig.ControlPlane.Advanced.Registry)
+	if err != nil {
+		return err
+	}
+	err = setPullThroughContainerdMirrors(vConfig)
+	if err != nil {
+		return err
+	}
+
+	err = validateDatabaseMaintenance(vConfig.ControlPlane.BackingStore.Database.Maintenance)
+	if err != nil {
+		return err
+	}
+
+	// check tracing
+	err = validateTracing(vConfig.ControlPlane.Advanced.Tracing)
+	if err != nil {
+		return err
+	}
+
+	// check telemetry sink
+	err = validateTelemetrySink(vConfig.Telemetry.Sink)
+	if err != nil {
+		return err
+	}
+
 	// check config for exporting kubeconfig Secrets
 	err = validateExportKubeConfig(vConfig.ExportKubeConfig)
 	if err != nil {
@@ -385,6 +456,104 @@ func validateGatewayVirtualNamespacePolicy(path string, policy *config.GatewayVi
 	}
 }
 
+func validateHostnamePolicy(policy config.HostnamePolicy) error {
+	if !policy.Enabled {
+		return nil
+	}
+	if len(policy.AllowedDomains) == 0 {
+		return errors.New("policies.hostnames.allowedDomains must not be empty when policies.hostnames.enabled is true")
+	}
+	for i, domain := range policy.AllowedDomains {
+		if errs := utilvalidation.IsDNS1123Subdomain(strings.TrimSuffix(domain, ".")); len(errs) > 0 {
+			return fmt.Errorf("policies.hostnames.allowedDomains[%d] %q is not a valid domain: %s", i, domain, strings.Join(errs, ", "))
+		}
+	}
+
+	// prefix and suffix are part of a single dns label
+	const labelCharacters = "abcdefghijklmnopqrstuvwxyz0123456789-"
+	if strings.Trim(policy.Prefix, labelCharacters) != "" {
+		return fmt.Errorf("policies.hostnames.prefix %q must only consist of lower case alphanumeric characters or '-'", policy.Prefix)
+	}
+	if strings.Trim(policy.Suffix, labelCharacters) != "" {
+		return fmt.Errorf("policies.hostnames.suffix %q must only consist of lower case alphanumeric characters or '-'", policy.Suffix)
+	}
+
+	return nil
+}
+
+func validateNamingTemplate(toHost config.SyncToHost) error {
+	if toHost.Naming.Template == "" {
+		return nil
+	}
+	if toHost.Namespaces.Enabled {
+		return errors.New("sync.toHost.naming.template is not supported when sync.toHost.namespaces.enabled is true, because objects keep their names in synced namespaces")
+	}
+
+	_, err := nametemplate.Parse(toHost.Naming.Template)
+	if err != nil {
+		return fmt.Errorf("sync.toHost.naming.template: %w", err)
+	}
+
+	return nil
+}
+
+func validateAdminNetworkPolicies(toHost config.SyncToHost) error {
+	if !toHost.AdminNetworkPolicies.Enabled {
+		return nil
+	}
+	if toHost.Namespaces.Enabled {
+		return errors.New("sync.toHost.adminNetworkPolicies is not supported when sync.toHost.namespaces.enabled is true")
+	}
+	if toHost.AdminNetworkPolicies.MinPriority < 0 || toHost.AdminNetworkPolicies.MinPriority > 1000 {
+		return fmt.Errorf("sync.toHost.adminNetworkPolicies.minPriority must be between 0 and 1000, got %d", toHost.AdminNetworkPolicies.MinPriority)
+	}
+
+	return nil
+}
+
+func validateIngressAnnotationTranslation(translation config.IngressAnnotationTranslation) error {
+	for _, preset := range translation.Presets {
+		if _, ok := ingressannotations.Presets[preset]; !ok {
+			return fmt.Errorf("sync.toHost.ingresses.annotationTranslation.presets: unknown preset %q, supported presets are: %s", preset, strings.Join(slices.Sorted(maps.Keys(ingressannotations.Presets)), ", "))
+		}
+	}
+
+	annotations := map[string]bool{}
+	for i, rule := range translation.Rules {
+		path := fmt.Sprintf("sync.toHost.ingresses.annotationTranslation.rules[%d]", i)
+		if errs := utilvalidation.IsQualifiedName(strings.ToLower(rule.Annotation)); len(errs) > 0 {
+			return fmt.Errorf("%s.annotation %q is invalid: %s", path, rule.Annotation, strings.Join(errs, ", "))
+		}
+		if annotations[rule.Annotation] {
+			return fmt.Errorf("%s.annotation %q is defined multiple times", path, rule.Annotation)
+		}
+		annotations[rule.Annotation] = true
+
+		switch rule.Type {
+		case ingressannotations.TypeService, ingressannotations.TypeSecret, ingressannotations.TypeTraefikMiddleware:
+			if len(rule.Paths) > 0 {
+				return fmt.Errorf("%s.paths is only supported for type %q", path, ingressannotations.TypeJSON)
+			}
+		case ingressannotations.TypeJSON:
+			if len(rule.Paths) == 0 {
+				return fmt.Errorf("%s.paths must not be empty for type %q", path, ingressannotations.TypeJSON)
+			}
+			for j, jsonPath := range rule.Paths {
+				if err := ingressannotations.ValidatePath(jsonPath.Path); err != nil {
+					return fmt.Errorf("%s.paths[%d].path: %w", path, j, err)
+				}
+				if jsonPath.Type != ingressannotations.TypeService && jsonPath.Type != ingressannotations.TypeSecret {
+					return fmt.Errorf("%s.paths[%d].type %q is invalid, must be one of: %s, %s", path, j, jsonPath.Type, ingressannotations.TypeService, ingressannotations.TypeSecret)
+				}
+			}
+		default:
+			return fmt.Errorf("%s.type %q is invalid, must be one of: %s, %s, %s, %s", path, rule.Type, ingressannotations.TypeService, ingressannotations.TypeSecret, ingressannotations.TypeTraefikMiddleware, ingressannotations.TypeJSON)
+		}
+	}
+
+	return nil
+}
+
 func validGatewayHostnamePattern(hostname string) bool {
 	hostname = strings.TrimSpace(strings.ToLower(hostname))
 	if strings.HasPrefix(hostname, "*.") {
@@ -702,6 +871,76 @@ func validateFromHostSyncMappings(s config.EnableSwitchWithResourcesMappings, re
 	return nil
 }
 
+func validateToHostSyncMappings(s config.SyncAllResource, fromHost config.EnableSwitchWithResourcesMappings, namespacesEnabled bool, hostNamespace, resourceNamePlural string) error {
+	if len(s.Mappings.ByName) == 0 {
+		return nil
+	}
+	if !s.Enabled {
+		return fmt.Errorf("sync.toHost.%s.mappings requires sync.toHost.%s.enabled", resourceNamePlural, resourceNamePlural)
+	}
+	if namespacesEnabled {
+		return fmt.Errorf("sync.toHost.%s.mappings is not supported when sync.toHost.namespaces.enabled is true", resourceNamePlural)
+	}
+
+	hostObjects := map[string]string{}
+	for key, value := range s.Mappings.ByName {
+		if err := validateToHostMappingObject(key, "virtual"); err != nil {
+			return fmt.Errorf("sync.toHost.%s.mappings.byName key %q: %w", resourceNamePlural, key, err)
+		}
+		if err := validateToHostMappingObject(value, "host"); err != nil {
+			return fmt.Errorf("sync.toHost.%s.mappings.byName value %q: %w", resourceNamePlural, value, err)
+		}
+		if other, ok := hostObjects[value]; ok {
+			return fmt.Errorf("sync.toHost.%s.mappings.byName maps both %s and %s to host object %s", resourceNamePlural, min(key, other), max(key, other), value)
+		}
+		hostObjects[value] = key
+
+		// host objects that are synced from the host cluster can't be owned by the virtual cluster
+		if fromHost.Enabled && fromHostMappingsCover(fromHost.Mappings.ByName, hostNamespace, value) {
+			return fmt.Errorf("sync.toHost.%s.mappings.byName host object %s is also synced by sync.fromHost.%s.mappings", resourceNamePlural, value, resourceNamePlural)
+		}
+	}
+
+	return nil
+}
+
+// validateToHostMappingObject validates a NAMESPACE/NAME reference of a toHost mapping
+func validateToHostMappingObject(ref, kind string) error {
+	namespace, name, ok := strings.Cut(ref, "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("expected NAMESPACE/NAME")
+	}
+	if errs := validation.ValidateNamespaceName(namespace, false); len(errs) > 0 {
+		return fmt.Errorf("%s namespace is not a valid namespace name: %s", kind, strings.Join(errs, ", "))
+	}
+	if errs := validation.NameIsDNSSubdomain(name, false); len(errs) > 0 {
+		return fmt.Errorf("%s name is not a valid name: %s", kind, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// fromHostMappingsCover returns true if the host object NAMESPACE/NAME is matched by the given fromHost mappings
+func fromHostMappingsCover(mappings map[string]string, hostNamespace, hostObject string) bool {
+	namespace, name, _ := strings.Cut(hostObject, "/")
+	for key := range mappings {
+		switch {
+		case key == constants.VClusterNamespaceInHostMappingSpecialCharacter:
+			if namespace == hostNamespace {
+				return true
+			}
+		case key == "/"+name:
+			if namespace == hostNamespace {
+				return true
+			}
+		case key == hostObject || key == namespace+"/*":
+			return true
+		}
+	}
+
+	return false
+}
+
 func validateFromHostMappingEntry(key, value, resourceNamePlural string) error {
 	if strings.Count(key, "/") > 1 || strings.Count(value, "/") > 1 {
 		return fmt.Errorf("config.sync.fromHost.%s.selector.mappings has key:value pair in invalid format: %s:%s (expected NAMESPACE_NAME/NAME, NAMESPACE_NAME/*, /NAME or \"\")", resourceNamePlural, key, value)
@@ -812,6 +1051,35 @@ func validateEnabledIntegrations(
 	if err := validateKubeVirtEnabled(toHostCustomResources, integrations.KubeVirt); err != nil {
 		return err
 	}
+	if err := validatePrometheusOperatorEnabled(toHostCustomResources, integrations.PrometheusOperator); err != nil {
+		return err
+	}
+	return nil
+}
+
+func validatePrometheusOperatorEnabled(
+	toHostCustomResources map[string]config.SyncToHostCustomResource,
+	prometheusOperatorIntegration config.PrometheusOperator) error {
+	if !prometheusOperatorIntegration.Enabled {
+		return nil
+	}
+	for crdName, crdConfig := range toHostCustomResources {
+		if crdConfig.Enabled &&
+			(crdName == "servicemonitors.monitoring.coreos.com" && prometheusOperatorIntegration.Sync.ToHost.ServiceMonitors.Enabled ||
+				crdName == "podmonitors.monitoring.coreos.com" && prometheusOperatorIntegration.Sync.ToHost.PodMonitors.Enabled ||
+				crdName == "prometheusrules.monitoring.coreos.com" && prometheusOperatorIntegration.Sync.ToHost.PrometheusRules.Enabled) {
+			return fmt.Errorf("prometheus-operator integration is enabled but prometheus-operator custom resource (%s) is also set in the sync.toHost.customResources. "+
+				"This is not supported, please remove the entry from sync.toHost.customResources", crdName)
+		}
+	}
+	for key, value := range prometheusOperatorIntegration.EnforcedLabels {
+		if errs := utilvalidation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("integrations.prometheusOperator.enforcedLabels: invalid label key %q: %s", key, strings.Join(errs, ", "))
+		}
+		if errs := utilvalidation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("integrations.prometheusOperator.enforcedLabels: invalid label value %q for key %q: %s", value, key, strings.Join(errs, ", "))
+		}
+	}
 	return nil
 }
 
@@ -948,6 +1216,12 @@ func validatePrivatedNodesMode(vConfig *VirtualClusterConfig) error {
 	if vConfig.Integrations.MetricsServer.Enabled {
 		return fmt.Errorf("metrics-server integration is not supported in private nodes mode")
 	}
+	if vConfig.Integrations.CustomMetrics.Enabled {
+		return fmt.Errorf("custom metrics integration is not supported in private nodes mode")
+	}
+	if vConfig.Integrations.ExternalMetrics.Enabled {
+		return fmt.Errorf("external metrics integration is not supported in private nodes mode")
+	}
 	if vConfig.Integrations.CertManager.Enabled {
 		return fmt.Errorf("cert-manager integration is not supported in private nodes mode")
 	}
@@ -960,6 +1234,9 @@ func validatePrivatedNodesMode(vConfig *VirtualClusterConfig) error {
 	if vConfig.Integrations.KubeVirt.Enabled {
 		return fmt.Errorf("kubevirt integration is not supported in private nodes mode")
 	}
+	if vConfig.Integrations.PrometheusOperator.Enabled {
+		return fmt.Errorf("prometheus-operator integration is not supported in private nodes mode")
+	}
 
 	// embedded coredns is not supported in private nodes mode
 	if vConfig.ControlPlane.CoreDNS.Embedded {
@@ -1083,6 +1360,163 @@ func validateAdvancedControlPlaneConfig(controlPlaneAdvanced config.ControlPlane
 	return nil
 }
 
+func validateProxyOIDC(oidc config.ControlPlaneProxyOIDC) error {
+	if !oidc.Enabled {
+		return nil
+	}
+
+	issuerURL, err := url.Parse(oidc.IssuerURL)
+	if err != nil || issuerURL.Scheme != "https" || issuerURL.Host == "" {
+		return fmt.Errorf("controlPlane.proxy.oidc.issuerURL has to be a valid https URL, got %q", oidc.IssuerURL)
+	} else if issuerURL.RawQuery != "" || issuerURL.Fragment != "" {
+		return fmt.Errorf("controlPlane.proxy.oidc.issuerURL must not contain a query or fragment")
+	}
+	if oidc.ClientID == "" {
+		return fmt.Errorf("controlPlane.proxy.oidc.clientID is required")
+	}
+	if oidc.CA != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(oidc.CA)) {
+		return fmt.Errorf("controlPlane.proxy.oidc.ca does not contain a valid PEM encoded certificate")
+	}
+
+	return nil
+}
+
+func validateRegistryPullThrough(registry config.Registry) error {
+	pullThrough := registry.PullThrough
+	if !pullThrough.Enabled {
+		return nil
+	} else if !registry.Enabled {
+		return fmt.Errorf("controlPlane.advanced.registry.pullThrough requires controlPlane.advanced.registry.enabled")
+	} else if len(pullThrough.Upstreams) == 0 {
+		return fmt.Errorf("controlPlane.advanced.registry.pullThrough.upstreams must contain at least one upstream")
+	}
+
+	names := map[string]bool{}
+	for idx, upstream := range pullThrough.Upstreams {
+		if upstream.Name == "" || strings.ContainsAny(upstream.Name, "/@ ") {
+			return fmt.Errorf("controlPlane.advanced.registry.pullThrough.upstreams[%d].name has to be a registry host, e.g. docker.io, got %q", idx, upstream.Name)
+		} else if names[upstream.Name] {
+			return fmt.Errorf("controlPlane.advanced.registry.pullThrough.upstreams[%d].name %s is defined twice", idx, upstream.Name)
+		}
+		names[upstream.Name] = true
+
+		if upstream.URL != "" {
+			upstreamURL, err := url.Parse(upstream.URL)
+			if err != nil || (upstreamURL.Scheme != "https" && upstreamURL.Scheme != "http") || upstreamURL.Host == "" {
+				return fmt.Errorf("controlPlane.advanced.registry.pullThrough.upstreams[%d].url has to be a valid http or https URL, got %q", idx, upstream.URL)
+			} else if strings.Trim(upstreamURL.Path, "/") != "" || upstreamURL.RawQuery != "" {
+				return fmt.Errorf("controlPlane.advanced.registry.pullThrough.upstreams[%d].url must not contain a path or query", idx)
+			}
+		}
+		if upstream.CredentialsSecret != "" {
+			if errs := validation.NameIsDNSSubdomain(upstream.CredentialsSecret, false); len(errs) > 0 {
+				return fmt.Errorf("controlPlane.advanced.registry.pullThrough.upstreams[%d].credentialsSecret is not a valid secret name: %s", idx, strings.Join(errs, ", "))
+			}
+		}
+	}
+
+	if pullThrough.MaxCacheSize != "" {
+		quantity, err := resource.ParseQuantity(pullThrough.MaxCacheSize)
+		if err != nil || quantity.Sign() <= 0 {
+			return fmt.Errorf("controlPlane.advanced.registry.pullThrough.maxCacheSize has to be a positive quantity, e.g. 20Gi, got %q", pullThrough.MaxCacheSize)
+		}
+	}
+	if pullThrough.TagTTL != "" {
+		if _, err := time.ParseDuration(pullThrough.TagTTL); err != nil {
+			return fmt.Errorf("controlPlane.advanced.registry.pullThrough.tagTTL has to be a duration, e.g. 5m, got %q", pullThrough.TagTTL)
+		}
+	}
+
+	return nil
+}
+
+// setPullThroughContainerdMirrors adds the pull-through cache of the embedded registry as containerd mirror to the
+// join configurations, so joining nodes pull images of the upstreams through the vCluster endpoint.
+func setPullThroughContainerdMirrors(vConfig *VirtualClusterConfig) error {
+	pullThrough := vConfig.ControlPlane.Advanced.Registry.PullThrough
+	if !pullThrough.Enabled || !pullThrough.ContainerdMirrors {
+		return nil
+	} else if vConfig.ControlPlane.Endpoint == "" {
+		return fmt.Errorf("controlPlane.advanced.registry.pullThrough.containerdMirrors requires controlPlane.endpoint")
+	} else if !vConfig.ControlPlane.Advanced.Registry.AnonymousPull {
+		// containerd has no credentials for the vCluster endpoint, so pulls through the mirror would be rejected
+		return fmt.Errorf("controlPlane.advanced.registry.pullThrough.containerdMirrors requires controlPlane.advanced.registry.anonymousPull")
+	}
+
+	if vConfig.PrivateNodes.Enabled {
+		joinNode := &vConfig.PrivateNodes.JoinNode
+		joinNode.Containerd.Registry.Mirrors = vConfig.ContainerdRegistryMirrors(*joinNode, vConfig.ControlPlane.Endpoint)
+	}
+	if vConfig.ControlPlane.Standalone.Enabled && vConfig.ControlPlane.Standalone.JoinNode.Enabled {
+		joinNode := &vConfig.ControlPlane.Standalone.JoinNode.JoinConfiguration
+		joinNode.Containerd.Registry.Mirrors = vConfig.ContainerdRegistryMirrors(*joinNode, vConfig.ControlPlane.Endpoint)
+	}
+
+	return nil
+}
+
+func validateDatabaseMaintenance(maintenance config.DatabaseMaintenance) error {
+	if maintenance.VolumeUsageWarningPercentage < 0 || maintenance.VolumeUsageWarningPercentage > 100 {
+		return fmt.Errorf("controlPlane.backingStore.database.maintenance.volumeUsageWarningPercentage has to be between 0 and 100, got %d", maintenance.VolumeUsageWarningPercentage)
+	}
+	if maintenance.CompactInterval != "" {
+		if interval, err := time.ParseDuration(maintenance.CompactInterval); err != nil || interval < 0 {
+			return fmt.Errorf("controlPlane.backingStore.database.maintenance.compactInterval has to be a duration, e.g. 5m, got %q", maintenance.CompactInterval)
+		}
+	}
+	if maintenance.RetainRevisions < 0 {
+		return fmt.Errorf("controlPlane.backingStore.database.maintenance.retainRevisions must not be negative, got %d", maintenance.RetainRevisions)
+	}
+	if !maintenance.Vacuum.Enabled {
+		return nil
+	}
+
+	if _, err := cron.ParseStandard(maintenance.Vacuum.Schedule); err != nil {
+		return fmt.Errorf("controlPlane.backingStore.database.maintenance.vacuum.schedule has to be a cron schedule, e.g. 0 3 * * *, got %q: %w", maintenance.Vacuum.Schedule, err)
+	}
+	if duration, err := time.ParseDuration(maintenance.Vacuum.Duration); err != nil || duration <= 0 {
+		return fmt.Errorf("controlPlane.backingStore.database.maintenance.vacuum.duration has to be a positive duration, e.g. 1h, got %q", maintenance.Vacuum.Duration)
+	}
+
+	return nil
+}
+
+func validateTracing(tracing config.ControlPlaneTracing) error {
+	if tracing.SamplingRatePerMillion < 0 || tracing.SamplingRatePerMillion > 1000000 {
+		return fmt.Errorf("controlPlane.advanced.tracing.samplingRatePerMillion has to be between 0 and 1000000, got %d", tracing.SamplingRatePerMillion)
+	} else if !tracing.Enabled || tracing.Endpoint == "" {
+		return nil
+	}
+
+	if _, port, err := net.SplitHostPort(tracing.Endpoint); err != nil || port == "" {
+		return fmt.Errorf("controlPlane.advanced.tracing.endpoint has to be a host and port, e.g. otel-collector.monitoring:4317, got %q", tracing.Endpoint)
+	}
+
+	return nil
+}
+
+func validateTelemetrySink(sink config.TelemetrySink) error {
+	switch sink.Type {
+	case "", config.TelemetrySinkTypeStdout:
+	case config.TelemetrySinkTypeFile:
+		if sink.Path == "" {
+			return fmt.Errorf("telemetry.sink.path is required if telemetry.sink.type is %s", sink.Type)
+		}
+	case config.TelemetrySinkTypeOTLP:
+		if sink.Endpoint == "" {
+			return nil
+		}
+
+		if _, port, err := net.SplitHostPort(sink.Endpoint); err != nil || port == "" {
+			return fmt.Errorf("telemetry.sink.endpoint has to be a host and port, e.g. otel-collector.monitoring:4317, got %q", sink.Endpoint)
+		}
+	default:
+		return fmt.Errorf("unsupported telemetry.sink.type %q, has to be one of file, stdout or otlp", sink.Type)
+	}
+
+	return nil
+}
+
 func ValidateCustomResourceSyncProxyConflicts(toHostCustomResources map[string]config.SyncToHostCustomResource, fromHostCustomResources map[string]config.SyncFromHostCustomResource, proxyCustomResources map[string]config.CustomResourceProxy) error {
 	// Only consider enabled resources for conflict detection
 	enabledToHost := lo.Keys(lo.PickBy(toHostCustomResources, func(_ string, v config.SyncToHostCustomResource) bool { return v.Enabled }))
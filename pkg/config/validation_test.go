@@ -901,6 +901,381 @@ func TestValidateToHostSyncAndIstioIntegration(t *testing.T) {
 	}
 }
 
+func TestValidatePrometheusOperatorIntegration(t *testing.T) {
+	prometheusOperatorEnabled := config.PrometheusOperator{
+		EnableSwitch: config.EnableSwitch{Enabled: true},
+		Sync: config.PrometheusOperatorSync{ToHost: config.PrometheusOperatorSyncToHost{
+			ServiceMonitors: config.EnableSwitch{Enabled: true},
+			PodMonitors:     config.EnableSwitch{Enabled: true},
+			PrometheusRules: config.EnableSwitch{Enabled: false},
+		}},
+	}
+
+	cases := []struct {
+		name                          string
+		customResourcesToHostSync     map[string]config.SyncToHostCustomResource
+		prometheusOperatorIntegration config.PrometheusOperator
+		checkErr                      func(t *testing.T, err error)
+	}{
+		{
+			name: "valid config",
+			customResourcesToHostSync: map[string]config.SyncToHostCustomResource{
+				"prometheusrules.monitoring.coreos.com": {
+					Enabled: true,
+					Scope:   config.ScopeNamespaced,
+				},
+			},
+			prometheusOperatorIntegration: prometheusOperatorEnabled,
+			checkErr:                      noErrExpected,
+		},
+		{
+			name: "service monitors listed in sync.toHost.customResources",
+			customResourcesToHostSync: map[string]config.SyncToHostCustomResource{
+				"servicemonitors.monitoring.coreos.com": {
+					Enabled: true,
+					Scope:   config.ScopeNamespaced,
+				},
+			},
+			prometheusOperatorIntegration: prometheusOperatorEnabled,
+			checkErr: expectErr("prometheus-operator integration is enabled but prometheus-operator custom resource " +
+				"(servicemonitors.monitoring.coreos.com) is also set in the sync.toHost.customResources. " +
+				"This is not supported, please remove the entry from sync.toHost.customResources"),
+		},
+		{
+			name: "service monitors listed in sync.toHost.customResources but integration disabled",
+			customResourcesToHostSync: map[string]config.SyncToHostCustomResource{
+				"servicemonitors.monitoring.coreos.com": {
+					Enabled: true,
+					Scope:   config.ScopeNamespaced,
+				},
+			},
+			checkErr: noErrExpected,
+		},
+		{
+			name: "valid enforced labels",
+			prometheusOperatorIntegration: config.PrometheusOperator{
+				EnableSwitch:   config.EnableSwitch{Enabled: true},
+				EnforcedLabels: map[string]string{"example.com/tenant": "team-a"},
+			},
+			checkErr: noErrExpected,
+		},
+		{
+			name: "invalid enforced label value",
+			prometheusOperatorIntegration: config.PrometheusOperator{
+				EnableSwitch:   config.EnableSwitch{Enabled: true},
+				EnforcedLabels: map[string]string{"tenant": "team a"},
+			},
+			checkErr: expectErr(`integrations.prometheusOperator.enforcedLabels: invalid label value "team a" for key "tenant": ` +
+				"a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validatePrometheusOperatorEnabled(tc.customResourcesToHostSync, tc.prometheusOperatorIntegration)
+			tc.checkErr(t, err)
+		})
+	}
+}
+
+func TestValidateHostnamePolicy(t *testing.T) {
+	cases := []struct {
+		name     string
+		policy   config.HostnamePolicy
+		checkErr func(t *testing.T, err error)
+	}{
+		{
+			name:     "disabled policy",
+			policy:   config.HostnamePolicy{Prefix: "Team A"},
+			checkErr: noErrExpected,
+		},
+		{
+			name: "valid policy",
+			policy: config.HostnamePolicy{
+				Enabled:        true,
+				AllowedDomains: []string{"apps.example.com", "example.org."},
+				Prefix:         "team-a-",
+				Suffix:         "-prod",
+			},
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "no allowed domains",
+			policy:   config.HostnamePolicy{Enabled: true, Prefix: "team-a-"},
+			checkErr: expectErr("policies.hostnames.allowedDomains must not be empty when policies.hostnames.enabled is true"),
+		},
+		{
+			name:   "wildcard domain",
+			policy: config.HostnamePolicy{Enabled: true, AllowedDomains: []string{"*.apps.example.com"}},
+			checkErr: expectErr(`policies.hostnames.allowedDomains[0] "*.apps.example.com" is not a valid domain: ` +
+				"a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')"),
+		},
+		{
+			name:     "invalid prefix",
+			policy:   config.HostnamePolicy{Enabled: true, AllowedDomains: []string{"apps.example.com"}, Prefix: "team.a-"},
+			checkErr: expectErr(`policies.hostnames.prefix "team.a-" must only consist of lower case alphanumeric characters or '-'`),
+		},
+		{
+			name:     "invalid suffix",
+			policy:   config.HostnamePolicy{Enabled: true, AllowedDomains: []string{"apps.example.com"}, Suffix: "-Team-A"},
+			checkErr: expectErr(`policies.hostnames.suffix "-Team-A" must only consist of lower case alphanumeric characters or '-'`),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateHostnamePolicy(tc.policy)
+			tc.checkErr(t, err)
+		})
+	}
+}
+
+func TestValidateNamingTemplate(t *testing.T) {
+	cases := []struct {
+		name     string
+		toHost   config.SyncToHost
+		checkErr func(t *testing.T, err error)
+	}{
+		{
+			name:     "no template",
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "valid template",
+			toHost:   config.SyncToHost{Naming: config.SyncToHostNaming{Template: "{{.VCluster}}-{{.Namespace}}-{{.Name}}"}},
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "template missing namespace",
+			toHost:   config.SyncToHost{Naming: config.SyncToHostNaming{Template: "{{.VCluster}}-{{.Name}}"}},
+			checkErr: expectErr(`sync.toHost.naming.template: name template "{{.VCluster}}-{{.Name}}": must use .Namespace exactly once, but uses it 0 times`),
+		},
+		{
+			name: "template with namespace sync",
+			toHost: config.SyncToHost{
+				Naming:     config.SyncToHostNaming{Template: "{{.VCluster}}-{{.Namespace}}-{{.Name}}"},
+				Namespaces: config.SyncToHostNamespaces{Enabled: true},
+			},
+			checkErr: expectErr("sync.toHost.naming.template is not supported when sync.toHost.namespaces.enabled is true, because objects keep their names in synced namespaces"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateNamingTemplate(tc.toHost)
+			tc.checkErr(t, err)
+		})
+	}
+}
+
+func TestValidateAdminNetworkPolicies(t *testing.T) {
+	cases := []struct {
+		name     string
+		toHost   config.SyncToHost
+		checkErr func(t *testing.T, err error)
+	}{
+		{
+			name:     "disabled",
+			toHost:   config.SyncToHost{AdminNetworkPolicies: config.SyncToHostAdminNetworkPolicies{MinPriority: -1}},
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "enabled",
+			toHost:   config.SyncToHost{AdminNetworkPolicies: config.SyncToHostAdminNetworkPolicies{Enabled: true, MinPriority: 500}},
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "priority out of range",
+			toHost:   config.SyncToHost{AdminNetworkPolicies: config.SyncToHostAdminNetworkPolicies{Enabled: true, MinPriority: 1001}},
+			checkErr: expectErr("sync.toHost.adminNetworkPolicies.minPriority must be between 0 and 1000, got 1001"),
+		},
+		{
+			name: "namespace sync",
+			toHost: config.SyncToHost{
+				AdminNetworkPolicies: config.SyncToHostAdminNetworkPolicies{Enabled: true},
+				Namespaces:           config.SyncToHostNamespaces{Enabled: true},
+			},
+			checkErr: expectErr("sync.toHost.adminNetworkPolicies is not supported when sync.toHost.namespaces.enabled is true"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateAdminNetworkPolicies(tc.toHost)
+			tc.checkErr(t, err)
+		})
+	}
+}
+
+func TestValidateToHostSyncMappings(t *testing.T) {
+	mappings := func(byName map[string]string) config.SyncAllResource {
+		return config.SyncAllResource{Enabled: true, Mappings: config.ToHostMappings{ByName: byName}}
+	}
+
+	cases := []struct {
+		name              string
+		toHost            config.SyncAllResource
+		fromHost          config.EnableSwitchWithResourcesMappings
+		namespacesEnabled bool
+		checkErr          func(t *testing.T, err error)
+	}{
+		{
+			name:     "no mappings",
+			toHost:   config.SyncAllResource{},
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "valid mapping",
+			toHost:   mappings(map[string]string{"team-a/registry": "shared/team-a-registry"}),
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "sync disabled",
+			toHost:   config.SyncAllResource{Mappings: config.ToHostMappings{ByName: map[string]string{"team-a/registry": "shared/team-a-registry"}}},
+			checkErr: expectErr("sync.toHost.secrets.mappings requires sync.toHost.secrets.enabled"),
+		},
+		{
+			name:              "namespace sync",
+			toHost:            mappings(map[string]string{"team-a/registry": "shared/team-a-registry"}),
+			namespacesEnabled: true,
+			checkErr:          expectErr("sync.toHost.secrets.mappings is not supported when sync.toHost.namespaces.enabled is true"),
+		},
+		{
+			name:     "wildcard",
+			toHost:   mappings(map[string]string{"team-a/*": "shared/*"}),
+			checkErr: expectErr(`sync.toHost.secrets.mappings.byName key "team-a/*": virtual name is not a valid name: a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`),
+		},
+		{
+			name:     "missing namespace",
+			toHost:   mappings(map[string]string{"team-a/registry": "team-a-registry"}),
+			checkErr: expectErr(`sync.toHost.secrets.mappings.byName value "team-a-registry": expected NAMESPACE/NAME`),
+		},
+		{
+			name: "duplicate host object",
+			toHost: mappings(map[string]string{
+				"team-a/registry": "shared/registry",
+				"team-b/registry": "shared/registry",
+			}),
+			checkErr: expectErr("sync.toHost.secrets.mappings.byName maps both team-a/registry and team-b/registry to host object shared/registry"),
+		},
+		{
+			name:   "host object synced from host",
+			toHost: mappings(map[string]string{"team-a/registry": "shared/team-a-registry"}),
+			fromHost: config.EnableSwitchWithResourcesMappings{
+				Enabled:  true,
+				Mappings: config.FromHostMappings{ByName: map[string]string{"shared/*": "shared/*"}},
+			},
+			checkErr: expectErr("sync.toHost.secrets.mappings.byName host object shared/team-a-registry is also synced by sync.fromHost.secrets.mappings"),
+		},
+		{
+			name:   "host object in vcluster namespace synced from host",
+			toHost: mappings(map[string]string{"team-a/registry": "vcluster/team-a-registry"}),
+			fromHost: config.EnableSwitchWithResourcesMappings{
+				Enabled:  true,
+				Mappings: config.FromHostMappings{ByName: map[string]string{"": "from-host"}},
+			},
+			checkErr: expectErr("sync.toHost.secrets.mappings.byName host object vcluster/team-a-registry is also synced by sync.fromHost.secrets.mappings"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateToHostSyncMappings(tc.toHost, tc.fromHost, tc.namespacesEnabled, "vcluster", "secrets")
+			tc.checkErr(t, err)
+		})
+	}
+}
+
+func TestValidateIngressAnnotationTranslation(t *testing.T) {
+	cases := []struct {
+		name        string
+		translation config.IngressAnnotationTranslation
+		checkErr    func(t *testing.T, err error)
+	}{
+		{
+			name:        "defaults",
+			translation: config.IngressAnnotationTranslation{Presets: []string{"nginx"}},
+			checkErr:    noErrExpected,
+		},
+		{
+			name: "valid rules",
+			translation: config.IngressAnnotationTranslation{
+				Presets: []string{"nginx", "haproxy", "traefik"},
+				Rules: []config.IngressAnnotationRule{
+					{Annotation: "example.com/auth-secret", Type: "secret"},
+					{Annotation: "example.com/middlewares", Type: "traefikMiddleware"},
+					{Annotation: "example.com/backends", Type: "json", Paths: []config.IngressAnnotationPath{{Path: "targets[*].serviceName", Type: "service"}}},
+				},
+			},
+			checkErr: noErrExpected,
+		},
+		{
+			name:        "unknown preset",
+			translation: config.IngressAnnotationTranslation{Presets: []string{"contour"}},
+			checkErr:    expectErr(`sync.toHost.ingresses.annotationTranslation.presets: unknown preset "contour", supported presets are: haproxy, nginx, traefik`),
+		},
+		{
+			name: "invalid annotation",
+			translation: config.IngressAnnotationTranslation{
+				Rules: []config.IngressAnnotationRule{{Annotation: "example.com/", Type: "secret"}},
+			},
+			checkErr: expectErr(`sync.toHost.ingresses.annotationTranslation.rules[0].annotation "example.com/" is invalid: name part must be non-empty, name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')`),
+		},
+		{
+			name: "duplicate annotation",
+			translation: config.IngressAnnotationTranslation{
+				Rules: []config.IngressAnnotationRule{
+					{Annotation: "example.com/auth-secret", Type: "secret"},
+					{Annotation: "example.com/auth-secret", Type: "service"},
+				},
+			},
+			checkErr: expectErr(`sync.toHost.ingresses.annotationTranslation.rules[1].annotation "example.com/auth-secret" is defined multiple times`),
+		},
+		{
+			name: "invalid type",
+			translation: config.IngressAnnotationTranslation{
+				Rules: []config.IngressAnnotationRule{{Annotation: "example.com/auth", Type: "configmap"}},
+			},
+			checkErr: expectErr(`sync.toHost.ingresses.annotationTranslation.rules[0].type "configmap" is invalid, must be one of: service, secret, traefikMiddleware, json`),
+		},
+		{
+			name: "paths without json",
+			translation: config.IngressAnnotationTranslation{
+				Rules: []config.IngressAnnotationRule{{Annotation: "example.com/auth", Type: "secret", Paths: []config.IngressAnnotationPath{{Path: "name", Type: "secret"}}}},
+			},
+			checkErr: expectErr(`sync.toHost.ingresses.annotationTranslation.rules[0].paths is only supported for type "json"`),
+		},
+		{
+			name: "json without paths",
+			translation: config.IngressAnnotationTranslation{
+				Rules: []config.IngressAnnotationRule{{Annotation: "example.com/backends", Type: "json"}},
+			},
+			checkErr: expectErr(`sync.toHost.ingresses.annotationTranslation.rules[0].paths must not be empty for type "json"`),
+		},
+		{
+			name: "invalid path",
+			translation: config.IngressAnnotationTranslation{
+				Rules: []config.IngressAnnotationRule{{Annotation: "example.com/backends", Type: "json", Paths: []config.IngressAnnotationPath{{Path: "targets[x].serviceName", Type: "service"}}}},
+			},
+			checkErr: expectErr(`sync.toHost.ingresses.annotationTranslation.rules[0].paths[0].path: path "targets[x].serviceName" has an invalid index "x", expected a number or *`),
+		},
+		{
+			name: "invalid path type",
+			translation: config.IngressAnnotationTranslation{
+				Rules: []config.IngressAnnotationRule{{Annotation: "example.com/backends", Type: "json", Paths: []config.IngressAnnotationPath{{Path: "targets[*]", Type: "json"}}}},
+			},
+			checkErr: expectErr(`sync.toHost.ingresses.annotationTranslation.rules[0].paths[0].type "json" is invalid, must be one of: service, secret`),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateIngressAnnotationTranslation(tc.translation)
+			tc.checkErr(t, err)
+		})
+	}
+}
+
 func TestValidateToHostSyncAndCertManagerIntegration(t *testing.T) {
 	certManagerEnabled := config.CertManager{
 		EnableSwitch: config.EnableSwitch{Enabled: true},
@@ -1823,6 +2198,378 @@ func TestValidateAdvancedControlPlaneConfig(t *testing.T) {
 	}
 }
 
+func TestValidateProxyOIDC(t *testing.T) {
+	type testCase struct {
+		name     string
+		oidc     config.ControlPlaneProxyOIDC
+		checkErr func(t *testing.T, err error)
+	}
+
+	testCases := []testCase{
+		{
+			name:     "Valid: disabled",
+			oidc:     config.ControlPlaneProxyOIDC{IssuerURL: "http://invalid"},
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "Valid: issuer and client id set",
+			oidc:     config.ControlPlaneProxyOIDC{Enabled: true, IssuerURL: "https://dex.example.com/dex", ClientID: "vcluster"},
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "Invalid: http issuer",
+			oidc:     config.ControlPlaneProxyOIDC{Enabled: true, IssuerURL: "http://dex.example.com", ClientID: "vcluster"},
+			checkErr: expectErr(`controlPlane.proxy.oidc.issuerURL has to be a valid https URL, got "http://dex.example.com"`),
+		},
+		{
+			name:     "Invalid: issuer with query",
+			oidc:     config.ControlPlaneProxyOIDC{Enabled: true, IssuerURL: "https://dex.example.com?foo=bar", ClientID: "vcluster"},
+			checkErr: expectErr("controlPlane.proxy.oidc.issuerURL must not contain a query or fragment"),
+		},
+		{
+			name:     "Invalid: missing client id",
+			oidc:     config.ControlPlaneProxyOIDC{Enabled: true, IssuerURL: "https://dex.example.com"},
+			checkErr: expectErr("controlPlane.proxy.oidc.clientID is required"),
+		},
+		{
+			name:     "Invalid: ca",
+			oidc:     config.ControlPlaneProxyOIDC{Enabled: true, IssuerURL: "https://dex.example.com", ClientID: "vcluster", CA: "invalid"},
+			checkErr: expectErr("controlPlane.proxy.oidc.ca does not contain a valid PEM encoded certificate"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.checkErr(t, validateProxyOIDC(tc.oidc))
+		})
+	}
+}
+
+func TestValidateRegistryPullThrough(t *testing.T) {
+	type testCase struct {
+		name        string
+		pullThrough config.RegistryPullThrough
+		disabled    bool
+		checkErr    func(t *testing.T, err error)
+	}
+
+	testCases := []testCase{
+		{
+			name:        "Valid: disabled",
+			pullThrough: config.RegistryPullThrough{MaxCacheSize: "invalid"},
+			checkErr:    noErrExpected,
+		},
+		{
+			name: "Valid: upstreams",
+			pullThrough: config.RegistryPullThrough{
+				Enabled:      true,
+				MaxCacheSize: "20Gi",
+				TagTTL:       "5m",
+				Upstreams: []config.RegistryUpstream{
+					{Name: "docker.io", CredentialsSecret: "docker-hub"},
+					{Name: "mirror.example.com:5000", URL: "http://mirror.example.com:5000/"},
+				},
+			},
+			checkErr: noErrExpected,
+		},
+		{
+			name:        "Invalid: registry disabled",
+			pullThrough: config.RegistryPullThrough{Enabled: true, Upstreams: []config.RegistryUpstream{{Name: "docker.io"}}},
+			disabled:    true,
+			checkErr:    expectErr("controlPlane.advanced.registry.pullThrough requires controlPlane.advanced.registry.enabled"),
+		},
+		{
+			name:        "Invalid: no upstreams",
+			pullThrough: config.RegistryPullThrough{Enabled: true},
+			checkErr:    expectErr("controlPlane.advanced.registry.pullThrough.upstreams must contain at least one upstream"),
+		},
+		{
+			name:        "Invalid: name with path",
+			pullThrough: config.RegistryPullThrough{Enabled: true, Upstreams: []config.RegistryUpstream{{Name: "docker.io/library"}}},
+			checkErr:    expectErr(`controlPlane.advanced.registry.pullThrough.upstreams[0].name has to be a registry host, e.g. docker.io, got "docker.io/library"`),
+		},
+		{
+			name:        "Invalid: duplicate name",
+			pullThrough: config.RegistryPullThrough{Enabled: true, Upstreams: []config.RegistryUpstream{{Name: "ghcr.io"}, {Name: "ghcr.io"}}},
+			checkErr:    expectErr("controlPlane.advanced.registry.pullThrough.upstreams[1].name ghcr.io is defined twice"),
+		},
+		{
+			name:        "Invalid: url with path",
+			pullThrough: config.RegistryPullThrough{Enabled: true, Upstreams: []config.RegistryUpstream{{Name: "ghcr.io", URL: "https://ghcr.io/v2"}}},
+			checkErr:    expectErr("controlPlane.advanced.registry.pullThrough.upstreams[0].url must not contain a path or query"),
+		},
+		{
+			name:        "Invalid: max cache size",
+			pullThrough: config.RegistryPullThrough{Enabled: true, MaxCacheSize: "lots", Upstreams: []config.RegistryUpstream{{Name: "ghcr.io"}}},
+			checkErr:    expectErr(`controlPlane.advanced.registry.pullThrough.maxCacheSize has to be a positive quantity, e.g. 20Gi, got "lots"`),
+		},
+		{
+			name:        "Invalid: tag ttl",
+			pullThrough: config.RegistryPullThrough{Enabled: true, TagTTL: "5", Upstreams: []config.RegistryUpstream{{Name: "ghcr.io"}}},
+			checkErr:    expectErr(`controlPlane.advanced.registry.pullThrough.tagTTL has to be a duration, e.g. 5m, got "5"`),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.checkErr(t, validateRegistryPullThrough(config.Registry{Enabled: !tc.disabled, PullThrough: tc.pullThrough}))
+		})
+	}
+}
+
+func TestSetPullThroughContainerdMirrors(t *testing.T) {
+	vConfig := &VirtualClusterConfig{}
+	vConfig.PrivateNodes.Enabled = true
+	vConfig.ControlPlane.Advanced.Registry.Enabled = true
+	vConfig.ControlPlane.Advanced.Registry.PullThrough = config.RegistryPullThrough{
+		Enabled:           true,
+		ContainerdMirrors: true,
+		Upstreams:         []config.RegistryUpstream{{Name: "docker.io"}},
+	}
+	expectErr("controlPlane.advanced.registry.pullThrough.containerdMirrors requires controlPlane.endpoint")(t, setPullThroughContainerdMirrors(vConfig))
+
+	vConfig.ControlPlane.Endpoint = "my-vcluster.example.com:443"
+	expectErr("controlPlane.advanced.registry.pullThrough.containerdMirrors requires controlPlane.advanced.registry.anonymousPull")(t, setPullThroughContainerdMirrors(vConfig))
+
+	vConfig.ControlPlane.Advanced.Registry.AnonymousPull = true
+	noErrExpected(t, setPullThroughContainerdMirrors(vConfig))
+	mirror := vConfig.PrivateNodes.JoinNode.Containerd.Registry.Mirrors["docker.io"]
+	if len(mirror.Hosts) != 1 || mirror.Hosts[0].Server != "https://my-vcluster.example.com:443/v2/docker.io" {
+		t.Fatalf("unexpected containerd mirror for docker.io: %+v", mirror)
+	}
+}
+
+func TestValidateDatabaseMaintenance(t *testing.T) {
+	testCases := []struct {
+		name        string
+		maintenance config.DatabaseMaintenance
+		checkErr    func(t *testing.T, err error)
+	}{
+		{
+			name:        "Valid: defaults",
+			maintenance: config.DatabaseMaintenance{},
+			checkErr:    noErrExpected,
+		},
+		{
+			name:        "Valid: compaction",
+			maintenance: config.DatabaseMaintenance{CompactInterval: "5m", RetainRevisions: 1000, VolumeUsageWarningPercentage: 85},
+			checkErr:    noErrExpected,
+		},
+		{
+			name:        "Valid: compaction disabled",
+			maintenance: config.DatabaseMaintenance{CompactInterval: "0s"},
+			checkErr:    noErrExpected,
+		},
+		{
+			name:        "Invalid: percentage",
+			maintenance: config.DatabaseMaintenance{VolumeUsageWarningPercentage: 120},
+			checkErr:    expectErr("controlPlane.backingStore.database.maintenance.volumeUsageWarningPercentage has to be between 0 and 100, got 120"),
+		},
+		{
+			name:        "Invalid: compact interval",
+			maintenance: config.DatabaseMaintenance{CompactInterval: "daily"},
+			checkErr:    expectErr(`controlPlane.backingStore.database.maintenance.compactInterval has to be a duration, e.g. 5m, got "daily"`),
+		},
+		{
+			name:        "Invalid: retain revisions",
+			maintenance: config.DatabaseMaintenance{RetainRevisions: -1},
+			checkErr:    expectErr("controlPlane.backingStore.database.maintenance.retainRevisions must not be negative, got -1"),
+		},
+		{
+			name:        "Valid: vacuum",
+			maintenance: config.DatabaseMaintenance{Vacuum: config.DatabaseVacuum{Enabled: true, Schedule: "0 3 * * *", Duration: "1h"}},
+			checkErr:    noErrExpected,
+		},
+		{
+			name:        "Valid: vacuum disabled",
+			maintenance: config.DatabaseMaintenance{Vacuum: config.DatabaseVacuum{Schedule: "invalid"}},
+			checkErr:    noErrExpected,
+		},
+		{
+			name:        "Invalid: vacuum schedule",
+			maintenance: config.DatabaseMaintenance{Vacuum: config.DatabaseVacuum{Enabled: true, Schedule: "daily", Duration: "1h"}},
+			checkErr:    expectErr(`controlPlane.backingStore.database.maintenance.vacuum.schedule has to be a cron schedule, e.g. 0 3 * * *, got "daily": expected exactly 5 fields, found 1: [daily]`),
+		},
+		{
+			name:        "Invalid: vacuum duration",
+			maintenance: config.DatabaseMaintenance{Vacuum: config.DatabaseVacuum{Enabled: true, Schedule: "0 3 * * *", Duration: "0s"}},
+			checkErr:    expectErr(`controlPlane.backingStore.database.maintenance.vacuum.duration has to be a positive duration, e.g. 1h, got "0s"`),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.checkErr(t, validateDatabaseMaintenance(tc.maintenance))
+		})
+	}
+}
+
+func TestValidateTracing(t *testing.T) {
+	testCases := []struct {
+		name     string
+		tracing  config.ControlPlaneTracing
+		checkErr func(t *testing.T, err error)
+	}{
+		{
+			name:     "Valid: disabled",
+			tracing:  config.ControlPlaneTracing{Endpoint: "invalid"},
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "Valid: default endpoint",
+			tracing:  config.ControlPlaneTracing{Enabled: true, SamplingRatePerMillion: 1000000},
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "Valid: endpoint",
+			tracing:  config.ControlPlaneTracing{Enabled: true, Endpoint: "otel-collector.monitoring:4317", SamplingRatePerMillion: 10000},
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "Invalid: sampling rate",
+			tracing:  config.ControlPlaneTracing{Enabled: true, SamplingRatePerMillion: 2000000},
+			checkErr: expectErr("controlPlane.advanced.tracing.samplingRatePerMillion has to be between 0 and 1000000, got 2000000"),
+		},
+		{
+			name:     "Invalid: endpoint with scheme",
+			tracing:  config.ControlPlaneTracing{Enabled: true, Endpoint: "http://otel-collector.monitoring:4317"},
+			checkErr: expectErr(`controlPlane.advanced.tracing.endpoint has to be a host and port, e.g. otel-collector.monitoring:4317, got "http://otel-collector.monitoring:4317"`),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.checkErr(t, validateTracing(tc.tracing))
+		})
+	}
+}
+
+func TestValidateTelemetrySink(t *testing.T) {
+	testCases := []struct {
+		name     string
+		sink     config.TelemetrySink
+		checkErr func(t *testing.T, err error)
+	}{
+		{
+			name:     "Valid: hosted backend",
+			sink:     config.TelemetrySink{},
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "Valid: stdout",
+			sink:     config.TelemetrySink{Type: config.TelemetrySinkTypeStdout},
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "Valid: file",
+			sink:     config.TelemetrySink{Type: config.TelemetrySinkTypeFile, Path: "/data/telemetry.jsonl"},
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "Valid: otlp default endpoint",
+			sink:     config.TelemetrySink{Type: config.TelemetrySinkTypeOTLP},
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "Valid: otlp endpoint",
+			sink:     config.TelemetrySink{Type: config.TelemetrySinkTypeOTLP, Endpoint: "otel-collector.monitoring:4317"},
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "Invalid: file without path",
+			sink:     config.TelemetrySink{Type: config.TelemetrySinkTypeFile},
+			checkErr: expectErr("telemetry.sink.path is required if telemetry.sink.type is file"),
+		},
+		{
+			name:     "Invalid: otlp endpoint without port",
+			sink:     config.TelemetrySink{Type: config.TelemetrySinkTypeOTLP, Endpoint: "otel-collector.monitoring"},
+			checkErr: expectErr(`telemetry.sink.endpoint has to be a host and port, e.g. otel-collector.monitoring:4317, got "otel-collector.monitoring"`),
+		},
+		{
+			name:     "Invalid: type",
+			sink:     config.TelemetrySink{Type: "http"},
+			checkErr: expectErr(`unsupported telemetry.sink.type "http", has to be one of file, stdout or otlp`),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.checkErr(t, validateTelemetrySink(tc.sink))
+		})
+	}
+}
+
+func TestValidateCoreDNSZones(t *testing.T) {
+	testCases := []struct {
+		name       string
+		coreDNS    config.CoreDNS
+		networking config.Networking
+		checkErr   func(t *testing.T, err error)
+	}{
+		{
+			name: "Valid: zones",
+			coreDNS: config.CoreDNS{
+				StubDomains: []config.CoreDNSStubDomain{
+					{Domain: "corp.internal", CoreDNSForward: config.CoreDNSForward{Upstreams: []string{"10.0.0.10", "10.0.0.11:5353"}, Policy: "sequential"}},
+					{Domain: "lab.internal.", CoreDNSForward: config.CoreDNSForward{Upstreams: []string{"tls://10.0.1.10", "[fd00::10]:53"}}},
+				},
+				Forward:  config.CoreDNSForward{Upstreams: []string{"1.1.1.1"}, Policy: "round_robin"},
+				Hosts:    []config.CoreDNSHost{{IP: "10.0.0.5", Hostnames: []string{"registry.corp.internal"}}},
+				Rewrites: []config.CoreDNSRewrite{{Match: "regex", From: "(.*)\\.legacy\\.internal", To: "{1}.default.svc.cluster.local"}},
+			},
+			checkErr: noErrExpected,
+		},
+		{
+			name:     "Invalid: overwrite config",
+			coreDNS:  config.CoreDNS{OverwriteConfig: ".:1053 {}", Hosts: []config.CoreDNSHost{{IP: "10.0.0.5", Hostnames: []string{"registry.corp.internal"}}}},
+			checkErr: expectErr("controlPlane.coredns.overwriteConfig cannot be combined with controlPlane.coredns.stubDomains, forward, hosts or rewrites"),
+		},
+		{
+			name:       "Invalid: stub domain within cluster domain",
+			coreDNS:    config.CoreDNS{StubDomains: []config.CoreDNSStubDomain{{Domain: "corp.vcluster.local", CoreDNSForward: config.CoreDNSForward{Upstreams: []string{"10.0.0.10"}}}}},
+			networking: config.Networking{Advanced: config.NetworkingAdvanced{ClusterDomain: "vcluster.local"}},
+			checkErr:   expectErr("error validating controlPlane.coredns.stubDomains[0].domain: corp.vcluster.local is part of the cluster domain vcluster.local"),
+		},
+		{
+			name:     "Invalid: stub domain without upstreams",
+			coreDNS:  config.CoreDNS{StubDomains: []config.CoreDNSStubDomain{{Domain: "corp.internal"}}},
+			checkErr: expectErr("error validating controlPlane.coredns.stubDomains[0].upstreams: at least one upstream is required"),
+		},
+		{
+			name:     "Invalid: stub domain upstream hostname",
+			coreDNS:  config.CoreDNS{StubDomains: []config.CoreDNSStubDomain{{Domain: "corp.internal", CoreDNSForward: config.CoreDNSForward{Upstreams: []string{"dns.corp.internal"}}}}},
+			checkErr: expectErr("error validating controlPlane.coredns.stubDomains[0]: upstream has to be an IP address with an optional port and dns:// or tls:// scheme, but got dns.corp.internal"),
+		},
+		{
+			name:     "Invalid: forward policy",
+			coreDNS:  config.CoreDNS{Forward: config.CoreDNSForward{Upstreams: []string{"1.1.1.1"}, Policy: "first"}},
+			checkErr: expectErr("error validating controlPlane.coredns.forward: policy has to be one of [random round_robin sequential], but got first"),
+		},
+		{
+			name:       "Invalid: forward with fallback host cluster",
+			coreDNS:    config.CoreDNS{Forward: config.CoreDNSForward{Upstreams: []string{"1.1.1.1"}}},
+			networking: config.Networking{Advanced: config.NetworkingAdvanced{FallbackHostCluster: true}},
+			checkErr:   expectErr("controlPlane.coredns.forward cannot be combined with networking.advanced.fallbackHostCluster"),
+		},
+		{
+			name:     "Invalid: host ip",
+			coreDNS:  config.CoreDNS{Hosts: []config.CoreDNSHost{{IP: "10.0.0", Hostnames: []string{"registry.corp.internal"}}}},
+			checkErr: expectErr(`error validating controlPlane.coredns.hosts[0].ip: "10.0.0" is not a valid IP address`),
+		},
+		{
+			name:     "Invalid: rewrite match",
+			coreDNS:  config.CoreDNS{Rewrites: []config.CoreDNSRewrite{{Match: "glob", From: "*.example.com", To: "example.com"}}},
+			checkErr: expectErr("error validating controlPlane.coredns.rewrites[0].match: has to be one of [exact prefix suffix substring regex], but got glob"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.checkErr(t, validateCoreDNSZones(tc.coreDNS, tc.networking))
+		})
+	}
+}
+
 func TestValidateCustomResourceSyncProxyConflicts(t *testing.T) {
 	cases := []struct {
 		name        string
@@ -5,6 +5,23 @@ import "path/filepath"
 const (
 	VClusterStorageOptionsEnv = "VCLUSTER_STORAGE_OPTIONS"
 
+	// VClusterMigrateBackingStoreEnv holds the json encoded target backing store of vcluster migrate-backing-store
+	VClusterMigrateBackingStoreEnv = "VCLUSTER_MIGRATE_BACKING_STORE"
+
+	// VClusterMigrateBackingStoreSourceEnv holds the json encoded source backing store of vcluster migrate-backing-store,
+	// as the config of the vCluster may already point to the target
+	VClusterMigrateBackingStoreSourceEnv = "VCLUSTER_MIGRATE_BACKING_STORE_SOURCE"
+
+	// VClusterRestoreFromHostNamespaceEnv holds the host namespace of the vCluster a snapshot was taken from when it is
+	// restored by vcluster move
+	VClusterRestoreFromHostNamespaceEnv = "VCLUSTER_RESTORE_FROM_HOST_NAMESPACE"
+
+	// VClusterRestoreScrubSecretsEnv empties all secret values on restore if set to true, used by vcluster clone
+	VClusterRestoreScrubSecretsEnv = "VCLUSTER_RESTORE_SCRUB_SECRETS"
+
+	// VClusterRestoreScaleDownWorkloadsEnv scales all workloads to zero on restore if set to true, used by vcluster clone
+	VClusterRestoreScaleDownWorkloadsEnv = "VCLUSTER_RESTORE_SCALE_DOWN_WORKLOADS"
+
 	// LocalBackingStoreMetricsHost is the loopback host:port that the in-pod
 	// backing store (kine or embedded etcd) binds its Prometheus metrics
 	// endpoint to. The two are mutually exclusive, so they share a port.
@@ -0,0 +1,164 @@
+package adminnetworkpolicies
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	syncertypes "github.com/loft-sh/vcluster/pkg/syncer/types"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var baselineRequest = []reconcile.Request{{NamespacedName: types.NamespacedName{Name: baselineAdminNetworkPolicyName}}}
+
+// NewBaseline creates the controller for the BaselineAdminNetworkPolicy. The host BaselineAdminNetworkPolicy is a
+// singleton that belongs to the host cluster, so the virtual one is translated into a host NetworkPolicy instead.
+// NetworkPolicies take precedence over the baseline policy in the host cluster as well.
+func NewBaseline(ctx *synccontext.RegisterContext) (syncertypes.Object, error) {
+	_, _, err := translate.EnsureCRDFromPhysicalCluster(ctx, ctx.HostManager.GetConfig(), ctx.VirtualManager.GetConfig(), BaselineAdminNetworkPolicies)
+	if err != nil {
+		return nil, fmt.Errorf("ensure %s crd from host cluster: %w", BaselineAdminNetworkPolicies.Kind, err)
+	}
+
+	return &baselineAdminNetworkPolicySyncer{
+		hostName:      translate.SafeConcatName("vcluster", "baseline", "x", translate.VClusterName),
+		hostNamespace: ctx.Config.HostNamespace,
+		eventRecorder: ctx.VirtualManager.GetEventRecorder("baselineadminnetworkpolicy-syncer"),
+	}, nil
+}
+
+type baselineAdminNetworkPolicySyncer struct {
+	virtualClient client.Client
+	hostClient    client.Client
+	eventRecorder events.EventRecorder
+
+	hostName      string
+	hostNamespace string
+
+	// reportedGeneration is the generation of the virtual policy the warnings were last reported for
+	reportedGeneration int64
+}
+
+func (s *baselineAdminNetworkPolicySyncer) Name() string {
+	return "baselineadminnetworkpolicy"
+}
+
+func (s *baselineAdminNetworkPolicySyncer) Resource() client.Object {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(BaselineAdminNetworkPolicies)
+	return obj
+}
+
+var _ syncertypes.ControllerStarter = &baselineAdminNetworkPolicySyncer{}
+
+func (s *baselineAdminNetworkPolicySyncer) Register(ctx *synccontext.RegisterContext) error {
+	s.virtualClient = ctx.VirtualManager.GetClient()
+	s.hostClient = ctx.HostManager.GetClient()
+
+	return ctrl.NewControllerManagedBy(ctx.VirtualManager).
+		WithOptions(controller.Options{
+			CacheSyncTimeout: constants.DefaultCacheSyncTimeout,
+		}).
+		Named(s.Name()).
+		For(s.Resource()).
+		// the host selectors list the matching virtual namespaces
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+			return baselineRequest
+		})).
+		WatchesRawSource(source.Kind(ctx.HostManager.GetCache(), &networkingv1.NetworkPolicy{}, handler.TypedEnqueueRequestsFromMapFunc(func(_ context.Context, networkPolicy *networkingv1.NetworkPolicy) []reconcile.Request {
+			if networkPolicy.Name != s.hostName || networkPolicy.Namespace != s.hostNamespace {
+				return nil
+			}
+			return baselineRequest
+		}))).
+		Complete(s)
+}
+
+func (s *baselineAdminNetworkPolicySyncer) Reconcile(ctx context.Context, _ reconcile.Request) (ctrl.Result, error) {
+	vObj := &unstructured.Unstructured{}
+	vObj.SetGroupVersionKind(BaselineAdminNetworkPolicies)
+	err := s.virtualClient.Get(ctx, types.NamespacedName{Name: baselineAdminNetworkPolicyName}, vObj)
+	if kerrors.IsNotFound(err) {
+		return ctrl.Result{}, s.deleteHostNetworkPolicy(ctx)
+	} else if err != nil {
+		return ctrl.Result{}, fmt.Errorf("get %s: %w", BaselineAdminNetworkPolicies.Kind, err)
+	} else if vObj.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, s.deleteHostNetworkPolicy(ctx)
+	}
+
+	spec, err := specFromUnstructured(vObj)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	err = s.virtualClient.List(ctx, namespaces)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("list virtual namespaces: %w", err)
+	}
+
+	t := &policyTranslator{hostNamespace: s.hostNamespace, namespaces: namespaces.Items}
+	networkPolicySpec := t.translateBaselineAdminNetworkPolicySpec(spec)
+
+	// only report once per change of the policy, so events aren't repeated on every resync
+	if vObj.GetGeneration() != s.reportedGeneration {
+		for _, warning := range t.warnings {
+			s.eventRecorder.Eventf(vObj, nil, corev1.EventTypeWarning, "UnsupportedField", "Sync"+BaselineAdminNetworkPolicies.Kind, "%s", warning)
+		}
+		s.reportedGeneration = vObj.GetGeneration()
+	}
+
+	if networkPolicySpec == nil {
+		// nothing is isolated, so there is nothing to enforce
+		return ctrl.Result{}, s.deleteHostNetworkPolicy(ctx)
+	}
+
+	pObj := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: s.hostName, Namespace: s.hostNamespace}}
+	_, err = controllerutil.CreateOrPatch(ctx, s.hostClient, pObj, func() error {
+		if pObj.Labels == nil {
+			pObj.Labels = map[string]string{}
+		}
+		pObj.Labels[translate.MarkerLabel] = translate.VClusterName
+		pObj.Spec = *networkPolicySpec
+		return nil
+	})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("apply host network policy: %w", err)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (s *baselineAdminNetworkPolicySyncer) deleteHostNetworkPolicy(ctx context.Context) error {
+	pObj := &networkingv1.NetworkPolicy{}
+	err := s.hostClient.Get(ctx, types.NamespacedName{Name: s.hostName, Namespace: s.hostNamespace}, pObj)
+	if kerrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("get host network policy: %w", err)
+	} else if pObj.Labels[translate.MarkerLabel] != translate.VClusterName {
+		return nil
+	}
+
+	err = s.hostClient.Delete(ctx, pObj)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("delete host network policy: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package adminnetworkpolicies
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/loft-sh/vcluster/pkg/mappings/generic"
+	"github.com/loft-sh/vcluster/pkg/patcher"
+	"github.com/loft-sh/vcluster/pkg/syncer"
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/syncer/translator"
+	syncertypes "github.com/loft-sh/vcluster/pkg/syncer/types"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// New creates the syncer for AdminNetworkPolicies. The policies are synced to host AdminNetworkPolicies, so the
+// CRD has to exist in the host cluster.
+func New(ctx *synccontext.RegisterContext) (syncertypes.Object, error) {
+	_, _, err := translate.EnsureCRDFromPhysicalCluster(ctx, ctx.HostManager.GetConfig(), ctx.VirtualManager.GetConfig(), AdminNetworkPolicies)
+	if err != nil {
+		return nil, fmt.Errorf("ensure %s crd from host cluster: %w", AdminNetworkPolicies.Kind, err)
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(AdminNetworkPolicies)
+	mapper, err := generic.NewMapper(ctx, obj, func(_ *synccontext.SyncContext, vName, _ string) types.NamespacedName {
+		return types.NamespacedName{Name: translate.Default.HostNameCluster(vName)}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &adminNetworkPolicySyncer{
+		GenericTranslator: translator.NewGenericTranslator(ctx, "adminnetworkpolicy", obj, mapper),
+
+		hostNamespace: ctx.Config.HostNamespace,
+		minPriority:   ctx.Config.Sync.ToHost.AdminNetworkPolicies.MinPriority,
+	}, nil
+}
+
+// adminNetworkPolicySyncer syncs AdminNetworkPolicies from the virtual cluster to the host. The host policies only
+// select pods of the virtual cluster and their priority is moved into the configured range.
+type adminNetworkPolicySyncer struct {
+	syncertypes.GenericTranslator
+
+	hostNamespace string
+	minPriority   int32
+}
+
+var _ syncertypes.ControllerModifier = &adminNetworkPolicySyncer{}
+
+// ModifyController requeues all AdminNetworkPolicies whenever a virtual namespace is added, removed or relabeled,
+// because their host selectors list the matching namespaces
+func (s *adminNetworkPolicySyncer) ModifyController(registerContext *synccontext.RegisterContext, builder *builder.Builder) (*builder.Builder, error) {
+	enqueue := func(ctx context.Context, q workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+		policies := &unstructured.UnstructuredList{}
+		policies.SetGroupVersionKind(AdminNetworkPolicies.GroupVersion().WithKind(AdminNetworkPolicies.Kind + "List"))
+		err := registerContext.VirtualManager.GetClient().List(ctx, policies)
+		if err != nil {
+			klog.FromContext(ctx).Info("failed to list admin network policies when handling namespace change", "error", err)
+			return
+		}
+
+		for _, policy := range policies.Items {
+			q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: policy.GetName()}})
+		}
+	}
+
+	eventHandler := handler.Funcs{
+		CreateFunc: func(ctx context.Context, _ event.CreateEvent, q workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+			enqueue(ctx, q)
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+			// no need to reconcile the policies if namespace labels didn't change
+			if reflect.DeepEqual(e.ObjectNew.GetLabels(), e.ObjectOld.GetLabels()) {
+				return
+			}
+
+			enqueue(ctx, q)
+		},
+		DeleteFunc: func(ctx context.Context, _ event.DeleteEvent, q workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+			enqueue(ctx, q)
+		},
+	}
+
+	return builder.Watches(&corev1.Namespace{}, eventHandler), nil
+}
+
+var _ syncertypes.Syncer = &adminNetworkPolicySyncer{}
+
+func (s *adminNetworkPolicySyncer) Syncer() syncertypes.Sync[client.Object] {
+	return syncer.ToGenericSyncer[*unstructured.Unstructured](s)
+}
+
+func (s *adminNetworkPolicySyncer) SyncToHost(ctx *synccontext.SyncContext, event *synccontext.SyncToHostEvent[*unstructured.Unstructured]) (ctrl.Result, error) {
+	if event.HostOld != nil || event.Virtual.GetDeletionTimestamp() != nil {
+		return patcher.DeleteVirtualObject(ctx, event.Virtual, event.HostOld, "host object was deleted")
+	}
+
+	pObj := translate.HostMetadata(event.Virtual, s.VirtualToHost(ctx, types.NamespacedName{Name: event.Virtual.GetName()}, event.Virtual))
+	err := s.translateSpec(ctx, pObj, event.Virtual)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return patcher.CreateHostObject(ctx, event.Virtual, pObj, s.EventRecorder(), false)
+}
+
+func (s *adminNetworkPolicySyncer) Sync(ctx *synccontext.SyncContext, event *synccontext.SyncEvent[*unstructured.Unstructured]) (_ ctrl.Result, retErr error) {
+	patch, err := patcher.NewSyncerPatcher(ctx, event.Host, event.Virtual)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("new syncer patcher: %w", err)
+	}
+	defer func() {
+		if err := patch.Patch(ctx, event.Host, event.Virtual); err != nil {
+			retErr = utilerrors.NewAggregate([]error{retErr, err})
+		}
+		if retErr != nil {
+			s.EventRecorder().Eventf(
+				event.Virtual,
+				nil,
+				"Warning",
+				"SyncError",
+				fmt.Sprintf("Sync%s", event.Virtual.GetKind()),
+				"Error syncing: %v",
+				retErr,
+			)
+		}
+	}()
+
+	err = s.translateSpec(ctx, event.Host, event.Virtual)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// bi-directional sync of annotations and labels
+	newVirtualAnnotations, newHostAnnotations := translate.AnnotationsBidirectionalUpdate(event)
+	event.Virtual.SetAnnotations(newVirtualAnnotations)
+	event.Host.SetAnnotations(newHostAnnotations)
+	newVirtualLabels, newHostLabels := translate.LabelsBidirectionalUpdate(event)
+	event.Virtual.SetLabels(newVirtualLabels)
+	event.Host.SetLabels(newHostLabels)
+	return ctrl.Result{}, nil
+}
+
+func (s *adminNetworkPolicySyncer) SyncToVirtual(ctx *synccontext.SyncContext, event *synccontext.SyncToVirtualEvent[*unstructured.Unstructured]) (_ ctrl.Result, retErr error) {
+	// virtual object is not here anymore, so we delete
+	return patcher.DeleteHostObject(ctx, event.Host, event.VirtualOld, "virtual object was deleted")
+}
+
+// translateSpec sets the translated spec on the host object. Fields that can't be translated are reported as events
+// on the virtual object whenever the host spec changes.
+func (s *adminNetworkPolicySyncer) translateSpec(ctx *synccontext.SyncContext, pObj, vObj *unstructured.Unstructured) error {
+	spec, err := specFromUnstructured(vObj)
+	if err != nil {
+		return err
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	err = ctx.VirtualClient.List(ctx, namespaces)
+	if err != nil {
+		return fmt.Errorf("list virtual namespaces: %w", err)
+	}
+
+	t := &policyTranslator{hostNamespace: s.hostNamespace, namespaces: namespaces.Items}
+	oldSpec, _, _ := unstructured.NestedMap(pObj.Object, "spec")
+	err = setSpec(pObj, t.translateAdminNetworkPolicySpec(spec, s.minPriority))
+	if err != nil {
+		return err
+	}
+
+	newSpec, _, _ := unstructured.NestedMap(pObj.Object, "spec")
+	if !reflect.DeepEqual(oldSpec, newSpec) {
+		for _, warning := range t.warnings {
+			s.EventRecorder().Eventf(vObj, nil, corev1.EventTypeWarning, "UnsupportedField", fmt.Sprintf("Sync%s", vObj.GetKind()), "%s", warning)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,295 @@
+package adminnetworkpolicies
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+// policyTranslator confines the subjects and peers of a virtual policy to the pods of the virtual cluster. Everything
+// that can't be translated is collected in warnings, so it can be reported to the user through events.
+type policyTranslator struct {
+	hostNamespace string
+	namespaces    []corev1.Namespace
+
+	warnings []string
+}
+
+func (t *policyTranslator) warnf(format string, args ...interface{}) {
+	t.warnings = append(t.warnings, fmt.Sprintf(format, args...))
+}
+
+func specFromUnstructured(obj *unstructured.Unstructured) (*policySpec, error) {
+	rawSpec, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return nil, fmt.Errorf("get spec: %w", err)
+	}
+
+	spec := &policySpec{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(rawSpec, spec)
+	if err != nil {
+		return nil, fmt.Errorf("convert spec: %w", err)
+	}
+
+	return spec, nil
+}
+
+func setSpec(obj *unstructured.Unstructured, spec *policySpec) error {
+	rawSpec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(spec)
+	if err != nil {
+		return fmt.Errorf("convert spec: %w", err)
+	}
+
+	return unstructured.SetNestedMap(obj.Object, rawSpec, "spec")
+}
+
+// hostPriority maps the virtual priority 0-1000 into the range minPriority-1000. The order of the policies is kept,
+// but policies with close priorities might end up with the same host priority.
+func hostPriority(priority, minPriority int32) int32 {
+	priority = min(max(priority, 0), 1000)
+	return minPriority + priority*(1000-minPriority)/1000
+}
+
+// hostPodSelector selects the synced pods that match the pod selector in the virtual namespaces matched by the
+// namespace selector
+func (t *policyTranslator) hostPodSelector(namespaceSelector, podSelector *metav1.LabelSelector) *metav1.LabelSelector {
+	return translate.MergeLabelSelectors(
+		translate.HostLabelSelector(podSelector),
+		translate.NamespaceSelectorToHostPodSelector(namespaceSelector, t.namespaces),
+		&metav1.LabelSelector{MatchLabels: map[string]string{translate.MarkerLabel: translate.VClusterName}},
+	)
+}
+
+func (t *policyTranslator) hostPods(namespaceSelector, podSelector *metav1.LabelSelector) *namespacedPod {
+	return &namespacedPod{
+		NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: t.hostNamespace}},
+		PodSelector:       *t.hostPodSelector(namespaceSelector, podSelector),
+	}
+}
+
+func (t *policyTranslator) subjectPodSelector(s subject) *metav1.LabelSelector {
+	if s.Pods != nil {
+		return t.hostPodSelector(&s.Pods.NamespaceSelector, &s.Pods.PodSelector)
+	}
+
+	return t.hostPodSelector(s.Namespaces, nil)
+}
+
+// translateAdminNetworkPolicySpec translates the spec of a virtual AdminNetworkPolicy into the spec of a host
+// AdminNetworkPolicy that only selects pods of the virtual cluster
+func (t *policyTranslator) translateAdminNetworkPolicySpec(spec *policySpec, minPriority int32) *policySpec {
+	out := &policySpec{
+		Subject: subject{Pods: &namespacedPod{
+			NamespaceSelector: metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: t.hostNamespace}},
+			PodSelector:       *t.subjectPodSelector(spec.Subject),
+		}},
+		Ingress: t.translateRules(spec.Ingress, false),
+		Egress:  t.translateRules(spec.Egress, true),
+	}
+	if spec.Priority != nil {
+		out.Priority = ptr.To(hostPriority(*spec.Priority, minPriority))
+	}
+
+	return out
+}
+
+// translateRules confines the peers of the rules to the pods of the virtual cluster. Networks and domainNames peers
+// can't be confined, as networks might contain other pods of the host cluster. Allowing them would take precedence
+// over all NetworkPolicies in the host cluster, so they are passed on to the NetworkPolicies instead.
+func (t *policyTranslator) translateRules(rules []policyRule, egress bool) []policyRule {
+	var out []policyRule
+	for _, rule := range rules {
+		peers := []peer{}
+		externalPeers := []peer{}
+		for _, p := range rule.peers(egress) {
+			switch {
+			case p.Nodes != nil:
+				t.warnf("rule %q: nodes peers are not supported and are ignored", rule.Name)
+			case p.Pods != nil:
+				peers = append(peers, peer{Pods: t.hostPods(&p.Pods.NamespaceSelector, &p.Pods.PodSelector)})
+			case p.Namespaces != nil:
+				peers = append(peers, peer{Pods: t.hostPods(p.Namespaces, nil)})
+			default:
+				externalPeers = append(externalPeers, peer{Networks: p.Networks, DomainNames: p.DomainNames})
+			}
+		}
+		if len(peers) == 0 && len(externalPeers) == 0 {
+			t.warnf("rule %q has no supported peers and is ignored", rule.Name)
+			continue
+		}
+
+		// split off the external peers of allow rules, so only they are passed on
+		if rule.Action == actionAllow && len(externalPeers) > 0 {
+			t.warnf("rule %q: networks and domainNames peers can't be allowed and are passed on to the NetworkPolicies instead", rule.Name)
+			if len(peers) > 0 {
+				out = append(out, newPolicyRule(rule, rule.Action, peers, egress))
+			}
+			out = append(out, newPolicyRule(rule, actionPass, externalPeers, egress))
+			continue
+		}
+
+		out = append(out, newPolicyRule(rule, rule.Action, append(peers, externalPeers...), egress))
+	}
+
+	return out
+}
+
+func newPolicyRule(rule policyRule, action string, peers []peer, egress bool) policyRule {
+	newRule := policyRule{Name: rule.Name, Action: action, Ports: rule.Ports}
+	if egress {
+		newRule.To = peers
+	} else {
+		newRule.From = peers
+	}
+
+	return newRule
+}
+
+// translateBaselineAdminNetworkPolicySpec translates the BaselineAdminNetworkPolicy into a host NetworkPolicy.
+// NetworkPolicies can only allow traffic, so a direction is only isolated if the policy contains a deny rule that
+// matches all traffic. Allow rules in front of that rule become NetworkPolicy rules, deny rules that only match
+// part of the traffic can't be expressed. Returns nil if the policy doesn't isolate the subject in any direction.
+func (t *policyTranslator) translateBaselineAdminNetworkPolicySpec(spec *policySpec) *networkingv1.NetworkPolicySpec {
+	out := &networkingv1.NetworkPolicySpec{
+		PodSelector: *t.subjectPodSelector(spec.Subject),
+	}
+
+	ingress, isolated := t.translateBaselineRules(spec.Ingress, false)
+	if isolated {
+		t.warnf("denying all ingress traffic also denies traffic from outside of the virtual cluster")
+		out.PolicyTypes = append(out.PolicyTypes, networkingv1.PolicyTypeIngress)
+		for _, rule := range ingress {
+			out.Ingress = append(out.Ingress, networkingv1.NetworkPolicyIngressRule{From: rule.peers, Ports: rule.ports})
+		}
+	}
+
+	egress, isolated := t.translateBaselineRules(spec.Egress, true)
+	if isolated {
+		out.PolicyTypes = append(out.PolicyTypes, networkingv1.PolicyTypeEgress)
+		for _, rule := range egress {
+			out.Egress = append(out.Egress, networkingv1.NetworkPolicyEgressRule{To: rule.peers, Ports: rule.ports})
+		}
+	}
+
+	if len(out.PolicyTypes) == 0 {
+		return nil
+	}
+
+	return out
+}
+
+type networkPolicyRule struct {
+	peers []networkingv1.NetworkPolicyPeer
+	ports []networkingv1.NetworkPolicyPort
+}
+
+// translateBaselineRules returns the allow rules in front of the first deny rule that matches all traffic and
+// if there is such a rule at all. Rules after it can never match.
+func (t *policyTranslator) translateBaselineRules(rules []policyRule, egress bool) ([]networkPolicyRule, bool) {
+	var allowed []networkPolicyRule
+	for _, rule := range rules {
+		if rule.Action == actionDeny {
+			if deniesAll(rule, egress) {
+				return allowed, true
+			}
+
+			t.warnf("rule %q: deny rules that don't match all traffic can't be expressed as NetworkPolicy and are ignored", rule.Name)
+			continue
+		} else if rule.Action != actionAllow {
+			t.warnf("rule %q: action %q is not supported and the rule is ignored", rule.Name, rule.Action)
+			continue
+		}
+
+		peers := t.networkPolicyPeers(rule, egress)
+		if len(peers) == 0 {
+			t.warnf("rule %q has no supported peers and is ignored", rule.Name)
+			continue
+		}
+
+		allowed = append(allowed, networkPolicyRule{peers: peers, ports: networkPolicyPorts(rule.Ports)})
+	}
+
+	return nil, false
+}
+
+func (t *policyTranslator) networkPolicyPeers(rule policyRule, egress bool) []networkingv1.NetworkPolicyPeer {
+	var out []networkingv1.NetworkPolicyPeer
+	for _, p := range rule.peers(egress) {
+		switch {
+		case p.Nodes != nil:
+			t.warnf("rule %q: nodes peers are not supported and are ignored", rule.Name)
+		case p.Pods != nil:
+			out = append(out, networkingv1.NetworkPolicyPeer{PodSelector: t.hostPodSelector(&p.Pods.NamespaceSelector, &p.Pods.PodSelector)})
+		case p.Namespaces != nil:
+			out = append(out, networkingv1.NetworkPolicyPeer{PodSelector: t.hostPodSelector(p.Namespaces, nil)})
+		case len(p.Networks) > 0:
+			for _, network := range p.Networks {
+				out = append(out, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: network}})
+			}
+		default:
+			t.warnf("rule %q: domainNames peers are not supported and are ignored", rule.Name)
+		}
+	}
+
+	return out
+}
+
+func networkPolicyPorts(ports *[]port) []networkingv1.NetworkPolicyPort {
+	if ports == nil {
+		return nil
+	}
+
+	out := make([]networkingv1.NetworkPolicyPort, 0, len(*ports))
+	for _, p := range *ports {
+		switch {
+		case p.PortNumber != nil:
+			out = append(out, networkingv1.NetworkPolicyPort{Protocol: ptr.To(p.PortNumber.Protocol), Port: ptr.To(intstr.FromInt32(p.PortNumber.Port))})
+		case p.NamedPort != nil:
+			out = append(out, networkingv1.NetworkPolicyPort{Port: ptr.To(intstr.FromString(*p.NamedPort))})
+		case p.PortRange != nil:
+			protocol := p.PortRange.Protocol
+			if protocol == "" {
+				protocol = corev1.ProtocolTCP
+			}
+			out = append(out, networkingv1.NetworkPolicyPort{Protocol: ptr.To(protocol), Port: ptr.To(intstr.FromInt32(p.PortRange.Start)), EndPort: ptr.To(p.PortRange.End)})
+		}
+	}
+
+	return out
+}
+
+// deniesAll checks if the rule matches all traffic of the direction. For egress this includes traffic leaving the
+// cluster, as an isolated NetworkPolicy denies that as well.
+func deniesAll(rule policyRule, egress bool) bool {
+	if rule.Ports != nil {
+		return false
+	}
+
+	allPods := false
+	var networks []string
+	for _, p := range rule.peers(egress) {
+		if p.Namespaces != nil && isEmptySelector(p.Namespaces) {
+			allPods = true
+		} else if p.Pods != nil && isEmptySelector(&p.Pods.NamespaceSelector) && isEmptySelector(&p.Pods.PodSelector) {
+			allPods = true
+		}
+		networks = append(networks, p.Networks...)
+	}
+	if !egress {
+		return allPods
+	}
+
+	return allPods && slices.Contains(networks, "0.0.0.0/0") && slices.Contains(networks, "::/0")
+}
+
+func isEmptySelector(selector *metav1.LabelSelector) bool {
+	return len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0
+}
@@ -0,0 +1,233 @@
+package adminnetworkpolicies
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+var namespaces = []corev1.Namespace{
+	{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}}},
+	{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"team": "b"}}},
+	{ObjectMeta: metav1.ObjectMeta{Name: "monitoring", Labels: map[string]string{"team": "a", "monitoring": "true"}}},
+}
+
+var hostNamespaceSelector = metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: "vcluster"}}
+
+func podsIn(virtualNamespaces ...string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels:      map[string]string{translate.MarkerLabel: translate.VClusterName},
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: translate.NamespaceLabel, Operator: metav1.LabelSelectorOpIn, Values: virtualNamespaces}},
+	}
+}
+
+func TestHostPriority(t *testing.T) {
+	testCases := []struct {
+		priority    int32
+		minPriority int32
+		expected    int32
+	}{
+		{priority: 0, minPriority: 500, expected: 500},
+		{priority: 10, minPriority: 500, expected: 505},
+		{priority: 11, minPriority: 500, expected: 505},
+		{priority: 1000, minPriority: 500, expected: 1000},
+		{priority: 42, minPriority: 0, expected: 42},
+		{priority: 5000, minPriority: 900, expected: 1000},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, hostPriority(testCase.priority, testCase.minPriority), testCase.expected, "priority %d with min %d", testCase.priority, testCase.minPriority)
+	}
+}
+
+func TestTranslateAdminNetworkPolicySpec(t *testing.T) {
+	vObj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"priority": int64(10),
+			"subject": map[string]interface{}{
+				"namespaces": map[string]interface{}{"matchLabels": map[string]interface{}{"team": "a"}},
+			},
+			"ingress": []interface{}{
+				map[string]interface{}{
+					"name":   "allow-monitoring",
+					"action": "Allow",
+					"from": []interface{}{map[string]interface{}{
+						"pods": map[string]interface{}{
+							"namespaceSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"monitoring": "true"}},
+							"podSelector":       map[string]interface{}{"matchLabels": map[string]interface{}{"app": "prometheus"}},
+						},
+					}},
+					"ports": []interface{}{map[string]interface{}{"portNumber": map[string]interface{}{"protocol": "TCP", "port": int64(9090)}}},
+				},
+				map[string]interface{}{
+					"name":   "deny-nodes",
+					"action": "Deny",
+					"from":   []interface{}{map[string]interface{}{"nodes": map[string]interface{}{}}},
+				},
+			},
+			"egress": []interface{}{
+				map[string]interface{}{
+					"name":   "pass-external",
+					"action": "Pass",
+					"to":     []interface{}{map[string]interface{}{"networks": []interface{}{"0.0.0.0/0"}}},
+				},
+				map[string]interface{}{
+					"name":   "allow-dns",
+					"action": "Allow",
+					"to": []interface{}{
+						map[string]interface{}{"namespaces": map[string]interface{}{"matchLabels": map[string]interface{}{"kubernetes.io/metadata.name": "kube-system"}}},
+						map[string]interface{}{"networks": []interface{}{"10.0.0.10/32"}},
+					},
+				},
+				map[string]interface{}{
+					"name":   "allow-domain",
+					"action": "Allow",
+					"to":     []interface{}{map[string]interface{}{"domainNames": []interface{}{"*.example.com"}}},
+				},
+			},
+		},
+	}}
+
+	spec, err := specFromUnstructured(vObj)
+	assert.NilError(t, err)
+
+	translator := &policyTranslator{hostNamespace: "vcluster", namespaces: namespaces}
+	hostSpec := translator.translateAdminNetworkPolicySpec(spec, 500)
+	assert.DeepEqual(t, hostSpec, &policySpec{
+		Priority: ptr.To[int32](505),
+		Subject:  subject{Pods: &namespacedPod{NamespaceSelector: hostNamespaceSelector, PodSelector: *podsIn("monitoring", "team-a")}},
+		Ingress: []policyRule{{
+			Name:   "allow-monitoring",
+			Action: "Allow",
+			From: []peer{{Pods: &namespacedPod{
+				NamespaceSelector: hostNamespaceSelector,
+				PodSelector: metav1.LabelSelector{
+					MatchLabels:      map[string]string{"app": "prometheus", translate.MarkerLabel: translate.VClusterName},
+					MatchExpressions: podsIn("monitoring").MatchExpressions,
+				},
+			}}},
+			Ports: &[]port{{PortNumber: &portNumber{Protocol: corev1.ProtocolTCP, Port: 9090}}},
+		}},
+		Egress: []policyRule{
+			{
+				Name:   "pass-external",
+				Action: "Pass",
+				To:     []peer{{Networks: []string{"0.0.0.0/0"}}},
+			},
+			{
+				Name:   "allow-dns",
+				Action: "Allow",
+				To:     []peer{{Pods: translator.hostPods(&metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "kube-system"}}, nil)}},
+			},
+			{
+				Name:   "allow-dns",
+				Action: "Pass",
+				To:     []peer{{Networks: []string{"10.0.0.10/32"}}},
+			},
+			{
+				Name:   "allow-domain",
+				Action: "Pass",
+				To:     []peer{{DomainNames: []string{"*.example.com"}}},
+			},
+		},
+	})
+	assert.DeepEqual(t, translator.warnings, []string{
+		`rule "deny-nodes": nodes peers are not supported and are ignored`,
+		`rule "deny-nodes" has no supported peers and is ignored`,
+		`rule "allow-dns": networks and domainNames peers can't be allowed and are passed on to the NetworkPolicies instead`,
+		`rule "allow-domain": networks and domainNames peers can't be allowed and are passed on to the NetworkPolicies instead`,
+	})
+
+	pObj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	assert.NilError(t, setSpec(pObj, hostSpec))
+	priority, _, err := unstructured.NestedInt64(pObj.Object, "spec", "priority")
+	assert.NilError(t, err)
+	assert.Equal(t, priority, int64(505))
+}
+
+func TestTranslateBaselineAdminNetworkPolicySpec(t *testing.T) {
+	allNamespaces := []peer{{Namespaces: &metav1.LabelSelector{}}}
+	teamA := &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}
+	subjectTeamB := subject{Namespaces: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "b"}}}
+
+	testCases := []struct {
+		name string
+		spec policySpec
+
+		expectedSpec     *networkingv1.NetworkPolicySpec
+		expectedWarnings []string
+	}{
+		{
+			name: "allow before deny all ingress",
+			spec: policySpec{
+				Subject: subjectTeamB,
+				Ingress: []policyRule{
+					{Name: "allow-team-a", Action: actionAllow, From: []peer{{Namespaces: teamA}}, Ports: &[]port{{NamedPort: ptr.To("http")}}},
+					{Name: "deny-all", Action: actionDeny, From: allNamespaces},
+					{Name: "shadowed", Action: actionAllow, From: allNamespaces},
+				},
+			},
+			expectedSpec: &networkingv1.NetworkPolicySpec{
+				PodSelector: *podsIn("team-b"),
+				Ingress: []networkingv1.NetworkPolicyIngressRule{{
+					From:  []networkingv1.NetworkPolicyPeer{{PodSelector: podsIn("monitoring", "team-a")}},
+					Ports: []networkingv1.NetworkPolicyPort{{Port: ptr.To(intstr.FromString("http"))}},
+				}},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			},
+			expectedWarnings: []string{"denying all ingress traffic also denies traffic from outside of the virtual cluster"},
+		},
+		{
+			name: "deny all egress",
+			spec: policySpec{
+				Subject: subjectTeamB,
+				Egress: []policyRule{
+					{Name: "allow-dns", Action: actionAllow, To: []peer{{Networks: []string{"10.0.0.10/32"}}}, Ports: &[]port{{PortRange: &portRange{Protocol: corev1.ProtocolUDP, Start: 53, End: 54}}}},
+					{Name: "deny-all", Action: actionDeny, To: []peer{{Namespaces: &metav1.LabelSelector{}}, {Networks: []string{"0.0.0.0/0", "::/0"}}}},
+				},
+			},
+			expectedSpec: &networkingv1.NetworkPolicySpec{
+				PodSelector: *podsIn("team-b"),
+				Egress: []networkingv1.NetworkPolicyEgressRule{{
+					To:    []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.10/32"}}},
+					Ports: []networkingv1.NetworkPolicyPort{{Protocol: ptr.To(corev1.ProtocolUDP), Port: ptr.To(intstr.FromInt32(53)), EndPort: ptr.To[int32](54)}},
+				}},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			},
+		},
+		{
+			name: "allow only",
+			spec: policySpec{
+				Subject: subjectTeamB,
+				Ingress: []policyRule{{Name: "allow-team-a", Action: actionAllow, From: []peer{{Namespaces: teamA}}}},
+			},
+		},
+		{
+			name: "partial deny",
+			spec: policySpec{
+				Subject: subjectTeamB,
+				Ingress: []policyRule{{Name: "deny-team-a", Action: actionDeny, From: []peer{{Namespaces: teamA}}}},
+				Egress:  []policyRule{{Name: "deny-pods", Action: actionDeny, To: allNamespaces}},
+			},
+			expectedWarnings: []string{
+				`rule "deny-team-a": deny rules that don't match all traffic can't be expressed as NetworkPolicy and are ignored`,
+				`rule "deny-pods": deny rules that don't match all traffic can't be expressed as NetworkPolicy and are ignored`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			translator := &policyTranslator{hostNamespace: "vcluster", namespaces: namespaces}
+			assert.DeepEqual(t, translator.translateBaselineAdminNetworkPolicySpec(&testCase.spec), testCase.expectedSpec)
+			assert.DeepEqual(t, translator.warnings, testCase.expectedWarnings)
+		})
+	}
+}
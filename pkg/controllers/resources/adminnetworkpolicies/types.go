@@ -0,0 +1,82 @@
+package adminnetworkpolicies
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	AdminNetworkPolicies         = schema.GroupVersionKind{Group: "policy.networking.k8s.io", Version: "v1alpha1", Kind: "AdminNetworkPolicy"}
+	BaselineAdminNetworkPolicies = schema.GroupVersionKind{Group: "policy.networking.k8s.io", Version: "v1alpha1", Kind: "BaselineAdminNetworkPolicy"}
+)
+
+const (
+	actionAllow = "Allow"
+	actionDeny  = "Deny"
+	actionPass  = "Pass"
+
+	// baselineAdminNetworkPolicyName is the name of the singleton BaselineAdminNetworkPolicy
+	baselineAdminNetworkPolicyName = "default"
+)
+
+// policySpec mirrors the spec of AdminNetworkPolicies and BaselineAdminNetworkPolicies. The policy.networking.k8s.io
+// types are not vendored, so only the fields the syncer understands are listed here. Priority is not set for
+// BaselineAdminNetworkPolicies.
+type policySpec struct {
+	Priority *int32       `json:"priority,omitempty"`
+	Subject  subject      `json:"subject"`
+	Ingress  []policyRule `json:"ingress,omitempty"`
+	Egress   []policyRule `json:"egress,omitempty"`
+}
+
+type subject struct {
+	Namespaces *metav1.LabelSelector `json:"namespaces,omitempty"`
+	Pods       *namespacedPod        `json:"pods,omitempty"`
+}
+
+type namespacedPod struct {
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+	PodSelector       metav1.LabelSelector `json:"podSelector"`
+}
+
+// policyRule is used for ingress and egress rules, ingress rules use From and egress rules use To
+type policyRule struct {
+	Name   string  `json:"name,omitempty"`
+	Action string  `json:"action"`
+	From   []peer  `json:"from,omitempty"`
+	To     []peer  `json:"to,omitempty"`
+	Ports  *[]port `json:"ports,omitempty"`
+}
+
+type peer struct {
+	Namespaces  *metav1.LabelSelector `json:"namespaces,omitempty"`
+	Pods        *namespacedPod        `json:"pods,omitempty"`
+	Nodes       *metav1.LabelSelector `json:"nodes,omitempty"`
+	Networks    []string              `json:"networks,omitempty"`
+	DomainNames []string              `json:"domainNames,omitempty"`
+}
+
+type port struct {
+	PortNumber *portNumber `json:"portNumber,omitempty"`
+	NamedPort  *string     `json:"namedPort,omitempty"`
+	PortRange  *portRange  `json:"portRange,omitempty"`
+}
+
+type portNumber struct {
+	Protocol corev1.Protocol `json:"protocol"`
+	Port     int32           `json:"port"`
+}
+
+type portRange struct {
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+	Start    int32           `json:"start"`
+	End      int32           `json:"end"`
+}
+
+func (r *policyRule) peers(egress bool) []peer {
+	if egress {
+		return r.To
+	}
+	return r.From
+}
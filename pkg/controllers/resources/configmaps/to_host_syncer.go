@@ -5,6 +5,7 @@ import (
 
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/mappings"
+	"github.com/loft-sh/vcluster/pkg/mappings/resources"
 	"github.com/loft-sh/vcluster/pkg/patcher"
 	"github.com/loft-sh/vcluster/pkg/pro"
 	"github.com/loft-sh/vcluster/pkg/syncer"
@@ -78,7 +79,19 @@ func (s *configMapSyncer) SyncToHost(ctx *synccontext.SyncContext, event *syncco
 	}
 
 	pObj := translate.HostMetadata(event.Virtual, s.VirtualToHost(ctx, types.NamespacedName{Name: event.Virtual.Name, Namespace: event.Virtual.Namespace}, event.Virtual))
-	err := pro.ApplyPatchesHostObject(ctx, nil, pObj, event.Virtual, ctx.Config.Sync.ToHost.ConfigMaps.Patches, false)
+	err := s.checkMappedHostObject(ctx, event.Virtual, nil, types.NamespacedName{Namespace: pObj.Namespace, Name: pObj.Name})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if _, _, ok := ctx.Config.Sync.ToHost.ConfigMaps.Mappings.HostName(event.Virtual.Namespace, event.Virtual.Name); ok {
+		err = resources.PrepareToHostMappingTarget(ctx, "configmaps", pObj)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	err = pro.ApplyPatchesHostObject(ctx, nil, pObj, event.Virtual, ctx.Config.Sync.ToHost.ConfigMaps.Patches, false)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -112,6 +125,11 @@ func (s *configMapSyncer) Sync(ctx *synccontext.SyncContext, event *synccontext.
 		return patcher.DeleteHostObject(ctx, event.Host, event.Virtual, "configmap is not used anymore")
 	}
 
+	err := s.checkMappedHostObject(ctx, event.Virtual, event.Host, types.NamespacedName{Namespace: event.Host.Namespace, Name: event.Host.Name})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	patchHelper, err := patcher.NewSyncerPatcher(ctx, event.Host, event.Virtual, patcher.TranslatePatches(ctx.Config.Sync.ToHost.ConfigMaps.Patches, false))
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("new syncer patcher: %w", err)
@@ -156,6 +174,8 @@ func (s *configMapSyncer) isConfigMapUsed(ctx *synccontext.SyncContext, vObj *co
 		return true
 	} else if ctx.Config.Sync.ToHost.ConfigMaps.All {
 		return true
+	} else if _, _, ok := ctx.Config.Sync.ToHost.ConfigMaps.Mappings.HostName(vObj.Namespace, vObj.Name); ok {
+		return true
 	}
 
 	// retrieve references for config map
@@ -169,3 +189,23 @@ func (s *configMapSyncer) isConfigMapUsed(ctx *synccontext.SyncContext, vObj *co
 
 	return len(references) > 0
 }
+
+// checkMappedHostObject makes sure config maps mapped by sync.toHost.configMaps.mappings don't take over host objects in shared
+// namespaces. The host object is retrieved if it's nil.
+func (s *configMapSyncer) checkMappedHostObject(ctx *synccontext.SyncContext, vObj, pObj *corev1.ConfigMap, hostName types.NamespacedName) error {
+	if _, _, ok := ctx.Config.Sync.ToHost.ConfigMaps.Mappings.HostName(vObj.Namespace, vObj.Name); !ok {
+		return nil
+	}
+
+	var existing client.Object
+	if pObj != nil {
+		existing = pObj
+	}
+	err := resources.CheckToHostMappingTarget(ctx, vObj, existing, hostName)
+	if err != nil {
+		s.EventRecorder().Eventf(vObj, nil, "Warning", "SyncError", "SyncConfigMap", "Error syncing to host cluster: %v", err)
+		return err
+	}
+
+	return nil
+}
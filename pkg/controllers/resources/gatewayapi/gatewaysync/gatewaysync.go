@@ -11,6 +11,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/pro"
 	"github.com/loft-sh/vcluster/pkg/scheme"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/hostnamepolicy"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/events"
@@ -117,7 +118,7 @@ func CreateToVirtual[O any, T interface {
 	return patcher.CreateVirtualObject(ctx, event.Host, vObj, rec, true)
 }
 
-// recordTerminalRefError records denied or unsupported reference errors.
+// recordTerminalRefError records denied or unsupported reference and hostname policy errors.
 func recordTerminalRefError(rec events.EventRecorder, obj client.Object, err error) bool {
 	switch {
 	case gatewayauthz.IsNotPermitted(err):
@@ -126,6 +127,9 @@ func recordTerminalRefError(rec events.EventRecorder, obj client.Object, err err
 	case routetranslate.IsUnsupportedReference(err):
 		RecordUnsupportedReference(rec, obj, err)
 		return true
+	case hostnamepolicy.IsNotPermitted(err):
+		RecordHostnameNotPermitted(rec, obj, err)
+		return true
 	default:
 		return false
 	}
@@ -157,6 +161,12 @@ func RecordUnsupportedReference(rec events.EventRecorder, obj client.Object, err
 	recordWarning(rec, obj, "UnsupportedReference", "Gateway API reference kind is not supported and will not be synced to the host: %v", err)
 }
 
+// RecordHostnameNotPermitted records a Warning event for a hostname rejected by
+// policies.hostnames; the object is not synced to the host.
+func RecordHostnameNotPermitted(rec events.EventRecorder, obj client.Object, err error) {
+	recordWarning(rec, obj, hostnamepolicy.Reason, "Hostname is not permitted and will not be synced to the host: %v", err)
+}
+
 func recordWarning(rec events.EventRecorder, obj client.Object, reason, note string, args ...any) {
 	kind := obj.GetObjectKind().GroupVersionKind().Kind
 	if kind == "" {
@@ -7,6 +7,7 @@ import (
 	rootconfig "github.com/loft-sh/vcluster/config"
 	"github.com/loft-sh/vcluster/pkg/mappings/resources"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/hostnamepolicy"
 	"k8s.io/apimachinery/pkg/types"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
@@ -34,6 +35,20 @@ func ValidateImportedGatewayHostnamePolicy(ctx *synccontext.SyncContext, routeKi
 	return nil
 }
 
+// ValidateHostnamePolicy enforces policies.hostnames for the hostnames and ExternalDNS annotations of a Gateway API object.
+func ValidateHostnamePolicy(ctx *synccontext.SyncContext, hostnames []gatewayv1.Hostname, annotations map[string]string) error {
+	if ctx == nil || ctx.Config == nil {
+		return nil
+	}
+
+	for _, hostname := range hostnames {
+		if err := hostnamepolicy.Validate(ctx.Config.Policies.Hostnames, string(hostname)); err != nil {
+			return err
+		}
+	}
+	return hostnamepolicy.ValidateAnnotations(ctx.Config.Policies.Hostnames, annotations)
+}
+
 func importedGatewayForParent(ctx *synccontext.SyncContext, routeNamespace string, parent gatewayv1.ParentReference) *rootconfig.GatewayAllowedRoutesPolicyOverride {
 	if parent.Group != nil && string(*parent.Group) != gatewayv1.GroupVersion.Group {
 		return nil
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	rootconfig "github.com/loft-sh/vcluster/config"
+	routetranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/gatewayroutes/translate"
 	"github.com/loft-sh/vcluster/pkg/mappings/resources"
 	"github.com/loft-sh/vcluster/pkg/patcher"
 	"github.com/loft-sh/vcluster/pkg/pro"
@@ -11,6 +12,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	"github.com/loft-sh/vcluster/pkg/syncer/translator"
 	syncertypes "github.com/loft-sh/vcluster/pkg/syncer/types"
+	"github.com/loft-sh/vcluster/pkg/util/hostnamepolicy"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -179,6 +181,10 @@ func tenantGatewayEligible(ctx *synccontext.SyncContext, s *tenantGatewaySyncer,
 		s.EventRecorder().Eventf(gateway, nil, "Warning", "SyncWarning", "SyncGateway", "Gateway %s/%s is reserved for an imported Gateway", gateway.Namespace, gateway.Name)
 		return false, nil
 	}
+	if err := routetranslate.ValidateHostnamePolicy(ctx, listenerHostnames(gateway), gateway.Annotations); err != nil {
+		s.EventRecorder().Eventf(gateway, nil, "Warning", hostnamepolicy.Reason, "SyncGateway", "Gateway will not be synced to the host: %v", err)
+		return false, nil
+	}
 	gatewayClass := &gatewayv1.GatewayClass{}
 	err := ctx.VirtualClient.Get(ctx, types.NamespacedName{Name: string(gateway.Spec.GatewayClassName)}, gatewayClass)
 	if kerrors.IsNotFound(err) {
@@ -190,6 +196,16 @@ func tenantGatewayEligible(ctx *synccontext.SyncContext, s *tenantGatewaySyncer,
 	return true, nil
 }
 
+func listenerHostnames(gateway *gatewayv1.Gateway) []gatewayv1.Hostname {
+	hostnames := []gatewayv1.Hostname{}
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.Hostname != nil && *listener.Hostname != "" {
+			hostnames = append(hostnames, *listener.Hostname)
+		}
+	}
+	return hostnames
+}
+
 func (s *gatewaySyncer) SyncToVirtual(ctx *synccontext.SyncContext, event *synccontext.SyncToVirtualEvent[*gatewayv1.Gateway]) (ctrl.Result, error) {
 	selected, reason, err := gatewaySelected(ctx, event.Host)
 	if err != nil {
@@ -17,6 +17,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	"github.com/loft-sh/vcluster/pkg/syncer/translator"
 	syncertypes "github.com/loft-sh/vcluster/pkg/syncer/types"
+	"github.com/loft-sh/vcluster/pkg/util/hostnamepolicy"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 )
 
@@ -71,6 +72,10 @@ func (s *ingressSyncer) SyncToHost(ctx *synccontext.SyncContext, event *synccont
 		return patcher.DeleteVirtualObject(ctx, event.Virtual, event.HostOld, "host object was deleted")
 	}
 
+	if s.applyHostnamePolicy(ctx, event.Virtual) {
+		return ctrl.Result{}, nil
+	}
+
 	pObj, err := s.translate(ctx, event.Virtual)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -89,6 +94,10 @@ func (s *ingressSyncer) Sync(ctx *synccontext.SyncContext, event *synccontext.Sy
 		return ctrl.Result{}, nil
 	}
 
+	if s.applyHostnamePolicy(ctx, event.Virtual) {
+		return patcher.DeleteHostObject(ctx, event.Host, event.Virtual, "ingress hostname is not permitted")
+	}
+
 	patch, err := patcher.NewSyncerPatcher(ctx, event.Host, event.Virtual, patcher.TranslatePatches(ctx.Config.Sync.ToHost.Ingresses.Patches, false))
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("new syncer patcher: %w", err)
@@ -192,3 +201,27 @@ func (s *ingressSyncer) applyLimitByClass(ctx *synccontext.SyncContext, virtual
 
 	return false
 }
+
+// applyHostnamePolicy records an event and returns true if the ingress claims hostnames that are not permitted by
+// policies.hostnames
+func (s *ingressSyncer) applyHostnamePolicy(ctx *synccontext.SyncContext, virtual *networkingv1.Ingress) bool {
+	err := hostnamepolicy.Validate(ctx.Config.Policies.Hostnames, ingressHostnames(virtual)...)
+	if err == nil {
+		err = hostnamepolicy.ValidateIngressAnnotations(ctx.Config.Policies.Hostnames, virtual.Annotations)
+	}
+	if err == nil {
+		return false
+	}
+
+	s.EventRecorder().Eventf(
+		virtual,
+		nil,
+		"Warning",
+		hostnamepolicy.Reason,
+		fmt.Sprintf("Sync%s", virtual.GetObjectKind().GroupVersionKind().Kind),
+		"did not sync ingress %q to host: %v",
+		virtual.GetName(),
+		err,
+	)
+	return true
+}
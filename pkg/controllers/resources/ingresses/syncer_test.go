@@ -3,6 +3,7 @@ package ingresses
 import (
 	"testing"
 
+	rootconfig "github.com/loft-sh/vcluster/config"
 	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	syncertesting "github.com/loft-sh/vcluster/pkg/syncer/testing"
@@ -162,6 +163,36 @@ func TestSync(t *testing.T) {
 		Status: changedIngressStatus,
 	}
 
+	hostnamePolicy := rootconfig.HostnamePolicy{
+		Enabled:        true,
+		AllowedDomains: []string{"apps.example.com"},
+		Prefix:         "team-a-",
+	}
+	notPermittedIngress := baseIngress.DeepCopy()
+	notPermittedIngress.Spec.Rules[0].Host = "shop.apps.example.com"
+
+	annotationTranslation := rootconfig.IngressAnnotationTranslation{
+		Presets: []string{"haproxy"},
+		Rules: []rootconfig.IngressAnnotationRule{
+			{
+				Annotation: "example.com/backends",
+				Type:       "json",
+				Paths:      []rootconfig.IngressAnnotationPath{{Path: "targets[*].serviceName", Type: "service"}},
+			},
+		},
+	}
+	annotatedIngress := baseIngress.DeepCopy()
+	annotatedIngress.Annotations = map[string]string{
+		"haproxy.org/auth-secret":                 "my-secret",
+		"example.com/backends":                    `{"targets":[{"serviceName":"my-service","weight":100}]}`,
+		"nginx.ingress.kubernetes.io/auth-secret": "my-secret",
+	}
+	createdAnnotatedIngress := createdIngress.DeepCopy()
+	createdAnnotatedIngress.Annotations["haproxy.org/auth-secret"] = translate.Default.HostName(nil, "my-secret", baseIngress.Namespace).Name
+	createdAnnotatedIngress.Annotations["example.com/backends"] = `{"targets":[{"serviceName":"` + translate.Default.HostName(nil, "my-service", baseIngress.Namespace).Name + `","weight":100}]}`
+	createdAnnotatedIngress.Annotations["nginx.ingress.kubernetes.io/auth-secret"] = "my-secret"
+	createdAnnotatedIngress.Annotations[translate.ManagedAnnotationsAnnotation] = "example.com/backends\nhaproxy.org/auth-secret\nnginx.ingress.kubernetes.io/auth-secret"
+
 	syncertesting.RunTestsWithContext(t, func(vConfig *config.VirtualClusterConfig, pClient *testingutil.FakeIndexClient, vClient *testingutil.FakeIndexClient) *synccontext.RegisterContext {
 		vConfig.Sync.ToHost.Ingresses.Enabled = true
 		return syncertesting.NewFakeRegisterContext(vConfig, pClient, vClient)
@@ -527,6 +558,55 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                "Create forward with hostname not permitted",
+			InitialVirtualState: []runtime.Object{notPermittedIngress.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				networkingv1.SchemeGroupVersion.WithKind("Ingress"): {notPermittedIngress.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				networkingv1.SchemeGroupVersion.WithKind("Ingress"): {},
+			},
+			Sync: func(registerContext *synccontext.RegisterContext) {
+				registerContext.Config.Policies.Hostnames = hostnamePolicy
+				syncCtx, syncer := syncertesting.FakeStartSyncer(t, registerContext, NewSyncer)
+				_, err := syncer.(*ingressSyncer).SyncToHost(syncCtx, synccontext.NewSyncToHostEvent(notPermittedIngress.DeepCopy()))
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                 "Update forward with hostname not permitted",
+			InitialVirtualState:  []runtime.Object{notPermittedIngress.DeepCopy()},
+			InitialPhysicalState: []runtime.Object{createdIngress.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				networkingv1.SchemeGroupVersion.WithKind("Ingress"): {notPermittedIngress.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				networkingv1.SchemeGroupVersion.WithKind("Ingress"): {},
+			},
+			Sync: func(registerContext *synccontext.RegisterContext) {
+				registerContext.Config.Policies.Hostnames = hostnamePolicy
+				syncCtx, syncer := syncertesting.FakeStartSyncer(t, registerContext, NewSyncer)
+				_, err := syncer.(*ingressSyncer).Sync(syncCtx, synccontext.NewSyncEventWithOld(createdIngress.DeepCopy(), createdIngress.DeepCopy(), baseIngress.DeepCopy(), notPermittedIngress.DeepCopy()))
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name:                "Create forward with annotation translation rules",
+			InitialVirtualState: []runtime.Object{annotatedIngress.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				networkingv1.SchemeGroupVersion.WithKind("Ingress"): {annotatedIngress.DeepCopy()},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				networkingv1.SchemeGroupVersion.WithKind("Ingress"): {createdAnnotatedIngress.DeepCopy()},
+			},
+			Sync: func(registerContext *synccontext.RegisterContext) {
+				registerContext.Config.Sync.ToHost.Ingresses.AnnotationTranslation = annotationTranslation
+				syncCtx, syncer := syncertesting.FakeStartSyncer(t, registerContext, NewSyncer)
+				_, err := syncer.(*ingressSyncer).SyncToHost(syncCtx, synccontext.NewSyncToHostEvent(annotatedIngress.DeepCopy()))
+				assert.NilError(t, err)
+			},
+		},
 	})
 }
 
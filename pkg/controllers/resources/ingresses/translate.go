@@ -37,7 +37,7 @@ func (s *ingressSyncer) translateUpdate(ctx *synccontext.SyncContext, event *syn
 		event,
 		func(key string, value interface{}) (string, interface{}) {
 			// we need to ignore the rewritten annotations here
-			if resources.TranslateAnnotations[key] {
+			if resources.IsTranslatedIngressAnnotation(ctx, key) {
 				return "", nil
 			}
 			if strings.HasPrefix(key, AlbActionsAnnotation) || strings.HasPrefix(key, AlbConditionAnnotation) {
@@ -104,6 +104,25 @@ func translateSpec(ctx *synccontext.SyncContext, namespace string, vIngressSpec
 	return retSpec
 }
 
+// ingressHostnames returns the hostnames of the rules and tls entries of an ingress
+func ingressHostnames(vIngress *networkingv1.Ingress) []string {
+	hostnames := []string{}
+	for _, rule := range vIngress.Spec.Rules {
+		if rule.Host != "" {
+			hostnames = append(hostnames, rule.Host)
+		}
+	}
+	for _, tls := range vIngress.Spec.TLS {
+		for _, host := range tls.Hosts {
+			if host != "" {
+				hostnames = append(hostnames, host)
+			}
+		}
+	}
+
+	return hostnames
+}
+
 func getActionOrConditionValue(annotation, actionOrCondition string) string {
 	i := strings.Index(annotation, actionOrCondition)
 	if i > -1 {
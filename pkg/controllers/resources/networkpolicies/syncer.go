@@ -1,7 +1,9 @@
 package networkpolicies
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 
 	"github.com/loft-sh/vcluster/pkg/mappings"
 	"github.com/loft-sh/vcluster/pkg/patcher"
@@ -11,10 +13,18 @@ import (
 	"github.com/loft-sh/vcluster/pkg/syncer/translator"
 	syncertypes "github.com/loft-sh/vcluster/pkg/syncer/types"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func New(ctx *synccontext.RegisterContext) (syncertypes.Object, error) {
@@ -40,6 +50,51 @@ func (s *networkPolicySyncer) Options() *syncertypes.Options {
 	}
 }
 
+var _ syncertypes.ControllerModifier = &networkPolicySyncer{}
+
+// ModifyController requeues the network policies that select namespaces whenever a virtual namespace is added, removed
+// or relabeled, because their host peers list the matching namespaces
+func (s *networkPolicySyncer) ModifyController(registerContext *synccontext.RegisterContext, builder *builder.Builder) (*builder.Builder, error) {
+	enqueue := func(ctx context.Context, q workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+		networkPolicies := &networkingv1.NetworkPolicyList{}
+		err := registerContext.VirtualManager.GetClient().List(ctx, networkPolicies)
+		if err != nil {
+			klog.FromContext(ctx).Info("failed to list network policies when handling namespace change", "error", err)
+			return
+		}
+
+		for _, networkPolicy := range networkPolicies.Items {
+			if !usesNamespaceSelector(&networkPolicy.Spec) {
+				continue
+			}
+
+			q.Add(reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      networkPolicy.GetName(),
+				Namespace: networkPolicy.GetNamespace(),
+			}})
+		}
+	}
+
+	eventHandler := handler.Funcs{
+		CreateFunc: func(ctx context.Context, _ event.CreateEvent, q workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+			enqueue(ctx, q)
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+			// no need to reconcile network policies if namespace labels didn't change
+			if reflect.DeepEqual(e.ObjectNew.GetLabels(), e.ObjectOld.GetLabels()) {
+				return
+			}
+
+			enqueue(ctx, q)
+		},
+		DeleteFunc: func(ctx context.Context, _ event.DeleteEvent, q workqueue.TypedRateLimitingInterface[ctrl.Request]) {
+			enqueue(ctx, q)
+		},
+	}
+
+	return builder.Watches(&corev1.Namespace{}, eventHandler), nil
+}
+
 var _ syncertypes.Syncer = &networkPolicySyncer{}
 
 func (s *networkPolicySyncer) Syncer() syncertypes.Sync[client.Object] {
@@ -51,8 +106,12 @@ func (s *networkPolicySyncer) SyncToHost(ctx *synccontext.SyncContext, event *sy
 		return patcher.DeleteVirtualObject(ctx, event.Virtual, event.HostOld, "host object was deleted")
 	}
 
-	pObj := s.translate(ctx, event.Virtual)
-	err := pro.ApplyPatchesHostObject(ctx, nil, pObj, event.Virtual, ctx.Config.Sync.ToHost.NetworkPolicies.Patches, false)
+	pObj, err := s.translate(ctx, event.Virtual)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	err = pro.ApplyPatchesHostObject(ctx, nil, pObj, event.Virtual, ctx.Config.Sync.ToHost.NetworkPolicies.Patches, false)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -82,7 +141,10 @@ func (s *networkPolicySyncer) Sync(ctx *synccontext.SyncContext, event *synccont
 		}
 	}()
 
-	s.translateUpdate(event.Host, event.Virtual)
+	err = s.translateUpdate(ctx, event.Host, event.Virtual)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 
 	// bi-directional sync of annotations and labels
 	event.Virtual.Annotations, event.Host.Annotations = translate.AnnotationsBidirectionalUpdate(event)
@@ -12,6 +12,7 @@ import (
 
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -150,7 +151,27 @@ func TestSync(t *testing.T) {
 
 	pnetworkPolicyWithLabelSelectorNsSelector := pnetworkPolicyWithLabelSelectorNoNs.DeepCopy()
 	delete(pnetworkPolicyWithLabelSelectorNsSelector.Spec.Ingress[0].From[0].PodSelector.MatchLabels, translate.NamespaceLabel)
-	pnetworkPolicyWithLabelSelectorNsSelector.Spec.Ingress[0].From[0].PodSelector.MatchLabels[translate.HostLabelNamespace("nslabelkey")] = "abc"
+	pnetworkPolicyWithLabelSelectorNsSelector.Spec.Ingress[0].From[0].PodSelector.MatchExpressions = []metav1.LabelSelectorRequirement{
+		{
+			Key:      translate.NamespaceLabel,
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   []string{"team-b", "test"},
+		},
+	}
+
+	vnetworkPolicyWithUnmatchedNsSelector := vnetworkPolicyWithPodSelectorNoNs.DeepCopy()
+	vnetworkPolicyWithUnmatchedNsSelector.Spec.Ingress[0].From[0].NamespaceSelector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{"nslabelkey": "unknown"},
+	}
+
+	pnetworkPolicyWithUnmatchedNsSelector := pnetworkPolicyWithLabelSelectorNoNs.DeepCopy()
+	delete(pnetworkPolicyWithUnmatchedNsSelector.Spec.Ingress[0].From[0].PodSelector.MatchLabels, translate.NamespaceLabel)
+	pnetworkPolicyWithUnmatchedNsSelector.Spec.Ingress[0].From[0].PodSelector.MatchExpressions = []metav1.LabelSelectorRequirement{
+		{
+			Key:      translate.NamespaceLabel,
+			Operator: metav1.LabelSelectorOpDoesNotExist,
+		},
+	}
 
 	vnetworkPolicyEgressWithPodSelectorNoNs := vBaseNetworkPolicy.DeepCopy()
 	vnetworkPolicyEgressWithPodSelectorNoNs.Spec.Egress = []networkingv1.NetworkPolicyEgressRule{
@@ -186,7 +207,6 @@ func TestSync(t *testing.T) {
 						{
 							Key:      "ns-expr-key",
 							Operator: metav1.LabelSelectorOpDoesNotExist,
-							Values:   []string{"forbidden-ns-key"},
 						},
 					},
 				},
@@ -209,9 +229,9 @@ func TestSync(t *testing.T) {
 							Values:   []string{"some-pod-key"},
 						},
 						{
-							Key:      translate.HostLabelNamespace("ns-expr-key"),
-							Operator: metav1.LabelSelectorOpDoesNotExist,
-							Values:   []string{"forbidden-ns-key"},
+							Key:      translate.NamespaceLabel,
+							Operator: metav1.LabelSelectorOpIn,
+							Values:   []string{"other", "test"},
 						},
 					},
 				},
@@ -219,6 +239,14 @@ func TestSync(t *testing.T) {
 		},
 	}
 
+	namespaces := func() []runtime.Object {
+		return []runtime.Object{
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test", Labels: map[string]string{"nslabelkey": "abc"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"nslabelkey": "abc", "ns-expr-key": "x"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+		}
+	}
+
 	syncertesting.RunTestsWithContext(t, func(vConfig *config.VirtualClusterConfig, pClient *testingutil.FakeIndexClient, vClient *testingutil.FakeIndexClient) *synccontext.RegisterContext {
 		vConfig.Sync.ToHost.NetworkPolicies.Enabled = true
 		return syncertesting.NewFakeRegisterContext(vConfig, pClient, vClient)
@@ -366,7 +394,7 @@ func TestSync(t *testing.T) {
 		},
 		{
 			Name:                "Create forward - ingress policy that uses pod label selector and namespace selector",
-			InitialVirtualState: []runtime.Object{vnetworkPolicyWithPodSelectorNsSelector.DeepCopy()},
+			InitialVirtualState: append(namespaces(), vnetworkPolicyWithPodSelectorNsSelector.DeepCopy()),
 			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
 				networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"): {vnetworkPolicyWithPodSelectorNsSelector},
 			},
@@ -381,7 +409,7 @@ func TestSync(t *testing.T) {
 		},
 		{
 			Name:                "Create forward - ingress policy that uses pod label selector and namespace selector which use MatchExpressions",
-			InitialVirtualState: []runtime.Object{vnetworkPolicyWithMatchExpressions.DeepCopy()},
+			InitialVirtualState: append(namespaces(), vnetworkPolicyWithMatchExpressions.DeepCopy()),
 			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
 				networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"): {vnetworkPolicyWithMatchExpressions},
 			},
@@ -394,6 +422,21 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                "Create forward - ingress policy that uses namespace selector without matching namespaces",
+			InitialVirtualState: append(namespaces(), vnetworkPolicyWithUnmatchedNsSelector.DeepCopy()),
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"): {vnetworkPolicyWithUnmatchedNsSelector},
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"): {pnetworkPolicyWithUnmatchedNsSelector},
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncCtx, syncer := syncertesting.FakeStartSyncer(t, ctx, New)
+				_, err := syncer.(*networkPolicySyncer).SyncToHost(syncCtx, synccontext.NewSyncToHostEvent(vnetworkPolicyWithUnmatchedNsSelector.DeepCopy()))
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name:                "Create forward - egress policy that uses pod label selector",
 			InitialVirtualState: []runtime.Object{vnetworkPolicyEgressWithPodSelectorNoNs.DeepCopy()},
@@ -1,31 +1,53 @@
 package networkpolicies
 
 import (
+	"fmt"
+
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	"github.com/loft-sh/vcluster/pkg/util/osutil"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 )
 
-func (s *networkPolicySyncer) translate(ctx *synccontext.SyncContext, vNetworkPolicy *networkingv1.NetworkPolicy) *networkingv1.NetworkPolicy {
+func (s *networkPolicySyncer) translate(ctx *synccontext.SyncContext, vNetworkPolicy *networkingv1.NetworkPolicy) (*networkingv1.NetworkPolicy, error) {
 	newNetworkPolicy := translate.HostMetadata(vNetworkPolicy, s.VirtualToHost(ctx, types.NamespacedName{Name: vNetworkPolicy.GetName(), Namespace: vNetworkPolicy.GetNamespace()}, vNetworkPolicy))
-	if spec := translateSpec(&vNetworkPolicy.Spec, vNetworkPolicy.GetNamespace()); spec != nil {
+	spec, err := translateSpec(ctx, &vNetworkPolicy.Spec, vNetworkPolicy.GetNamespace())
+	if err != nil {
+		return nil, err
+	} else if spec != nil {
 		newNetworkPolicy.Spec = *spec
 	}
-	return newNetworkPolicy
+	return newNetworkPolicy, nil
 }
 
-func (s *networkPolicySyncer) translateUpdate(pObj, vObj *networkingv1.NetworkPolicy) {
-	if translatedSpec := translateSpec(&vObj.Spec, vObj.GetNamespace()); translatedSpec != nil {
+func (s *networkPolicySyncer) translateUpdate(ctx *synccontext.SyncContext, pObj, vObj *networkingv1.NetworkPolicy) error {
+	translatedSpec, err := translateSpec(ctx, &vObj.Spec, vObj.GetNamespace())
+	if err != nil {
+		return err
+	} else if translatedSpec != nil {
 		pObj.Spec = *translatedSpec
 	}
+	return nil
 }
 
-func translateSpec(spec *networkingv1.NetworkPolicySpec, namespace string) *networkingv1.NetworkPolicySpec {
+func translateSpec(ctx *synccontext.SyncContext, spec *networkingv1.NetworkPolicySpec, namespace string) (*networkingv1.NetworkPolicySpec, error) {
 	if spec == nil {
-		return nil
+		return nil, nil
+	}
+
+	// all virtual namespaces end up in the same host namespace, so namespace selectors are resolved to the
+	// matching virtual namespaces
+	var namespaces []corev1.Namespace
+	if usesNamespaceSelector(spec) {
+		namespaceList := &corev1.NamespaceList{}
+		err := ctx.VirtualClient.List(ctx, namespaceList)
+		if err != nil {
+			return nil, fmt.Errorf("list virtual namespaces: %w", err)
+		}
+		namespaces = namespaceList.Items
 	}
 
 	outSpec := &networkingv1.NetworkPolicySpec{}
@@ -35,7 +57,7 @@ func translateSpec(spec *networkingv1.NetworkPolicySpec, namespace string) *netw
 		}
 		outSpec.Egress = append(outSpec.Egress, networkingv1.NetworkPolicyEgressRule{
 			Ports: er.Ports,
-			To:    translateNetworkPolicyPeers(er.To, namespace),
+			To:    translateNetworkPolicyPeers(er.To, namespace, namespaces),
 		})
 	}
 	for _, ir := range spec.Ingress {
@@ -44,7 +66,7 @@ func translateSpec(spec *networkingv1.NetworkPolicySpec, namespace string) *netw
 		}
 		outSpec.Ingress = append(outSpec.Ingress, networkingv1.NetworkPolicyIngressRule{
 			Ports: ir.Ports,
-			From:  translateNetworkPolicyPeers(ir.From, namespace),
+			From:  translateNetworkPolicyPeers(ir.From, namespace, namespaces),
 		})
 	}
 
@@ -66,10 +88,10 @@ func translateSpec(spec *networkingv1.NetworkPolicySpec, namespace string) *netw
 	}
 
 	outSpec.PolicyTypes = spec.PolicyTypes
-	return outSpec
+	return outSpec, nil
 }
 
-func translateNetworkPolicyPeers(peers []networkingv1.NetworkPolicyPeer, namespace string) []networkingv1.NetworkPolicyPeer {
+func translateNetworkPolicyPeers(peers []networkingv1.NetworkPolicyPeer, namespace string, namespaces []corev1.Namespace) []networkingv1.NetworkPolicyPeer {
 	if peers == nil {
 		return nil
 	}
@@ -80,8 +102,7 @@ func translateNetworkPolicyPeers(peers []networkingv1.NetworkPolicyPeer, namespa
 			NamespaceSelector: nil, // must be set to nil as all vcluster pods are in the same host namespace as the NetworkPolicy
 		}
 		if peer.IPBlock == nil {
-			translatedNamespaceSelectors := translate.HostLabelSelectorNamespace(peer.NamespaceSelector)
-			newPeer.PodSelector = translate.MergeLabelSelectors(newPeer.PodSelector, translatedNamespaceSelectors)
+			newPeer.PodSelector = translate.MergeLabelSelectors(newPeer.PodSelector, translate.NamespaceSelectorToHostPodSelector(peer.NamespaceSelector, namespaces))
 
 			if newPeer.PodSelector.MatchLabels == nil {
 				newPeer.PodSelector.MatchLabels = map[string]string{}
@@ -98,3 +119,23 @@ func translateNetworkPolicyPeers(peers []networkingv1.NetworkPolicyPeer, namespa
 	}
 	return out
 }
+
+// usesNamespaceSelector checks if any peer of the policy selects namespaces
+func usesNamespaceSelector(spec *networkingv1.NetworkPolicySpec) bool {
+	for _, rule := range spec.Ingress {
+		for _, peer := range rule.From {
+			if peer.NamespaceSelector != nil {
+				return true
+			}
+		}
+	}
+	for _, rule := range spec.Egress {
+		for _, peer := range rule.To {
+			if peer.NamespaceSelector != nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -3,6 +3,7 @@ package resources
 import (
 	"fmt"
 
+	"github.com/loft-sh/vcluster/pkg/controllers/resources/adminnetworkpolicies"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/backendtlspolicies"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/configmaps"
 	"github.com/loft-sh/vcluster/pkg/controllers/resources/csidrivers"
@@ -72,6 +73,8 @@ func getSyncers(ctx *synccontext.RegisterContext) []BuildController {
 		isEnabled(ctx.Config.Sync.ToHost.PriorityClasses.Enabled || ctx.Config.Sync.FromHost.PriorityClasses.Enabled, priorityclasses.New),
 		isEnabled(ctx.Config.Sync.ToHost.PodDisruptionBudgets.Enabled, poddisruptionbudgets.New),
 		isEnabled(ctx.Config.Sync.ToHost.NetworkPolicies.Enabled, networkpolicies.New),
+		isEnabled(ctx.Config.Sync.ToHost.AdminNetworkPolicies.Enabled, adminnetworkpolicies.New),
+		isEnabled(ctx.Config.Sync.ToHost.AdminNetworkPolicies.Enabled, adminnetworkpolicies.NewBaseline),
 		isEnabled(ctx.Config.Sync.ToHost.ServiceAccounts.Enabled, serviceaccounts.New),
 		isEnabled(ctx.Config.Sync.FromHost.CSINodes.Enabled == "true", csinodes.New),
 		isEnabled(ctx.Config.Sync.FromHost.CSIDrivers.Enabled == "true", csidrivers.New),
@@ -5,6 +5,7 @@ import (
 
 	"github.com/loft-sh/vcluster/pkg/constants"
 	"github.com/loft-sh/vcluster/pkg/mappings"
+	"github.com/loft-sh/vcluster/pkg/mappings/resources"
 	"github.com/loft-sh/vcluster/pkg/patcher"
 	"github.com/loft-sh/vcluster/pkg/pro"
 	"github.com/loft-sh/vcluster/pkg/syncer"
@@ -90,6 +91,18 @@ func (s *secretSyncer) SyncToHost(ctx *synccontext.SyncContext, event *syncconte
 		newSecret.Type = corev1.SecretTypeOpaque
 	}
 
+	err = s.checkMappedHostObject(ctx, event.Virtual, nil, types.NamespacedName{Namespace: newSecret.Namespace, Name: newSecret.Name})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if _, _, ok := ctx.Config.Sync.ToHost.Secrets.Mappings.HostName(event.Virtual.Namespace, event.Virtual.Name); ok {
+		err = resources.PrepareToHostMappingTarget(ctx, "secrets", newSecret)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	err = pro.ApplyPatchesHostObject(ctx, nil, newSecret, event.Virtual, ctx.Config.Sync.ToHost.Secrets.Patches, false)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -106,6 +119,11 @@ func (s *secretSyncer) Sync(ctx *synccontext.SyncContext, event *synccontext.Syn
 		return patcher.DeleteHostObject(ctx, event.Host, event.Virtual, "secret is not used anymore")
 	}
 
+	err = s.checkMappedHostObject(ctx, event.Virtual, event.Host, types.NamespacedName{Namespace: event.Host.Namespace, Name: event.Host.Name})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// patch objects
 	patch, err := patcher.NewSyncerPatcher(ctx, event.Host, event.Virtual, patcher.TranslatePatches(ctx.Config.Sync.ToHost.Secrets.Patches, false))
 	if err != nil {
@@ -184,6 +202,11 @@ func (s *secretSyncer) isSecretUsed(ctx *synccontext.SyncContext, secret *corev1
 		return true, nil
 	}
 
+	// mapped secrets are always synced
+	if _, _, ok := ctx.Config.Sync.ToHost.Secrets.Mappings.HostName(secret.Namespace, secret.Name); ok {
+		return true, nil
+	}
+
 	// if other objects reference this secret we sync it
 	if len(ctx.Mappings.Store().ReferencesTo(ctx, synccontext.Object{
 		GroupVersionKind: s.GroupVersionKind(),
@@ -197,3 +220,23 @@ func (s *secretSyncer) isSecretUsed(ctx *synccontext.SyncContext, secret *corev1
 
 	return false, nil
 }
+
+// checkMappedHostObject makes sure secrets mapped by sync.toHost.secrets.mappings don't take over host objects in shared
+// namespaces. The host object is retrieved if it's nil.
+func (s *secretSyncer) checkMappedHostObject(ctx *synccontext.SyncContext, vObj, pObj *corev1.Secret, hostName types.NamespacedName) error {
+	if _, _, ok := ctx.Config.Sync.ToHost.Secrets.Mappings.HostName(vObj.Namespace, vObj.Name); !ok {
+		return nil
+	}
+
+	var existing client.Object
+	if pObj != nil {
+		existing = pObj
+	}
+	err := resources.CheckToHostMappingTarget(ctx, vObj, existing, hostName)
+	if err != nil {
+		s.EventRecorder().Eventf(vObj, nil, "Warning", "SyncError", "SyncSecret", "Error syncing to host cluster: %v", err)
+		return err
+	}
+
+	return nil
+}
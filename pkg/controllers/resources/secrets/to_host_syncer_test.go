@@ -1,17 +1,23 @@
 package secrets
 
 import (
+	"context"
 	"testing"
 
+	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/mappings/resources"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	generictesting "github.com/loft-sh/vcluster/pkg/syncer/testing"
 	syncer "github.com/loft-sh/vcluster/pkg/syncer/types"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func newFakeSyncer(t *testing.T, ctx *synccontext.RegisterContext) (*synccontext.SyncContext, syncer.Object) {
@@ -79,6 +85,58 @@ func TestSync(t *testing.T) {
 		},
 	}
 
+	mappedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "registry-credentials",
+			Namespace: "team-a",
+		},
+		Data: map[string][]byte{
+			"token": []byte("secret"),
+		},
+	}
+	syncedMappedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "team-a-credentials",
+			Namespace: "shared-registry",
+			Annotations: map[string]string{
+				translate.NameAnnotation:          mappedSecret.Name,
+				translate.NamespaceAnnotation:     mappedSecret.Namespace,
+				translate.UIDAnnotation:           "",
+				translate.KindAnnotation:          corev1.SchemeGroupVersion.WithKind("Secret").String(),
+				translate.HostNamespaceAnnotation: "shared-registry",
+				translate.HostNameAnnotation:      "team-a-credentials",
+			},
+			Labels: map[string]string{
+				translate.MarkerLabel:    translate.SafeConcatName(testingutil.DefaultTestTargetNamespace, "x", translate.VClusterName),
+				translate.NamespaceLabel: mappedSecret.Namespace,
+			},
+		},
+		Data: mappedSecret.Data,
+	}
+	mappingRole := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.ToHostMappingRoleName(testingutil.DefaultTestVClusterName, testingutil.DefaultTestTargetNamespace, "secrets", "shared-registry", "team-a-credentials"),
+			Namespace: "shared-registry",
+			UID:       "role-uid",
+		},
+	}
+	otherVClusterSecret := syncedMappedSecret.DeepCopy()
+	otherVClusterSecret.Labels[translate.MarkerLabel] = translate.SafeConcatName("other-namespace", "x", translate.VClusterName)
+	foreignSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "team-a-credentials",
+			Namespace: "shared-registry",
+		},
+		Data: map[string][]byte{
+			"token": []byte("foreign"),
+		},
+	}
+	withMappings := func(vConfig *config.VirtualClusterConfig) {
+		vConfig.Sync.ToHost.Secrets.Mappings.ByName = map[string]string{
+			"team-a/registry-credentials": "shared-registry/team-a-credentials",
+		}
+	}
+
 	generictesting.RunTests(t, []*generictesting.SyncTest{
 		{
 			Name: "Unused secret",
@@ -151,5 +209,93 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name: "Mapped secret",
+			InitialVirtualState: []runtime.Object{
+				mappedSecret,
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Secret"): {
+					syncedMappedSecret,
+				},
+			},
+			AdjustConfig: withMappings,
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncContext, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.(*secretSyncer).SyncToHost(syncContext, synccontext.NewSyncToHostEvent(mappedSecret.DeepCopy()))
+				assert.NilError(t, err)
+			},
+		},
+		{
+			Name: "Mapped secret doesn't overwrite foreign host secret",
+			InitialVirtualState: []runtime.Object{
+				mappedSecret,
+			},
+			InitialPhysicalState: []runtime.Object{
+				foreignSecret,
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Secret"): {
+					foreignSecret,
+				},
+			},
+			AdjustConfig: withMappings,
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncContext, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.(*secretSyncer).SyncToHost(syncContext, synccontext.NewSyncToHostEvent(mappedSecret.DeepCopy()))
+				assert.ErrorContains(t, err, "host object shared-registry/team-a-credentials already exists and is not managed by this virtual cluster")
+			},
+		},
+		{
+			Name: "Mapped secret doesn't overwrite host secret of a virtual cluster with the same name",
+			InitialVirtualState: []runtime.Object{
+				mappedSecret,
+			},
+			InitialPhysicalState: []runtime.Object{
+				otherVClusterSecret,
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Secret"): {
+					otherVClusterSecret,
+				},
+			},
+			AdjustConfig: withMappings,
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncContext, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.(*secretSyncer).SyncToHost(syncContext, synccontext.NewSyncToHostEvent(mappedSecret.DeepCopy()))
+				assert.ErrorContains(t, err, "host object shared-registry/team-a-credentials already exists and is not managed by this virtual cluster")
+			},
+		},
+		{
+			Name: "Mapped secret is owned by its role",
+			InitialVirtualState: []runtime.Object{
+				mappedSecret,
+			},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Secret"): {
+					syncedMappedSecret,
+				},
+			},
+			AdjustConfig: func(vConfig *config.VirtualClusterConfig) {
+				withMappings(vConfig)
+				_, err := vConfig.HostClient.RbacV1().Roles(mappingRole.Namespace).Create(context.Background(), mappingRole, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			},
+			Sync: func(ctx *synccontext.RegisterContext) {
+				syncContext, syncer := newFakeSyncer(t, ctx)
+				_, err := syncer.(*secretSyncer).SyncToHost(syncContext, synccontext.NewSyncToHostEvent(mappedSecret.DeepCopy()))
+				assert.NilError(t, err)
+
+				hostSecret := &corev1.Secret{}
+				err = syncContext.HostClient.Get(syncContext, types.NamespacedName{Namespace: "shared-registry", Name: "team-a-credentials"}, hostSecret)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, hostSecret.OwnerReferences, []metav1.OwnerReference{{
+					APIVersion: rbacv1.SchemeGroupVersion.String(),
+					Kind:       "Role",
+					Name:       mappingRole.Name,
+					UID:        mappingRole.UID,
+				}})
+			},
+		},
 	})
 }
@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/loft-sh/vcluster/pkg/mappings"
@@ -13,6 +14,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	"github.com/loft-sh/vcluster/pkg/syncer/translator"
 	syncertypes "github.com/loft-sh/vcluster/pkg/syncer/types"
+	"github.com/loft-sh/vcluster/pkg/util/hostnamepolicy"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
@@ -202,9 +204,19 @@ func (s *serviceSyncer) Sync(ctx *synccontext.SyncContext, event *synccontext.Sy
 	ensureLoadBalancerStatus(event.Host)
 	event.Virtual.Status = event.Host.Status
 
-	// bi-directional sync of annotations and labels
-	event.Virtual.Annotations, event.Host.Annotations = translate.AnnotationsBidirectionalUpdate(event, s.excludedAnnotations...)
+	// bi-directional sync of annotations and labels, ExternalDNS annotations that are not permitted are removed from the host
+	excludedAnnotations := s.excludedAnnotations
+	hostnameAnnotationsNotPermitted := s.hostnameAnnotationsNotPermitted(ctx, event.Virtual)
+	if hostnameAnnotationsNotPermitted {
+		excludedAnnotations = append(slices.Clone(excludedAnnotations), hostnamepolicy.ExternalDNSAnnotations...)
+	}
+	event.Virtual.Annotations, event.Host.Annotations = translate.AnnotationsBidirectionalUpdate(event, excludedAnnotations...)
 	event.Virtual.Labels, event.Host.Labels = translate.LabelsBidirectionalUpdate(event)
+	if hostnameAnnotationsNotPermitted {
+		for _, key := range hostnamepolicy.ExternalDNSAnnotations {
+			delete(event.Host.Annotations, key)
+		}
+	}
 
 	// remove the ServiceBlockDeletion annotation if it's not needed
 	delete(event.Host.Annotations, ServiceBlockDeletion)
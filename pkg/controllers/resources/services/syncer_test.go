@@ -3,9 +3,11 @@ package services
 import (
 	"testing"
 
+	"github.com/loft-sh/vcluster/config"
 	"github.com/loft-sh/vcluster/pkg/specialservices"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	syncertesting "github.com/loft-sh/vcluster/pkg/syncer/testing"
+	"github.com/loft-sh/vcluster/pkg/util/hostnamepolicy"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -77,6 +79,8 @@ func TestSync(t *testing.T) {
 		},
 		Spec: updateForwardSpec,
 	}
+	externalDNSService := updateForwardService.DeepCopy()
+	externalDNSService.Annotations[hostnamepolicy.ExternalDNSHostnameAnnotation] = "shop.other.com"
 	updatedForwardService := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      pObjectMeta.Name,
@@ -526,6 +530,28 @@ func TestSync(t *testing.T) {
 				assert.NilError(t, err)
 			},
 		},
+		{
+			Name:                 "Update forward without ExternalDNS annotation not permitted",
+			InitialPhysicalState: []runtime.Object{createdByServerService.DeepCopy()},
+			ExpectedPhysicalState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {updatedForwardService.DeepCopy()},
+			},
+
+			InitialVirtualState: []runtime.Object{externalDNSService.DeepCopy()},
+			ExpectedVirtualState: map[schema.GroupVersionKind][]runtime.Object{
+				corev1.SchemeGroupVersion.WithKind("Service"): {externalDNSService.DeepCopy()},
+			},
+
+			Sync: func(ctx *synccontext.RegisterContext) {
+				ctx.Config.Policies.Hostnames = config.HostnamePolicy{Enabled: true, AllowedDomains: []string{"apps.example.com"}}
+				syncCtx, syncer := syncertesting.FakeStartSyncer(t, ctx, New)
+				pObjOld := createdByServerService.DeepCopy()
+				vObjOld := createdService.DeepCopy()
+				vObj := externalDNSService.DeepCopy()
+				_, err := syncer.(*serviceSyncer).Sync(syncCtx, synccontext.NewSyncEventWithOld(pObjOld, pObjOld, vObjOld, vObj))
+				assert.NilError(t, err)
+			},
+		},
 		{
 			Name:                 "Update forward not needed",
 			InitialVirtualState:  []runtime.Object{baseService.DeepCopy()},
@@ -1,14 +1,22 @@
 package services
 
 import (
+	"slices"
+
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/hostnamepolicy"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
 func (s *serviceSyncer) translate(ctx *synccontext.SyncContext, vObj *corev1.Service) *corev1.Service {
-	newService := translate.HostMetadata(vObj, s.VirtualToHost(ctx, types.NamespacedName{Name: vObj.GetName(), Namespace: vObj.GetNamespace()}, vObj), s.excludedAnnotations...)
+	excludedAnnotations := s.excludedAnnotations
+	if s.hostnameAnnotationsNotPermitted(ctx, vObj) {
+		excludedAnnotations = append(slices.Clone(excludedAnnotations), hostnamepolicy.ExternalDNSAnnotations...)
+	}
+
+	newService := translate.HostMetadata(vObj, s.VirtualToHost(ctx, types.NamespacedName{Name: vObj.GetName(), Namespace: vObj.GetNamespace()}, vObj), excludedAnnotations...)
 	newService.Spec.Selector = translate.HostLabelsMap(vObj.Spec.Selector, nil, vObj.Namespace, false)
 	if newService.Spec.ClusterIP != "None" {
 		newService.Spec.ClusterIP = ""
@@ -45,6 +53,19 @@ func (s *serviceSyncer) translateToVirtual(ctx *synccontext.SyncContext, pObj *c
 	return newService
 }
 
+// hostnameAnnotationsNotPermitted records an event and returns true if the ExternalDNS annotations of the service claim
+// hostnames that are not permitted by policies.hostnames. The service itself is still synced, only these annotations
+// are not.
+func (s *serviceSyncer) hostnameAnnotationsNotPermitted(ctx *synccontext.SyncContext, vObj *corev1.Service) bool {
+	err := hostnamepolicy.ValidateAnnotations(ctx.Config.Policies.Hostnames, vObj.Annotations)
+	if err == nil {
+		return false
+	}
+
+	s.EventRecorder().Eventf(vObj, nil, "Warning", hostnamepolicy.Reason, "SyncService", "did not sync ExternalDNS annotations of service %q to host: %v", vObj.Name, err)
+	return true
+}
+
 func StripNodePorts(vObj *corev1.Service) {
 	for i := range vObj.Spec.Ports {
 		vObj.Spec.Ports[i].NodePort = 0
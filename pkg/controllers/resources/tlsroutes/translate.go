@@ -27,6 +27,9 @@ func specToHost(ctx *synccontext.SyncContext, vRoute *gatewayv1.TLSRoute, valida
 	if err := routetranslate.ValidateImportedGatewayHostnamePolicy(ctx, "TLSRoute", vRoute.Namespace, vRoute.Spec.ParentRefs, vRoute.Spec.Hostnames); err != nil {
 		return nil, err
 	}
+	if err := routetranslate.ValidateHostnamePolicy(ctx, vRoute.Spec.Hostnames, vRoute.Annotations); err != nil {
+		return nil, err
+	}
 
 	retSpec := vRoute.Spec.DeepCopy()
 	for i := range retSpec.ParentRefs {
@@ -24,6 +24,9 @@ const corednsCorefile = `{{- if .Values.controlPlane.coredns.overwriteConfig }}
     {{- if .Values.networking.advanced.proxyKubelets.byHostname }}
     rewrite name regex .*\.nodes\.vcluster\.com kubernetes.default.svc.cluster.local
     {{- end }}
+    {{- range .Values.controlPlane.coredns.rewrites }}
+    rewrite name {{ if .match }}{{ .match }}{{ else }}exact{{ end }} {{ .from }} {{ .to }}{{ if and .match (eq .match "regex") }} answer auto{{ end }}
+    {{- end }}
     kubernetes{{ if and (.Values.networking.advanced.clusterDomain) (ne .Values.networking.advanced.clusterDomain "cluster.local") }} {{ .Values.networking.advanced.clusterDomain }}{{ end }} cluster.local in-addr.arpa ip6.arpa {
         {{- if .Values.controlPlane.coredns.embedded }}
         kubeconfig /data/vcluster/admin.conf
@@ -36,6 +39,9 @@ const corednsCorefile = `{{- if .Values.controlPlane.coredns.overwriteConfig }}
         {{- end }}
     }
     hosts /etc/coredns/NodeHosts {
+        {{- range .Values.controlPlane.coredns.hosts }}
+        {{ .ip }}{{ range .hostnames }} {{ . }}{{ end }}
+        {{- end }}
         ttl 60
         reload 15s
         fallthrough
@@ -43,6 +49,12 @@ const corednsCorefile = `{{- if .Values.controlPlane.coredns.overwriteConfig }}
     prometheus :9153
     {{- if .Values.networking.advanced.fallbackHostCluster }}
     forward . {{ .HOST_CLUSTER_DNS }}
+    {{- else if .Values.controlPlane.coredns.forward.upstreams }}
+    forward .{{ range .Values.controlPlane.coredns.forward.upstreams }} {{ . }}{{ end }}
+    {{- if .Values.controlPlane.coredns.forward.policy }} {
+        policy {{ .Values.controlPlane.coredns.forward.policy }}
+    }
+    {{- end }}
     {{- else if .Values.policies.networkPolicy.enabled }}
     forward . /etc/resolv.conf {{ .Values.policies.networkPolicy.fallbackDns }} {
         policy sequential
@@ -57,6 +69,18 @@ const corednsCorefile = `{{- if .Values.controlPlane.coredns.overwriteConfig }}
     {{- end }}
     loadbalance
 }
+{{- range .Values.controlPlane.coredns.stubDomains }}
+
+{{ .domain }}:1053 {
+    errors
+    forward .{{ range .upstreams }} {{ . }}{{ end }}
+    {{- if .policy }} {
+        policy {{ .policy }}
+    }
+    {{- end }}
+    cache 30
+}
+{{- end }}
 
 import /etc/coredns/custom/*.server
 {{- end }}`
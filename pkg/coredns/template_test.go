@@ -1012,6 +1012,69 @@ func TestProcessCorefile(t *testing.T) {
     loadbalance
 }
 
+import /etc/coredns/custom/*.server`,
+		},
+		{
+			name: "zones",
+			vars: map[string]interface{}{},
+			config: &config.Config{
+				ControlPlane: config.ControlPlane{
+					CoreDNS: config.CoreDNS{
+						Enabled:  true,
+						Embedded: true,
+						StubDomains: []config.CoreDNSStubDomain{
+							{Domain: "corp.internal", CoreDNSForward: config.CoreDNSForward{Upstreams: []string{"10.0.0.10", "10.0.0.11:5353"}, Policy: "sequential"}},
+							{Domain: "lab.internal", CoreDNSForward: config.CoreDNSForward{Upstreams: []string{"tls://10.0.1.10"}}},
+						},
+						Forward: config.CoreDNSForward{Upstreams: []string{"1.1.1.1", "8.8.8.8"}, Policy: "round_robin"},
+						Hosts:   []config.CoreDNSHost{{IP: "10.0.0.5", Hostnames: []string{"registry.corp.internal", "git.corp.internal"}}},
+						Rewrites: []config.CoreDNSRewrite{
+							{From: "db.example.com", To: "db.default.svc.cluster.local"},
+							{Match: "regex", From: "(.*)\\.legacy\\.internal", To: "{1}.default.svc.cluster.local"},
+						},
+					},
+				},
+			},
+			want: `.:1053 {
+    errors
+    health
+    ready
+    rewrite name exact db.example.com db.default.svc.cluster.local
+    rewrite name regex (.*)\.legacy\.internal {1}.default.svc.cluster.local answer auto
+    kubernetes cluster.local in-addr.arpa ip6.arpa {
+        kubeconfig /data/vcluster/admin.conf
+        pods insecure
+        fallthrough in-addr.arpa ip6.arpa
+    }
+    hosts /etc/coredns/NodeHosts {
+        10.0.0.5 registry.corp.internal git.corp.internal
+        ttl 60
+        reload 15s
+        fallthrough
+    }
+    prometheus :9153
+    forward . 1.1.1.1 8.8.8.8 {
+        policy round_robin
+    }
+    cache 30
+    loop
+    loadbalance
+}
+
+corp.internal:1053 {
+    errors
+    forward . 10.0.0.10 10.0.0.11:5353 {
+        policy sequential
+    }
+    cache 30
+}
+
+lab.internal:1053 {
+    errors
+    forward . tls://10.0.1.10
+    cache 30
+}
+
 import /etc/coredns/custom/*.server`,
 		},
 		{
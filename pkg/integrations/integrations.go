@@ -1,7 +1,9 @@
 package integrations
 
 import (
+	"github.com/loft-sh/vcluster/pkg/integrations/metricsadapter"
 	"github.com/loft-sh/vcluster/pkg/integrations/metricsserver"
+	"github.com/loft-sh/vcluster/pkg/integrations/prometheusoperator"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 )
 
@@ -9,6 +11,8 @@ type Integration func(ctx *synccontext.ControllerContext) error
 
 var Integrations = []Integration{
 	metricsserver.Register,
+	metricsadapter.Register,
+	prometheusoperator.Register,
 }
 
 func StartIntegrations(ctx *synccontext.ControllerContext) error {
@@ -0,0 +1,126 @@
+package metricsadapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/mappings"
+	"github.com/loft-sh/vcluster/pkg/server/filters"
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// namespaceMetricsResource is used by the custom metrics api for metrics describing a namespace itself
+const namespaceMetricsResource = "metrics"
+
+// handleCustomMetricsRequest translates the namespace, name and label selector of a custom metrics request to the host
+// objects and translates the described objects of the returned metrics back to the virtual objects. Requests are in the
+// form /apis/custom.metrics.k8s.io/VERSION/namespaces/NAMESPACE/RESOURCE/NAME/METRIC, where NAME can be * to select
+// objects by label selector.
+func handleCustomMetricsRequest(ctx *synccontext.RegisterContext, proxyHandler http.Handler, w http.ResponseWriter, req *http.Request, info *request.RequestInfo) {
+	syncContext := ctx.ToSyncContext("custom-metrics-proxy")
+	if info.Resource == namespaceMetricsResource {
+		// the host namespace holds objects of all virtual namespaces, so its metrics can't be translated
+		requestpkg.FailWithStatus(w, req, http.StatusForbidden, fmt.Errorf("custom metrics of namespaces are not supported"))
+		return
+	}
+
+	gvk, err := ctx.VirtualManager.GetRESTMapper().KindFor(schema.ParseGroupResource(info.Resource).WithVersion(""))
+	if err != nil {
+		requestpkg.FailWithStatus(w, req, http.StatusNotFound, fmt.Errorf("resource %s not found: %w", info.Resource, err))
+		return
+	} else if !syncContext.Mappings.Has(gvk) {
+		requestpkg.FailWithStatus(w, req, http.StatusNotFound, fmt.Errorf("custom metrics of %s are not supported, because they are not synced to the host cluster", info.Resource))
+		return
+	}
+
+	// translate the described objects
+	pName := info.Name
+	if pName != "*" {
+		pName = mappings.VirtualToHostName(syncContext, info.Name, info.Namespace, gvk)
+	} else {
+		query := req.URL.Query()
+		pSelector, err := translateLabelSelector(query.Get("labelSelector"), info.Namespace)
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusBadRequest, err)
+			return
+		}
+
+		query.Set("labelSelector", pSelector)
+		req.URL.RawQuery = query.Encode()
+	}
+	req.URL.Path = customMetricsPath(info, mappings.VirtualToHostNamespace(syncContext, info.Namespace), pName)
+
+	// execute request in host cluster
+	code, header, data, err := filters.ExecuteRequest(req, proxyHandler)
+	if err != nil {
+		requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+		return
+	} else if code != http.StatusOK {
+		filters.WriteWithHeader(w, code, header, data)
+		return
+	}
+
+	data, err = rewriteMetricValueList(data, info.Namespace, func(pName, pNamespace string) types.NamespacedName {
+		return mappings.HostToVirtual(syncContext, pName, pNamespace, nil, gvk)
+	})
+	if err != nil {
+		requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+		return
+	}
+
+	header.Del("Content-Length")
+	filters.WriteWithHeader(w, code, header, data)
+}
+
+func customMetricsPath(info *request.RequestInfo, pNamespace, pName string) string {
+	parts := []string{"", "apis", info.APIGroup, info.APIVersion, "namespaces", pNamespace, info.Resource, pName}
+	if info.Subresource != "" {
+		parts = append(parts, info.Subresource)
+	}
+
+	return strings.Join(parts, "/")
+}
+
+// rewriteMetricValueList translates the described objects of a custom metrics MetricValueList back to the virtual
+// objects and drops metrics of objects that don't belong to the requested virtual namespace. The list is handled as
+// raw json to support all versions of the custom metrics api.
+func rewriteMetricValueList(data []byte, vNamespace string, hostToVirtual func(pName, pNamespace string) types.NamespacedName) ([]byte, error) {
+	metricValueList := map[string]interface{}{}
+	err := json.Unmarshal(data, &metricValueList)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal metric value list: %w", err)
+	}
+
+	items, _ := metricValueList["items"].([]interface{})
+	newItems := []interface{}{}
+	for _, item := range items {
+		metricValue, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		describedObject, ok := metricValue["describedObject"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		pName, _ := describedObject["name"].(string)
+		pNamespace, _ := describedObject["namespace"].(string)
+		vName := hostToVirtual(pName, pNamespace)
+		if vName.Name == "" || vName.Namespace != vNamespace {
+			continue
+		}
+
+		describedObject["name"] = vName.Name
+		describedObject["namespace"] = vName.Namespace
+		newItems = append(newItems, metricValue)
+	}
+	metricValueList["items"] = newItems
+
+	return json.Marshal(metricValueList)
+}
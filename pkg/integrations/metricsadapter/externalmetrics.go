@@ -0,0 +1,20 @@
+package metricsadapter
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/mappings"
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+// handleExternalMetricsRequest translates the namespace of an external metrics request to the host namespace. Requests
+// are in the form /apis/external.metrics.k8s.io/VERSION/namespaces/NAMESPACE/METRIC. External metrics don't describe
+// Kubernetes objects and their label selector matches metric labels, so neither the selector nor the returned
+// metrics need to be translated.
+func handleExternalMetricsRequest(ctx *synccontext.RegisterContext, proxyHandler http.Handler, w http.ResponseWriter, req *http.Request, info *request.RequestInfo) {
+	syncContext := ctx.ToSyncContext("external-metrics-proxy")
+	req.URL.Path = strings.Join([]string{"", "apis", info.APIGroup, info.APIVersion, "namespaces", mappings.VirtualToHostNamespace(syncContext, info.Namespace), info.Resource}, "/")
+	proxyHandler.ServeHTTP(w, req)
+}
@@ -0,0 +1,205 @@
+package metricsadapter
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/apiservice"
+	"github.com/loft-sh/vcluster/pkg/server/handler"
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+const (
+	customMetricsHostPort   = 9002
+	externalMetricsHostPort = 9003
+
+	customMetricsServiceName   = "custom-metrics-apiserver"
+	externalMetricsServiceName = "external-metrics-apiserver"
+)
+
+var (
+	CustomMetricsGroupVersions = []schema.GroupVersion{
+		{Group: "custom.metrics.k8s.io", Version: "v1beta1"},
+		{Group: "custom.metrics.k8s.io", Version: "v1beta2"},
+	}
+	ExternalMetricsGroupVersions = []schema.GroupVersion{
+		{Group: "external.metrics.k8s.io", Version: "v1beta1"},
+	}
+)
+
+// adapter is a host metrics adapter that is made available within the vCluster
+type adapter struct {
+	config config.MetricsAdapter
+
+	serviceName   string
+	hostPort      int
+	groupVersions []schema.GroupVersion
+
+	defaultTargetService          string
+	defaultTargetServiceNamespace string
+
+	handleRequest func(ctx *synccontext.RegisterContext, proxyHandler http.Handler, w http.ResponseWriter, req *http.Request, info *request.RequestInfo)
+}
+
+// Register proxies the custom metrics and external metrics APIs of host metrics adapters into the vCluster, so
+// horizontal pod autoscalers within the vCluster can scale on custom and external metrics.
+func Register(ctx *synccontext.ControllerContext) error {
+	if ctx.Config.PrivateNodes.Enabled {
+		return nil
+	}
+
+	for _, a := range []*adapter{
+		{
+			config:                        ctx.Config.Integrations.CustomMetrics,
+			serviceName:                   customMetricsServiceName,
+			hostPort:                      customMetricsHostPort,
+			groupVersions:                 CustomMetricsGroupVersions,
+			defaultTargetService:          "prometheus-adapter",
+			defaultTargetServiceNamespace: "monitoring",
+			handleRequest:                 handleCustomMetricsRequest,
+		},
+		{
+			config:                        ctx.Config.Integrations.ExternalMetrics,
+			serviceName:                   externalMetricsServiceName,
+			hostPort:                      externalMetricsHostPort,
+			groupVersions:                 ExternalMetricsGroupVersions,
+			defaultTargetService:          "keda-operator-metrics-apiserver",
+			defaultTargetServiceNamespace: "keda",
+			handleRequest:                 handleExternalMetricsRequest,
+		},
+	} {
+		err := a.register(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *adapter) register(ctx *synccontext.ControllerContext) error {
+	ctx.AcquiredLeaderHooks = append(ctx.AcquiredLeaderHooks, a.registerOrDeregisterAPIServices)
+	if !a.config.Enabled {
+		return nil
+	}
+
+	err := apiservice.StartAPIServiceProxy(
+		ctx,
+		cmp.Or(a.config.APIService.Service.Name, a.defaultTargetService),
+		cmp.Or(a.config.APIService.Service.Namespace, a.defaultTargetServiceNamespace),
+		cmp.Or(a.config.APIService.Service.Port, 443),
+		a.hostPort,
+		func(h http.Handler) http.Handler {
+			return a.withProxy(h, ctx.ToRegisterContext())
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("start %s api service proxy: %w", a.serviceName, err)
+	}
+
+	ctx.PostServerHooks = append(ctx.PostServerHooks, func(h http.Handler, ctx *synccontext.ControllerContext) http.Handler {
+		return a.withProxy(h, ctx.ToRegisterContext())
+	})
+	return nil
+}
+
+func (a *adapter) registerOrDeregisterAPIServices(ctx *synccontext.ControllerContext) error {
+	for _, groupVersion := range a.groupVersions {
+		var err error
+		if a.config.Enabled {
+			err = apiservice.RegisterAPIService(ctx, a.serviceName, a.hostPort, groupVersion, a.serviceName)
+		} else {
+			err = apiservice.DeregisterAPIServiceForService(ctx, a.serviceName, groupVersion)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// withProxy executes metrics requests of the adapter's groups against the host cluster
+func (a *adapter) withProxy(h http.Handler, registerCtx *synccontext.RegisterContext) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info, ok := request.RequestInfoFrom(req.Context())
+		if !ok {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, fmt.Errorf("request info is missing"))
+			return
+		}
+
+		if !a.isProxyRequest(info) {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		// the metrics api is only exposed to namespaces of this vCluster
+		if info.Namespace == "" {
+			requestpkg.FailWithStatus(w, req, http.StatusForbidden, fmt.Errorf("only namespaced %s requests are supported", info.APIGroup))
+			return
+		}
+
+		proxyHandler, err := handler.Handler("", registerCtx.HostManager.GetConfig(), nil)
+		if err != nil {
+			requestpkg.FailWithStatus(w, req, http.StatusInternalServerError, err)
+			return
+		}
+
+		req.Header.Del("Authorization")
+		a.handleRequest(registerCtx, proxyHandler, w, req, info)
+	})
+}
+
+func (a *adapter) isProxyRequest(info *request.RequestInfo) bool {
+	if !info.IsResourceRequest {
+		return false
+	}
+
+	for _, groupVersion := range a.groupVersions {
+		if info.APIGroup == groupVersion.Group && info.APIVersion == groupVersion.Version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// translateLabelSelector rewrites a label selector for objects of the given virtual namespace to select their host
+// objects instead.
+func translateLabelSelector(selector, vNamespace string) (string, error) {
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return "", fmt.Errorf("parse label selector %q: %w", selector, err)
+	}
+
+	requirements, _ := parsed.Requirements()
+	hostSelector := labels.NewSelector()
+	for _, requirement := range requirements {
+		hostRequirement, err := labels.NewRequirement(translate.HostLabel(requirement.Key()), requirement.Operator(), requirement.Values().List())
+		if err != nil {
+			return "", err
+		}
+
+		hostSelector = hostSelector.Add(*hostRequirement)
+	}
+
+	// only select objects of this vCluster in the requested namespace
+	markerRequirement, err := labels.NewRequirement(translate.MarkerLabel, selection.Equals, []string{translate.VClusterName})
+	if err != nil {
+		return "", err
+	}
+	namespaceRequirement, err := labels.NewRequirement(translate.NamespaceLabel, selection.Equals, []string{vNamespace})
+	if err != nil {
+		return "", err
+	}
+
+	return hostSelector.Add(*markerRequirement, *namespaceRequirement).String(), nil
+}
@@ -0,0 +1,82 @@
+package metricsadapter
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apiserver/pkg/endpoints/request"
+)
+
+func TestTranslateLabelSelector(t *testing.T) {
+	testCases := []struct {
+		name     string
+		selector string
+
+		expectedSelector string
+		expectedErr      string
+	}{
+		{
+			name:             "empty selector",
+			expectedSelector: translate.MarkerLabel + "=" + translate.VClusterName + "," + translate.NamespaceLabel + "=test",
+		},
+		{
+			name:             "set based selector",
+			selector:         "app=web,tier in (backend,frontend),!canary",
+			expectedSelector: "app=web,!canary,tier in (backend,frontend)," + translate.MarkerLabel + "=" + translate.VClusterName + "," + translate.NamespaceLabel + "=test",
+		},
+		{
+			name:        "invalid selector",
+			selector:    "app in web",
+			expectedErr: `parse label selector "app in web"`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			selector, err := translateLabelSelector(testCase.selector, "test")
+			if testCase.expectedErr != "" {
+				assert.ErrorContains(t, err, testCase.expectedErr)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, selector, testCase.expectedSelector)
+		})
+	}
+}
+
+func TestCustomMetricsPath(t *testing.T) {
+	info := &request.RequestInfo{
+		APIGroup:    "custom.metrics.k8s.io",
+		APIVersion:  "v1beta2",
+		Namespace:   "test",
+		Resource:    "pods",
+		Name:        "*",
+		Subresource: "http_requests",
+	}
+	assert.Equal(t, customMetricsPath(info, "vcluster", "*"), "/apis/custom.metrics.k8s.io/v1beta2/namespaces/vcluster/pods/*/http_requests")
+}
+
+func TestRewriteMetricValueList(t *testing.T) {
+	hostToVirtual := map[types.NamespacedName]types.NamespacedName{
+		{Namespace: "vcluster", Name: "web-x-test-x-suffix"}:  {Namespace: "test", Name: "web"},
+		{Namespace: "vcluster", Name: "web-x-other-x-suffix"}: {Namespace: "other", Name: "web"},
+	}
+
+	data, err := rewriteMetricValueList([]byte(`{
+  "kind": "MetricValueList",
+  "apiVersion": "custom.metrics.k8s.io/v1beta2",
+  "metadata": {},
+  "items": [
+    {"describedObject": {"kind": "Pod", "namespace": "vcluster", "name": "web-x-test-x-suffix", "apiVersion": "/v1"}, "metric": {"name": "http_requests", "selector": null}, "value": "1"},
+    {"describedObject": {"kind": "Pod", "namespace": "vcluster", "name": "web-x-other-x-suffix", "apiVersion": "/v1"}, "metric": {"name": "http_requests", "selector": null}, "value": "2"},
+    {"describedObject": {"kind": "Pod", "namespace": "vcluster", "name": "unknown", "apiVersion": "/v1"}, "metric": {"name": "http_requests", "selector": null}, "value": "3"}
+  ]
+}`), "test", func(pName, pNamespace string) types.NamespacedName {
+		return hostToVirtual[types.NamespacedName{Namespace: pNamespace, Name: pName}]
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{"apiVersion":"custom.metrics.k8s.io/v1beta2","items":[{"describedObject":{"apiVersion":"/v1","kind":"Pod","name":"web","namespace":"test"},"metric":{"name":"http_requests","selector":null},"value":"1"}],"kind":"MetricValueList","metadata":{}}`)
+}
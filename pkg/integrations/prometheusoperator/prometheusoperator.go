@@ -0,0 +1,47 @@
+package prometheusoperator
+
+import (
+	"fmt"
+
+	syncerresources "github.com/loft-sh/vcluster/pkg/controllers/resources"
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	ServiceMonitors = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+	PodMonitors     = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}
+	PrometheusRules = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}
+)
+
+// Register copies the prometheus-operator CRDs from the host cluster into the vCluster and starts the syncers for
+// the enabled resources.
+func Register(ctx *synccontext.ControllerContext) error {
+	if !ctx.Config.Integrations.PrometheusOperator.Enabled {
+		return nil
+	}
+
+	toHost := ctx.Config.Integrations.PrometheusOperator.Sync.ToHost
+	for _, kind := range []struct {
+		gvk     schema.GroupVersionKind
+		enabled bool
+	}{
+		{gvk: ServiceMonitors, enabled: toHost.ServiceMonitors.Enabled},
+		{gvk: PodMonitors, enabled: toHost.PodMonitors.Enabled},
+		{gvk: PrometheusRules, enabled: toHost.PrometheusRules.Enabled},
+	} {
+		if !kind.enabled {
+			continue
+		}
+
+		_, _, err := translate.EnsureCRDFromPhysicalCluster(ctx, ctx.HostManager.GetConfig(), ctx.VirtualManager.GetConfig(), kind.gvk)
+		if err != nil {
+			return fmt.Errorf("ensure %s crd from host cluster: %w", kind.gvk.Kind, err)
+		}
+
+		syncerresources.ExtraControllers = append(syncerresources.ExtraControllers, newBuildController(kind.gvk))
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package prometheusoperator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	syncerresources "github.com/loft-sh/vcluster/pkg/controllers/resources"
+	"github.com/loft-sh/vcluster/pkg/mappings/generic"
+	"github.com/loft-sh/vcluster/pkg/patcher"
+	"github.com/loft-sh/vcluster/pkg/syncer"
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/syncer/translator"
+	syncertypes "github.com/loft-sh/vcluster/pkg/syncer/types"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newBuildController(gvk schema.GroupVersionKind) syncerresources.BuildController {
+	return func(ctx *synccontext.RegisterContext) (syncertypes.Object, error) {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+
+		mapper, err := generic.NewMapper(ctx, obj, translate.Default.HostName)
+		if err != nil {
+			return nil, err
+		}
+
+		return &prometheusOperatorSyncer{
+			GenericTranslator: translator.NewGenericTranslator(ctx, strings.ToLower(gvk.Kind), obj, mapper),
+
+			enforcedLabels: ctx.Config.Integrations.PrometheusOperator.EnforcedLabels,
+		}, nil
+	}
+}
+
+// prometheusOperatorSyncer syncs ServiceMonitors, PodMonitors and PrometheusRules from the virtual cluster to the host
+type prometheusOperatorSyncer struct {
+	syncertypes.GenericTranslator
+
+	enforcedLabels map[string]string
+}
+
+var _ syncertypes.Syncer = &prometheusOperatorSyncer{}
+
+func (s *prometheusOperatorSyncer) Syncer() syncertypes.Sync[client.Object] {
+	return syncer.ToGenericSyncer[*unstructured.Unstructured](s)
+}
+
+func (s *prometheusOperatorSyncer) SyncToHost(ctx *synccontext.SyncContext, event *synccontext.SyncToHostEvent[*unstructured.Unstructured]) (ctrl.Result, error) {
+	if event.HostOld != nil || event.Virtual.GetDeletionTimestamp() != nil {
+		return patcher.DeleteVirtualObject(ctx, event.Virtual, event.HostOld, "host object was deleted")
+	}
+
+	pObj, err := s.translate(ctx, event.Virtual)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return patcher.CreateHostObject(ctx, event.Virtual, pObj, s.EventRecorder(), false)
+}
+
+func (s *prometheusOperatorSyncer) Sync(ctx *synccontext.SyncContext, event *synccontext.SyncEvent[*unstructured.Unstructured]) (_ ctrl.Result, retErr error) {
+	patch, err := patcher.NewSyncerPatcher(ctx, event.Host, event.Virtual)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("new syncer patcher: %w", err)
+	}
+	defer func() {
+		if err := patch.Patch(ctx, event.Host, event.Virtual); err != nil {
+			retErr = utilerrors.NewAggregate([]error{retErr, err})
+		}
+		if retErr != nil {
+			s.EventRecorder().Eventf(
+				event.Virtual,
+				nil,
+				"Warning",
+				"SyncError",
+				fmt.Sprintf("Sync%s", event.Virtual.GetKind()),
+				"Error syncing: %v",
+				retErr,
+			)
+		}
+	}()
+
+	err = translateSpec(ctx, event.Host, event.Virtual)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// bi-directional sync of annotations and labels, the enforced labels are only set on the host object
+	newVirtualAnnotations, newHostAnnotations := translate.AnnotationsBidirectionalUpdate(event)
+	event.Virtual.SetAnnotations(newVirtualAnnotations)
+	event.Host.SetAnnotations(newHostAnnotations)
+	newVirtualLabels, newHostLabels := translate.LabelsBidirectionalUpdate(event, slices.Collect(maps.Keys(s.enforcedLabels))...)
+	event.Virtual.SetLabels(newVirtualLabels)
+	event.Host.SetLabels(withEnforcedLabels(newHostLabels, s.enforcedLabels))
+	return ctrl.Result{}, nil
+}
+
+func (s *prometheusOperatorSyncer) SyncToVirtual(ctx *synccontext.SyncContext, event *synccontext.SyncToVirtualEvent[*unstructured.Unstructured]) (_ ctrl.Result, retErr error) {
+	// virtual object is not here anymore, so we delete
+	return patcher.DeleteHostObject(ctx, event.Host, event.VirtualOld, "virtual object was deleted")
+}
@@ -0,0 +1,359 @@
+package prometheusoperator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/mappings"
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// endpointFields are the spec fields that hold the scrape endpoints of the monitors
+var endpointFields = map[schema.GroupVersionKind]string{
+	ServiceMonitors: "endpoints",
+	PodMonitors:     "podMetricsEndpoints",
+}
+
+var (
+	// endpointSecretPaths are the secret key selectors of an endpoint
+	endpointSecretPaths = [][]string{
+		{"basicAuth", "username"},
+		{"basicAuth", "password"},
+		{"bearerTokenSecret"},
+		{"authorization", "credentials"},
+		{"oauth2", "clientId", "secret"},
+		{"oauth2", "clientSecret"},
+		{"tlsConfig", "ca", "secret"},
+		{"tlsConfig", "cert", "secret"},
+		{"tlsConfig", "keySecret"},
+	}
+	// endpointConfigMapPaths are the config map key selectors of an endpoint
+	endpointConfigMapPaths = [][]string{
+		{"oauth2", "clientId", "configMap"},
+		{"tlsConfig", "ca", "configMap"},
+		{"tlsConfig", "cert", "configMap"},
+	}
+	// endpointFilePaths are the fields of an endpoint that read files from the Prometheus pod
+	endpointFilePaths = [][]string{
+		{"bearerTokenFile"},
+		{"tlsConfig", "caFile"},
+		{"tlsConfig", "certFile"},
+		{"tlsConfig", "keyFile"},
+	}
+)
+
+// reservedTargetLabels are the labels that define where and how a target is scraped
+var reservedTargetLabels = []string{"__address__", "__scheme__"}
+
+func (s *prometheusOperatorSyncer) translate(ctx *synccontext.SyncContext, vObj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	pObj := translate.HostMetadata(vObj, s.VirtualToHost(ctx, types.NamespacedName{Name: vObj.GetName(), Namespace: vObj.GetNamespace()}, vObj))
+	pObj.SetLabels(withEnforcedLabels(pObj.GetLabels(), s.enforcedLabels))
+
+	err := translateSpec(ctx, pObj, vObj)
+	if err != nil {
+		return nil, err
+	}
+
+	return pObj, nil
+}
+
+// translateSpec sets the spec of the host object to the spec of the virtual object. The selectors of monitors are
+// rewritten to only select the services or pods of the virtual namespaces the monitor selects within the vCluster,
+// their endpoints may only reference secrets and config maps of the vCluster. Specs that would let Prometheus scrape
+// or label targets outside of the vCluster are rejected.
+func translateSpec(ctx *synccontext.SyncContext, pObj, vObj *unstructured.Unstructured) error {
+	spec, _, err := unstructured.NestedMap(vObj.Object, "spec")
+	if err != nil {
+		return fmt.Errorf("get spec: %w", err)
+	}
+
+	switch vObj.GroupVersionKind() {
+	case ServiceMonitors, PodMonitors:
+		spec, err = translateMonitorSpec(ctx, spec, endpointFields[vObj.GroupVersionKind()], vObj.GetNamespace(), pObj.GetNamespace())
+	case PrometheusRules:
+		err = validateRuleSpec(spec)
+	}
+	if err != nil {
+		return fmt.Errorf("translate %s %s/%s: %w", vObj.GetKind(), vObj.GetNamespace(), vObj.GetName(), err)
+	}
+
+	if spec == nil {
+		unstructured.RemoveNestedField(pObj.Object, "spec")
+		return nil
+	}
+
+	return unstructured.SetNestedMap(pObj.Object, spec, "spec")
+}
+
+func translateMonitorSpec(ctx *synccontext.SyncContext, spec map[string]interface{}, endpointsField, vNamespace, pNamespace string) (map[string]interface{}, error) {
+	if spec == nil {
+		spec = map[string]interface{}{}
+	}
+
+	// the selected services or pods are synced with translated labels
+	selector := &metav1.LabelSelector{}
+	vSelector, ok, err := unstructured.NestedMap(spec, "selector")
+	if err != nil {
+		return nil, fmt.Errorf("get selector: %w", err)
+	} else if ok {
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(vSelector, selector)
+		if err != nil {
+			return nil, fmt.Errorf("convert selector: %w", err)
+		}
+	}
+
+	// the monitor only selects objects of this vCluster and of the virtual namespaces it would select within the vCluster
+	pSelector := translate.MergeLabelSelectors(translate.HostLabelSelector(selector), &metav1.LabelSelector{
+		MatchLabels: map[string]string{translate.MarkerLabel: translate.VClusterName},
+	})
+	vNamespaces, err := selectedNamespaces(spec, vNamespace)
+	if err != nil {
+		return nil, err
+	} else if vNamespaces != nil {
+		pSelector.MatchExpressions = append(pSelector.MatchExpressions, metav1.LabelSelectorRequirement{
+			Key:      translate.NamespaceLabel,
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   vNamespaces,
+		})
+	}
+	pSelectorMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pSelector)
+	if err != nil {
+		return nil, fmt.Errorf("convert selector: %w", err)
+	}
+	spec["selector"] = pSelectorMap
+	spec["namespaceSelector"] = map[string]interface{}{
+		"matchNames": []interface{}{pNamespace},
+	}
+
+	// label names that are read from the selected services or pods
+	err = translateLabelName(spec, "jobLabel")
+	if err != nil {
+		return nil, err
+	}
+	err = translateLabelNames(spec, "targetLabels")
+	if err != nil {
+		return nil, err
+	}
+	err = translateLabelNames(spec, "podTargetLabels")
+	if err != nil {
+		return nil, err
+	}
+
+	err = translateEndpoints(ctx, spec, endpointsField, vNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+// translateEndpoints rewrites the secret and config map references of the endpoints to the synced host objects. Without
+// the rewrite, the references would resolve against any secret in the host namespace.
+func translateEndpoints(ctx *synccontext.SyncContext, spec map[string]interface{}, field, vNamespace string) error {
+	endpoints, ok, err := unstructured.NestedSlice(spec, field)
+	if err != nil {
+		return fmt.Errorf("get %s: %w", field, err)
+	} else if !ok {
+		return nil
+	}
+
+	for i, rawEndpoint := range endpoints {
+		endpoint, ok := rawEndpoint.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s[%d] is not an object", field, i)
+		}
+
+		for _, filePath := range endpointFilePaths {
+			if _, ok, _ := unstructured.NestedFieldNoCopy(endpoint, filePath...); ok {
+				return fmt.Errorf("%s[%d].%s is not allowed, files of the Prometheus pod cannot be referenced, use a secret instead", field, i, strings.Join(filePath, "."))
+			}
+		}
+		for _, relabelingsField := range []string{"relabelings", "metricRelabelings"} {
+			err = validateRelabelings(endpoint, relabelingsField)
+			if err != nil {
+				return fmt.Errorf("%s[%d]: %w", field, i, err)
+			}
+		}
+
+		for _, refPath := range endpointSecretPaths {
+			err = translateReference(ctx, endpoint, refPath, vNamespace, mappings.Secrets())
+			if err != nil {
+				return fmt.Errorf("%s[%d]: %w", field, i, err)
+			}
+		}
+		for _, refPath := range endpointConfigMapPaths {
+			err = translateReference(ctx, endpoint, refPath, vNamespace, mappings.ConfigMaps())
+			if err != nil {
+				return fmt.Errorf("%s[%d]: %w", field, i, err)
+			}
+		}
+	}
+
+	spec[field] = endpoints
+	return nil
+}
+
+func translateReference(ctx *synccontext.SyncContext, endpoint map[string]interface{}, refPath []string, vNamespace string, gvk schema.GroupVersionKind) error {
+	name, ok, err := unstructured.NestedString(endpoint, append(refPath, "name")...)
+	if err != nil {
+		return fmt.Errorf("get %s: %w", strings.Join(refPath, "."), err)
+	} else if !ok || name == "" {
+		return nil
+	}
+
+	return unstructured.SetNestedField(endpoint, mappings.VirtualToHostName(ctx, name, vNamespace, gvk), append(refPath, "name")...)
+}
+
+// validateRelabelings rejects relabelings that set the address or scheme of the scraped target, as the monitor could
+// scrape any endpoint reachable from Prometheus otherwise
+func validateRelabelings(endpoint map[string]interface{}, field string) error {
+	relabelings, _, err := unstructured.NestedSlice(endpoint, field)
+	if err != nil {
+		return fmt.Errorf("get %s: %w", field, err)
+	}
+
+	for i, rawRelabeling := range relabelings {
+		relabeling, ok := rawRelabeling.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s[%d] is not an object", field, i)
+		}
+
+		targetLabel, _, _ := unstructured.NestedString(relabeling, "targetLabel")
+		if slices.Contains(reservedTargetLabels, targetLabel) {
+			return fmt.Errorf("%s[%d] sets %s, which is not allowed", field, i, targetLabel)
+		}
+
+		// labelmap copies label names with the replacement as new name, so it could produce the reserved labels as well
+		action, _, _ := unstructured.NestedString(relabeling, "action")
+		replacement, _, _ := unstructured.NestedString(relabeling, "replacement")
+		if strings.EqualFold(action, "labelmap") && strings.HasPrefix(replacement, "_") {
+			return fmt.Errorf("%s[%d] maps labels to reserved label names, which is not allowed", field, i)
+		}
+	}
+
+	return nil
+}
+
+// validateRuleSpec rejects rules and rule groups that set reserved labels, as the labels of alerts and recorded series
+// starting with __ are reserved for Prometheus internally
+func validateRuleSpec(spec map[string]interface{}) error {
+	groups, _, err := unstructured.NestedSlice(spec, "groups")
+	if err != nil {
+		return fmt.Errorf("get groups: %w", err)
+	}
+
+	for i, rawGroup := range groups {
+		group, ok := rawGroup.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("groups[%d] is not an object", i)
+		}
+
+		err = validateRuleLabels(group, fmt.Sprintf("groups[%d]", i))
+		if err != nil {
+			return err
+		}
+
+		rules, _, err := unstructured.NestedSlice(group, "rules")
+		if err != nil {
+			return fmt.Errorf("get groups[%d].rules: %w", i, err)
+		}
+		for j, rawRule := range rules {
+			rule, ok := rawRule.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("groups[%d].rules[%d] is not an object", i, j)
+			}
+
+			err = validateRuleLabels(rule, fmt.Sprintf("groups[%d].rules[%d]", i, j))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateRuleLabels(obj map[string]interface{}, path string) error {
+	labels, _, err := unstructured.NestedStringMap(obj, "labels")
+	if err != nil {
+		return fmt.Errorf("get %s.labels: %w", path, err)
+	}
+
+	for label := range labels {
+		if strings.HasPrefix(label, "__") {
+			return fmt.Errorf("%s.labels sets %s, which is not allowed", path, label)
+		}
+	}
+
+	return nil
+}
+
+// selectedNamespaces returns the virtual namespaces selected by the namespaceSelector of a monitor or nil if all
+// namespaces are selected. Without a namespaceSelector, monitors only select objects in their own namespace.
+func selectedNamespaces(spec map[string]interface{}, vNamespace string) ([]string, error) {
+	anyNamespace, _, err := unstructured.NestedBool(spec, "namespaceSelector", "any")
+	if err != nil {
+		return nil, fmt.Errorf("get namespaceSelector.any: %w", err)
+	} else if anyNamespace {
+		return nil, nil
+	}
+
+	matchNames, _, err := unstructured.NestedStringSlice(spec, "namespaceSelector", "matchNames")
+	if err != nil {
+		return nil, fmt.Errorf("get namespaceSelector.matchNames: %w", err)
+	} else if len(matchNames) > 0 {
+		return matchNames, nil
+	}
+
+	return []string{vNamespace}, nil
+}
+
+func translateLabelName(spec map[string]interface{}, field string) error {
+	label, ok, err := unstructured.NestedString(spec, field)
+	if err != nil {
+		return fmt.Errorf("get %s: %w", field, err)
+	} else if !ok || label == "" {
+		return nil
+	}
+
+	spec[field] = translate.HostLabel(label)
+	return nil
+}
+
+func translateLabelNames(spec map[string]interface{}, field string) error {
+	labels, ok, err := unstructured.NestedStringSlice(spec, field)
+	if err != nil {
+		return fmt.Errorf("get %s: %w", field, err)
+	} else if !ok {
+		return nil
+	}
+
+	pLabels := make([]interface{}, 0, len(labels))
+	for _, label := range labels {
+		pLabels = append(pLabels, translate.HostLabel(label))
+	}
+	spec[field] = pLabels
+	return nil
+}
+
+// withEnforcedLabels adds the enforced labels to the labels of a host object, overwriting labels set within the vCluster
+func withEnforcedLabels(labels, enforcedLabels map[string]string) map[string]string {
+	if len(enforcedLabels) == 0 {
+		return labels
+	}
+
+	newLabels := maps.Clone(labels)
+	if newLabels == nil {
+		newLabels = map[string]string{}
+	}
+	maps.Copy(newLabels, enforcedLabels)
+	return newLabels
+}
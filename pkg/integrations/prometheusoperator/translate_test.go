@@ -0,0 +1,218 @@
+package prometheusoperator
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/scheme"
+	syncertesting "github.com/loft-sh/vcluster/pkg/syncer/testing"
+	testingutil "github.com/loft-sh/vcluster/pkg/util/testing"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestTranslateSpec(t *testing.T) {
+	testCases := []struct {
+		name string
+		gvk  schema.GroupVersionKind
+		spec map[string]interface{}
+
+		expectedSpec map[string]interface{}
+		expectedErr  string
+	}{
+		{
+			name: "service monitor in own namespace",
+			gvk:  ServiceMonitors,
+			spec: map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{"app": "web"},
+				},
+				"endpoints": []interface{}{map[string]interface{}{"port": "metrics"}},
+				"jobLabel":  "app",
+			},
+			expectedSpec: map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{"app": "web", translate.MarkerLabel: translate.VClusterName},
+					"matchExpressions": []interface{}{map[string]interface{}{
+						"key":      translate.NamespaceLabel,
+						"operator": "In",
+						"values":   []interface{}{"test"},
+					}},
+				},
+				"namespaceSelector": map[string]interface{}{"matchNames": []interface{}{"vcluster"}},
+				"endpoints":         []interface{}{map[string]interface{}{"port": "metrics"}},
+				"jobLabel":          "app",
+			},
+		},
+		{
+			name: "pod monitor with namespace selector",
+			gvk:  PodMonitors,
+			spec: map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchExpressions": []interface{}{map[string]interface{}{
+						"key":      "app",
+						"operator": "Exists",
+					}},
+				},
+				"namespaceSelector": map[string]interface{}{"matchNames": []interface{}{"a", "b"}},
+				"podTargetLabels":   []interface{}{"team"},
+			},
+			expectedSpec: map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{translate.MarkerLabel: translate.VClusterName},
+					"matchExpressions": []interface{}{
+						map[string]interface{}{
+							"key":      "app",
+							"operator": "Exists",
+						},
+						map[string]interface{}{
+							"key":      translate.NamespaceLabel,
+							"operator": "In",
+							"values":   []interface{}{"a", "b"},
+						},
+					},
+				},
+				"namespaceSelector": map[string]interface{}{"matchNames": []interface{}{"vcluster"}},
+				"podTargetLabels":   []interface{}{"team"},
+			},
+		},
+		{
+			name: "service monitor selecting any namespace",
+			gvk:  ServiceMonitors,
+			spec: map[string]interface{}{
+				"selector":          map[string]interface{}{},
+				"namespaceSelector": map[string]interface{}{"any": true},
+			},
+			expectedSpec: map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{translate.MarkerLabel: translate.VClusterName},
+				},
+				"namespaceSelector": map[string]interface{}{"matchNames": []interface{}{"vcluster"}},
+			},
+		},
+		{
+			name: "service monitor endpoint secrets are translated",
+			gvk:  ServiceMonitors,
+			spec: map[string]interface{}{
+				"selector": map[string]interface{}{},
+				"endpoints": []interface{}{map[string]interface{}{
+					"port": "metrics",
+					"basicAuth": map[string]interface{}{
+						"username": map[string]interface{}{"name": "auth", "key": "user"},
+						"password": map[string]interface{}{"name": "auth", "key": "password"},
+					},
+					"tlsConfig": map[string]interface{}{
+						"ca":        map[string]interface{}{"configMap": map[string]interface{}{"name": "ca", "key": "ca.crt"}},
+						"keySecret": map[string]interface{}{"name": "tls", "key": "tls.key"},
+					},
+					"relabelings": []interface{}{map[string]interface{}{"targetLabel": "team", "replacement": "a"}},
+				}},
+			},
+			expectedSpec: map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{translate.MarkerLabel: translate.VClusterName},
+					"matchExpressions": []interface{}{map[string]interface{}{
+						"key":      translate.NamespaceLabel,
+						"operator": "In",
+						"values":   []interface{}{"test"},
+					}},
+				},
+				"namespaceSelector": map[string]interface{}{"matchNames": []interface{}{"vcluster"}},
+				"endpoints": []interface{}{map[string]interface{}{
+					"port": "metrics",
+					"basicAuth": map[string]interface{}{
+						"username": map[string]interface{}{"name": translate.SingleNamespaceHostName("auth", "test", translate.VClusterName), "key": "user"},
+						"password": map[string]interface{}{"name": translate.SingleNamespaceHostName("auth", "test", translate.VClusterName), "key": "password"},
+					},
+					"tlsConfig": map[string]interface{}{
+						"ca":        map[string]interface{}{"configMap": map[string]interface{}{"name": translate.SingleNamespaceHostName("ca", "test", translate.VClusterName), "key": "ca.crt"}},
+						"keySecret": map[string]interface{}{"name": translate.SingleNamespaceHostName("tls", "test", translate.VClusterName), "key": "tls.key"},
+					},
+					"relabelings": []interface{}{map[string]interface{}{"targetLabel": "team", "replacement": "a"}},
+				}},
+			},
+		},
+		{
+			name: "pod monitor relabeling the address",
+			gvk:  PodMonitors,
+			spec: map[string]interface{}{
+				"podMetricsEndpoints": []interface{}{map[string]interface{}{
+					"relabelings": []interface{}{map[string]interface{}{"targetLabel": "__address__", "replacement": "10.0.0.1:9090"}},
+				}},
+			},
+			expectedErr: "podMetricsEndpoints[0]: relabelings[0] sets __address__, which is not allowed",
+		},
+		{
+			name: "service monitor mapping labels to reserved labels",
+			gvk:  ServiceMonitors,
+			spec: map[string]interface{}{
+				"endpoints": []interface{}{map[string]interface{}{
+					"metricRelabelings": []interface{}{map[string]interface{}{"action": "labelmap", "regex": "(.+)", "replacement": "__${1}"}},
+				}},
+			},
+			expectedErr: "endpoints[0]: metricRelabelings[0] maps labels to reserved label names",
+		},
+		{
+			name: "service monitor reading a file of the prometheus pod",
+			gvk:  ServiceMonitors,
+			spec: map[string]interface{}{
+				"endpoints": []interface{}{map[string]interface{}{
+					"bearerTokenFile": "/var/run/secrets/kubernetes.io/serviceaccount/token",
+				}},
+			},
+			expectedErr: "endpoints[0].bearerTokenFile is not allowed",
+		},
+		{
+			name: "prometheus rule setting a reserved label",
+			gvk:  PrometheusRules,
+			spec: map[string]interface{}{
+				"groups": []interface{}{map[string]interface{}{
+					"name":  "test",
+					"rules": []interface{}{map[string]interface{}{"record": "up:sum", "labels": map[string]interface{}{"__scheme__": "https"}}},
+				}},
+			},
+			expectedErr: "groups[0].rules[0].labels sets __scheme__, which is not allowed",
+		},
+		{
+			name: "prometheus rule is copied",
+			gvk:  PrometheusRules,
+			spec: map[string]interface{}{
+				"groups": []interface{}{map[string]interface{}{"name": "test"}},
+			},
+			expectedSpec: map[string]interface{}{
+				"groups": []interface{}{map[string]interface{}{"name": "test"}},
+			},
+		},
+	}
+
+	registerCtx := syncertesting.NewFakeRegisterContext(testingutil.NewFakeConfig(), testingutil.NewFakeClient(scheme.Scheme), testingutil.NewFakeClient(scheme.Scheme))
+	ctx := registerCtx.ToSyncContext("prometheus-operator")
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			vObj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": testCase.spec}}
+			vObj.SetGroupVersionKind(testCase.gvk)
+			vObj.SetNamespace("test")
+			pObj := &unstructured.Unstructured{}
+			pObj.SetGroupVersionKind(testCase.gvk)
+			pObj.SetNamespace("vcluster")
+
+			err := translateSpec(ctx, pObj, vObj)
+			if testCase.expectedErr != "" {
+				assert.ErrorContains(t, err, testCase.expectedErr)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.DeepEqual(t, pObj.Object["spec"], testCase.expectedSpec)
+		})
+	}
+}
+
+func TestWithEnforcedLabels(t *testing.T) {
+	labels := map[string]string{"team": "a", "prometheus": "tenant"}
+	assert.DeepEqual(t, withEnforcedLabels(labels, map[string]string{"prometheus": "vcluster"}), map[string]string{"team": "a", "prometheus": "vcluster"})
+	assert.DeepEqual(t, labels, map[string]string{"team": "a", "prometheus": "tenant"})
+	assert.DeepEqual(t, withEnforcedLabels(nil, map[string]string{"prometheus": "vcluster"}), map[string]string{"prometheus": "vcluster"})
+	assert.Assert(t, withEnforcedLabels(nil, nil) == nil)
+}
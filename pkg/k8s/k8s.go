@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -318,7 +319,7 @@ func StartBackingStore(ctx context.Context, vConfig *config.VirtualClusterConfig
 			CaCert:     vConfig.ControlPlane.BackingStore.Database.Embedded.CaFile,
 			ServerKey:  vConfig.ControlPlane.BackingStore.Database.Embedded.KeyFile,
 			ServerCert: vConfig.ControlPlane.BackingStore.Database.Embedded.CertFile,
-		}, vConfig.ControlPlane.BackingStore.Database.Embedded.ExtraArgs)
+		}, command.MergeArgs(kineCompactionArgs(vConfig.ControlPlane.BackingStore.Database.Maintenance), vConfig.ControlPlane.BackingStore.Database.Embedded.ExtraArgs))
 
 		etcdEndpoints = constants.K8sKineEndpoint
 	} else if vConfig.ControlPlane.BackingStore.Database.External.Enabled {
@@ -472,3 +473,16 @@ func waitForAPI(ctx context.Context, kubeConfig string) error {
 
 	return nil
 }
+
+// kineCompactionArgs returns the flags that configure the compaction kine runs on the embedded database
+func kineCompactionArgs(maintenance vclusterconfig.DatabaseMaintenance) []string {
+	args := []string{}
+	if maintenance.CompactInterval != "" {
+		args = append(args, "--compact-interval="+maintenance.CompactInterval)
+	}
+	if maintenance.RetainRevisions > 0 {
+		args = append(args, "--compact-min-retain="+strconv.FormatInt(maintenance.RetainRevisions, 10))
+	}
+
+	return args
+}
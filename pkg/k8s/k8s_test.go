@@ -174,3 +174,10 @@ func hasArg(args []string, expected string) bool {
 
 	return false
 }
+
+func TestKineCompactionArgs(t *testing.T) {
+	args := command.MergeArgs(kineCompactionArgs(vclusterconfig.DatabaseMaintenance{CompactInterval: "5m", RetainRevisions: 1000}), []string{"--compact-interval=10m"})
+	assert.DeepEqual(t, args, []string{"--compact-min-retain=1000", "--compact-interval=10m"})
+
+	assert.DeepEqual(t, kineCompactionArgs(vclusterconfig.DatabaseMaintenance{}), []string{})
+}
@@ -3,6 +3,7 @@ package resources
 import (
 	"fmt"
 
+	"github.com/loft-sh/vcluster/config"
 	"github.com/loft-sh/vcluster/pkg/mappings"
 	"github.com/loft-sh/vcluster/pkg/mappings/generic"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
@@ -22,7 +23,9 @@ func CreateConfigMapsMapper(ctx *synccontext.RegisterContext) (synccontext.Mappe
 	}
 
 	return generic.WithRecorder(&configMapsMapper{
-		Mapper: mapper,
+		Mapper: WithToHostMappings(mapper, func(cfg *config.Config) config.ToHostMappings {
+			return cfg.Sync.ToHost.ConfigMaps.Mappings
+		}),
 	}), nil
 }
 
@@ -2,27 +2,34 @@ package resources
 
 import (
 	"fmt"
-	"strings"
 
+	"github.com/loft-sh/vcluster/config"
 	podtranslate "github.com/loft-sh/vcluster/pkg/controllers/resources/pods/token"
 	"github.com/loft-sh/vcluster/pkg/mappings"
 	"github.com/loft-sh/vcluster/pkg/mappings/generic"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/ingressannotations"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func CreateSecretsMapper(ctx *synccontext.RegisterContext) (synccontext.Mapper, error) {
-	mapper, err := generic.NewMapper(ctx, &corev1.Secret{}, translate.Default.HostName)
+	mapper, err := generic.NewMapperWithoutRecorder(ctx, &corev1.Secret{}, func(ctx *synccontext.SyncContext, vName, vNamespace string, _ client.Object) types.NamespacedName {
+		return translate.Default.HostName(ctx, vName, vNamespace)
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	return &secretsMapper{
-		Mapper: mapper,
+		Mapper: generic.WithRecorder(WithToHostMappings(mapper, func(cfg *config.Config) config.ToHostMappings {
+			return cfg.Sync.ToHost.Secrets.Mappings
+		})),
 	}, nil
 }
 
@@ -109,40 +116,69 @@ func (s *secretsMapper) Migrate(ctx *synccontext.RegisterContext, mapper synccon
 	return s.Mapper.Migrate(ctx, mapper)
 }
 
-var TranslateAnnotations = map[string]bool{
-	"nginx.ingress.kubernetes.io/auth-secret":      true,
-	"nginx.ingress.kubernetes.io/auth-tls-secret":  true,
-	"nginx.ingress.kubernetes.io/proxy-ssl-secret": true,
+// IsTranslatedIngressAnnotation returns true if the ingress annotation references other objects and is rewritten when
+// syncing to the host cluster
+func IsTranslatedIngressAnnotation(ctx *synccontext.SyncContext, key string) bool {
+	_, ok := ingressAnnotationRules(ctx)[key]
+	return ok
+}
+
+func ingressAnnotationRules(ctx *synccontext.SyncContext) map[string]config.IngressAnnotationRule {
+	if ctx == nil || ctx.Config == nil {
+		return ingressannotations.Rules(config.IngressAnnotationTranslation{Presets: ingressannotations.DefaultPresets})
+	}
+
+	return ingressannotations.Rules(ctx.Config.Sync.ToHost.Ingresses.AnnotationTranslation)
 }
 
 func TranslateIngressAnnotations(ctx *synccontext.SyncContext, annotations map[string]string, ingressNamespace string) (map[string]string, []types.NamespacedName) {
+	rules := ingressAnnotationRules(ctx)
 	foundSecrets := []types.NamespacedName{}
 	newAnnotations := map[string]string{}
 	for k, v := range annotations {
-		if !TranslateAnnotations[k] {
+		rule, ok := rules[k]
+		if !ok {
 			newAnnotations[k] = v
 			continue
 		}
 
-		splitted := strings.Split(annotations[k], "/")
-		if len(splitted) == 1 { // If value is only "secret"
-			secret := splitted[0]
-			foundSecrets = append(foundSecrets, types.NamespacedName{Namespace: ingressNamespace, Name: secret})
-			newAnnotations[k] = mappings.VirtualToHostName(ctx, secret, ingressNamespace, mappings.Secrets())
-		} else if len(splitted) == 2 { // If value is "namespace/secret"
-			namespace := splitted[0]
-			secret := splitted[1]
-			foundSecrets = append(foundSecrets, types.NamespacedName{Namespace: namespace, Name: secret})
-			pName := mappings.VirtualToHost(ctx, secret, namespace, mappings.Secrets())
-			newAnnotations[k] = pName.Namespace + "/" + pName.Name
-		} else {
-			newAnnotations[k] = v
+		newValue, err := ingressannotations.Translate(rule, v, ingressNamespace, func(refType string, ref types.NamespacedName) types.NamespacedName {
+			if refType == ingressannotations.TypeService {
+				return mappings.VirtualToHost(ctx, ref.Name, ref.Namespace, mappings.Services())
+			} else if refType == ingressannotations.TypeTraefikMiddleware {
+				return traefikMiddlewareToHost(ctx, ref)
+			}
+
+			foundSecrets = append(foundSecrets, ref)
+			return mappings.VirtualToHost(ctx, ref.Name, ref.Namespace, mappings.Secrets())
+		})
+		if err != nil {
+			klog.FromContext(ctx).Error(err, "translate ingress annotation", "annotation", k)
 		}
+		newAnnotations[k] = newValue
 	}
 
 	return newAnnotations, foundSecrets
 }
 
+// traefikMiddlewareGVKs are the kinds of Traefik middlewares, of the current and the deprecated api group
+var traefikMiddlewareGVKs = []schema.GroupVersionKind{
+	{Group: "traefik.io", Version: "v1alpha1", Kind: "Middleware"},
+	{Group: "traefik.containo.us", Version: "v1alpha1", Kind: "Middleware"},
+}
+
+// traefikMiddlewareToHost translates the middleware reference if middlewares are synced to the host cluster, otherwise
+// the reference is kept
+func traefikMiddlewareToHost(ctx *synccontext.SyncContext, ref types.NamespacedName) types.NamespacedName {
+	for _, gvk := range traefikMiddlewareGVKs {
+		if ctx.Mappings != nil && ctx.Mappings.Has(gvk) {
+			return mappings.VirtualToHost(ctx, ref.Name, ref.Namespace, gvk)
+		}
+	}
+
+	return ref
+}
+
 func secretNamesFromIngress(ctx *synccontext.SyncContext, ingress *networkingv1.Ingress) []types.NamespacedName {
 	secrets := []types.NamespacedName{}
 	_, extraSecrets := TranslateIngressAnnotations(ctx, ingress.Annotations, ingress.Namespace)
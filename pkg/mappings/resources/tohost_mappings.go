@@ -0,0 +1,149 @@
+package resources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ToHostMappingsFunc returns the sync.toHost mappings of a resource
+type ToHostMappingsFunc func(cfg *config.Config) config.ToHostMappings
+
+// WithToHostMappings translates the virtual objects listed in the sync.toHost mappings to their fixed host namespace and
+// name. It needs to be wrapped by a recorder, so the mappings store tracks the host objects for cleanup.
+func WithToHostMappings(mapper synccontext.Mapper, mappings ToHostMappingsFunc) synccontext.Mapper {
+	return &toHostMappingsMapper{
+		Mapper:   mapper,
+		mappings: mappings,
+	}
+}
+
+type toHostMappingsMapper struct {
+	synccontext.Mapper
+
+	mappings ToHostMappingsFunc
+}
+
+func (m *toHostMappingsMapper) VirtualToHost(ctx *synccontext.SyncContext, req types.NamespacedName, vObj client.Object) types.NamespacedName {
+	if ctx != nil && ctx.Config != nil {
+		namespace, name, ok := m.mappings(&ctx.Config.Config).HostName(req.Namespace, req.Name)
+		if ok {
+			return types.NamespacedName{Namespace: namespace, Name: name}
+		}
+	}
+
+	return m.Mapper.VirtualToHost(ctx, req, vObj)
+}
+
+func (m *toHostMappingsMapper) HostToVirtual(ctx *synccontext.SyncContext, req types.NamespacedName, pObj client.Object) types.NamespacedName {
+	if ctx != nil && ctx.Config != nil {
+		namespace, name, ok := m.mappings(&ctx.Config.Config).VirtualName(req.Namespace, req.Name)
+		if ok {
+			return types.NamespacedName{Namespace: namespace, Name: name}
+		}
+	}
+
+	return m.Mapper.HostToVirtual(ctx, req, pObj)
+}
+
+func (m *toHostMappingsMapper) IsManaged(ctx *synccontext.SyncContext, pObj client.Object) (bool, error) {
+	if ctx != nil && ctx.Config != nil {
+		namespace, name, ok := m.mappings(&ctx.Config.Config).VirtualName(pObj.GetNamespace(), pObj.GetName())
+		if ok {
+			return ownsToHostMappingTarget(pObj, namespace, name), nil
+		}
+	}
+
+	return m.Mapper.IsManaged(ctx, pObj)
+}
+
+// CheckToHostMappingTarget returns an error if the host object of a mapped virtual object exists, but wasn't created
+// by this virtual cluster for it. Host objects in shared namespaces are never overwritten. pObj can be nil, then the
+// host object is retrieved.
+func CheckToHostMappingTarget(ctx *synccontext.SyncContext, vObj, pObj client.Object, hostName types.NamespacedName) error {
+	if pObj == nil {
+		existing := vObj.DeepCopyObject().(client.Object)
+		err := ctx.HostClient.Get(ctx, hostName, existing)
+		if kerrors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("get host object %s: %w", hostName.String(), err)
+		}
+
+		pObj = existing
+	}
+
+	if !ownsToHostMappingTarget(pObj, vObj.GetNamespace(), vObj.GetName()) {
+		return fmt.Errorf("host object %s already exists and is not managed by this virtual cluster", hostName.String())
+	}
+
+	return nil
+}
+
+// PrepareToHostMappingTarget marks a new host object of a mapped virtual object as owned by this virtual cluster. The
+// object is also owned by the role the chart creates for the mapping, so it is garbage collected together with the
+// role once the mapping is removed and the virtual cluster has no access to it anymore.
+func PrepareToHostMappingTarget(ctx *synccontext.SyncContext, resource string, pObj client.Object) error {
+	labels := pObj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[translate.MarkerLabel] = ToHostMappingMarker()
+	pObj.SetLabels(labels)
+
+	// the role doesn't exist if rbac is managed outside of the chart, then the object stays until its virtual object
+	// is deleted
+	roleName := ToHostMappingRoleName(ctx.Config.Name, ctx.Config.HostNamespace, resource, pObj.GetNamespace(), pObj.GetName())
+	role, err := ctx.Config.HostClient.RbacV1().Roles(pObj.GetNamespace()).Get(ctx, roleName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) || kerrors.IsForbidden(err) {
+		pObj.SetOwnerReferences(nil)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("get role %s/%s: %w", pObj.GetNamespace(), roleName, err)
+	}
+
+	pObj.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: rbacv1.SchemeGroupVersion.String(),
+		Kind:       "Role",
+		Name:       role.Name,
+		UID:        role.UID,
+	}})
+	return nil
+}
+
+// ToHostMappingMarker returns the marker label value of host objects synced by sync.toHost mappings. It contains the
+// host namespace of the virtual cluster, so virtual clusters with the same name in different namespaces don't collide
+// in shared host namespaces.
+func ToHostMappingMarker() string {
+	return translate.Default.MarkerLabelCluster()
+}
+
+// ToHostMappingRoleName returns the name of the role the chart creates for the host object of a sync.toHost mapping,
+// this needs to match vcluster.rbac.toHostMappings in the chart
+func ToHostMappingRoleName(vClusterName, vClusterNamespace, resource, hostNamespace, hostName string) string {
+	digest := sha256.Sum256([]byte(strings.Join([]string{vClusterNamespace, vClusterName, resource, hostNamespace, hostName}, "/")))
+	name := vClusterName
+	if len(name) > 40 {
+		name = name[:40]
+	}
+
+	return "vc-tohost-" + strings.TrimSuffix(name, "-") + "-" + hex.EncodeToString(digest[:])[:10]
+}
+
+// ownsToHostMappingTarget checks if the host object was created by this virtual cluster for the given virtual object,
+// everything else in shared host namespaces is left alone
+func ownsToHostMappingTarget(pObj client.Object, vNamespace, vName string) bool {
+	return pObj.GetLabels()[translate.MarkerLabel] == ToHostMappingMarker() &&
+		pObj.GetAnnotations()[translate.NamespaceAnnotation] == vNamespace &&
+		pObj.GetAnnotations()[translate.NameAnnotation] == vName
+}
@@ -0,0 +1,18 @@
+package resources
+
+import (
+	"testing"
+)
+
+func TestToHostMappingRoleName(t *testing.T) {
+	// needs to match the role names of vcluster.rbac.toHostMappings in the chart
+	for expected, vClusterName := range map[string]string{
+		"vc-tohost-my-vcluster-ef99bbc062":                              "my-vcluster",
+		"vc-tohost-a-very-long-virtual-cluster-name-that-is-78ef98ea0a": "a-very-long-virtual-cluster-name-that-is-truncated",
+	} {
+		roleName := ToHostMappingRoleName(vClusterName, "test", "secrets", "shared-registry", "team-a-registry")
+		if roleName != expected {
+			t.Fatalf("expected role name %q for %q, got %q", expected, vClusterName, roleName)
+		}
+	}
+}
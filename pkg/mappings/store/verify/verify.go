@@ -1,6 +1,7 @@
 package verify
 
 import (
+	"github.com/loft-sh/vcluster/config"
 	"github.com/loft-sh/vcluster/pkg/mappings/store"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
@@ -15,7 +16,7 @@ func NewVerifyMapping(ctx *synccontext.SyncContext) store.VerifyMapping {
 
 func CheckHostObject(ctx *synccontext.SyncContext, hostObject synccontext.Object) bool {
 	// we don't allow mappings that are not within targeted namespaces
-	if hostObject.Namespace != "" && !translate.Default.IsTargetedNamespace(ctx, hostObject.Namespace) {
+	if hostObject.Namespace != "" && !translate.Default.IsTargetedNamespace(ctx, hostObject.Namespace) && !isToHostMappingTarget(ctx, hostObject) {
 		return false
 	}
 
@@ -26,3 +27,25 @@ func CheckHostObject(ctx *synccontext.SyncContext, hostObject synccontext.Object
 
 	return true
 }
+
+// isToHostMappingTarget checks if the host object is the target of a sync.toHost mapping, these can be in any namespace.
+// Mappings of host objects that are not mapped anymore are dropped, the host objects are owned by the role of their
+// mapping and garbage collected together with it.
+func isToHostMappingTarget(ctx *synccontext.SyncContext, hostObject synccontext.Object) bool {
+	if ctx == nil || ctx.Config == nil {
+		return false
+	}
+
+	var mappings config.ToHostMappings
+	switch hostObject.GroupVersionKind {
+	case corev1.SchemeGroupVersion.WithKind("ConfigMap"):
+		mappings = ctx.Config.Sync.ToHost.ConfigMaps.Mappings
+	case corev1.SchemeGroupVersion.WithKind("Secret"):
+		mappings = ctx.Config.Sync.ToHost.Secrets.Mappings
+	default:
+		return false
+	}
+
+	_, _, ok := mappings.VirtualName(hostObject.Namespace, hostObject.Name)
+	return ok
+}
@@ -20,7 +20,9 @@ import (
 	"github.com/loft-sh/vcluster/pkg/config"
 	plugintypes "github.com/loft-sh/vcluster/pkg/plugin/types"
 	"github.com/loft-sh/vcluster/pkg/plugin/v2/pluginv2"
+	"github.com/loft-sh/vcluster/pkg/tracing"
 	"github.com/loft-sh/vcluster/pkg/util/kubeconfig"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/endpoints/request"
@@ -286,10 +288,20 @@ func (m *Manager) MutateObject(ctx context.Context, obj client.Object, hookType
 	return nil
 }
 
-func (m *Manager) mutateObject(ctx context.Context, versionKindType plugintypes.VersionKindType, obj []byte, plugin *vClusterPlugin) ([]byte, error) {
+func (m *Manager) mutateObject(ctx context.Context, versionKindType plugintypes.VersionKindType, obj []byte, plugin *vClusterPlugin) (_ []byte, retErr error) {
 	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
 	defer cancel()
 
+	ctx, span := tracing.StartSpan(ctx, "plugin.Mutate",
+		attribute.String("plugin", plugin.Path),
+		attribute.String("apiVersion", versionKindType.APIVersion),
+		attribute.String("kind", versionKindType.Kind),
+		attribute.String("type", versionKindType.Type),
+	)
+	defer func() {
+		tracing.EndSpan(span, retErr)
+	}()
+
 	klog.FromContext(ctx).V(1).Info("calling plugin to mutate object", "plugin", plugin.Path, "apiVersion", versionKindType.APIVersion, "kind", versionKindType.Kind)
 	mutateResult, err := plugin.GRPCClient.Mutate(ctx, &pluginv2.Mutate_Request{
 		ApiVersion: versionKindType.APIVersion,
@@ -718,6 +730,7 @@ func (m *Manager) loadPlugin(pluginPath string, vConfig *config.VirtualClusterCo
 		SyncStderr:       os.Stderr,
 		SkipHostEnv:      true,
 		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+		GRPCDialOptions:  tracing.GRPCDialOptions(),
 	})
 
 	// Connect via RPC
@@ -0,0 +1,159 @@
+package pullthrough
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NewFromConfig creates the pull-through cache for the embedded registry. Credentials secrets are read from the given namespace.
+func NewFromConfig(ctx context.Context, registry config.Registry, secretClient client.Client, namespace string) (*Cache, error) {
+	pullThrough := registry.PullThrough
+	options := Options{
+		CacheDir: filepath.Join(constants.DataDir, "registry", "pull-through"),
+	}
+	if pullThrough.MaxCacheSize != "" {
+		quantity, err := resource.ParseQuantity(pullThrough.MaxCacheSize)
+		if err != nil {
+			return nil, fmt.Errorf("parse max cache size: %w", err)
+		}
+
+		options.MaxCacheSize = quantity.Value()
+	}
+	if pullThrough.TagTTL != "" {
+		tagTTL, err := time.ParseDuration(pullThrough.TagTTL)
+		if err != nil {
+			return nil, fmt.Errorf("parse tag ttl: %w", err)
+		}
+
+		options.TagTTL = tagTTL
+	}
+
+	for _, u := range pullThrough.Upstreams {
+		upstreamURL, err := url.Parse(u.GetURL())
+		if err != nil {
+			return nil, fmt.Errorf("parse url of upstream %s: %w", u.Name, err)
+		}
+
+		nameOptions := []name.Option{}
+		if upstreamURL.Scheme == "http" {
+			nameOptions = append(nameOptions, name.Insecure)
+		}
+		upstreamRegistry, err := name.NewRegistry(upstreamURL.Host, nameOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("upstream %s: %w", u.Name, err)
+		}
+
+		upstream := Upstream{
+			Name:     u.Name,
+			Registry: upstreamRegistry,
+		}
+		if u.CredentialsSecret != "" {
+			if secretClient == nil {
+				return nil, fmt.Errorf("upstream %s: credentialsSecret is not supported without a host cluster", u.Name)
+			}
+
+			upstream.Auth = &secretAuthenticator{
+				ctx:      ctx,
+				client:   secretClient,
+				secret:   types.NamespacedName{Namespace: namespace, Name: u.CredentialsSecret},
+				registry: upstreamRegistry,
+			}
+		}
+
+		options.Upstreams = append(options.Upstreams, upstream)
+	}
+
+	return New(options)
+}
+
+// secretAuthenticator reads the upstream credentials from a secret every time a token is requested, so rotated credentials are picked up
+type secretAuthenticator struct {
+	ctx      context.Context
+	client   client.Client
+	secret   types.NamespacedName
+	registry name.Registry
+}
+
+type dockerConfigJSON struct {
+	Auths map[string]authn.AuthConfig `json:"auths"`
+}
+
+func (s *secretAuthenticator) Authorization() (*authn.AuthConfig, error) {
+	return s.AuthorizationContext(s.ctx)
+}
+
+func (s *secretAuthenticator) AuthorizationContext(ctx context.Context) (*authn.AuthConfig, error) {
+	secret := &corev1.Secret{}
+	err := s.client.Get(ctx, s.secret, secret)
+	if err != nil {
+		return nil, fmt.Errorf("get credentials secret %s: %w", s.secret.String(), err)
+	}
+
+	return authConfigFromSecret(secret, s.registry)
+}
+
+func authConfigFromSecret(secret *corev1.Secret, registry name.Registry) (*authn.AuthConfig, error) {
+	if len(secret.Data[corev1.BasicAuthUsernameKey]) > 0 {
+		return &authn.AuthConfig{
+			Username: string(secret.Data[corev1.BasicAuthUsernameKey]),
+			Password: string(secret.Data[corev1.BasicAuthPasswordKey]),
+		}, nil
+	}
+
+	raw, ok := secret.Data[corev1.DockerConfigJsonKey]
+	if !ok {
+		return nil, fmt.Errorf("credentials secret %s/%s has neither a %s nor a %s key", secret.Namespace, secret.Name, corev1.BasicAuthUsernameKey, corev1.DockerConfigJsonKey)
+	}
+
+	dockerConfig := &dockerConfigJSON{}
+	err := json.Unmarshal(raw, dockerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s of secret %s/%s: %w", corev1.DockerConfigJsonKey, secret.Namespace, secret.Name, err)
+	}
+
+	hosts := []string{registry.RegistryStr()}
+	if registry.RegistryStr() == name.DefaultRegistry || registry.RegistryStr() == "registry-1.docker.io" {
+		hosts = []string{authn.DefaultAuthKey, name.DefaultRegistry, "docker.io", "registry-1.docker.io"}
+	}
+	for key, authConfig := range dockerConfig.Auths {
+		host := strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://")
+		if key != authn.DefaultAuthKey {
+			host, _, _ = strings.Cut(host, "/")
+		}
+
+		for _, candidate := range hosts {
+			if host != candidate && key != candidate {
+				continue
+			}
+
+			if authConfig.Auth != "" && authConfig.Username == "" {
+				decoded, err := base64.StdEncoding.DecodeString(authConfig.Auth)
+				if err != nil {
+					return nil, fmt.Errorf("decode auth of %s: %w", key, err)
+				}
+
+				authConfig.Username, authConfig.Password, _ = strings.Cut(string(decoded), ":")
+				authConfig.Auth = ""
+			}
+
+			return &authConfig, nil
+		}
+	}
+
+	return nil, fmt.Errorf("credentials secret %s/%s has no credentials for %s", secret.Namespace, secret.Name, registry.RegistryStr())
+}
@@ -0,0 +1,74 @@
+package pullthrough
+
+import (
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestAuthConfigFromSecret(t *testing.T) {
+	dockerHub, err := name.NewRegistry("registry-1.docker.io")
+	assert.NilError(t, err)
+	ghcr, err := name.NewRegistry("ghcr.io")
+	assert.NilError(t, err)
+
+	dockerConfig := []byte(`{"auths":{"https://index.docker.io/v1/":{"auth":"aHViOnNlY3JldA=="},"ghcr.io":{"username":"gh","password":"token"}}}`)
+	testCases := []struct {
+		name             string
+		secret           *corev1.Secret
+		registry         name.Registry
+		expectedUsername string
+		expectedPassword string
+		expectedErr      string
+	}{
+		{
+			name:             "basic auth",
+			secret:           &corev1.Secret{Data: map[string][]byte{"username": []byte("user"), "password": []byte("pass")}},
+			registry:         ghcr,
+			expectedUsername: "user",
+			expectedPassword: "pass",
+		},
+		{
+			name:             "docker hub from docker config",
+			secret:           &corev1.Secret{Data: map[string][]byte{corev1.DockerConfigJsonKey: dockerConfig}},
+			registry:         dockerHub,
+			expectedUsername: "hub",
+			expectedPassword: "secret",
+		},
+		{
+			name:             "registry from docker config",
+			secret:           &corev1.Secret{Data: map[string][]byte{corev1.DockerConfigJsonKey: dockerConfig}},
+			registry:         ghcr,
+			expectedUsername: "gh",
+			expectedPassword: "token",
+		},
+		{
+			name:        "missing registry",
+			secret:      &corev1.Secret{Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{}}`)}},
+			registry:    ghcr,
+			expectedErr: "has no credentials for ghcr.io",
+		},
+		{
+			name:        "missing keys",
+			secret:      &corev1.Secret{Data: map[string][]byte{"token": []byte("abc")}},
+			registry:    ghcr,
+			expectedErr: "has neither a username nor a .dockerconfigjson key",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			authConfig, err := authConfigFromSecret(tc.secret, tc.registry)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, authConfig.Username, tc.expectedUsername)
+			assert.Equal(t, authConfig.Password, tc.expectedPassword)
+		})
+	}
+}
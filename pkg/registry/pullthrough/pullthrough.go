@@ -0,0 +1,543 @@
+package pullthrough
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"golang.org/x/sync/singleflight"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultTagTTL is how long resolved tags are cached if no ttl is configured
+	DefaultTagTTL = 5 * time.Minute
+
+	// DefaultDownloadTimeout is how long a blob download from an upstream may take if no timeout is configured
+	DefaultDownloadTimeout = 30 * time.Minute
+)
+
+var (
+	// repositoryComponentRegexp and tagRegexp follow the distribution reference grammar, which also keeps them safe to use as file paths
+	repositoryComponentRegexp = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*$`)
+	tagRegexp                 = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+)
+
+// Upstream is a registry that is proxied by the cache
+type Upstream struct {
+	// Name is the path prefix the upstream is served under
+	Name string
+
+	// Registry is the upstream registry
+	Registry name.Registry
+
+	// Auth is used to authenticate against the upstream
+	Auth authn.Authenticator
+}
+
+type Options struct {
+	Upstreams []Upstream
+
+	// CacheDir is the directory manifests and blobs are stored in
+	CacheDir string
+
+	// MaxCacheSize is the maximum size of the cached blobs in bytes. 0 means unlimited.
+	MaxCacheSize int64
+
+	// TagTTL is how long a resolved tag is served without asking the upstream again
+	TagTTL time.Duration
+
+	// DownloadTimeout is how long a blob download from an upstream may take. Downloads are shared between all
+	// requests for the blob and continue if the request that started it is cancelled.
+	DownloadTimeout time.Duration
+}
+
+// Cache is a pull-through cache for upstream registries. Images are served under /v2/<upstream-name>/<repository>.
+// Manifests and blobs are cached per upstream, so content pulled with the credentials of one upstream is never
+// served through another one.
+type Cache struct {
+	options   Options
+	upstreams map[string]*upstream
+
+	downloads singleflight.Group
+	evictLock sync.Mutex
+}
+
+type upstream struct {
+	Upstream
+
+	options []remote.Option
+}
+
+type cachedManifest struct {
+	MediaType string `json:"mediaType"`
+	Manifest  []byte `json:"manifest"`
+}
+
+func New(options Options) (*Cache, error) {
+	if options.TagTTL <= 0 {
+		options.TagTTL = DefaultTagTTL
+	}
+	if options.DownloadTimeout <= 0 {
+		options.DownloadTimeout = DefaultDownloadTimeout
+	}
+
+	cache := &Cache{
+		options:   options,
+		upstreams: map[string]*upstream{},
+	}
+	for _, u := range options.Upstreams {
+		auth := u.Auth
+		if auth == nil {
+			auth = authn.Anonymous
+		}
+
+		// reuse the transport and tokens across requests
+		puller, err := remote.NewPuller(remote.WithAuth(auth))
+		if err != nil {
+			return nil, fmt.Errorf("create puller for %s: %w", u.Name, err)
+		}
+
+		cache.upstreams[u.Name] = &upstream{
+			Upstream: u,
+			options:  []remote.Option{remote.Reuse(puller)},
+		}
+	}
+
+	for _, dir := range []string{"blobs", "manifests", "tags", "tmp"} {
+		err := os.MkdirAll(filepath.Join(options.CacheDir, dir), 0755)
+		if err != nil {
+			return nil, fmt.Errorf("create cache dir: %w", err)
+		}
+	}
+
+	return cache, nil
+}
+
+// Handler serves the upstream images and passes all other requests to next
+func (c *Cache) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		upstream, repository, kind, reference, ok := c.parsePath(req.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, req)
+			return
+		} else if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			writeError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", "the pull-through cache is read-only")
+			return
+		}
+
+		repo := upstream.Registry.Repo(repository)
+		switch kind {
+		case "manifests":
+			c.serveManifest(w, req, upstream, repo, reference)
+		case "blobs":
+			c.serveBlob(w, req, upstream, repo, reference)
+		}
+	})
+}
+
+// parsePath splits /v2/<upstream>/<repository>/(manifests|blobs)/<reference>
+func (c *Cache) parsePath(path string) (*upstream, string, string, string, bool) {
+	path, ok := strings.CutPrefix(path, "/v2/")
+	if !ok {
+		return nil, "", "", "", false
+	}
+
+	upstreamName, rest, ok := strings.Cut(path, "/")
+	if !ok {
+		return nil, "", "", "", false
+	}
+	upstream, ok := c.upstreams[upstreamName]
+	if !ok {
+		return nil, "", "", "", false
+	}
+
+	for _, kind := range []string{"manifests", "blobs"} {
+		idx := strings.LastIndex(rest, "/"+kind+"/")
+		if idx <= 0 {
+			continue
+		}
+
+		// blobs are always referenced by digest, manifests either by digest or tag
+		repository, reference := rest[:idx], rest[idx+len(kind)+2:]
+		_, digestErr := v1.NewHash(reference)
+		if !isValidRepository(repository) || (digestErr != nil && (kind == "blobs" || !tagRegexp.MatchString(reference))) {
+			return nil, "", "", "", false
+		}
+
+		return upstream, repository, kind, reference, true
+	}
+
+	return nil, "", "", "", false
+}
+
+func (c *Cache) serveManifest(w http.ResponseWriter, req *http.Request, upstream *upstream, repo name.Repository, reference string) {
+	var manifest *cachedManifest
+	var digest string
+	var err error
+	if strings.Contains(reference, ":") {
+		digest = reference
+		manifest, err = c.getManifestByDigest(req.Context(), upstream, repo, reference)
+	} else {
+		digest, manifest, err = c.getManifestByTag(req.Context(), upstream, repo, reference)
+	}
+	if err != nil {
+		writeUpstreamError(w, req, "MANIFEST_UNKNOWN", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", manifest.MediaType)
+	w.Header().Set("Docker-Content-Digest", digest)
+	w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+	http.ServeContent(w, req, "", time.Time{}, bytes.NewReader(manifest.Manifest))
+}
+
+func (c *Cache) getManifestByTag(ctx context.Context, upstream *upstream, repo name.Repository, tag string) (string, *cachedManifest, error) {
+	tagPath := c.tagPath(upstream.Name, repo.RepositoryStr(), tag)
+	stat, statErr := os.Stat(tagPath)
+	if statErr == nil && time.Since(stat.ModTime()) < c.options.TagTTL {
+		digest, err := os.ReadFile(tagPath)
+		if err == nil {
+			manifest, err := c.readManifest(upstream.Name, string(digest))
+			if err == nil {
+				return string(digest), manifest, nil
+			}
+		}
+	}
+
+	descriptor, err := remote.Get(repo.Tag(tag), append(upstream.options, remote.WithContext(ctx))...)
+	if err != nil {
+		// serve the stale tag if the upstream is not reachable
+		if statErr == nil && !isNotFound(err) {
+			digest, readErr := os.ReadFile(tagPath)
+			if readErr == nil {
+				manifest, readErr := c.readManifest(upstream.Name, string(digest))
+				if readErr == nil {
+					klog.FromContext(ctx).Info("Serving stale tag, because upstream is not reachable", "upstream", upstream.Name, "repository", repo.RepositoryStr(), "tag", tag, "err", err.Error())
+					return string(digest), manifest, nil
+				}
+			}
+		}
+
+		return "", nil, err
+	}
+
+	manifest := &cachedManifest{MediaType: string(descriptor.MediaType), Manifest: descriptor.Manifest}
+	err = c.writeManifest(upstream.Name, descriptor.Digest.String(), manifest)
+	if err != nil {
+		return "", nil, err
+	}
+	err = writeFileAtomic(c.tempDir(), tagPath, []byte(descriptor.Digest.String()))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return descriptor.Digest.String(), manifest, nil
+}
+
+func (c *Cache) getManifestByDigest(ctx context.Context, upstream *upstream, repo name.Repository, digest string) (*cachedManifest, error) {
+	hash, err := v1.NewHash(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := c.readManifest(upstream.Name, hash.String())
+	if err == nil {
+		return manifest, nil
+	}
+
+	descriptor, err := remote.Get(repo.Digest(hash.String()), append(upstream.options, remote.WithContext(ctx))...)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest = &cachedManifest{MediaType: string(descriptor.MediaType), Manifest: descriptor.Manifest}
+	err = c.writeManifest(upstream.Name, hash.String(), manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+func (c *Cache) readManifest(upstreamName, digest string) (*cachedManifest, error) {
+	manifestPath, err := c.manifestPath(upstreamName, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &cachedManifest{}
+	err = json.Unmarshal(raw, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+func (c *Cache) writeManifest(upstreamName, digest string, manifest *cachedManifest) error {
+	hash, err := v1.NewHash(digest)
+	if err != nil {
+		return err
+	} else if hash.Algorithm == "sha256" {
+		sum := sha256.Sum256(manifest.Manifest)
+		if hex.EncodeToString(sum[:]) != hash.Hex {
+			return fmt.Errorf("manifest digest mismatch, expected %s", digest)
+		}
+	}
+
+	raw, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	manifestPath, err := c.manifestPath(upstreamName, digest)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(c.tempDir(), manifestPath, raw)
+}
+
+func (c *Cache) serveBlob(w http.ResponseWriter, req *http.Request, upstream *upstream, repo name.Repository, digest string) {
+	hash, err := v1.NewHash(digest)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "DIGEST_INVALID", err.Error())
+		return
+	}
+
+	blobPath := c.blobPath(upstream.Name, hash)
+	_, err = os.Stat(blobPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		// download the blob only once, even if multiple nodes pull it at the same time. The download is shared, so
+		// it must not be cancelled with the request that started it, but it must not hang forever either.
+		_, err, _ = c.downloads.Do(blobPath, func() (interface{}, error) {
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(req.Context()), c.options.DownloadTimeout)
+			defer cancel()
+
+			return nil, c.downloadBlob(ctx, upstream, repo, hash)
+		})
+	}
+	if err != nil {
+		writeUpstreamError(w, req, "BLOB_UNKNOWN", err)
+		return
+	}
+
+	f, err := os.Open(blobPath)
+	if err != nil {
+		writeUpstreamError(w, req, "BLOB_UNKNOWN", err)
+		return
+	}
+	defer f.Close()
+
+	// remember when the blob was used last for the eviction
+	now := time.Now()
+	_ = os.Chtimes(blobPath, now, now)
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Docker-Content-Digest", hash.String())
+	w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+	http.ServeContent(w, req, "", time.Time{}, f)
+}
+
+func (c *Cache) downloadBlob(ctx context.Context, upstream *upstream, repo name.Repository, hash v1.Hash) error {
+	layer, err := remote.Layer(repo.Digest(hash.String()), append(upstream.options, remote.WithContext(ctx))...)
+	if err != nil {
+		return err
+	}
+
+	// the reader verifies the digest once it is fully read
+	reader, err := layer.Compressed()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	tempFile, err := os.CreateTemp(c.tempDir(), "blob-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	_, err = io.Copy(tempFile, reader)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("download blob %s: %w", hash.String(), err)
+	}
+
+	blobPath := c.blobPath(upstream.Name, hash)
+	err = os.MkdirAll(filepath.Dir(blobPath), 0755)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tempFile.Name(), blobPath)
+	if err != nil {
+		return err
+	}
+
+	if c.options.MaxCacheSize > 0 {
+		err = c.evict(blobPath)
+		if err != nil {
+			klog.FromContext(ctx).Error(err, "error evicting blobs from pull-through cache")
+		}
+	}
+
+	return nil
+}
+
+// evict removes the least recently used blobs until the cache is below the maximum size. The blob at keepPath was just
+// downloaded and is never removed, even if it is larger than the cache itself.
+func (c *Cache) evict(keepPath string) error {
+	c.evictLock.Lock()
+	defer c.evictLock.Unlock()
+
+	type blob struct {
+		path    string
+		size    int64
+		lastUse time.Time
+	}
+
+	blobs := []blob{}
+	total := int64(0)
+	err := filepath.WalkDir(filepath.Join(c.options.CacheDir, "blobs"), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+
+		blobs = append(blobs, blob{path: path, size: info.Size(), lastUse: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(blobs, func(i, j int) bool {
+		return blobs[i].lastUse.Before(blobs[j].lastUse)
+	})
+	for _, b := range blobs {
+		if total <= c.options.MaxCacheSize {
+			break
+		} else if b.path == keepPath {
+			continue
+		}
+
+		err = os.Remove(b.path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		total -= b.size
+	}
+
+	return nil
+}
+
+func (c *Cache) blobPath(upstreamName string, hash v1.Hash) string {
+	return filepath.Join(c.options.CacheDir, "blobs", upstreamName, hash.Algorithm, hash.Hex)
+}
+
+func (c *Cache) manifestPath(upstreamName, digest string) (string, error) {
+	hash, err := v1.NewHash(digest)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(c.options.CacheDir, "manifests", upstreamName, hash.Algorithm, hash.Hex), nil
+}
+
+func (c *Cache) tagPath(upstreamName, repository, tag string) string {
+	return filepath.Join(c.options.CacheDir, "tags", upstreamName, filepath.FromSlash(repository), tag)
+}
+
+func (c *Cache) tempDir() string {
+	return filepath.Join(c.options.CacheDir, "tmp")
+}
+
+func isValidRepository(repository string) bool {
+	for _, component := range strings.Split(repository, "/") {
+		if !repositoryComponentRegexp.MatchString(component) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func writeFileAtomic(tempDir, path string, data []byte) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := os.CreateTemp(tempDir, "file-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+
+	_, err = tempFile.Write(data)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), path)
+}
+
+func isNotFound(err error) bool {
+	var transportErr *transport.Error
+	return errors.As(err, &transportErr) && transportErr.StatusCode == http.StatusNotFound
+}
+
+func writeUpstreamError(w http.ResponseWriter, req *http.Request, notFoundCode string, err error) {
+	if isNotFound(err) {
+		writeError(w, http.StatusNotFound, notFoundCode, err.Error())
+		return
+	}
+
+	klog.FromContext(req.Context()).Error(err, "error pulling from upstream registry", "path", req.URL.Path)
+	writeError(w, http.StatusBadGateway, "UNKNOWN", err.Error())
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{"code": code, "message": message}},
+	})
+}
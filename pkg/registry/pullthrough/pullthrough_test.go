@@ -0,0 +1,245 @@
+package pullthrough
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"gotest.tools/v3/assert"
+)
+
+type fakeUpstream struct {
+	server *httptest.Server
+
+	manifest       []byte
+	manifestDigest string
+	blobs          map[string][]byte
+
+	manifestRequests atomic.Int32
+	blobRequests     atomic.Int32
+}
+
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func newFakeUpstream(t *testing.T) *fakeUpstream {
+	configBlob := []byte(`{"architecture":"amd64","os":"linux"}`)
+	layerBlob := []byte(strings.Repeat("layer", 100))
+	manifest, err := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     types.DockerManifestSchema2,
+		"config":        map[string]interface{}{"mediaType": types.DockerConfigJSON, "size": len(configBlob), "digest": digestOf(configBlob)},
+		"layers":        []interface{}{map[string]interface{}{"mediaType": types.DockerLayer, "size": len(layerBlob), "digest": digestOf(layerBlob)}},
+	})
+	assert.NilError(t, err)
+
+	upstream := &fakeUpstream{
+		manifest:       manifest,
+		manifestDigest: digestOf(manifest),
+		blobs: map[string][]byte{
+			digestOf(configBlob): configBlob,
+			digestOf(layerBlob):  layerBlob,
+		},
+	}
+	upstream.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.URL.Path == "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case req.URL.Path == "/v2/library/nginx/manifests/latest" || req.URL.Path == "/v2/library/nginx/manifests/"+upstream.manifestDigest:
+			upstream.manifestRequests.Add(1)
+			w.Header().Set("Content-Type", string(types.DockerManifestSchema2))
+			w.Header().Set("Docker-Content-Digest", upstream.manifestDigest)
+			w.Header().Set("Content-Length", fmt.Sprint(len(upstream.manifest)))
+			if req.Method == http.MethodGet {
+				_, _ = w.Write(upstream.manifest)
+			}
+		case strings.HasPrefix(req.URL.Path, "/v2/library/nginx/blobs/"):
+			blob, ok := upstream.blobs[strings.TrimPrefix(req.URL.Path, "/v2/library/nginx/blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			upstream.blobRequests.Add(1)
+			_, _ = w.Write(blob)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[{"code":"NAME_UNKNOWN"}]}`))
+		}
+	}))
+	t.Cleanup(upstream.server.Close)
+	return upstream
+}
+
+func newTestCache(t *testing.T, upstream *fakeUpstream, options Options) *Cache {
+	upstreamURL, err := url.Parse(upstream.server.URL)
+	assert.NilError(t, err)
+	registry, err := name.NewRegistry(upstreamURL.Host, name.Insecure)
+	assert.NilError(t, err)
+
+	options.CacheDir = t.TempDir()
+	options.Upstreams = []Upstream{{Name: "docker.io", Registry: registry}}
+	cache, err := New(options)
+	assert.NilError(t, err)
+	return cache
+}
+
+func get(t *testing.T, handler http.Handler, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	return recorder
+}
+
+func TestCacheManifests(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	cache := newTestCache(t, upstream, Options{TagTTL: time.Hour})
+	handler := cache.Handler(http.NotFoundHandler())
+
+	// fetched from upstream
+	response := get(t, handler, "/v2/docker.io/library/nginx/manifests/latest")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Header().Get("Docker-Content-Digest"), upstream.manifestDigest)
+	assert.Equal(t, response.Header().Get("Content-Type"), string(types.DockerManifestSchema2))
+	assert.Equal(t, response.Body.String(), string(upstream.manifest))
+	requests := upstream.manifestRequests.Load()
+
+	// served from the cache
+	response = get(t, handler, "/v2/docker.io/library/nginx/manifests/latest")
+	assert.Equal(t, response.Code, http.StatusOK)
+	response = get(t, handler, "/v2/docker.io/library/nginx/manifests/"+upstream.manifestDigest)
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Body.String(), string(upstream.manifest))
+	assert.Equal(t, upstream.manifestRequests.Load(), requests)
+
+	// unknown at the upstream
+	response = get(t, handler, "/v2/docker.io/library/redis/manifests/latest")
+	assert.Equal(t, response.Code, http.StatusNotFound)
+	assert.Assert(t, strings.Contains(response.Body.String(), "MANIFEST_UNKNOWN"))
+}
+
+func TestCacheStaleTag(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	cache := newTestCache(t, upstream, Options{TagTTL: time.Nanosecond})
+	handler := cache.Handler(http.NotFoundHandler())
+
+	response := get(t, handler, "/v2/docker.io/library/nginx/manifests/latest")
+	assert.Equal(t, response.Code, http.StatusOK)
+
+	// the tag is expired, but the upstream is gone
+	upstream.server.Close()
+	response = get(t, handler, "/v2/docker.io/library/nginx/manifests/latest")
+	assert.Equal(t, response.Code, http.StatusOK)
+	assert.Equal(t, response.Header().Get("Docker-Content-Digest"), upstream.manifestDigest)
+}
+
+func TestCacheBlobs(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	cache := newTestCache(t, upstream, Options{})
+	handler := cache.Handler(http.NotFoundHandler())
+
+	for digest, blob := range upstream.blobs {
+		for i := 0; i < 2; i++ {
+			response := get(t, handler, "/v2/docker.io/library/nginx/blobs/"+digest)
+			assert.Equal(t, response.Code, http.StatusOK)
+			assert.Equal(t, response.Header().Get("Docker-Content-Digest"), digest)
+			body, err := io.ReadAll(response.Body)
+			assert.NilError(t, err)
+			assert.Equal(t, string(body), string(blob))
+		}
+	}
+	assert.Equal(t, upstream.blobRequests.Load(), int32(len(upstream.blobs)))
+
+	// unknown blob
+	response := get(t, handler, "/v2/docker.io/library/nginx/blobs/"+digestOf([]byte("unknown")))
+	assert.Equal(t, response.Code, http.StatusNotFound)
+}
+
+func TestCacheBlobsPerUpstream(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	otherUpstream := newFakeUpstream(t)
+	otherUpstream.blobs = map[string][]byte{}
+
+	upstreams := []Upstream{}
+	for upstreamName, u := range map[string]*fakeUpstream{"docker.io": upstream, "ghcr.io": otherUpstream} {
+		upstreamURL, err := url.Parse(u.server.URL)
+		assert.NilError(t, err)
+		registry, err := name.NewRegistry(upstreamURL.Host, name.Insecure)
+		assert.NilError(t, err)
+		upstreams = append(upstreams, Upstream{Name: upstreamName, Registry: registry})
+	}
+	cache, err := New(Options{CacheDir: t.TempDir(), Upstreams: upstreams})
+	assert.NilError(t, err)
+	handler := cache.Handler(http.NotFoundHandler())
+
+	// a blob cached for one upstream is not served through another one
+	for digest := range upstream.blobs {
+		response := get(t, handler, "/v2/docker.io/library/nginx/blobs/"+digest)
+		assert.Equal(t, response.Code, http.StatusOK)
+		response = get(t, handler, "/v2/ghcr.io/library/nginx/blobs/"+digest)
+		assert.Equal(t, response.Code, http.StatusNotFound)
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	cache := newTestCache(t, upstream, Options{MaxCacheSize: 100})
+	handler := cache.Handler(http.NotFoundHandler())
+
+	for digest := range upstream.blobs {
+		response := get(t, handler, "/v2/docker.io/library/nginx/blobs/"+digest)
+		assert.Equal(t, response.Code, http.StatusOK)
+	}
+
+	// only the small config blob fits into the cache
+	for digest, blob := range upstream.blobs {
+		response := get(t, handler, "/v2/docker.io/library/nginx/blobs/"+digest)
+		assert.Equal(t, response.Code, http.StatusOK)
+		assert.Equal(t, response.Body.String(), string(blob))
+	}
+	assert.Assert(t, upstream.blobRequests.Load() > int32(len(upstream.blobs)))
+}
+
+func TestCacheHandler(t *testing.T) {
+	upstream := newFakeUpstream(t)
+	cache := newTestCache(t, upstream, Options{})
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := cache.Handler(next)
+
+	testCases := []struct {
+		name     string
+		method   string
+		path     string
+		expected int
+	}{
+		{name: "pushed image", method: http.MethodGet, path: "/v2/my-app/manifests/latest", expected: http.StatusTeapot},
+		{name: "unknown upstream", method: http.MethodGet, path: "/v2/ghcr.io/loft-sh/vcluster/manifests/latest", expected: http.StatusTeapot},
+		{name: "api version check", method: http.MethodGet, path: "/v2/", expected: http.StatusTeapot},
+		{name: "path traversal", method: http.MethodGet, path: "/v2/docker.io/../../etc/manifests/latest", expected: http.StatusTeapot},
+		{name: "blob by tag", method: http.MethodGet, path: "/v2/docker.io/library/nginx/blobs/latest", expected: http.StatusTeapot},
+		{name: "push", method: http.MethodPut, path: "/v2/docker.io/library/nginx/manifests/latest", expected: http.StatusMethodNotAllowed},
+		{name: "head", method: http.MethodHead, path: "/v2/docker.io/library/nginx/manifests/latest", expected: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(tc.method, tc.path, nil))
+			assert.Equal(t, recorder.Code, tc.expected)
+		})
+	}
+}
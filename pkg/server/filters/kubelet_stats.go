@@ -0,0 +1,479 @@
+package filters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/mappings"
+	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	statsv1alpha1 "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cpuCounterTTL is how long the cpu counter of a container is kept after it was last reported
+const cpuCounterTTL = 10 * time.Minute
+
+// syntheticCPUCounters is shared by all requests, because the cumulative cpu counters have to keep increasing between
+// scrapes of the stats and metrics endpoints
+var syntheticCPUCounters = newCPUCounters()
+
+// isSyntheticKubeletRequest checks if the request targets a kubelet endpoint that is synthesized for fake nodes. Fake
+// nodes have no kubelet vCluster could forward to, so stats and metrics are built from the host metrics API usage of
+// the virtual cluster pods on the node.
+func isSyntheticKubeletRequest(ctx *synccontext.RegisterContext, path string) bool {
+	if ctx.Config.Sync.FromHost.Nodes.Enabled || !ctx.Config.Networking.Advanced.ProxyKubelets.SyntheticStats {
+		return false
+	}
+
+	return IsKubeletStats(path) || IsKubeletMetrics(path)
+}
+
+func handleSyntheticKubeletRequest(registerCtx *synccontext.RegisterContext, w http.ResponseWriter, req *http.Request) error {
+	ctx := registerCtx.ToSyncContext("synthetic-kubelet")
+	node := &corev1.Node{}
+	err := ctx.VirtualClient.Get(ctx, types.NamespacedName{Name: nodeNameFromProxyPath(req.URL.Path)}, node)
+	if err != nil {
+		return err
+	}
+
+	pods, scrapeError, err := listNodePodUsage(ctx, registerCtx.HostManager.GetAPIReader(), node.Name)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	sample := syntheticCPUCounters.sample(node, pods, now)
+	sample.scrapeError = scrapeError
+	syntheticCPUCounters.prune(now)
+
+	if IsKubeletStats(req.URL.Path) {
+		out, err := json.MarshalIndent(buildStatsSummary(sample), "", "  ")
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(out)
+		return nil
+	}
+
+	// fake nodes only have resource metrics, there are no probes, cadvisor or kubelet metrics to report
+	var metricFamilies []*dto.MetricFamily
+	if isResourceMetrics(req.URL.Path) {
+		metricFamilies = buildResourceMetrics(sample)
+	}
+
+	format := expfmt.Negotiate(req.Header)
+	out, err := MetricsEncode(metricFamilies, format)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", string(format))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(out)
+	return nil
+}
+
+func isResourceMetrics(path string) bool {
+	return strings.HasSuffix(path, "/metrics/resource") || strings.HasSuffix(path, "/metrics/resource/v1alpha1") || strings.HasSuffix(path, "/metrics/resource/v1beta1")
+}
+
+// nodeNameFromProxyPath returns the node of a /api/v1/nodes/NODE/proxy/... path, where NODE might be prefixed with a
+// scheme and suffixed with a port
+func nodeNameFromProxyPath(path string) string {
+	splitted := strings.Split(path, "/")
+	if len(splitted) < 5 {
+		return ""
+	}
+
+	parts := strings.Split(splitted[4], ":")
+	if len(parts) == 3 {
+		return parts[1]
+	}
+
+	return parts[0]
+}
+
+// podUsage is a virtual pod with the host metrics API usage of its host pod
+type podUsage struct {
+	pod     *corev1.Pod
+	hostUID types.UID
+	// metrics is nil if the host metrics API didn't report the pod
+	metrics *metricsv1beta1.PodMetrics
+}
+
+// listNodePodUsage returns the virtual pods on the node with their usage. If the usage of some pods couldn't be
+// retrieved from the host metrics API, they are still returned without usage and a scrape error is reported.
+func listNodePodUsage(ctx *synccontext.SyncContext, metricsReader client.Reader, nodeName string) ([]podUsage, bool, error) {
+	hostPods := &corev1.PodList{}
+	err := ctx.HostClient.List(ctx, hostPods, client.MatchingFields{constants.IndexByAssigned: nodeName})
+	if err != nil {
+		return nil, false, fmt.Errorf("list host pods: %w", err)
+	}
+
+	scrapeError := false
+	podMetrics := map[types.NamespacedName]*metricsv1beta1.PodMetrics{}
+	listedNamespaces := map[string]bool{}
+	pods := []podUsage{}
+	for i := range hostPods.Items {
+		hostPod := &hostPods.Items[i]
+		name := mappings.HostToVirtual(ctx, hostPod.Name, hostPod.Namespace, hostPod, mappings.Pods())
+		if name.Name == "" {
+			continue
+		}
+
+		vPod := &corev1.Pod{}
+		err := ctx.VirtualClient.Get(ctx, name, vPod)
+		if kerrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, false, err
+		}
+
+		if !listedNamespaces[hostPod.Namespace] {
+			listedNamespaces[hostPod.Namespace] = true
+
+			metricsList := &metricsv1beta1.PodMetricsList{}
+			err := metricsReader.List(ctx, metricsList, client.InNamespace(hostPod.Namespace))
+			if err != nil {
+				klog.Warningf("list host pod metrics in namespace %s: %v", hostPod.Namespace, err)
+				scrapeError = true
+			}
+			for j := range metricsList.Items {
+				podMetrics[types.NamespacedName{Namespace: metricsList.Items[j].Namespace, Name: metricsList.Items[j].Name}] = &metricsList.Items[j]
+			}
+		}
+
+		pods = append(pods, podUsage{
+			pod:     vPod,
+			hostUID: hostPod.UID,
+			metrics: podMetrics[types.NamespacedName{Namespace: hostPod.Namespace, Name: hostPod.Name}],
+		})
+	}
+
+	slices.SortFunc(pods, func(a, b podUsage) int {
+		return strings.Compare(a.pod.Namespace+"/"+a.pod.Name, b.pod.Namespace+"/"+b.pod.Name)
+	})
+	return pods, scrapeError, nil
+}
+
+// usageSample is the usage of a node, pod or container at a point in time
+type usageSample struct {
+	time            metav1.Time
+	nanoCores       uint64
+	coreNanoSeconds uint64
+	workingSetBytes uint64
+}
+
+func (u *usageSample) add(other usageSample) {
+	if other.time.After(u.time.Time) {
+		u.time = other.time
+	}
+	u.nanoCores += other.nanoCores
+	u.coreNanoSeconds += other.coreNanoSeconds
+	u.workingSetBytes += other.workingSetBytes
+}
+
+type containerSample struct {
+	name      string
+	startTime metav1.Time
+	usage     usageSample
+}
+
+type podSample struct {
+	pod        *corev1.Pod
+	startTime  metav1.Time
+	containers []containerSample
+	// usage is nil if there are no host metrics for the pod
+	usage *usageSample
+}
+
+type nodeSample struct {
+	node  *corev1.Node
+	pods  []podSample
+	usage *usageSample
+	// scrapeError is true if the usage of some pods couldn't be retrieved
+	scrapeError bool
+}
+
+// cpuCounters turns the cpu usage rates of the metrics API into the cumulative counters kubelets report. Every
+// metrics API sample is the average usage over its window, so a counter grows by usage * window per new sample.
+type cpuCounters struct {
+	m        sync.Mutex
+	counters map[string]*cpuCounter
+}
+
+type cpuCounter struct {
+	coreNanoSeconds uint64
+	sampleTime      time.Time
+	lastSeen        time.Time
+}
+
+func newCPUCounters() *cpuCounters {
+	return &cpuCounters{counters: map[string]*cpuCounter{}}
+}
+
+// observe adds the sample to the counter if it wasn't observed yet and returns the total and the increase
+func (c *cpuCounters) observe(key string, nanoCores uint64, sampleTime time.Time, window time.Duration, now time.Time) (uint64, uint64) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	counter, ok := c.counters[key]
+	if !ok {
+		counter = &cpuCounter{}
+		c.counters[key] = counter
+	}
+	counter.lastSeen = now
+	if !sampleTime.After(counter.sampleTime) {
+		return counter.coreNanoSeconds, 0
+	}
+
+	increase := uint64(float64(nanoCores) * window.Seconds())
+	counter.sampleTime = sampleTime
+	counter.coreNanoSeconds += increase
+	return counter.coreNanoSeconds, increase
+}
+
+// add increases the counter, it's used for pods and nodes that sum up the increases of their containers
+func (c *cpuCounters) add(key string, increase uint64, now time.Time) uint64 {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	counter, ok := c.counters[key]
+	if !ok {
+		counter = &cpuCounter{}
+		c.counters[key] = counter
+	}
+	counter.lastSeen = now
+	counter.coreNanoSeconds += increase
+	return counter.coreNanoSeconds
+}
+
+// prune removes the counters of containers, pods and nodes that are gone
+func (c *cpuCounters) prune(now time.Time) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	for key, counter := range c.counters {
+		if now.Sub(counter.lastSeen) > cpuCounterTTL {
+			delete(c.counters, key)
+		}
+	}
+}
+
+// sample calculates the usage of the node and its pods and advances the cpu counters
+func (c *cpuCounters) sample(node *corev1.Node, pods []podUsage, now time.Time) *nodeSample {
+	out := &nodeSample{node: node}
+	nodeIncrease := uint64(0)
+	for _, pod := range pods {
+		podOut := podSample{pod: pod.pod, startTime: podStartTime(pod.pod)}
+		if pod.metrics != nil {
+			podOut.usage = &usageSample{}
+			podIncrease := uint64(0)
+			for _, container := range pod.metrics.Containers {
+				// skip containers that were added to the host pod only
+				if !hasContainer(pod.pod, container.Name) {
+					continue
+				}
+
+				nanoCores := uint64(container.Usage.Cpu().ScaledValue(resource.Nano))
+				total, increase := c.observe(string(pod.hostUID)+"/"+container.Name, nanoCores, pod.metrics.Timestamp.Time, pod.metrics.Window.Duration, now)
+				podIncrease += increase
+
+				containerOut := containerSample{
+					name:      container.Name,
+					startTime: containerStartTime(pod.pod, container.Name),
+					usage: usageSample{
+						time:            pod.metrics.Timestamp,
+						nanoCores:       nanoCores,
+						coreNanoSeconds: total,
+						workingSetBytes: uint64(container.Usage.Memory().Value()),
+					},
+				}
+				podOut.containers = append(podOut.containers, containerOut)
+				podOut.usage.add(containerOut.usage)
+			}
+
+			podOut.usage.coreNanoSeconds = c.add(string(pod.hostUID), podIncrease, now)
+			nodeIncrease += podIncrease
+			if out.usage == nil {
+				out.usage = &usageSample{}
+			}
+			out.usage.add(*podOut.usage)
+		}
+
+		out.pods = append(out.pods, podOut)
+	}
+	if out.usage != nil {
+		out.usage.coreNanoSeconds = c.add("node/"+node.Name, nodeIncrease, now)
+	}
+
+	return out
+}
+
+func hasContainer(pod *corev1.Pod, name string) bool {
+	return slices.ContainsFunc(pod.Spec.Containers, func(container corev1.Container) bool {
+		return container.Name == name
+	}) || slices.ContainsFunc(pod.Spec.InitContainers, func(container corev1.Container) bool {
+		return container.Name == name
+	})
+}
+
+func podStartTime(pod *corev1.Pod) metav1.Time {
+	if pod.Status.StartTime != nil {
+		return *pod.Status.StartTime
+	}
+
+	return pod.CreationTimestamp
+}
+
+func containerStartTime(pod *corev1.Pod, name string) metav1.Time {
+	for _, status := range append(slices.Clone(pod.Status.InitContainerStatuses), pod.Status.ContainerStatuses...) {
+		if status.Name == name && status.State.Running != nil {
+			return status.State.Running.StartedAt
+		}
+	}
+
+	return podStartTime(pod)
+}
+
+func cpuStats(usage *usageSample) *statsv1alpha1.CPUStats {
+	return &statsv1alpha1.CPUStats{
+		Time:                 usage.time,
+		UsageNanoCores:       ptr.To(usage.nanoCores),
+		UsageCoreNanoSeconds: ptr.To(usage.coreNanoSeconds),
+	}
+}
+
+func memoryStats(usage *usageSample) *statsv1alpha1.MemoryStats {
+	return &statsv1alpha1.MemoryStats{
+		Time:            usage.time,
+		UsageBytes:      ptr.To(usage.workingSetBytes),
+		WorkingSetBytes: ptr.To(usage.workingSetBytes),
+	}
+}
+
+// buildStatsSummary builds the /stats/summary response of a fake node. The node usage is the sum of the usage of
+// the virtual cluster pods on it.
+func buildStatsSummary(sample *nodeSample) *statsv1alpha1.Summary {
+	summary := &statsv1alpha1.Summary{
+		Node: statsv1alpha1.NodeStats{
+			NodeName:  sample.node.Name,
+			StartTime: sample.node.CreationTimestamp,
+		},
+		Pods: []statsv1alpha1.PodStats{},
+	}
+	if sample.usage != nil {
+		summary.Node.CPU = cpuStats(sample.usage)
+		summary.Node.Memory = memoryStats(sample.usage)
+	}
+
+	for _, pod := range sample.pods {
+		podStats := statsv1alpha1.PodStats{
+			PodRef: statsv1alpha1.PodReference{
+				Name:      pod.pod.Name,
+				Namespace: pod.pod.Namespace,
+				UID:       string(pod.pod.UID),
+			},
+			StartTime:  pod.startTime,
+			Containers: []statsv1alpha1.ContainerStats{},
+		}
+		if pod.usage != nil {
+			podStats.CPU = cpuStats(pod.usage)
+			podStats.Memory = memoryStats(pod.usage)
+		}
+		for _, container := range pod.containers {
+			podStats.Containers = append(podStats.Containers, statsv1alpha1.ContainerStats{
+				Name:      container.name,
+				StartTime: container.startTime,
+				CPU:       cpuStats(&container.usage),
+				Memory:    memoryStats(&container.usage),
+			})
+		}
+
+		summary.Pods = append(summary.Pods, podStats)
+	}
+
+	return summary
+}
+
+// buildResourceMetrics builds the metrics of the kubelet /metrics/resource endpoint for a fake node
+func buildResourceMetrics(sample *nodeSample) []*dto.MetricFamily {
+	containerCPU := newMetricFamily("container_cpu_usage_seconds_total", "Cumulative cpu time consumed by the container in core-seconds", dto.MetricType_COUNTER)
+	containerMemory := newMetricFamily("container_memory_working_set_bytes", "Current working set of the container in bytes", dto.MetricType_GAUGE)
+	containerStartTime := newMetricFamily("container_start_time_seconds", "Start time of the container since unix epoch in seconds", dto.MetricType_GAUGE)
+	podCPU := newMetricFamily("pod_cpu_usage_seconds_total", "Cumulative cpu time consumed by the pod in core-seconds", dto.MetricType_COUNTER)
+	podMemory := newMetricFamily("pod_memory_working_set_bytes", "Current working set of the pod in bytes", dto.MetricType_GAUGE)
+	nodeCPU := newMetricFamily("node_cpu_usage_seconds_total", "Cumulative cpu time consumed by the node in core-seconds", dto.MetricType_COUNTER)
+	nodeMemory := newMetricFamily("node_memory_working_set_bytes", "Current working set of the node in bytes", dto.MetricType_GAUGE)
+	scrapeError := newMetricFamily("scrape_error", "1 if there was an error while getting container metrics, 0 otherwise", dto.MetricType_GAUGE)
+	scrapeErrorValue := float64(0)
+	if sample.scrapeError {
+		scrapeErrorValue = 1
+	}
+	scrapeError.Metric = append(scrapeError.Metric, newMetric(dto.MetricType_GAUGE, scrapeErrorValue, nil))
+
+	if sample.usage != nil {
+		nodeCPU.Metric = append(nodeCPU.Metric, newMetric(dto.MetricType_COUNTER, coreSeconds(sample.usage.coreNanoSeconds), &sample.usage.time))
+		nodeMemory.Metric = append(nodeMemory.Metric, newMetric(dto.MetricType_GAUGE, float64(sample.usage.workingSetBytes), &sample.usage.time))
+	}
+	for _, pod := range sample.pods {
+		podLabels := []string{"namespace", pod.pod.Namespace, "pod", pod.pod.Name}
+		if pod.usage != nil {
+			podCPU.Metric = append(podCPU.Metric, newMetric(dto.MetricType_COUNTER, coreSeconds(pod.usage.coreNanoSeconds), &pod.usage.time, podLabels...))
+			podMemory.Metric = append(podMemory.Metric, newMetric(dto.MetricType_GAUGE, float64(pod.usage.workingSetBytes), &pod.usage.time, podLabels...))
+		}
+		for _, container := range pod.containers {
+			containerLabels := append([]string{"container", container.name}, podLabels...)
+			containerCPU.Metric = append(containerCPU.Metric, newMetric(dto.MetricType_COUNTER, coreSeconds(container.usage.coreNanoSeconds), &container.usage.time, containerLabels...))
+			containerMemory.Metric = append(containerMemory.Metric, newMetric(dto.MetricType_GAUGE, float64(container.usage.workingSetBytes), &container.usage.time, containerLabels...))
+			containerStartTime.Metric = append(containerStartTime.Metric, newMetric(dto.MetricType_GAUGE, float64(container.startTime.Unix()), nil, containerLabels...))
+		}
+	}
+
+	return []*dto.MetricFamily{containerCPU, containerMemory, containerStartTime, nodeCPU, nodeMemory, podCPU, podMemory, scrapeError}
+}
+
+func coreSeconds(coreNanoSeconds uint64) float64 {
+	return float64(coreNanoSeconds) / float64(time.Second)
+}
+
+func newMetricFamily(name, help string, metricType dto.MetricType) *dto.MetricFamily {
+	return &dto.MetricFamily{Name: ptr.To(name), Help: ptr.To(help), Type: metricType.Enum()}
+}
+
+// newMetric creates a metric with the given label name and value pairs
+func newMetric(metricType dto.MetricType, value float64, timestamp *metav1.Time, labels ...string) *dto.Metric {
+	metric := &dto.Metric{}
+	for i := 0; i+1 < len(labels); i += 2 {
+		metric.Label = append(metric.Label, &dto.LabelPair{Name: ptr.To(labels[i]), Value: ptr.To(labels[i+1])})
+	}
+	if timestamp != nil {
+		metric.TimestampMs = ptr.To(timestamp.UnixMilli())
+	}
+	if metricType == dto.MetricType_COUNTER {
+		metric.Counter = &dto.Counter{Value: ptr.To(value)}
+	} else {
+		metric.Gauge = &dto.Gauge{Value: ptr.To(value)}
+	}
+
+	return metric
+}
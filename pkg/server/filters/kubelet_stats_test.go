@@ -0,0 +1,158 @@
+package filters
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+func TestNodeNameFromProxyPath(t *testing.T) {
+	testCases := map[string]string{
+		"/api/v1/nodes/node-1/proxy/stats/summary":          "node-1",
+		"/api/v1/nodes/node-1:10250/proxy/metrics/cadvisor": "node-1",
+		"/api/v1/nodes/https:node-1:10250/proxy/metrics":    "node-1",
+		"/api/v1/nodes": "",
+	}
+
+	for path, expected := range testCases {
+		assert.Equal(t, nodeNameFromProxyPath(path), expected, path)
+	}
+}
+
+func TestCPUCountersObserve(t *testing.T) {
+	counters := newCPUCounters()
+	now := time.Unix(1000, 0)
+
+	// 0.5 cores over a 10s window
+	total, increase := counters.observe("pod/container", 500_000_000, now, 10*time.Second, now)
+	assert.Equal(t, total, uint64(5_000_000_000))
+	assert.Equal(t, increase, uint64(5_000_000_000))
+
+	// the same sample again doesn't advance the counter
+	total, increase = counters.observe("pod/container", 500_000_000, now, 10*time.Second, now.Add(time.Second))
+	assert.Equal(t, total, uint64(5_000_000_000))
+	assert.Equal(t, increase, uint64(0))
+
+	total, increase = counters.observe("pod/container", 1_000_000_000, now.Add(15*time.Second), 15*time.Second, now.Add(15*time.Second))
+	assert.Equal(t, total, uint64(20_000_000_000))
+	assert.Equal(t, increase, uint64(15_000_000_000))
+
+	counters.prune(now.Add(15*time.Second + cpuCounterTTL))
+	assert.Equal(t, len(counters.counters), 1)
+	counters.prune(now.Add(16*time.Second + cpuCounterTTL))
+	assert.Equal(t, len(counters.counters), 0)
+}
+
+func newSyntheticStatsTestData() (*corev1.Node, []podUsage, time.Time) {
+	created := metav1.Unix(100, 0)
+	started := metav1.Unix(200, 0)
+	sampleTime := metav1.Unix(1000, 0)
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", CreationTimestamp: created}}
+	pods := []podUsage{
+		{
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "virtual-uid", CreationTimestamp: created},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx"}}},
+				Status: corev1.PodStatus{
+					StartTime: &started,
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:  "nginx",
+						State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.Unix(300, 0)}},
+					}},
+				},
+			},
+			hostUID: "host-uid",
+			metrics: &metricsv1beta1.PodMetrics{
+				Timestamp: sampleTime,
+				Window:    metav1.Duration{Duration: 10 * time.Second},
+				Containers: []metricsv1beta1.ContainerMetrics{
+					{Name: "nginx", Usage: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m"), corev1.ResourceMemory: resource.MustParse("64Mi")}},
+					// injected into the host pod only
+					{Name: "sidecar", Usage: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")}},
+				},
+			},
+		},
+		{
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "pending", Namespace: "default", UID: "pending-uid", CreationTimestamp: created},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+			},
+			hostUID: "pending-host-uid",
+		},
+	}
+
+	return node, pods, sampleTime.Time
+}
+
+func TestBuildStatsSummary(t *testing.T) {
+	node, pods, now := newSyntheticStatsTestData()
+	summary := buildStatsSummary(newCPUCounters().sample(node, pods, now))
+
+	assert.Equal(t, summary.Node.NodeName, "node-1")
+	assert.Equal(t, *summary.Node.CPU.UsageNanoCores, uint64(250_000_000))
+	assert.Equal(t, *summary.Node.CPU.UsageCoreNanoSeconds, uint64(2_500_000_000))
+	assert.Equal(t, *summary.Node.Memory.WorkingSetBytes, uint64(64*1024*1024))
+
+	assert.Equal(t, len(summary.Pods), 2)
+	web := summary.Pods[0]
+	assert.Equal(t, web.PodRef.UID, "virtual-uid")
+	assert.Equal(t, web.StartTime, metav1.Unix(200, 0))
+	assert.Equal(t, *web.CPU.UsageCoreNanoSeconds, uint64(2_500_000_000))
+	assert.Equal(t, len(web.Containers), 1)
+	assert.Equal(t, web.Containers[0].Name, "nginx")
+	assert.Equal(t, web.Containers[0].StartTime, metav1.Unix(300, 0))
+
+	pending := summary.Pods[1]
+	assert.Assert(t, pending.CPU == nil)
+	assert.Equal(t, len(pending.Containers), 0)
+}
+
+func TestBuildResourceMetrics(t *testing.T) {
+	node, pods, now := newSyntheticStatsTestData()
+	families := buildResourceMetrics(newCPUCounters().sample(node, pods, now))
+
+	buf := &bytes.Buffer{}
+	encoder := expfmt.NewEncoder(buf, expfmt.NewFormat(expfmt.TypeTextPlain))
+	for _, family := range families {
+		assert.NilError(t, encoder.Encode(family))
+	}
+
+	output := buf.String()
+	for _, expected := range []string{
+		`container_cpu_usage_seconds_total{container="nginx",namespace="default",pod="web"} 2.5 1000000`,
+		`container_memory_working_set_bytes{container="nginx",namespace="default",pod="web"} 6.7108864e+07 1000000`,
+		`container_start_time_seconds{container="nginx",namespace="default",pod="web"} 300`,
+		`node_cpu_usage_seconds_total 2.5 1000000`,
+		`pod_cpu_usage_seconds_total{namespace="default",pod="web"} 2.5 1000000`,
+		`scrape_error 0`,
+	} {
+		assert.Assert(t, strings.Contains(output, expected), "expected %q in output:\n%s", expected, output)
+	}
+	assert.Assert(t, !strings.Contains(output, "sidecar"))
+	assert.Assert(t, !strings.Contains(output, `pod="pending"`))
+
+	// failed host metrics requests are reported as scrape error
+	sample := newCPUCounters().sample(node, pods, now)
+	sample.scrapeError = true
+	families = buildResourceMetrics(sample)
+	buf.Reset()
+	assert.NilError(t, encoder.Encode(families[len(families)-1]))
+	assert.Assert(t, strings.Contains(buf.String(), "scrape_error 1"), buf.String())
+}
+
+func TestIsResourceMetrics(t *testing.T) {
+	assert.Assert(t, isResourceMetrics("/api/v1/nodes/node-1/proxy/metrics/resource"))
+	assert.Assert(t, isResourceMetrics("/api/v1/nodes/node-1:10250/proxy/metrics/resource/v1beta1"))
+	assert.Assert(t, !isResourceMetrics("/api/v1/nodes/node-1/proxy/metrics/cadvisor"))
+	assert.Assert(t, !isResourceMetrics("/api/v1/nodes/node-1/proxy/metrics/probes"))
+	assert.Assert(t, !isResourceMetrics("/api/v1/nodes/node-1/proxy/metrics"))
+}
@@ -238,6 +238,11 @@ func streamNodeRequest(ctx *synccontext.RegisterContext, w http.ResponseWriter,
 }
 
 func handleNodeRequest(ctx *synccontext.RegisterContext, w http.ResponseWriter, req *http.Request) (bool, error) {
+	// fake nodes have no kubelet to forward to
+	if isSyntheticKubeletRequest(ctx, req.URL.Path) {
+		return true, handleSyntheticKubeletRequest(ctx, w, req)
+	}
+
 	// authorization was done here already so we will just go forward with the rewrite
 	req.Header.Del("Authorization")
 	h, err := handler.Handler("", ctx.HostManager.GetConfig(), nil)
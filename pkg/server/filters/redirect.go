@@ -10,7 +10,9 @@ import (
 	"github.com/loft-sh/vcluster/pkg/scheme"
 	"github.com/loft-sh/vcluster/pkg/server/handler"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/tracing"
 	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -117,7 +119,13 @@ func callAdmissionWebhooks(req *http.Request, info *request.RequestInfo, paramet
 				}
 			}
 
-			err := validatingAdmission.Validate(req.Context(), admission.NewAttributesRecord(opts, nil, kind, info.Namespace, info.Name, corev1.SchemeGroupVersion.WithResource(info.Resource), info.Subresource, admission.Connect, nil, false, userInfo), NewFakeObjectInterfaces(uncachedVirtualClient.Scheme(), uncachedVirtualClient.RESTMapper()))
+			ctx, span := tracing.StartSpan(req.Context(), "admission.Validate",
+				attribute.String("namespace", info.Namespace),
+				attribute.String("name", info.Name),
+				attribute.String("subresource", info.Subresource),
+			)
+			err := validatingAdmission.Validate(ctx, admission.NewAttributesRecord(opts, nil, kind, info.Namespace, info.Name, corev1.SchemeGroupVersion.WithResource(info.Resource), info.Subresource, admission.Connect, nil, false, userInfo), NewFakeObjectInterfaces(uncachedVirtualClient.Scheme(), uncachedVirtualClient.RESTMapper()))
+			tracing.EndSpan(span, err)
 			if err != nil {
 				klog.Infof("Admission validate failed for %s: %v", info.Path, err)
 				return kerrors.NewForbidden(corev1.SchemeGroupVersion.WithResource(info.Resource+"/"+info.Subresource).GroupResource(), info.Name, err)
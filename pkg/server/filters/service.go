@@ -11,6 +11,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
 	"github.com/loft-sh/vcluster/pkg/util/encoding"
+	"github.com/loft-sh/vcluster/pkg/util/hostnamepolicy"
 	"github.com/loft-sh/vcluster/pkg/util/random"
 	requestpkg "github.com/loft-sh/vcluster/pkg/util/request"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
@@ -210,7 +211,14 @@ func createService(ctx *synccontext.SyncContext, req *http.Request, decoder enco
 		vService.Name = vService.GenerateName + random.String(5)
 	}
 
-	newService := translate.HostMetadata(vService, mappings.VirtualToHost(ctx, vService.Name, vService.Namespace, mappings.Services()))
+	// the service syncer reports ExternalDNS annotations that are not permitted, here we only make sure they are not
+	// created in the host cluster. The virtual service is copied, because excluded annotations are stripped from it.
+	var excludedAnnotations []string
+	if hostnamepolicy.ValidateAnnotations(ctx.Config.Policies.Hostnames, vService.Annotations) != nil {
+		excludedAnnotations = hostnamepolicy.ExternalDNSAnnotations
+	}
+
+	newService := translate.HostMetadata(vService.DeepCopy(), mappings.VirtualToHost(ctx, vService.Name, vService.Namespace, mappings.Services()), excludedAnnotations...)
 	if newService.Annotations == nil {
 		newService.Annotations = map[string]string{}
 	}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/loft-sh/vcluster/pkg/authentication/delegatingauthenticator"
+	"github.com/loft-sh/vcluster/pkg/authentication/oidcauthenticator"
 	"github.com/loft-sh/vcluster/pkg/authentication/platformauthenticator"
 	"github.com/loft-sh/vcluster/pkg/authorization/allowall"
 	"github.com/loft-sh/vcluster/pkg/authorization/delegatingauthorizer"
@@ -18,11 +19,13 @@ import (
 	"github.com/loft-sh/vcluster/pkg/authorization/kubeletauthorizer"
 	"github.com/loft-sh/vcluster/pkg/plugin"
 	"github.com/loft-sh/vcluster/pkg/pro"
+	"github.com/loft-sh/vcluster/pkg/registry/pullthrough"
 	"github.com/loft-sh/vcluster/pkg/server/cert"
 	"github.com/loft-sh/vcluster/pkg/server/filters"
 	"github.com/loft-sh/vcluster/pkg/server/handler"
 	servertypes "github.com/loft-sh/vcluster/pkg/server/types"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/tracing"
 	"github.com/loft-sh/vcluster/pkg/util/pluginhookclient"
 	"github.com/loft-sh/vcluster/pkg/util/serverhelper"
 	"github.com/pkg/errors"
@@ -176,6 +179,19 @@ func NewServer(ctx *synccontext.ControllerContext) (*Server, error) {
 		h = f(h, ctx)
 	}
 
+	// trace the time spent in the vCluster filters and the virtual cluster api server
+	h = tracing.WithSpan(h, "vcluster.Proxy")
+
+	// the pull-through cache needs to see its requests before the embedded registry does
+	if ctx.Config.ControlPlane.Advanced.Registry.Enabled && ctx.Config.ControlPlane.Advanced.Registry.PullThrough.Enabled {
+		cache, err := pullthrough.NewFromConfig(ctx, ctx.Config.ControlPlane.Advanced.Registry, ctx.HostNamespaceClient, ctx.Config.HostNamespace)
+		if err != nil {
+			return nil, errors.Wrap(err, "create registry pull-through cache")
+		}
+
+		h = cache.Handler(h)
+	}
+
 	serverhelper.HandleRoute(s.handler, "/", h)
 	return s, nil
 }
@@ -184,6 +200,7 @@ func NewServer(ctx *synccontext.ControllerContext) (*Server, error) {
 func (s *Server) ServeOnListenerTLS(ctx *synccontext.ControllerContext) error {
 	// kubernetes build handler configuration
 	serverConfig := server.NewConfig(serializer.NewCodecFactory(s.uncachedVirtualClient.Scheme()))
+	serverConfig.TracerProvider = tracing.Provider
 	serverConfig.RequestInfoResolver = &request.RequestInfoFactory{
 		APIPrefixes:          sets.NewString("api", "apis"),
 		GrouplessAPIPrefixes: sets.NewString("api"),
@@ -296,10 +313,18 @@ func (s *Server) ServeOnListenerTLS(ctx *synccontext.ControllerContext) error {
 
 	// make sure the tokens are correctly authenticated. We use the following order:
 	// 1. try the service account token one first since it's cheap to check this.
-	// 2. try the extra authenticators like platform that might take longer
+	// 2. try the extra authenticators like oidc or platform that might take longer
 	// 3. last is the certificate authenticator
 	authenticators := []authenticator.Request{}
 	authenticators = append(authenticators, delegatingauthenticator.New(s.uncachedVirtualClient))
+	if ctx.Config.ControlPlane.Proxy.OIDC.Enabled {
+		oidcAuthenticator, err := oidcauthenticator.New(ctx.Config.ControlPlane.Proxy.OIDC)
+		if err != nil {
+			return errors.Wrap(err, "create oidc authenticator")
+		}
+
+		authenticators = append(authenticators, oidcAuthenticator)
+	}
 	authenticators = append(authenticators, platformauthenticator.Default)
 	authenticators = append(authenticators, serverConfig.Authentication.Authenticator)
 	serverConfig.Authentication.Authenticator = unionauthentication.NewFailOnError(authenticators...)
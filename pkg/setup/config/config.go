@@ -13,6 +13,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/config"
 	"github.com/loft-sh/vcluster/pkg/pro"
 	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
+	"github.com/loft-sh/vcluster/pkg/util/nametemplate"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -81,7 +82,12 @@ func InitClients(vConfig *config.VirtualClusterConfig) error {
 			return err
 		}
 	} else {
-		translate.Default = translate.NewSingleNamespaceTranslator(vConfig.HostNamespace)
+		nameTemplate, err := nametemplate.Parse(vConfig.Sync.ToHost.Naming.Template)
+		if err != nil {
+			return fmt.Errorf("sync.toHost.naming.template: %w", err)
+		}
+
+		translate.Default = translate.NewSingleNamespaceTranslatorWithNameTemplate(vConfig.HostNamespace, nameTemplate)
 	}
 
 	return nil
@@ -130,7 +136,7 @@ func EnsureBackingStoreChanges(ctx context.Context, client kubernetes.Interface,
 	if ok, err := CheckUsingSecretAnnotation(ctx, client, name, namespace, backingStoreType); err != nil {
 		return fmt.Errorf("using secret annotations: %w", err)
 	} else if ok {
-		if err := updateSecretAnnotations(ctx, client, name, namespace, backingStoreType); err != nil {
+		if err := UpdateSecretAnnotations(ctx, client, name, namespace, backingStoreType); err != nil {
 			return fmt.Errorf("update secret annotations: %w", err)
 		}
 
@@ -166,8 +172,8 @@ func CheckUsingSecretAnnotation(ctx context.Context, client kubernetes.Interface
 	return okCounter == 1, nil
 }
 
-// updateSecretAnnotations udates the vCluster's config secret with the currently used distro and backing store type.
-func updateSecretAnnotations(ctx context.Context, client kubernetes.Interface, name, namespace string, backingStoreType vclusterconfig.StoreType) error {
+// UpdateSecretAnnotations updates the vCluster's config secret with the currently used distro and backing store type.
+func UpdateSecretAnnotations(ctx context.Context, client kubernetes.Interface, name, namespace string, backingStoreType vclusterconfig.StoreType) error {
 	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		secret, err := client.CoreV1().Secrets(namespace).Get(ctx, "vc-config-"+name, metav1.GetOptions{})
 		if err != nil {
@@ -18,9 +18,11 @@ import (
 	"github.com/loft-sh/vcluster/pkg/scheme"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	"github.com/loft-sh/vcluster/pkg/telemetry"
+	"github.com/loft-sh/vcluster/pkg/tracing"
 	"github.com/loft-sh/vcluster/pkg/util/blockingcacheclient"
 	"github.com/loft-sh/vcluster/pkg/util/pluginhookclient"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery"
@@ -50,6 +52,10 @@ func NewControllerContext(ctx context.Context, options *config.VirtualClusterCon
 		return nil, err
 	}
 
+	// trace requests to the host and virtual cluster
+	tracing.WrapConfig(options.HostConfig)
+	tracing.WrapConfig(virtualConfig)
+
 	// start plugins
 	if !plugin.IsPlugin && !options.ControlPlane.Standalone.Enabled {
 		err = startPlugins(ctx, virtualConfig, virtualRawConfig, options)
@@ -115,10 +121,12 @@ func NewControllerContext(ctx context.Context, options *config.VirtualClusterCon
 func getLocalCacheOptions(options *config.VirtualClusterConfig) cache.Options {
 	// is multi namespace mode?
 	defaultNamespaces := make(map[string]cache.Config)
-	gatewayNamespaces := make(map[string]cache.Config)
+	objectNamespaces := make(map[client.Object]map[string]cache.Config)
 	if !options.Sync.ToHost.Namespaces.Enabled {
 		defaultNamespaces[options.HostNamespace] = cache.Config{}
-		gatewayNamespaces = gatewaySourceNamespaces(options)
+		objectNamespaces[&gatewayv1.Gateway{}] = gatewaySourceNamespaces(options)
+		objectNamespaces[&corev1.ConfigMap{}] = toHostMappingNamespaces(options.Sync.ToHost.ConfigMaps)
+		objectNamespaces[&corev1.Secret{}] = toHostMappingNamespaces(options.Sync.ToHost.Secrets)
 	}
 	// do we need access to another namespace to export the kubeconfig ?
 	// we will need access to all the objects that the vcluster usually has access to
@@ -129,18 +137,23 @@ func getLocalCacheOptions(options *config.VirtualClusterConfig) cache.Options {
 		}
 	}
 
-	if len(defaultNamespaces) == 0 && len(gatewayNamespaces) == 0 {
+	if len(defaultNamespaces) == 0 {
 		return cache.Options{DefaultNamespaces: nil}
 	}
 
+	// some objects are also watched in other namespaces
 	cacheOptions := cache.Options{DefaultNamespaces: defaultNamespaces}
-	if len(gatewayNamespaces) > 0 {
+	for obj, namespaces := range objectNamespaces {
+		if len(namespaces) == 0 {
+			continue
+		}
 		for namespace := range defaultNamespaces {
-			gatewayNamespaces[namespace] = cache.Config{}
+			namespaces[namespace] = cache.Config{}
 		}
-		cacheOptions.ByObject = map[client.Object]cache.ByObject{
-			&gatewayv1.Gateway{}: {Namespaces: gatewayNamespaces},
+		if cacheOptions.ByObject == nil {
+			cacheOptions.ByObject = map[client.Object]cache.ByObject{}
 		}
+		cacheOptions.ByObject[obj] = cache.ByObject{Namespaces: namespaces}
 	}
 
 	return cacheOptions
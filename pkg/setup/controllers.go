@@ -19,6 +19,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/plugin"
 	"github.com/loft-sh/vcluster/pkg/pro"
 	"github.com/loft-sh/vcluster/pkg/specialservices"
+	"github.com/loft-sh/vcluster/pkg/sqlite"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	syncertypes "github.com/loft-sh/vcluster/pkg/syncer/types"
 	"github.com/loft-sh/vcluster/pkg/util/kubeconfig"
@@ -27,6 +28,7 @@ import (
 	"github.com/mitchellh/go-homedir"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -39,6 +41,7 @@ import (
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 func StartControllers(controllerContext *synccontext.ControllerContext, syncers []syncertypes.Object) error {
@@ -51,6 +54,12 @@ func StartControllers(controllerContext *synccontext.ControllerContext, syncers
 		return err
 	}
 
+	// make sure we can sync to the host namespaces of sync.toHost mappings
+	err = checkToHostMappingAccess(controllerContext.Context, controllerContext.Config.HostClient, controllerContext.Config.Sync.ToHost)
+	if err != nil {
+		return err
+	}
+
 	// register init manifests configmap watcher controller
 	err = deploy.RegisterInitManifestsController(controllerContext)
 	if err != nil {
@@ -144,6 +153,12 @@ func StartControllers(controllerContext *synccontext.ControllerContext, syncers
 		return fmt.Errorf("failed to delete previouly synced resources: %w", err)
 	}
 
+	// start embedded database monitoring
+	err = startSQLiteMonitor(controllerContext)
+	if err != nil {
+		return fmt.Errorf("start embedded database monitor: %w", err)
+	}
+
 	// ensure kubeadm setup
 	err = pro.StartPrivateNodesMode(controllerContext)
 	if err != nil {
@@ -155,6 +170,34 @@ func StartControllers(controllerContext *synccontext.ControllerContext, syncers
 	return nil
 }
 
+func startSQLiteMonitor(controllerContext *synccontext.ControllerContext) error {
+	databaseFile := sqlite.DatabaseFile(controllerContext.Config)
+	if databaseFile == "" {
+		return nil
+	}
+
+	err := sqlite.RegisterMetrics(ctrlmetrics.Registry)
+	if err != nil {
+		return fmt.Errorf("register metrics: %w", err)
+	}
+
+	monitor := &sqlite.Monitor{
+		File:        databaseFile,
+		Maintenance: controllerContext.Config.ControlPlane.BackingStore.Database.Maintenance,
+	}
+	if controllerContext.HostManager != nil && os.Getenv("POD_NAME") != "" {
+		monitor.Recorder = controllerContext.HostManager.GetEventRecorderFor("vcluster-sqlite-monitor")
+		monitor.Pod = &corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Name:       os.Getenv("POD_NAME"),
+			Namespace:  controllerContext.Config.HostNamespace,
+		}
+	}
+
+	return monitor.Start(controllerContext)
+}
+
 func ApplyCoreDNS(controllerContext *synccontext.ControllerContext) {
 	_ = wait.ExponentialBackoffWithContext(controllerContext.Context, wait.Backoff{Duration: time.Second, Factor: 1.5, Cap: time.Minute, Steps: math.MaxInt32}, func(ctx context.Context) (bool, error) {
 		dnsDeployment := &appsv1.Deployment{}
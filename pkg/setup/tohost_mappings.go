@@ -0,0 +1,75 @@
+package setup
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/mappings/resources"
+	"github.com/loft-sh/vcluster/pkg/util/clienthelper"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+)
+
+var (
+	// toHostMappingNamespaceVerbs are the verbs the syncer needs in the host namespaces objects are mapped to
+	toHostMappingNamespaceVerbs = []string{"list", "watch", "create"}
+
+	// toHostMappingObjectVerbs are the verbs the syncer needs on the mapped host objects
+	toHostMappingObjectVerbs = []string{"get", "update", "patch", "delete"}
+)
+
+// toHostMappingNamespaces returns the host namespaces objects are synced to by sync.toHost mappings. Only the objects
+// of this virtual cluster are watched in there, as these namespaces are usually shared.
+func toHostMappingNamespaces(resource vclusterconfig.SyncAllResource) map[string]cache.Config {
+	if !resource.Enabled {
+		return nil
+	}
+
+	namespaces := map[string]cache.Config{}
+	for _, namespace := range resource.Mappings.HostNamespaces() {
+		namespaces[namespace] = cache.Config{
+			LabelSelector: labels.SelectorFromSet(labels.Set{translate.MarkerLabel: resources.ToHostMappingMarker()}),
+		}
+	}
+
+	return namespaces
+}
+
+// checkToHostMappingAccess makes sure the virtual cluster has the permissions to sync objects to all host namespaces
+// they are mapped to, so missing RBAC is reported on startup instead of on every sync
+func checkToHostMappingAccess(ctx context.Context, hostClient kubernetes.Interface, sync vclusterconfig.SyncToHost) error {
+	for _, resource := range []struct {
+		path     string
+		config   vclusterconfig.SyncAllResource
+		resource string
+	}{
+		{path: "sync.toHost.configMaps.mappings", config: sync.ConfigMaps, resource: "configmaps"},
+		{path: "sync.toHost.secrets.mappings", config: sync.Secrets, resource: "secrets"},
+	} {
+		if !resource.config.Enabled {
+			continue
+		}
+
+		for _, namespace := range resource.config.Mappings.HostNamespaces() {
+			err := clienthelper.CheckAccess(ctx, hostClient, namespace, "", resource.resource, toHostMappingNamespaceVerbs...)
+			if err != nil {
+				return fmt.Errorf("%s: %w", resource.path, err)
+			}
+		}
+		for _, target := range slices.Sorted(maps.Values(resource.config.Mappings.ByName)) {
+			namespace, name, _ := strings.Cut(target, "/")
+			err := clienthelper.CheckObjectAccess(ctx, hostClient, namespace, "", resource.resource, name, toHostMappingObjectVerbs...)
+			if err != nil {
+				return fmt.Errorf("%s: %w", resource.path, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package setup
+
+import (
+	"context"
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	pkgconfig "github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestLocalCacheWatchesToHostMappingNamespaces(t *testing.T) {
+	options := &pkgconfig.VirtualClusterConfig{}
+	options.HostNamespace = "loft-default-v-test"
+	translate.Default = translate.NewSingleNamespaceTranslator(options.HostNamespace)
+	options.Sync.ToHost.Secrets.Enabled = true
+	options.Sync.ToHost.Secrets.Mappings.ByName = map[string]string{
+		"team-a/registry": "shared-registry/team-a-registry",
+	}
+	options.Sync.ToHost.ConfigMaps.Enabled = true
+
+	cacheOptions := getLocalCacheOptions(options)
+	if _, ok := cacheOptions.DefaultNamespaces["shared-registry"]; ok {
+		t.Fatalf("expected default local cache not to watch mapped namespace, got %#v", cacheOptions.DefaultNamespaces)
+	}
+
+	found := false
+	for obj, byObject := range cacheOptions.ByObject {
+		switch obj.(type) {
+		case *corev1.Secret:
+			found = true
+			for _, namespace := range []string{"loft-default-v-test", "shared-registry"} {
+				if _, ok := byObject.Namespaces[namespace]; !ok {
+					t.Fatalf("expected Secret cache to watch namespace %q, got %#v", namespace, byObject.Namespaces)
+				}
+			}
+			if byObject.Namespaces["loft-default-v-test"].LabelSelector != nil {
+				t.Fatalf("expected Secret cache to watch all secrets in the vCluster namespace, got %v", byObject.Namespaces["loft-default-v-test"].LabelSelector)
+			}
+			expectedSelector := translate.MarkerLabel + "=" + translate.SafeConcatName("loft-default-v-test", "x", translate.VClusterName)
+			if selector := byObject.Namespaces["shared-registry"].LabelSelector; selector == nil || selector.String() != expectedSelector {
+				t.Fatalf("expected Secret cache to only watch secrets of the vCluster in mapped namespace, got %v", selector)
+			}
+		case *corev1.ConfigMap:
+			t.Fatalf("expected no ConfigMap cache config without mappings, got %#v", byObject.Namespaces)
+		}
+	}
+	if !found {
+		t.Fatalf("expected Secret cache ByObject config, got %#v", cacheOptions.ByObject)
+	}
+}
+
+func TestCheckToHostMappingAccess(t *testing.T) {
+	sync := vclusterconfig.SyncToHost{}
+	sync.Secrets.Enabled = true
+	sync.Secrets.Mappings.ByName = map[string]string{
+		"team-a/registry": "shared-registry/team-a-registry",
+	}
+
+	fakeClient := fake.NewClientset()
+	fakeClient.PrependReactor("create", "selfsubjectaccessreviews", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		review := action.(clienttesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Verb != "delete"
+		return true, review, nil
+	})
+
+	err := checkToHostMappingAccess(context.Background(), fakeClient, sync)
+	expected := "sync.toHost.secrets.mappings: not allowed to delete secrets team-a-registry in namespace shared-registry"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected error %q, got %v", expected, err)
+	}
+
+	sync.Secrets.Enabled = false
+	err = checkToHostMappingAccess(context.Background(), fakeClient, sync)
+	if err != nil {
+		t.Fatalf("expected no access check for disabled secrets sync, got %v", err)
+	}
+}
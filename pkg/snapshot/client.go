@@ -44,8 +44,12 @@ const (
 )
 
 type Client struct {
-	Request  *snapshotapi.Request
-	Options  snapshotapi.Options
+	Request *snapshotapi.Request
+	Options snapshotapi.Options
+
+	// Paused is set if the vCluster is paused, the embedded backing store is started by the client then
+	Paused bool
+
 	skipKeys map[string]struct{}
 }
 
@@ -125,8 +129,8 @@ func (c *Client) Run(ctx context.Context, vConfig *config.VirtualClusterConfig)
 		return err
 	}
 
-	// create new etcd client
-	etcdClient, err := newEtcdClient(ctx, vConfig, false)
+	// create new etcd client, a paused vCluster doesn't serve its embedded backing store, so start it like on restore
+	etcdClient, err := newEtcdClient(ctx, vConfig, c.Paused)
 	if err != nil {
 		return fmt.Errorf("failed to create etcd client: %w", err)
 	}
@@ -0,0 +1,241 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/etcd"
+	"github.com/loft-sh/vcluster/pkg/k8s"
+	"github.com/loft-sh/vcluster/pkg/pro"
+	"github.com/loft-sh/vcluster/pkg/scheme"
+	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/runtime/serializer/protobuf"
+	"k8s.io/klog/v2"
+)
+
+// migrateKineSocket is where the source database is served during a migration, so it does not conflict with
+// the kine instance of a database target that listens on the default socket.
+var migrateKineSocket = filepath.Join(constants.DataDir, "kine-migrate.sock")
+
+// keyValuePutter is the write surface copyKeyValues needs.
+type keyValuePutter interface {
+	Put(ctx context.Context, key string, value []byte) (int64, error)
+}
+
+// MigrationTargetConfig returns a copy of vConfig that uses the given backing store instead of its current one.
+func MigrationTargetConfig(vConfig *config.VirtualClusterConfig, backingStore vclusterconfig.BackingStore) *config.VirtualClusterConfig {
+	targetConfig := *vConfig
+	targetConfig.ControlPlane.BackingStore = backingStore
+	return &targetConfig
+}
+
+// MigrateBackingStore copies all keys from the current backing store of the vCluster into the given backing store.
+// The target store is emptied first and, for the stores vCluster owns on disk, its revision is bumped above the
+// revision of the source. The vCluster has to be paused while migrating.
+func MigrateBackingStore(ctx context.Context, vConfig *config.VirtualClusterConfig, backingStore vclusterconfig.BackingStore) (retErr error) {
+	targetConfig := MigrationTargetConfig(vConfig, backingStore)
+	sourceType, targetType := vConfig.BackingStoreType(), targetConfig.BackingStoreType()
+	if sourceType == targetType {
+		return fmt.Errorf("vCluster %s already uses %s as backing store", vConfig.Name, sourceType)
+	}
+
+	// set global vCluster name
+	translate.VClusterName = vConfig.Name
+
+	// start the source store
+	klog.Infof("Migrating backing store from %s to %s...", sourceType, targetType)
+	sourceClient, stopSource, err := newMigrateSourceClient(ctx, vConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create source etcd client: %w", err)
+	}
+	defer stopSource()
+
+	sourceRevision, err := sourceClient.CurrentRevision(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get current revision of source: %w", err)
+	}
+
+	// create new etcd client that will delete the existing data / recreate the database of the target
+	targetClient, revertBackup, err := newRestoreEtcdClient(ctx, targetConfig, sourceRevision)
+	if err != nil {
+		revertBackup()
+		return fmt.Errorf("failed to create target etcd client: %w", err)
+	}
+	defer targetClient.Close()
+
+	// revert backup if there is an error
+	defer func() {
+		if retErr != nil {
+			klog.Errorf("Reverting target backing store from backup due to error: %v", retErr)
+			revertBackup()
+		}
+	}()
+
+	migratedKeys, latestRevision, err := copyKeyValues(ctx, sourceClient, targetClient, !vConfig.PrivateNodes.Enabled)
+	if err != nil {
+		return err
+	}
+
+	// rather unlikely but a compaction of 0 returns an error
+	if migratedKeys == 0 {
+		klog.Info("Skipping compaction because of 0 etcd keys migrated")
+		return nil
+	}
+	if latestRevision < sourceRevision {
+		klog.Warningf("Revision of %s (%d) is below the revision of %s (%d), clients with cached resource versions need to relist", targetType, latestRevision, sourceType, sourceRevision)
+	}
+
+	// compact the database until that revision
+	klog.Infof("Compact etcd database until revision %d", latestRevision)
+	err = targetClient.Compact(ctx, latestRevision)
+	if err != nil {
+		return fmt.Errorf("compact etcd database: %w", err)
+	}
+
+	klog.Infof("Successfully migrated %d etcd keys from %s to %s", migratedKeys, sourceType, targetType)
+	return nil
+}
+
+// copyKeyValues streams all keys from src into dst and returns the number of copied keys and the revision of the last write
+func copyKeyValues(ctx context.Context, src keyValueSource, dst keyValuePutter, transformPods bool) (int, int64, error) {
+	decoder := serializer.NewCodecFactory(scheme.Scheme).UniversalDeserializer()
+	encoder := protobuf.NewSerializer(scheme.Scheme, scheme.Scheme)
+
+	copiedKeys := 0
+	latestRevision := int64(0)
+	listChan := src.ListStream(ctx, "/")
+	for {
+		var obj *etcd.ValueOrError
+		select {
+		case <-ctx.Done():
+			return copiedKeys, latestRevision, fmt.Errorf("context: %w", ctx.Err())
+		case obj = <-listChan:
+		}
+		if obj == nil {
+			// an aborted source just closes its channel
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return copiedKeys, latestRevision, fmt.Errorf("context: %w", ctxErr)
+			}
+
+			return copiedKeys, latestRevision, nil
+		} else if obj.Error != nil {
+			return copiedKeys, latestRevision, fmt.Errorf("failed to retrieve etcd items: %w", obj.Error)
+		}
+
+		// snapshot metadata persisted by a previous restore belongs to the old store
+		key, value := string(obj.Value.Key), obj.Value.Data
+		if strings.HasPrefix(key, SnapshotMetadataPrefix) {
+			continue
+		}
+
+		// transform pods to make sure they are not deleted on start
+		if transformPods && strings.HasPrefix(key, podPrefix) {
+			var err error
+			value, err = transformPod(value, decoder, encoder)
+			if err != nil {
+				return copiedKeys, latestRevision, fmt.Errorf("transform value of %s: %w", key, err)
+			}
+		}
+
+		klog.V(1).Infof("Migrate key %s", key)
+		revision, err := dst.Put(ctx, key, value)
+		if err != nil {
+			return copiedKeys, latestRevision, fmt.Errorf("migrate etcd key %s: %w", key, err)
+		}
+		latestRevision = revision
+
+		// print status update
+		copiedKeys++
+		if copiedKeys%100 == 0 {
+			klog.Infof("Migrated %d keys", copiedKeys)
+		}
+	}
+}
+
+// newMigrateSourceClient starts the current backing store of the vCluster if needed and returns a client for it
+// together with a function that closes the client and stops the store again.
+func newMigrateSourceClient(ctx context.Context, vConfig *config.VirtualClusterConfig) (etcdClient etcd.Client, stopSource func(), err error) {
+	storeCtx, cancel := context.WithCancel(ctx)
+	stop := func() {
+		cancel()
+	}
+	defer func() {
+		if err != nil {
+			stop()
+		}
+	}()
+
+	switch vConfig.BackingStoreType() {
+	case vclusterconfig.StoreTypeEmbeddedDatabase:
+		dataSource := vConfig.ControlPlane.BackingStore.Database.Embedded.DataSource
+		if dataSource == "" {
+			dataSource = fmt.Sprintf("sqlite://%s%s", constants.K8sSqliteDatabase, k8s.SQLiteParams)
+		}
+
+		// remove stale kine socket from a previous run to avoid "address already in use" errors
+		_ = os.Remove(migrateKineSocket)
+		klog.Info("Starting kine for the embedded database...")
+		doneChan := k8s.StartKineWithDone(storeCtx, dataSource, "unix://"+migrateKineSocket, nil,
+			// disable the kine metrics listener, it would conflict with the kine of a database target
+			[]string{"--metrics-bind-address=0"},
+		)
+		stop = func() {
+			cancel()
+			<-doneChan
+		}
+
+		etcdClient, err = etcd.New(ctx, nil, "unix://"+migrateKineSocket)
+	case vclusterconfig.StoreTypeExternalDatabase:
+		if vConfig.ControlPlane.BackingStore.Database.External.Connector != "" {
+			_, err = generateCertificates(ctx, vConfig)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get certificates: %w", err)
+			}
+
+			err = pro.LicenseInit(ctx, vConfig)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get license: %w", err)
+			}
+		}
+
+		var (
+			endpoint     string
+			certificates *etcd.Certificates
+		)
+		endpoint, certificates, err = pro.ConfigureExternalDatabase(storeCtx, "unix://"+migrateKineSocket, vConfig, false)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		etcdClient, err = etcd.New(ctx, certificates, endpoint)
+	case vclusterconfig.StoreTypeEmbeddedEtcd:
+		var stopEtcd func()
+		stopEtcd, err = startEmbeddedEtcd(storeCtx, vConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		stop = func() {
+			stopEtcd()
+			cancel()
+		}
+
+		etcdClient, err = newEtcdClient(ctx, vConfig, false)
+	case vclusterconfig.StoreTypeDeployedEtcd, vclusterconfig.StoreTypeExternalEtcd:
+		etcdClient, err = newEtcdClient(ctx, vConfig, true)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return etcdClient, func() {
+		_ = etcdClient.Close()
+		stop()
+	}, nil
+}
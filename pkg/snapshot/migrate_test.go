@@ -0,0 +1,81 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/vcluster/pkg/etcd"
+)
+
+// fakeKeyValuePutter records the written keys and hands out increasing revisions.
+type fakeKeyValuePutter struct {
+	revision int64
+	values   map[string]string
+	putErr   error
+}
+
+func (f *fakeKeyValuePutter) Put(_ context.Context, key string, value []byte) (int64, error) {
+	if f.putErr != nil {
+		return 0, f.putErr
+	}
+
+	f.revision++
+	f.values[key] = string(value)
+	return f.revision, nil
+}
+
+func TestCopyKeyValues(t *testing.T) {
+	t.Parallel()
+
+	src := &fakeKeyValueSource{
+		revision: 5000,
+		values: []etcd.Value{
+			{Key: []byte("/registry/configmaps/default/a"), Data: []byte("va")},
+			{Key: []byte(RevisionStoreKey), Data: []byte("4000")},
+			{Key: []byte("/registry/secrets/default/b"), Data: []byte("vb")},
+		},
+	}
+	dst := &fakeKeyValuePutter{revision: 6000, values: map[string]string{}}
+
+	copiedKeys, latestRevision, err := copyKeyValues(t.Context(), src, dst, true)
+	if err != nil {
+		t.Fatalf("copyKeyValues failed: %v", err)
+	}
+	if copiedKeys != 2 {
+		t.Errorf("expected 2 copied keys, got %d", copiedKeys)
+	}
+	if latestRevision != 6002 {
+		t.Errorf("expected latest revision 6002, got %d", latestRevision)
+	}
+	if _, ok := dst.values[RevisionStoreKey]; ok {
+		t.Errorf("expected snapshot metadata to be skipped")
+	}
+	if dst.values["/registry/configmaps/default/a"] != "va" || dst.values["/registry/secrets/default/b"] != "vb" {
+		t.Errorf("unexpected values %v", dst.values)
+	}
+}
+
+func TestCopyKeyValues_Errors(t *testing.T) {
+	t.Parallel()
+
+	listErr := errors.New("list failed")
+	_, _, err := copyKeyValues(t.Context(), &fakeKeyValueSource{listErr: listErr}, &fakeKeyValuePutter{values: map[string]string{}}, true)
+	if !errors.Is(err, listErr) {
+		t.Errorf("expected list error, got %v", err)
+	}
+
+	putErr := errors.New("put failed")
+	src := &fakeKeyValueSource{values: []etcd.Value{{Key: []byte("/registry/a"), Data: []byte("va")}}}
+	_, _, err = copyKeyValues(t.Context(), src, &fakeKeyValuePutter{putErr: putErr}, true)
+	if !errors.Is(err, putErr) || !strings.Contains(err.Error(), "/registry/a") {
+		t.Errorf("expected put error for /registry/a, got %v", err)
+	}
+
+	src = &fakeKeyValueSource{values: []etcd.Value{{Key: []byte(podPrefix + "default/a"), Data: []byte("not a pod")}}}
+	_, _, err = copyKeyValues(t.Context(), src, &fakeKeyValuePutter{values: map[string]string{}}, true)
+	if err == nil || !strings.Contains(err.Error(), "transform value") {
+		t.Errorf("expected transform error, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"os/signal"
 	"slices"
@@ -41,6 +42,10 @@ type Options struct {
 	Image            string
 	ServiceAccount   string
 	ImagePullSecrets []string
+
+	// SecretEnv are environment variables that may hold credentials. They are stored in the
+	// snapshot options Secret and injected via secretKeyRef instead of the Pod spec.
+	SecretEnv map[string]string
 }
 
 func AddFlags(fs *pflag.FlagSet, podOptions *Options, isRestore bool) {
@@ -86,6 +91,9 @@ func SnapshotExec(
 		constants.VClusterStorageOptionsEnv + "=" + optionsString,
 		"POD_NAME=" + vCluster.Name + "-0",
 	}, podOptions.Env...)
+	for _, name := range slices.Sorted(maps.Keys(podOptions.SecretEnv)) {
+		envVariables = append(envVariables, name+"="+shellQuote(podOptions.SecretEnv[name]))
+	}
 
 	// run the command
 	return podhelper.ExecStream(ctx, kubeConfig, &podhelper.ExecStreamOptions{
@@ -149,7 +157,7 @@ func RunSnapshotPod(
 
 	// store the snapshot options (which may contain object store credentials) in a
 	// Secret instead of the Pod spec, injected into the pod via a secretKeyRef.
-	optionsSecret, err := createOptionsSecret(ctx, kubeClient, vCluster, secretName, snapshotOptions)
+	optionsSecret, err := createOptionsSecret(ctx, kubeClient, vCluster, secretName, snapshotOptions, podOptions.SecretEnv)
 	if err != nil {
 		return err
 	}
@@ -255,6 +263,18 @@ func CreateSnapshotPod(
 		},
 	})
 
+	for _, name := range slices.Sorted(maps.Keys(podOptions.SecretEnv)) {
+		env = append(env, corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: optionsSecretName},
+					Key:                  name,
+				},
+			},
+		})
+	}
+
 	// this is needed for embedded etcd as it otherwise wouldn't
 	// start the embedded etcd cluster correctly
 	env = slices.DeleteFunc(env, func(envVar corev1.EnvVar) bool {
@@ -417,10 +437,15 @@ func ToOptionsString(options *snapshotapi.Options) (string, error) {
 	return base64.StdEncoding.EncodeToString(jsonBytes), nil
 }
 
+// shellQuote quotes value for the sh -c command of SnapshotExec
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
 // createOptionsSecret stores the snapshot options (which may include object store
-// credentials) in a Secret so they don't appear as plaintext in the snapshot Pod
-// spec. The stored value is the same base64 string the in-pod reader expects.
-func createOptionsSecret(ctx context.Context, kubeClient kubernetes.Interface, vCluster *find.VCluster, name string, snapshotOptions *snapshotapi.Options) (*corev1.Secret, error) {
+// credentials) and secretEnv in a Secret so they don't appear as plaintext in the
+// snapshot Pod spec. The stored options are the same base64 string the in-pod reader expects.
+func createOptionsSecret(ctx context.Context, kubeClient kubernetes.Interface, vCluster *find.VCluster, name string, snapshotOptions *snapshotapi.Options, secretEnv map[string]string) (*corev1.Secret, error) {
 	optionsString, err := ToOptionsString(snapshotOptions)
 	if err != nil {
 		return nil, err
@@ -436,6 +461,9 @@ func createOptionsSecret(ctx context.Context, kubeClient kubernetes.Interface, v
 			constants.VClusterStorageOptionsEnv: []byte(optionsString),
 		},
 	}
+	for envName, value := range secretEnv {
+		secret.Data[envName] = []byte(value)
+	}
 
 	return kubeClient.CoreV1().Secrets(vCluster.Namespace).Create(ctx, secret, metav1.CreateOptions{})
 }
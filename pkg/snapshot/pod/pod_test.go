@@ -55,7 +55,8 @@ func TestCreateSnapshotPodStoresCredentialsInSecret(t *testing.T) {
 	}
 
 	const secretName, podName = "vcluster-snapshot-options-test", "vcluster-snapshot-test"
-	secret, err := createOptionsSecret(context.Background(), kubeClient, vCluster, secretName, snapshotOptions)
+	podOptions := &Options{SecretEnv: map[string]string{constants.VClusterMigrateBackingStoreEnv: `{"database":{"external":{"dataSource":"postgres://user:password@db"}}}`}}
+	secret, err := createOptionsSecret(context.Background(), kubeClient, vCluster, secretName, snapshotOptions, podOptions.SecretEnv)
 	if err != nil {
 		t.Fatalf("createOptionsSecret: %v", err)
 	}
@@ -63,7 +64,7 @@ func TestCreateSnapshotPodStoresCredentialsInSecret(t *testing.T) {
 		t.Fatalf("secret name = %q, want %q", secret.Name, secretName)
 	}
 
-	pod, err := CreateSnapshotPod(context.Background(), kubeClient, []string{"/vcluster", "snapshot"}, vCluster, &Options{}, podName, secret.Name, snapshotOptions, log.NewDiscardLogger(logrus.InfoLevel))
+	pod, err := CreateSnapshotPod(context.Background(), kubeClient, []string{"/vcluster", "snapshot"}, vCluster, podOptions, podName, secret.Name, snapshotOptions, log.NewDiscardLogger(logrus.InfoLevel))
 	if err != nil {
 		t.Fatalf("CreateSnapshotPod: %v", err)
 	}
@@ -112,6 +113,21 @@ func TestCreateSnapshotPodStoresCredentialsInSecret(t *testing.T) {
 	if !reflect.DeepEqual(roundTripped, snapshotOptions) {
 		t.Fatalf("stored options don't round-trip: got %+v, want %+v", roundTripped, snapshotOptions)
 	}
+
+	// secret env must be injected the same way
+	for _, e := range pod.Spec.Containers[0].Env {
+		if e.Name != constants.VClusterMigrateBackingStoreEnv {
+			continue
+		}
+		if e.Value != "" || e.ValueFrom == nil || e.ValueFrom.SecretKeyRef == nil || e.ValueFrom.SecretKeyRef.Name != secret.Name {
+			t.Fatalf("%s must be sourced from a secretKeyRef to %s, got %+v", e.Name, secret.Name, e)
+		}
+		if got := string(stored.Data[e.ValueFrom.SecretKeyRef.Key]); got != podOptions.SecretEnv[e.Name] {
+			t.Fatalf("stored %s = %q, want %q", e.Name, got, podOptions.SecretEnv[e.Name])
+		}
+		return
+	}
+	t.Fatalf("%s env var not set on snapshot container", constants.VClusterMigrateBackingStoreEnv)
 }
 
 // TestSetSecretOwner: the options Secret must gain a controller ownerReference
@@ -12,6 +12,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -35,18 +36,32 @@ import (
 	"go.etcd.io/etcd/server/v3/storage/mvcc"
 	"go.etcd.io/etcd/server/v3/storage/schema"
 	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/runtime/serializer/protobuf"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 )
 
 const (
 	podPrefix       = "/registry/pods/"
 	configMapPrefix = "/registry/configmaps/"
+	secretPrefix    = "/registry/secrets/"
 )
 
+// workloadPrefixes are the keys of the workloads that are scaled down or suspended with ScaleDownWorkloads
+var workloadPrefixes = []string{
+	"/registry/deployments/",
+	"/registry/statefulsets/",
+	"/registry/replicasets/",
+	"/registry/jobs/",
+	"/registry/cronjobs/",
+}
+
 // EtcdToKeyValueConversionLogMessage is logged when an etcd archive is converted
 // before restore. Exported so e2e specs can assert the conversion ran without
 // hardcoding the wording.
@@ -58,6 +73,20 @@ type RestoreClient struct {
 	etcdClient etcd.Client
 
 	NewVCluster bool
+
+	// FromHostNamespace is the host namespace of the vCluster the snapshot was taken from. If set together with
+	// NewVCluster, the mappings are kept and their host namespace is rewritten to the new host namespace instead
+	// of dropping them, so synced objects keep their host names.
+	FromHostNamespace string
+
+	// ScrubSecrets empties the values of all user secrets. The keys are kept, so workloads referencing them still start.
+	// System secrets such as service account tokens are kept as is. Helm release secrets are dropped, as they hold the
+	// values of the release which can contain credentials as well.
+	ScrubSecrets bool
+
+	// ScaleDownWorkloads scales deployments, statefulsets and replicasets to zero, suspends jobs and cronjobs and
+	// doesn't restore any pods
+	ScaleDownWorkloads bool
 }
 
 var (
@@ -446,15 +475,65 @@ func (o *RestoreClient) postRestoreSnapshotDataMutation(ctx context.Context, vCo
 			}
 		}
 
-		log.Info("Deleting old vcluster mappings")
-		if _, err := etcdClient.Delete(ctx, store.MappingsPrefix, clientv3.WithPrefix(), clientv3.WithRev(int64(0))); ignoreKeyNotFound(err) != nil {
-			return fmt.Errorf("failed to delete mappings prefix: %w", err)
+		if o.FromHostNamespace != "" {
+			log.Info("Rewriting host namespace of vcluster mappings", "from", o.FromHostNamespace, "to", vConfig.HostNamespace)
+			resp, err := etcdClient.Get(ctx, store.MappingsPrefix, clientv3.WithPrefix(), clientv3.WithRev(int64(0)))
+			if err != nil {
+				return fmt.Errorf("failed to get mappings: %w", err)
+			}
+
+			for _, kv := range resp.Kvs {
+				value, err := rewriteMappingHostNamespace(kv.Value, o.FromHostNamespace, vConfig.HostNamespace)
+				if err != nil {
+					return fmt.Errorf("failed to rewrite mapping %s: %w", string(kv.Key), err)
+				}
+				if _, err := etcdClient.Put(ctx, string(kv.Key), string(value)); err != nil {
+					return fmt.Errorf("failed to put mapping %s: %w", string(kv.Key), err)
+				}
+			}
+		} else {
+			log.Info("Deleting old vcluster mappings")
+			if _, err := etcdClient.Delete(ctx, store.MappingsPrefix, clientv3.WithPrefix(), clientv3.WithRev(int64(0))); ignoreKeyNotFound(err) != nil {
+				return fmt.Errorf("failed to delete mappings prefix: %w", err)
+			}
 		}
 	}
 
 	decoder := serializer.NewCodecFactory(scheme.Scheme).UniversalDeserializer()
 	encoder := protobuf.NewSerializer(scheme.Scheme, scheme.Scheme)
 
+	// scrub secrets and scale down workloads of a cloned vCluster
+	if o.ScaleDownWorkloads {
+		log.Info("Deleting pods of scaled down workloads")
+		if _, err := etcdClient.Delete(ctx, podPrefix, clientv3.WithPrefix()); ignoreKeyNotFound(err) != nil {
+			return fmt.Errorf("failed to delete pods: %w", err)
+		}
+	}
+	for _, prefix := range o.cloneTransformPrefixes() {
+		objectsCh, objectsErrCh := mirror.NewSyncer(etcdClient, prefix, 0).SyncBase(ctx)
+		for resp := range objectsCh {
+			log.Info("Transforming objects of cloned vCluster", "prefix", prefix, "count", len(resp.Kvs))
+			for _, kv := range resp.Kvs {
+				value, skip, err := o.transformClonedValue(string(kv.Key), kv.Value, decoder, encoder)
+				if err != nil {
+					return err
+				} else if skip {
+					if _, err := etcdClient.Delete(ctx, string(kv.Key)); ignoreKeyNotFound(err) != nil {
+						return fmt.Errorf("failed to delete %s: %w", string(kv.Key), err)
+					}
+					continue
+				}
+				if _, err := etcdClient.Put(ctx, string(kv.Key), string(value)); err != nil {
+					return fmt.Errorf("failed to put %s: %w", string(kv.Key), err)
+				}
+			}
+		}
+
+		for objectsErr := range objectsErrCh {
+			return fmt.Errorf("failed to sync %s: %w", prefix, objectsErr)
+		}
+	}
+
 	// transform pods to make sure they are not deleted on start
 	if !vConfig.PrivateNodes.Enabled {
 		podsCh, podsErrCh := mirror.NewSyncer(etcdClient, podPrefix, 0).SyncBase(ctx)
@@ -502,7 +581,7 @@ func (o *RestoreClient) restoreKeyValueSnapshot(ctx context.Context, vConfig *co
 	defer gzipReader.Close()
 
 	// create new etcd client that will delete the existing data / recreate the database
-	etcdClient, revertBackup, err := newRestoreEtcdClient(ctx, vConfig)
+	etcdClient, revertBackup, err := newRestoreEtcdClient(ctx, vConfig, 0)
 	if err != nil {
 		revertBackup()
 		return fmt.Errorf("failed to create etcd client: %w", err)
@@ -542,9 +621,14 @@ func (o *RestoreClient) restoreKeyValueSnapshot(ctx context.Context, vConfig *co
 
 		// transform value if we are restoring to a new vCluster
 		if o.NewVCluster {
-			// skip mappings
+			// skip mappings, unless the vCluster was moved to another host namespace
 			splitKey := strings.Split(string(key), "/")
-			if strings.HasPrefix(string(key), store.MappingsPrefix) {
+			if strings.HasPrefix(string(key), store.MappingsPrefix) && o.FromHostNamespace != "" {
+				value, err = rewriteMappingHostNamespace(value, o.FromHostNamespace, vConfig.HostNamespace)
+				if err != nil {
+					return fmt.Errorf("rewrite mapping %s: %w", string(key), err)
+				}
+			} else if strings.HasPrefix(string(key), store.MappingsPrefix) {
 				continue
 			} else if len(splitKey) == 5 && splitKey[2] == "configmaps" && splitKey[4] == "kube-root-ca.crt" {
 				// we will get separate certificates, so we need to skip these
@@ -552,6 +636,14 @@ func (o *RestoreClient) restoreKeyValueSnapshot(ctx context.Context, vConfig *co
 			}
 		}
 
+		// scrub secrets and scale down workloads of a cloned vCluster
+		value, skip, err := o.transformClonedValue(string(key), value, decoder, encoder)
+		if err != nil {
+			return err
+		} else if skip {
+			continue
+		}
+
 		// transform pods to make sure they are not deleted on start
 		if strings.HasPrefix(string(key), "/registry/pods/") {
 			// we need to only do this in shared nodes mode as otherwise kubelet will not update the status correctly
@@ -593,6 +685,104 @@ func (o *RestoreClient) restoreKeyValueSnapshot(ctx context.Context, vConfig *co
 	return nil
 }
 
+// rewriteMappingHostNamespace replaces the host namespace from with to in the host names of the mapping and its
+// references
+func rewriteMappingHostNamespace(value []byte, from, to string) ([]byte, error) {
+	mapping := &store.Mapping{}
+	err := json.Unmarshal(value, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	if mapping.HostName.Namespace == from {
+		mapping.HostName.Namespace = to
+	}
+	for i := range mapping.References {
+		if mapping.References[i].HostName.Namespace == from {
+			mapping.References[i].HostName.Namespace = to
+		}
+	}
+
+	return json.Marshal(mapping)
+}
+
+// cloneTransformPrefixes returns the key prefixes of the objects transformClonedValue changes
+func (o *RestoreClient) cloneTransformPrefixes() []string {
+	prefixes := []string{}
+	if o.ScrubSecrets {
+		prefixes = append(prefixes, secretPrefix)
+	}
+	if o.ScaleDownWorkloads {
+		prefixes = append(prefixes, workloadPrefixes...)
+	}
+
+	return prefixes
+}
+
+// transformClonedValue scrubs secrets and scales down workloads if requested. Pods aren't restored if workloads
+// are scaled down and helm releases aren't restored if secrets are scrubbed, skip is true for them.
+func (o *RestoreClient) transformClonedValue(key string, value []byte, decoder runtime.Decoder, encoder runtime.Encoder) ([]byte, bool, error) {
+	if o.ScaleDownWorkloads && strings.HasPrefix(key, podPrefix) {
+		return nil, true, nil
+	} else if !slices.ContainsFunc(o.cloneTransformPrefixes(), func(prefix string) bool { return strings.HasPrefix(key, prefix) }) {
+		return value, false, nil
+	}
+
+	obj, gvk, err := decoder.Decode(value, nil, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("decode %s: %w", key, err)
+	}
+
+	switch obj := obj.(type) {
+	case *corev1.Secret:
+		if obj.Type == helmReleaseSecretType {
+			return nil, true, nil
+		} else if !isUserSecret(obj) {
+			return value, false, nil
+		}
+		for dataKey := range obj.Data {
+			obj.Data[dataKey] = []byte{}
+		}
+	case *appsv1.Deployment:
+		obj.Spec.Replicas = ptr.To(int32(0))
+	case *appsv1.StatefulSet:
+		obj.Spec.Replicas = ptr.To(int32(0))
+	case *appsv1.ReplicaSet:
+		obj.Spec.Replicas = ptr.To(int32(0))
+	case *batchv1.Job:
+		obj.Spec.Suspend = ptr.To(true)
+	case *batchv1.CronJob:
+		obj.Spec.Suspend = ptr.To(true)
+	default:
+		return value, false, nil
+	}
+
+	// the protobuf encoding doesn't carry the type meta on the object itself
+	obj.GetObjectKind().SetGroupVersionKind(*gvk)
+	buf := &bytes.Buffer{}
+	err = encoder.Encode(obj, buf)
+	if err != nil {
+		return nil, false, fmt.Errorf("encode %s: %w", key, err)
+	}
+
+	return buf.Bytes(), false, nil
+}
+
+// helmReleaseSecretType is the type of the secrets helm stores its releases in
+const helmReleaseSecretType corev1.SecretType = "helm.sh/release.v1"
+
+// systemSecretTypes are secret types managed by Kubernetes within the virtual cluster, scrubbing them breaks the clone
+// instead of protecting user data
+var systemSecretTypes = []corev1.SecretType{
+	corev1.SecretTypeServiceAccountToken,
+	corev1.SecretTypeBootstrapToken,
+}
+
+// isUserSecret returns true if the secret holds user data that is scrubbed on clone
+func isUserSecret(secret *corev1.Secret) bool {
+	return secret.Namespace != metav1.NamespaceSystem && !slices.Contains(systemSecretTypes, secret.Type)
+}
+
 func transformPod(value []byte, decoder runtime.Decoder, encoder runtime.Encoder) ([]byte, error) {
 	// decode value
 	obj := &corev1.Pod{}
@@ -617,7 +807,9 @@ func transformPod(value []byte, decoder runtime.Decoder, encoder runtime.Encoder
 	return buf.Bytes(), nil
 }
 
-func newRestoreEtcdClient(ctx context.Context, vConfig *config.VirtualClusterConfig) (etcd.Client, func(), error) {
+// newRestoreEtcdClient creates a client for an emptied backing store. For the stores vCluster owns on disk the revision is
+// bumped above both the previous revision of the store and minRevision, so watches of existing clients are invalidated.
+func newRestoreEtcdClient(ctx context.Context, vConfig *config.VirtualClusterConfig, minRevision int64) (etcd.Client, func(), error) {
 	revertBackup := func() {}
 
 	// delete existing storage:
@@ -659,8 +851,8 @@ func newRestoreEtcdClient(ctx context.Context, vConfig *config.VirtualClusterCon
 		}
 
 		// set latest revision
-		if latestRevision > 0 {
-			err = setLatestRevisionSQLite(ctx, constants.K8sSqliteDatabase, latestRevision+BumpRevision)
+		if revision := bumpedRevision(latestRevision, minRevision); revision > 0 {
+			err = setLatestRevisionSQLite(ctx, constants.K8sSqliteDatabase, revision)
 			if err != nil {
 				return nil, revertBackup, fmt.Errorf("failed to set latest revision: %w", err)
 			}
@@ -686,8 +878,8 @@ func newRestoreEtcdClient(ctx context.Context, vConfig *config.VirtualClusterCon
 		}
 
 		// set latest revision
-		if latestRevision > 0 {
-			err = setLatestRevisionEtcd(ctx, vConfig, etcdDBPath, latestRevision+BumpRevision)
+		if revision := bumpedRevision(latestRevision, minRevision); revision > 0 {
+			err = setLatestRevisionEtcd(ctx, vConfig, etcdDBPath, revision)
 			if err != nil {
 				return nil, revertBackup, fmt.Errorf("failed to set latest revision: %w", err)
 			}
@@ -718,6 +910,17 @@ type prefixDeleter interface {
 	DeleteKeysWithPrefix(ctx context.Context, prefix string) error
 }
 
+// bumpedRevision returns the revision a recreated store should start at, which is BumpRevision above the latest
+// revision of the store and minRevision, or 0 if both are unset.
+func bumpedRevision(latestRevision, minRevision int64) int64 {
+	revision := max(latestRevision, minRevision)
+	if revision <= 0 {
+		return 0
+	}
+
+	return revision + BumpRevision
+}
+
 // deleteExistingData clears the store with the method its type requires, and
 // does nothing for stores whose files were already deleted.
 func deleteExistingData(ctx context.Context, client prefixDeleter, storeType vclusterconfig.StoreType) error {
@@ -2,8 +2,10 @@ package snapshot
 
 import (
 	"archive/tar"
+	"bytes"
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"errors"
 	"os"
 	"path/filepath"
@@ -13,6 +15,17 @@ import (
 
 	snapshotapi "github.com/loft-sh/api/v4/pkg/snapshot"
 	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/mappings/store"
+	"github.com/loft-sh/vcluster/pkg/scheme"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/runtime/serializer/protobuf"
+	"k8s.io/utils/ptr"
 )
 
 // This test verifies the wipe actually reaches the client method its store type
@@ -229,6 +242,146 @@ func TestSnapshotRestoreBumpRevision(t *testing.T) {
 	}
 }
 
+func TestBumpedRevision(t *testing.T) {
+	tests := []struct {
+		name           string
+		latestRevision int64
+		minRevision    int64
+		expected       int64
+	}{
+		{name: "new store", expected: 0},
+		{name: "existing store", latestRevision: 500, expected: 500 + BumpRevision},
+		{name: "migrated store", latestRevision: 500, minRevision: 800, expected: 800 + BumpRevision},
+		{name: "new migrated store", minRevision: 800, expected: 800 + BumpRevision},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := bumpedRevision(tt.latestRevision, tt.minRevision)
+			if result != tt.expected {
+				t.Errorf("bumpedRevision(%d, %d) = %d; want %d", tt.latestRevision, tt.minRevision, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRewriteMappingHostNamespace(t *testing.T) {
+	value := []byte(`{"Group":"","Version":"v1","Kind":"Pod","VirtualName":{"Namespace":"default","Name":"nginx"},"HostName":{"Namespace":"vcluster-old","Name":"nginx-x-default-x-test"},"references":[{"Group":"","Version":"v1","Kind":"ConfigMap","VirtualName":{"Namespace":"default","Name":"config"},"HostName":{"Namespace":"vcluster-old","Name":"config-x-default-x-test"}},{"Group":"","Version":"v1","Kind":"Namespace","VirtualName":{"Namespace":"","Name":"default"},"HostName":{"Namespace":"","Name":"vcluster-default"}}]}`)
+
+	rewritten, err := rewriteMappingHostNamespace(value, "vcluster-old", "vcluster-new")
+	if err != nil {
+		t.Fatalf("rewriteMappingHostNamespace() error = %v", err)
+	}
+
+	mapping := &store.Mapping{}
+	if err := json.Unmarshal(rewritten, mapping); err != nil {
+		t.Fatalf("unmarshal rewritten mapping: %v", err)
+	}
+	if mapping.HostName.Namespace != "vcluster-new" || mapping.HostName.Name != "nginx-x-default-x-test" {
+		t.Errorf("host name = %s; want vcluster-new/nginx-x-default-x-test", mapping.HostName)
+	}
+	if mapping.VirtualName.Namespace != "default" {
+		t.Errorf("virtual namespace = %s; want default", mapping.VirtualName.Namespace)
+	}
+	if len(mapping.References) != 2 {
+		t.Fatalf("references = %d; want 2", len(mapping.References))
+	}
+	if mapping.References[0].HostName.Namespace != "vcluster-new" {
+		t.Errorf("reference host namespace = %s; want vcluster-new", mapping.References[0].HostName.Namespace)
+	}
+	if mapping.References[1].HostName.Name != "vcluster-default" || mapping.References[1].HostName.Namespace != "" {
+		t.Errorf("cluster scoped reference = %s; want vcluster-default", mapping.References[1].HostName)
+	}
+}
+
+func TestTransformClonedValue(t *testing.T) {
+	decoder := serializer.NewCodecFactory(scheme.Scheme).UniversalDeserializer()
+	encoder := protobuf.NewSerializer(scheme.Scheme, scheme.Scheme)
+	encode := func(obj runtime.Object, gvk schema.GroupVersionKind) []byte {
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+		buf := &bytes.Buffer{}
+		if err := encoder.Encode(obj, buf); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+		return buf.Bytes()
+	}
+
+	restoreClient := &RestoreClient{ScrubSecrets: true, ScaleDownWorkloads: true}
+
+	// secret values are emptied, the keys are kept
+	secret := encode(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"}, Data: map[string][]byte{"password": []byte("secret")}}, corev1.SchemeGroupVersion.WithKind("Secret"))
+	value, skip, err := restoreClient.transformClonedValue("/registry/secrets/default/db", secret, decoder, encoder)
+	if err != nil || skip {
+		t.Fatalf("transformClonedValue() secret = %v, skip %v", err, skip)
+	}
+	obj, _, err := decoder.Decode(value, nil, nil)
+	if err != nil {
+		t.Fatalf("decode secret: %v", err)
+	}
+	if data := obj.(*corev1.Secret).Data; len(data) != 1 || len(data["password"]) != 0 {
+		t.Errorf("secret data = %v; want password key with empty value", data)
+	}
+
+	// helm releases hold the values of the release and are dropped
+	release := encode(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "sh.helm.release.v1.app.v1", Namespace: "default"}, Type: "helm.sh/release.v1", Data: map[string][]byte{"release": []byte("release")}}, corev1.SchemeGroupVersion.WithKind("Secret"))
+	if _, skip, err := restoreClient.transformClonedValue("/registry/secrets/default/sh.helm.release.v1.app.v1", release, decoder, encoder); err != nil || !skip {
+		t.Errorf("transformClonedValue() helm release skip %v, err %v; want skipped", skip, err)
+	}
+
+	// system secrets are kept
+	for _, systemSecret := range []*corev1.Secret{
+		{ObjectMeta: metav1.ObjectMeta{Name: "sa-token", Namespace: "default"}, Type: corev1.SecretTypeServiceAccountToken, Data: map[string][]byte{"token": []byte("token")}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "bootstrap", Namespace: "kube-system"}, Data: map[string][]byte{"key": []byte("key")}},
+	} {
+		encoded := encode(systemSecret, corev1.SchemeGroupVersion.WithKind("Secret"))
+		value, skip, err := restoreClient.transformClonedValue("/registry/secrets/"+systemSecret.Namespace+"/"+systemSecret.Name, encoded, decoder, encoder)
+		if err != nil || skip || !bytes.Equal(value, encoded) {
+			t.Errorf("transformClonedValue() secret %s changed, skip %v, err %v; want unchanged", systemSecret.Name, skip, err)
+		}
+	}
+
+	// deployments are scaled down
+	deployment := encode(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}, Spec: appsv1.DeploymentSpec{Replicas: ptr.To(int32(3))}}, appsv1.SchemeGroupVersion.WithKind("Deployment"))
+	value, _, err = restoreClient.transformClonedValue("/registry/deployments/default/web", deployment, decoder, encoder)
+	if err != nil {
+		t.Fatalf("transformClonedValue() deployment = %v", err)
+	}
+	obj, _, err = decoder.Decode(value, nil, nil)
+	if err != nil {
+		t.Fatalf("decode deployment: %v", err)
+	}
+	if replicas := obj.(*appsv1.Deployment).Spec.Replicas; replicas == nil || *replicas != 0 {
+		t.Errorf("deployment replicas = %v; want 0", replicas)
+	}
+
+	// cronjobs are suspended
+	cronJob := encode(&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default"}, Spec: batchv1.CronJobSpec{Schedule: "@daily"}}, batchv1.SchemeGroupVersion.WithKind("CronJob"))
+	value, _, err = restoreClient.transformClonedValue("/registry/cronjobs/default/backup", cronJob, decoder, encoder)
+	if err != nil {
+		t.Fatalf("transformClonedValue() cronjob = %v", err)
+	}
+	obj, _, err = decoder.Decode(value, nil, nil)
+	if err != nil {
+		t.Fatalf("decode cronjob: %v", err)
+	}
+	if suspend := obj.(*batchv1.CronJob).Spec.Suspend; suspend == nil || !*suspend {
+		t.Errorf("cronjob suspend = %v; want true", suspend)
+	}
+
+	// pods are skipped, other objects are kept as is
+	if _, skip, _ := restoreClient.transformClonedValue("/registry/pods/default/web-abc", []byte("pod"), decoder, encoder); !skip {
+		t.Errorf("transformClonedValue() pod not skipped")
+	}
+	if value, skip, err := restoreClient.transformClonedValue("/registry/configmaps/default/config", []byte("config"), decoder, encoder); err != nil || skip || string(value) != "config" {
+		t.Errorf("transformClonedValue() configmap = %q, skip %v, err %v; want unchanged", value, skip, err)
+	}
+
+	// nothing is decoded if no clone option is set
+	if value, skip, err := (&RestoreClient{}).transformClonedValue("/registry/secrets/default/db", []byte("secret"), decoder, encoder); err != nil || skip || string(value) != "secret" {
+		t.Errorf("transformClonedValue() without options = %q, skip %v, err %v; want unchanged", value, skip, err)
+	}
+}
+
 func TestGetSnapshotArchiveKind(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	databaseSizeGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "vcluster_sqlite_database_size_bytes",
+		Help: "Size of the embedded SQLite database file in bytes.",
+	})
+	walSizeGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "vcluster_sqlite_wal_size_bytes",
+		Help: "Size of the write-ahead log of the embedded SQLite database in bytes.",
+	})
+	rowsGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "vcluster_sqlite_rows",
+		Help: "Number of rows in the kine table of the embedded SQLite database.",
+	})
+	currentRevisionGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "vcluster_sqlite_current_revision",
+		Help: "Latest revision written to the embedded SQLite database.",
+	})
+	compactRevisionGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "vcluster_sqlite_compact_revision",
+		Help: "Revision the embedded SQLite database was compacted to.",
+	})
+	compactionLagGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "vcluster_sqlite_compaction_lag_revisions",
+		Help: "Number of revisions of the embedded SQLite database that were not compacted yet.",
+	})
+	freePagesGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "vcluster_sqlite_free_pages",
+		Help: "Number of unused pages of the embedded SQLite database.",
+	})
+	freeBytesGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "vcluster_sqlite_free_bytes",
+		Help: "Size of the unused pages of the embedded SQLite database in bytes.",
+	})
+	volumeCapacityGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "vcluster_sqlite_volume_capacity_bytes",
+		Help: "Capacity of the volume the embedded SQLite database is stored on in bytes.",
+	})
+	volumeUsedGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "vcluster_sqlite_volume_used_bytes",
+		Help: "Used bytes of the volume the embedded SQLite database is stored on.",
+	})
+	lastVacuumGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "vcluster_sqlite_last_vacuum_timestamp_seconds",
+		Help: "Unix timestamp of the last successful vacuum of the embedded SQLite database.",
+	})
+)
+
+// RegisterMetrics registers the embedded SQLite database metrics with the given registerer
+func RegisterMetrics(registerer prometheus.Registerer) error {
+	for _, collector := range []prometheus.Collector{
+		databaseSizeGauge,
+		walSizeGauge,
+		rowsGauge,
+		currentRevisionGauge,
+		compactRevisionGauge,
+		compactionLagGauge,
+		freePagesGauge,
+		freeBytesGauge,
+		volumeCapacityGauge,
+		volumeUsedGauge,
+		lastVacuumGauge,
+	} {
+		err := registerer.Register(collector)
+		if err != nil && !errors.As(err, &prometheus.AlreadyRegisteredError{}) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func recordStats(stats *Stats) {
+	databaseSizeGauge.Set(float64(stats.DatabaseSize))
+	walSizeGauge.Set(float64(stats.WALSize))
+	currentRevisionGauge.Set(float64(stats.CurrentRevision))
+	compactRevisionGauge.Set(float64(stats.CompactRevision))
+	compactionLagGauge.Set(float64(stats.CompactionLag()))
+	freePagesGauge.Set(float64(stats.FreePages))
+	freeBytesGauge.Set(float64(stats.FreeBytes()))
+}
+
+func recordVolumeUsage(usage *VolumeUsage) {
+	volumeCapacityGauge.Set(float64(usage.Capacity))
+	volumeUsedGauge.Set(float64(usage.Used))
+}
@@ -0,0 +1,188 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+const monitorInterval = time.Minute
+
+// Monitor records the health metrics of the embedded SQLite database and warns if its volume is nearly full. If
+// vacuuming is enabled, it also vacuums the database once within each maintenance window to return the pages freed by
+// the compaction of kine to the file system.
+type Monitor struct {
+	// File is the SQLite database file
+	File string
+	// Maintenance is the maintenance configuration of the database
+	Maintenance vclusterconfig.DatabaseMaintenance
+
+	// Recorder is used to emit events on Pod, can be nil
+	Recorder record.EventRecorder
+	// Pod is the vCluster pod the events are emitted on
+	Pod *corev1.ObjectReference
+
+	db           *sql.DB
+	failing      bool
+	volumeWarned bool
+
+	schedule   cron.Schedule
+	duration   time.Duration
+	lastWindow time.Time
+}
+
+// Start starts monitoring the database in the background until the context is canceled
+func (m *Monitor) Start(ctx context.Context) error {
+	if m.Maintenance.Vacuum.Enabled {
+		schedule, err := cron.ParseStandard(m.Maintenance.Vacuum.Schedule)
+		if err != nil {
+			return fmt.Errorf("parse vacuum schedule: %w", err)
+		}
+		duration, err := time.ParseDuration(m.Maintenance.Vacuum.Duration)
+		if err != nil {
+			return fmt.Errorf("parse vacuum duration: %w", err)
+		}
+
+		m.schedule = schedule
+		m.duration = duration
+	}
+
+	go func() {
+		wait.UntilWithContext(ctx, m.tick, monitorInterval)
+		if m.db != nil {
+			_ = m.db.Close()
+		}
+	}()
+
+	return nil
+}
+
+func (m *Monitor) tick(ctx context.Context) {
+	err := m.collect(ctx)
+	if err != nil {
+		// only log the first of repeated failures, the stale metrics show it anyway
+		if !m.failing {
+			klog.FromContext(ctx).WithName("sqlite-monitor").Error(err, "collect embedded database stats", "file", m.File)
+		}
+		m.failing = true
+		return
+	}
+
+	m.failing = false
+}
+
+func (m *Monitor) collect(ctx context.Context) error {
+	if m.db == nil {
+		db, err := Open(m.File)
+		if err != nil {
+			return err
+		}
+		m.db = db
+	}
+
+	stats, err := GetStats(ctx, m.db, m.File)
+	if err != nil {
+		return err
+	}
+	recordStats(stats)
+
+	rows, err := CountRows(ctx, m.db)
+	if err != nil {
+		return err
+	}
+	rowsGauge.Set(float64(rows))
+
+	usage, err := GetVolumeUsage(m.File)
+	if err != nil {
+		klog.FromContext(ctx).WithName("sqlite-monitor").V(1).Info("could not get volume usage", "error", err)
+	} else {
+		recordVolumeUsage(usage)
+		m.checkVolumeUsage(usage, stats)
+	}
+
+	m.maybeVacuum(ctx, stats, usage)
+	return nil
+}
+
+// maybeVacuum vacuums the database once per maintenance window. VACUUM writes a copy of the database, so it's skipped
+// if the volume doesn't have enough space left for it.
+func (m *Monitor) maybeVacuum(ctx context.Context, stats *Stats, usage *VolumeUsage) {
+	if m.schedule == nil {
+		return
+	}
+
+	window, ok := maintenanceWindow(m.schedule, m.duration, time.Now())
+	if !ok || window.Equal(m.lastWindow) {
+		return
+	}
+	m.lastWindow = window
+
+	logger := klog.FromContext(ctx).WithName("sqlite-monitor")
+	if usage != nil && usage.Capacity > 0 && usage.Available < stats.DatabaseSize {
+		m.event(corev1.EventTypeWarning, "DatabaseVacuumSkipped", "Skipped vacuuming the embedded database, it needs %d bytes but only %d bytes are available on its volume", stats.DatabaseSize, usage.Available)
+		return
+	}
+
+	start := time.Now()
+	err := Vacuum(ctx, m.db)
+	if err != nil {
+		logger.Error(err, "vacuum embedded database", "file", m.File)
+		m.event(corev1.EventTypeWarning, "DatabaseMaintenanceFailed", "Vacuuming the embedded database failed: %v", err)
+		return
+	}
+	lastVacuumGauge.SetToCurrentTime()
+
+	after, err := GetStats(ctx, m.db, m.File)
+	if err != nil {
+		logger.Info("vacuumed embedded database", "file", m.File, "duration", time.Since(start))
+		return
+	}
+	recordStats(after)
+	logger.Info("vacuumed embedded database", "file", m.File, "duration", time.Since(start), "sizeBefore", stats.DatabaseSize, "sizeAfter", after.DatabaseSize)
+}
+
+// maintenanceWindow returns the start of the maintenance window now falls into. Each window starts at an activation of
+// the schedule and lasts for the given duration.
+func maintenanceWindow(schedule cron.Schedule, duration time.Duration, now time.Time) (time.Time, bool) {
+	start := schedule.Next(now.Add(-duration))
+	if start.After(now) {
+		return time.Time{}, false
+	}
+
+	return start, true
+}
+
+// checkVolumeUsage emits a warning event once each time the volume usage crosses the configured threshold
+func (m *Monitor) checkVolumeUsage(usage *VolumeUsage, stats *Stats) {
+	exceeded := volumeUsageExceeded(usage, m.Maintenance.VolumeUsageWarningPercentage)
+	if exceeded && !m.volumeWarned {
+		m.event(corev1.EventTypeWarning, "DatabaseVolumeNearlyFull", "The volume of the embedded database is %d%% full (%d of %d bytes used, database %d bytes, %d bytes unused within the database)", usage.Percentage(), usage.Used, usage.Capacity, stats.DatabaseSize, stats.FreeBytes())
+	}
+	m.volumeWarned = exceeded
+}
+
+func (m *Monitor) event(eventType, reason, messageFmt string, args ...interface{}) {
+	if m.Recorder == nil || m.Pod == nil {
+		return
+	}
+
+	m.Recorder.Eventf(m.Pod, eventType, reason, messageFmt, args...)
+}
+
+// volumeUsageExceeded returns true if the usage of the volume is at or above the given percentage. A percentage of 0
+// disables the check.
+func volumeUsageExceeded(usage *VolumeUsage, percentage int) bool {
+	if percentage <= 0 || usage.Capacity <= 0 {
+		return false
+	}
+
+	return usage.Percentage() >= percentage
+}
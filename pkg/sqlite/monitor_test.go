@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+func TestVolumeUsageExceeded(t *testing.T) {
+	testCases := []struct {
+		name       string
+		usage      VolumeUsage
+		percentage int
+		expected   bool
+	}{
+		{name: "below", usage: VolumeUsage{Capacity: 100, Used: 84}, percentage: 85},
+		{name: "at threshold", usage: VolumeUsage{Capacity: 100, Used: 85}, percentage: 85, expected: true},
+		{name: "disabled", usage: VolumeUsage{Capacity: 100, Used: 100}},
+		{name: "unknown capacity", usage: VolumeUsage{}, percentage: 85},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if exceeded := volumeUsageExceeded(&tc.usage, tc.percentage); exceeded != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, exceeded)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindow(t *testing.T) {
+	schedule, err := cron.ParseStandard("0 3 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2024, 5, 10, 0, 0, 0, 0, time.Local)
+	testCases := []struct {
+		name     string
+		now      time.Time
+		expected time.Time
+		ok       bool
+	}{
+		{name: "before window", now: day.Add(2*time.Hour + 59*time.Minute)},
+		{name: "window start", now: day.Add(3 * time.Hour), expected: day.Add(3 * time.Hour), ok: true},
+		{name: "within window", now: day.Add(3*time.Hour + 59*time.Minute), expected: day.Add(3 * time.Hour), ok: true},
+		{name: "after window", now: day.Add(4*time.Hour + time.Minute)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			window, ok := maintenanceWindow(schedule, time.Hour, tc.now)
+			if ok != tc.ok {
+				t.Fatalf("expected ok %v, got %v", tc.ok, ok)
+			}
+			if !window.Equal(tc.expected) {
+				t.Errorf("expected window %v, got %v", tc.expected, window)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/constants"
+	"github.com/loft-sh/vcluster/pkg/k8s"
+)
+
+// Stats are the health numbers of an embedded kine SQLite database
+type Stats struct {
+	// DatabaseSize is the size of the database file in bytes
+	DatabaseSize int64 `json:"databaseSize"`
+	// WALSize is the size of the write-ahead log in bytes
+	WALSize int64 `json:"walSize"`
+	// CurrentRevision is the latest revision written by kine
+	CurrentRevision int64 `json:"currentRevision"`
+	// CompactRevision is the revision kine compacted the database to
+	CompactRevision int64 `json:"compactRevision"`
+	// PageSize is the size of a database page in bytes
+	PageSize int64 `json:"pageSize"`
+	// Pages is the number of pages of the database
+	Pages int64 `json:"pages"`
+	// FreePages is the number of unused pages that are reused for new writes
+	FreePages int64 `json:"freePages"`
+}
+
+// CompactionLag is the number of revisions that were not compacted yet
+func (s *Stats) CompactionLag() int64 {
+	return max(s.CurrentRevision-s.CompactRevision, 0)
+}
+
+// FreeBytes is the size of the unused pages in bytes
+func (s *Stats) FreeBytes() int64 {
+	return s.FreePages * s.PageSize
+}
+
+// DatabaseFile returns the SQLite file of the embedded database of the vCluster or an empty string if the vCluster
+// doesn't use an embedded SQLite database
+func DatabaseFile(vConfig *config.VirtualClusterConfig) string {
+	if !vConfig.EmbeddedDatabase() {
+		return ""
+	}
+
+	dataSource := vConfig.ControlPlane.BackingStore.Database.Embedded.DataSource
+	if dataSource == "" {
+		return constants.K8sSqliteDatabase
+	} else if !strings.HasPrefix(dataSource, "sqlite://") {
+		return ""
+	}
+
+	file, _, _ := strings.Cut(strings.TrimPrefix(dataSource, "sqlite://"), "?")
+	return file
+}
+
+// Open opens the SQLite database with the same parameters kine uses
+func Open(file string) (*sql.DB, error) {
+	_, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", file+k8s.SQLiteParams)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+
+	return db, nil
+}
+
+// GetStats returns the stats of the kine database in the given file
+func GetStats(ctx context.Context, db *sql.DB, file string) (*Stats, error) {
+	stats := &Stats{}
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+	stats.DatabaseSize = info.Size()
+
+	info, err = os.Stat(file + "-wal")
+	if err == nil {
+		stats.WALSize = info.Size()
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	for query, value := range map[string]*int64{
+		"SELECT COALESCE(MAX(id), 0) FROM kine":                                           &stats.CurrentRevision,
+		"SELECT COALESCE(MAX(prev_revision), 0) FROM kine WHERE name = 'compact_rev_key'": &stats.CompactRevision,
+		"PRAGMA page_size":      &stats.PageSize,
+		"PRAGMA page_count":     &stats.Pages,
+		"PRAGMA freelist_count": &stats.FreePages,
+	} {
+		err = db.QueryRowContext(ctx, query).Scan(value)
+		if err != nil {
+			return nil, fmt.Errorf("query %q: %w", query, err)
+		}
+	}
+
+	return stats, nil
+}
+
+// CountRows returns the number of rows in the kine table
+func CountRows(ctx context.Context, db *sql.DB) (int64, error) {
+	var rows int64
+	err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM kine").Scan(&rows)
+	if err != nil {
+		return 0, fmt.Errorf("count rows: %w", err)
+	}
+
+	return rows, nil
+}
+
+// Vacuum rebuilds the database to return its free pages to the file system and truncates the write-ahead log.
+// Writes of kine are blocked while vacuuming.
+func Vacuum(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, "VACUUM")
+	if err != nil {
+		return fmt.Errorf("vacuum: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)")
+	if err != nil {
+		return fmt.Errorf("checkpoint write-ahead log: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"testing"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/config"
+	"github.com/loft-sh/vcluster/pkg/constants"
+)
+
+func TestDatabaseFile(t *testing.T) {
+	testCases := []struct {
+		name         string
+		backingStore vclusterconfig.BackingStore
+		expected     string
+	}{
+		{
+			name:     "default",
+			expected: constants.K8sSqliteDatabase,
+		},
+		{
+			name: "custom sqlite data source",
+			backingStore: vclusterconfig.BackingStore{Database: vclusterconfig.Database{Embedded: vclusterconfig.DatabaseKine{
+				Enabled: true, DataSource: "sqlite:///data/custom.db?_journal=WAL",
+			}}},
+			expected: "/data/custom.db",
+		},
+		{
+			name: "non sqlite data source",
+			backingStore: vclusterconfig.BackingStore{Database: vclusterconfig.Database{Embedded: vclusterconfig.DatabaseKine{
+				Enabled: true, DataSource: "postgres://db",
+			}}},
+		},
+		{
+			name:         "embedded etcd",
+			backingStore: vclusterconfig.BackingStore{Etcd: vclusterconfig.Etcd{Embedded: vclusterconfig.EtcdEmbedded{Enabled: true}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			vConfig := &config.VirtualClusterConfig{}
+			vConfig.ControlPlane.BackingStore = tc.backingStore
+			if file := DatabaseFile(vConfig); file != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, file)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package sqlite
+
+// VolumeUsage is the usage of the volume the database is stored on
+type VolumeUsage struct {
+	// Capacity is the size of the volume in bytes
+	Capacity int64 `json:"capacity"`
+	// Used is the number of bytes in use
+	Used int64 `json:"used"`
+	// Available is the number of bytes that can still be written
+	Available int64 `json:"available"`
+}
+
+// Percentage returns the used share of the volume in percent
+func (v *VolumeUsage) Percentage() int {
+	if v.Capacity <= 0 {
+		return 0
+	}
+
+	return int(v.Used * 100 / v.Capacity)
+}
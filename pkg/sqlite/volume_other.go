@@ -0,0 +1,10 @@
+//go:build !unix
+
+package sqlite
+
+import "errors"
+
+// GetVolumeUsage returns the usage of the volume the given database file is stored on
+func GetVolumeUsage(_ string) (*VolumeUsage, error) {
+	return nil, errors.ErrUnsupported
+}
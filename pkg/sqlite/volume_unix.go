@@ -0,0 +1,26 @@
+//go:build unix
+
+package sqlite
+
+import (
+	"fmt"
+	"path/filepath"
+	"syscall"
+)
+
+// GetVolumeUsage returns the usage of the volume the given database file is stored on
+func GetVolumeUsage(file string) (*VolumeUsage, error) {
+	stat := &syscall.Statfs_t{}
+	err := syscall.Statfs(filepath.Dir(file), stat)
+	if err != nil {
+		return nil, fmt.Errorf("stat volume: %w", err)
+	}
+
+	blockSize := int64(stat.Bsize) //nolint:unconvert
+	capacity := int64(stat.Blocks) * blockSize
+	return &VolumeUsage{
+		Capacity:  capacity,
+		Used:      capacity - int64(stat.Bfree)*blockSize,
+		Available: int64(stat.Bavail) * blockSize,
+	}, nil
+}
@@ -13,7 +13,9 @@ import (
 	"github.com/loft-sh/vcluster/pkg/patcher"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	syncertypes "github.com/loft-sh/vcluster/pkg/syncer/types"
+	"github.com/loft-sh/vcluster/pkg/tracing"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
+	"go.opentelemetry.io/otel/attribute"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/util/workqueue"
@@ -126,6 +128,15 @@ func (r *SyncController) newSyncContext(ctx context.Context, logName string) *sy
 }
 
 func (r *SyncController) Reconcile(ctx context.Context, vReq reconcile.Request) (res ctrl.Result, retErr error) {
+	ctx, span := tracing.StartSpan(ctx, "syncer.Reconcile",
+		attribute.String("syncer", r.syncer.Name()),
+		attribute.String("namespace", vReq.Namespace),
+		attribute.String("name", vReq.Name),
+	)
+	defer func() {
+		tracing.EndSpan(span, retErr)
+	}()
+
 	defer func() {
 		if kerrors.IsConflict(retErr) {
 			res = ctrl.Result{RequeueAfter: time.Second}
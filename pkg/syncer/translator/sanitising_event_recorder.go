@@ -2,6 +2,9 @@ package translator
 
 import (
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
@@ -18,7 +21,7 @@ import (
 // embedded in event messages is rewritten to the virtual name before the event
 // is recorded on the virtual object.
 //
-// Two passes are applied:
+// Three passes are applied:
 //
 //  1. Exact replacement for the regarding object's translated name.
 //     virtualToHost is called with the regarding object's virtual name/namespace
@@ -26,12 +29,19 @@ import (
 //     (namespaced, cluster-scoped, hashed, or custom) without duplicating
 //     translation logic in the recorder.
 //
-//  2. Suffix stripping for secondary namespaced resource names that may appear
-//     in API server error messages (e.g. a ConfigMap or Secret in a "not found"
-//     error). The static suffix "-x-{namespace}-x-{vcName}" is stripped from the
-//     message. Skipped entirely when the regarding object's virtual name ends with
-//     that suffix (stripping would corrupt it). Only applied when the regarding
-//     object has a namespace. Hashed secondary names are left unchanged.
+//  2. Mapping store lookup for secondary namespaced resource names that may
+//     appear in API server error messages (e.g. a ConfigMap or Secret of the
+//     regarding object). Every name in the message is looked up as host object
+//     of each registered mapper in the regarding object's host namespace. This
+//     resolves names generated by a name template as well as names of objects
+//     that were synced with an earlier naming scheme.
+//
+//  3. Suffix stripping for secondary names that are not in the mapping store,
+//     e.g. a missing ConfigMap in a "not found" error. The static suffix
+//     "-x-{namespace}-x-{vcName}" is stripped from the message. Skipped entirely
+//     when the regarding object's virtual name ends with that suffix (stripping
+//     would corrupt it). Only applied when the regarding object has a namespace.
+//     Hashed and templated names of missing objects are left unchanged.
 //
 // virtualToHost must be a plain translation function — it must not emit events
 // itself, as that would cause infinite recursion (recorder → virtualToHost →
@@ -89,7 +99,12 @@ func (s *sanitisingEventRecorder) Eventf(
 		message = strings.ReplaceAll(message, hostName.Name, vName)
 	}
 
-	// Pass 2: strip the translated suffix from secondary namespaced resource names
+	// Pass 2: replace secondary host names that are known to the mapping store.
+	if hostName.Namespace != "" {
+		message = s.replaceStoredHostNames(message, vName, hostName.Namespace)
+	}
+
+	// Pass 3: strip the translated suffix from secondary namespaced resource names
 	// (e.g. a ConfigMap or Secret embedded in a "not found" error).
 	// Skipped when vName itself ends with the suffix — stripping would corrupt the
 	// virtual name that Pass 1 just placed. Secondary-resource sanitization is
@@ -103,3 +118,35 @@ func (s *sanitisingEventRecorder) Eventf(
 
 	s.underlying.Eventf(regarding, related, eventtype, reason, action, "%s", message)
 }
+
+// objectNameRegEx matches the names of Kubernetes objects within a message
+var objectNameRegEx = regexp.MustCompile(`[a-z0-9]([-a-z0-9.]*[a-z0-9])?`)
+
+// replaceStoredHostNames replaces every name in the message that the mapping store knows as host object in the given
+// host namespace with the name of its virtual object.
+func (s *sanitisingEventRecorder) replaceStoredHostNames(message, vName, hostNamespace string) string {
+	if s.syncCtx == nil || s.syncCtx.Mappings == nil || s.syncCtx.Mappings.Store() == nil {
+		return message
+	}
+
+	mappingsStore := s.syncCtx.Mappings.Store()
+	gvks := slices.Collect(maps.Keys(s.syncCtx.Mappings.List()))
+	return objectNameRegEx.ReplaceAllStringFunc(message, func(name string) string {
+		// the regarding object was already replaced by pass 1
+		if name == vName {
+			return name
+		}
+
+		for _, gvk := range gvks {
+			virtualName, ok := mappingsStore.HostToVirtualName(s.syncCtx, synccontext.Object{
+				GroupVersionKind: gvk,
+				NamespacedName:   types.NamespacedName{Namespace: hostNamespace, Name: name},
+			})
+			if ok && virtualName.Name != "" {
+				return virtualName.Name
+			}
+		}
+
+		return name
+	})
+}
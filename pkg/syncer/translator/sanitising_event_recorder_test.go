@@ -1,10 +1,14 @@
 package translator
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"github.com/loft-sh/vcluster/pkg/mappings"
+	"github.com/loft-sh/vcluster/pkg/mappings/store"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
+	"github.com/loft-sh/vcluster/pkg/util/nametemplate"
 	"github.com/loft-sh/vcluster/pkg/util/translate"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -261,3 +265,63 @@ func TestSanitisingEventRecorder(t *testing.T) {
 		})
 	}
 }
+
+// configMapMapper registers the ConfigMap GroupVersionKind, so the recorder looks up ConfigMaps in the mapping store.
+type configMapMapper struct {
+	namespacedMapper
+}
+
+func (m *configMapMapper) GroupVersionKind() schema.GroupVersionKind {
+	return corev1.SchemeGroupVersion.WithKind("ConfigMap")
+}
+
+func TestSanitisingEventRecorderMappingStore(t *testing.T) {
+	const vcName = "my-vc"
+
+	origVClusterName := translate.VClusterName
+	translate.VClusterName = vcName
+	t.Cleanup(func() { translate.VClusterName = origVClusterName })
+
+	oldTemplate, err := nametemplate.Parse("{{.VCluster}}-{{.Namespace}}-{{.Name}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newTemplate, err := nametemplate.Parse("{{.Name}}-{{.Namespace}}-{{.VCluster}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldTranslator := translate.NewSingleNamespaceTranslatorWithNameTemplate("host", oldTemplate)
+	newTranslator := translate.NewSingleNamespaceTranslatorWithNameTemplate("host", newTemplate)
+
+	// the config map was synced before the template changed and keeps its host name
+	configMapHostName := oldTranslator.HostName(nil, "app-config", "default")
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	mappingsStore, err := store.NewStore(ctx, nil, nil, store.NewMemoryBackend(&store.Mapping{NameMapping: synccontext.NameMapping{
+		GroupVersionKind: corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+		VirtualName:      types.NamespacedName{Namespace: "default", Name: "app-config"},
+		HostName:         configMapHostName,
+	}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := mappings.NewMappingsRegistry(mappingsStore)
+	if err := registry.AddMapper(&configMapMapper{}); err != nil {
+		t.Fatal(err)
+	}
+
+	virtualToHost := func(_ *synccontext.SyncContext, req types.NamespacedName, _ client.Object) types.NamespacedName {
+		return newTranslator.HostName(nil, req.Name, req.Namespace)
+	}
+	captured := &captureRecorder{}
+	rec := newSanitisingEventRecorder(&synccontext.SyncContext{Context: ctx, Mappings: registry}, captured, virtualToHost)
+
+	podHostName := newTranslator.HostName(nil, "my-pod", "default")
+	rec.Eventf(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "my-pod", Namespace: "default"}}, nil, "Warning", "SyncError", "SyncPod",
+		`Error syncing: pods "%s": referenced configmap "%s" is invalid`, podHostName.Name, configMapHostName.Name)
+
+	wantMessage := `Error syncing: pods "my-pod": referenced configmap "app-config" is invalid`
+	if captured.lastMessage != wantMessage {
+		t.Errorf("\ngot:  %s\nwant: %s", captured.lastMessage, wantMessage)
+	}
+}
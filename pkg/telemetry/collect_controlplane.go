@@ -31,7 +31,8 @@ type ControlPlaneCollector interface {
 }
 
 func StartControlPlane(config *config.VirtualClusterConfig) {
-	if !config.Telemetry.Enabled || SyncerVersion == "dev" || config.ControlPlane.Standalone.Enabled {
+	// dev builds only report to a local sink
+	if !isEnabled(config) || (config.Telemetry.Sink.Type == "" && SyncerVersion == "dev") {
 		return
 	}
 
@@ -45,9 +46,30 @@ func StartControlPlane(config *config.VirtualClusterConfig) {
 	}
 }
 
+// isEnabled returns true if events should be recorded, either to the hosted backend or to a local sink
+func isEnabled(config *config.VirtualClusterConfig) bool {
+	if config.ControlPlane.Standalone.Enabled {
+		return false
+	}
+
+	return config.Telemetry.Enabled || config.Telemetry.Sink.Type != ""
+}
+
 func newControlPlaneCollector(config *config.VirtualClusterConfig) (*controlPlaneCollector, error) {
+	var analyticsClient client.Client
+	if config.Telemetry.Sink.Type != "" {
+		sinkClient, err := newSinkClient(context.Background(), config)
+		if err != nil {
+			return nil, err
+		}
+
+		analyticsClient = sinkClient
+	} else {
+		analyticsClient = client.NewClient()
+	}
+
 	collector := &controlPlaneCollector{
-		analyticsClient: client.NewClient(),
+		analyticsClient: analyticsClient,
 
 		log: loghelper.New("telemetry"),
 
@@ -113,7 +135,7 @@ func (d *controlPlaneCollector) RecordStatus(ctx context.Context, platformUserID
 }
 
 func (d *controlPlaneCollector) RecordStart(ctx context.Context, config *config.VirtualClusterConfig) {
-	if !config.Telemetry.Enabled || config.ControlPlane.Standalone.Enabled {
+	if !isEnabled(config) {
 		return
 	}
 
@@ -152,7 +174,7 @@ func (d *controlPlaneCollector) RecordStart(ctx context.Context, config *config.
 }
 
 func (d *controlPlaneCollector) RecordError(ctx context.Context, config *config.VirtualClusterConfig, severity ErrorSeverityType, err error) {
-	if !config.Telemetry.Enabled || config.ControlPlane.Standalone.Enabled {
+	if !isEnabled(config) {
 		return
 	}
 
@@ -0,0 +1,163 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/loft-sh/analytics-client/client"
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const defaultOTLPEndpoint = "localhost:4317"
+
+// newSinkClient creates a client that writes the events to the sink configured in telemetry.sink instead of
+// sending them to the hosted backend
+func newSinkClient(ctx context.Context, config *config.VirtualClusterConfig) (client.Client, error) {
+	sink := config.Telemetry.Sink
+	switch sink.Type {
+	case vclusterconfig.TelemetrySinkTypeStdout:
+		return &writerClient{writer: os.Stdout}, nil
+	case vclusterconfig.TelemetrySinkTypeFile:
+		err := os.MkdirAll(filepath.Dir(sink.Path), 0755)
+		if err != nil {
+			return nil, fmt.Errorf("create telemetry sink directory: %w", err)
+		}
+
+		file, err := os.OpenFile(sink.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("open telemetry sink file: %w", err)
+		}
+
+		return &writerClient{writer: file}, nil
+	case vclusterconfig.TelemetrySinkTypeOTLP:
+		return newOTLPClient(ctx, config)
+	}
+
+	return nil, fmt.Errorf("unsupported telemetry sink type %q", sink.Type)
+}
+
+// writerClient writes every event as a JSON line to the writer
+type writerClient struct {
+	m      sync.Mutex
+	writer io.Writer
+}
+
+func (w *writerClient) RecordEvent(event client.Event) {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	_, _ = w.writer.Write(append(raw, '\n'))
+}
+
+func (w *writerClient) Flush() {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	if file, ok := w.writer.(*os.File); ok && file != os.Stdout {
+		_ = file.Sync()
+	}
+}
+
+// otlpClient exports every event as a span named after the event type. The event fields are added as
+// span attributes in the form <group>.<field>, e.g. event.vcluster_id.
+type otlpClient struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+}
+
+func newOTLPClient(ctx context.Context, config *config.VirtualClusterConfig) (*otlpClient, error) {
+	endpoint := config.Telemetry.Sink.Endpoint
+	if endpoint == "" {
+		endpoint = defaultOTLPEndpoint
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(endpoint), otlptracegrpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("create telemetry otlp exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName("vcluster"),
+			semconv.ServiceVersion(SyncerVersion),
+			semconv.ServiceInstanceID(config.Name),
+			semconv.K8SNamespaceName(config.HostNamespace),
+		)),
+	)
+
+	return &otlpClient{
+		provider: provider,
+		tracer:   provider.Tracer("github.com/loft-sh/vcluster/pkg/telemetry"),
+	}, nil
+}
+
+func (o *otlpClient) RecordEvent(event client.Event) {
+	name := "vcluster_event"
+	timestamp := time.Now()
+	if eventType, ok := event["event"]["type"].(string); ok {
+		name = eventType
+	}
+	if unix, ok := event["event"]["timestamp"].(int64); ok {
+		timestamp = time.Unix(unix, 0)
+	}
+
+	_, span := o.tracer.Start(context.Background(), name, trace.WithTimestamp(timestamp), trace.WithAttributes(eventAttributes(event)...))
+	span.End(trace.WithTimestamp(timestamp))
+}
+
+func (o *otlpClient) Flush() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	_ = o.provider.ForceFlush(ctx)
+}
+
+func eventAttributes(event client.Event) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{}
+	for group, fields := range event {
+		for field, value := range fields {
+			key := group + "." + field
+			switch v := value.(type) {
+			case string:
+				attributes = append(attributes, attribute.String(key, v))
+			case bool:
+				attributes = append(attributes, attribute.Bool(key, v))
+			case int:
+				attributes = append(attributes, attribute.Int(key, v))
+			case int64:
+				attributes = append(attributes, attribute.Int64(key, v))
+			case float64:
+				attributes = append(attributes, attribute.Float64(key, v))
+			default:
+				raw, _ := json.Marshal(v)
+				attributes = append(attributes, attribute.String(key, string(raw)))
+			}
+		}
+	}
+
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].Key < attributes[j].Key
+	})
+	return attributes
+}
@@ -0,0 +1,60 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/loft-sh/analytics-client/client"
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/config"
+	"gotest.tools/v3/assert"
+)
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telemetry", "events.jsonl")
+	vConfig := &config.VirtualClusterConfig{}
+	vConfig.Telemetry.Sink = vclusterconfig.TelemetrySink{Type: vclusterconfig.TelemetrySinkTypeFile, Path: path}
+
+	sinkClient, err := newSinkClient(context.Background(), vConfig)
+	assert.NilError(t, err)
+
+	sinkClient.RecordEvent(client.Event{"event": {"type": "vcluster_start", "timestamp": int64(1700000000)}})
+	sinkClient.RecordEvent(client.Event{"event": {"type": "vcluster_error", "properties": `{"severity":"warning"}`}})
+	sinkClient.Flush()
+
+	raw, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+	assert.Equal(t, len(lines), 2)
+
+	event := client.Event{}
+	assert.NilError(t, json.Unmarshal([]byte(lines[1]), &event))
+	assert.Equal(t, event["event"]["type"], "vcluster_error")
+	assert.Equal(t, event["event"]["properties"], `{"severity":"warning"}`)
+}
+
+func TestEventAttributes(t *testing.T) {
+	attributes := eventAttributes(client.Event{
+		"event": {
+			"type":      "vcluster_status",
+			"timestamp": int64(1700000000),
+		},
+		"vcluster_instance": {
+			"properties": map[string]interface{}{"pods": 2},
+		},
+	})
+
+	emitted := []string{}
+	for _, kv := range attributes {
+		emitted = append(emitted, string(kv.Key)+"="+kv.Value.Emit())
+	}
+	assert.DeepEqual(t, emitted, []string{
+		"event.timestamp=1700000000",
+		"event.type=vcluster_status",
+		`vcluster_instance.properties={"pods":2}`,
+	})
+}
@@ -0,0 +1,142 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/loft-sh/vcluster/pkg/config"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"k8s.io/client-go/rest"
+	"k8s.io/component-base/tracing"
+	tracingapi "k8s.io/component-base/tracing/api/v1"
+	"k8s.io/utils/ptr"
+)
+
+const instrumentationName = "github.com/loft-sh/vcluster"
+
+// Provider is the tracer provider used by vCluster. It doesn't record any spans until Start is called.
+var Provider tracing.TracerProvider = tracing.NewNoopTracerProvider()
+
+var enabled bool
+
+// Start configures Provider to export spans to the OTLP collector configured in controlPlane.advanced.tracing
+// and registers it globally, so trace context is propagated to the virtual and host cluster as well as plugins.
+func Start(ctx context.Context, vConfig *config.VirtualClusterConfig, version string) error {
+	tracingConfig := vConfig.ControlPlane.Advanced.Tracing
+	if !tracingConfig.Enabled {
+		return nil
+	}
+
+	apiConfig := &tracingapi.TracingConfiguration{
+		SamplingRatePerMillion: ptr.To(tracingConfig.SamplingRatePerMillion),
+	}
+	if tracingConfig.Endpoint != "" {
+		apiConfig.Endpoint = ptr.To(tracingConfig.Endpoint)
+	}
+
+	provider, err := tracing.NewProvider(ctx, apiConfig, []otlptracegrpc.Option{}, []resource.Option{
+		resource.WithAttributes(
+			semconv.ServiceName("vcluster"),
+			semconv.ServiceVersion(version),
+			semconv.ServiceInstanceID(vConfig.Name),
+			semconv.K8SNamespaceName(vConfig.HostNamespace),
+		),
+	})
+	if err != nil {
+		return err
+	}
+
+	Provider = &shutdownOnceProvider{TracerProvider: provider}
+	enabled = true
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(tracing.Propagators())
+	return nil
+}
+
+// Shutdown flushes all pending spans and stops the exporter. The provider is shut down by the api server as well, so
+// only the first call shuts it down and later calls return its result.
+func Shutdown(ctx context.Context) error {
+	return Provider.Shutdown(ctx)
+}
+
+// shutdownOnceProvider shuts the wrapped provider down only once
+type shutdownOnceProvider struct {
+	tracing.TracerProvider
+
+	once sync.Once
+	err  error
+}
+
+func (p *shutdownOnceProvider) Shutdown(ctx context.Context) error {
+	p.once.Do(func() {
+		p.err = p.TracerProvider.Shutdown(ctx)
+	})
+
+	return p.err
+}
+
+// Enabled returns true if tracing was started
+func Enabled() bool {
+	return enabled
+}
+
+// StartSpan starts a new span with the given name. The caller has to end the returned span.
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Provider.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// EndSpan records err on the span if it's not nil and ends the span
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// WithSpan wraps the handler in a span with the given name
+func WithSpan(h http.Handler, name string) http.Handler {
+	if !enabled {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx, span := StartSpan(req.Context(), name)
+		defer span.End()
+
+		h.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+// WrapConfig traces all requests made with the rest config and propagates the trace context to the api server
+func WrapConfig(restConfig *rest.Config) {
+	if !enabled || restConfig == nil {
+		return
+	}
+
+	restConfig.Wrap(tracing.WrapperFor(Provider))
+}
+
+// GRPCDialOptions returns the dial options to trace gRPC calls and propagate the trace context through the
+// request metadata
+func GRPCDialOptions() []grpc.DialOption {
+	if !enabled {
+		return nil
+	}
+
+	return []grpc.DialOption{
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+			otelgrpc.WithTracerProvider(Provider),
+			otelgrpc.WithPropagators(tracing.Propagators()),
+		)),
+	}
+}
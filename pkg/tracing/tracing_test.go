@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	vclusterconfig "github.com/loft-sh/vcluster/config"
+	"github.com/loft-sh/vcluster/pkg/config"
+	"go.opentelemetry.io/otel/trace"
+	"gotest.tools/v3/assert"
+	"k8s.io/client-go/rest"
+	"k8s.io/component-base/tracing"
+)
+
+func TestStart(t *testing.T) {
+	t.Cleanup(func() {
+		// there is no collector, so don't wait for the pending spans to be exported
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_ = Shutdown(ctx)
+		Provider = tracing.NewNoopTracerProvider()
+		enabled = false
+	})
+
+	vConfig := &config.VirtualClusterConfig{Name: "test", HostNamespace: "vcluster-test"}
+
+	// disabled
+	err := Start(context.Background(), vConfig, "dev")
+	assert.NilError(t, err)
+	assert.Assert(t, !Enabled())
+	assert.Assert(t, GRPCDialOptions() == nil)
+	_, span := StartSpan(context.Background(), "test")
+	assert.Assert(t, !span.IsRecording())
+	span.End()
+
+	restConfig := &rest.Config{}
+	WrapConfig(restConfig)
+	assert.Assert(t, restConfig.WrapTransport == nil)
+
+	// enabled
+	vConfig.ControlPlane.Advanced.Tracing = vclusterconfig.ControlPlaneTracing{
+		Enabled:                true,
+		Endpoint:               "localhost:4317",
+		SamplingRatePerMillion: 1000000,
+	}
+	err = Start(context.Background(), vConfig, "dev")
+	assert.NilError(t, err)
+	assert.Assert(t, Enabled())
+	assert.Equal(t, len(GRPCDialOptions()), 1)
+	WrapConfig(restConfig)
+	assert.Assert(t, restConfig.WrapTransport != nil)
+
+	_, span = StartSpan(context.Background(), "test")
+	assert.Assert(t, span.IsRecording())
+	EndSpan(span, nil)
+
+	// handler spans are children of the request span
+	var parent trace.SpanContext
+	handler := WithSpan(http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		parent = trace.SpanContextFromContext(req.Context())
+	}), "test")
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Assert(t, parent.IsValid())
+	assert.Assert(t, parent.IsSampled())
+}
+
+type countingProvider struct {
+	tracing.TracerProvider
+
+	shutdowns int
+}
+
+func (p *countingProvider) Shutdown(context.Context) error {
+	p.shutdowns++
+	return nil
+}
+
+func TestShutdownOnce(t *testing.T) {
+	counting := &countingProvider{TracerProvider: tracing.NewNoopTracerProvider()}
+	provider := &shutdownOnceProvider{TracerProvider: counting}
+
+	// the api server and the vCluster start command both shut the provider down
+	assert.NilError(t, provider.Shutdown(context.Background()))
+	assert.NilError(t, provider.Shutdown(context.Background()))
+	assert.Equal(t, counting.shutdowns, 1)
+}
@@ -5,15 +5,18 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
 	authenticationv1 "k8s.io/api/authentication/v1"
 	authorizationv1 "k8s.io/api/authorization/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -127,3 +130,41 @@ func IsNilObject(obj any) bool {
 
 	return false
 }
+
+// CheckAccess returns an error if the current user isn't allowed to use all of the given verbs on the resource in
+// the namespace
+func CheckAccess(ctx context.Context, kubeClient kubernetes.Interface, namespace, group, resource string, verbs ...string) error {
+	return CheckObjectAccess(ctx, kubeClient, namespace, group, resource, "", verbs...)
+}
+
+// CheckObjectAccess returns an error if the current user isn't allowed to use all of the given verbs on the object
+// with the given name. An empty name checks the access to all objects of the resource in the namespace.
+func CheckObjectAccess(ctx context.Context, kubeClient kubernetes.Interface, namespace, group, resource, name string, verbs ...string) error {
+	denied := []string{}
+	for _, verb := range verbs {
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      verb,
+					Group:     group,
+					Resource:  resource,
+					Name:      name,
+				},
+			},
+		}
+		review, err := kubeClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("create self subject access review: %w", err)
+		} else if !review.Status.Allowed {
+			denied = append(denied, verb)
+		}
+	}
+	if len(denied) > 0 && name != "" {
+		return fmt.Errorf("not allowed to %s %s %s in namespace %s", strings.Join(denied, ", "), resource, name, namespace)
+	} else if len(denied) > 0 {
+		return fmt.Errorf("not allowed to %s %s in namespace %s", strings.Join(denied, ", "), resource, namespace)
+	}
+
+	return nil
+}
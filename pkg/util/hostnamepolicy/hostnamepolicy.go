@@ -0,0 +1,161 @@
+package hostnamepolicy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/loft-sh/vcluster/config"
+)
+
+const (
+	// ExternalDNSHostnameAnnotation is used by ExternalDNS to create public records for Services, Ingresses and Gateways
+	ExternalDNSHostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+	// ExternalDNSInternalHostnameAnnotation is used by ExternalDNS to create records pointing to the cluster ip of a Service
+	ExternalDNSInternalHostnameAnnotation = "external-dns.alpha.kubernetes.io/internal-hostname"
+	// NginxServerAliasAnnotation adds further hostnames to the server ingress-nginx creates for an Ingress
+	NginxServerAliasAnnotation = "nginx.ingress.kubernetes.io/server-alias"
+
+	// Reason is the event reason used when an object is not synced because of the hostname policy
+	Reason = "HostnameNotPermitted"
+)
+
+// ExternalDNSAnnotations are the annotations that let ExternalDNS claim hostnames
+var ExternalDNSAnnotations = []string{ExternalDNSHostnameAnnotation, ExternalDNSInternalHostnameAnnotation}
+
+// IngressAnnotations are the annotations that let an Ingress claim hostnames in addition to its rules
+var IngressAnnotations = []string{ExternalDNSHostnameAnnotation, ExternalDNSInternalHostnameAnnotation, NginxServerAliasAnnotation}
+
+var errNotPermitted = errors.New("hostname not permitted")
+
+// NotPermittedError is returned when a hostname violates the configured hostname policy. It is a terminal condition:
+// retrying cannot succeed until the user changes the object, so callers must surface it and stop rather than requeue.
+type NotPermittedError struct {
+	msg string
+}
+
+// Error returns the not-permitted error message.
+func (e *NotPermittedError) Error() string {
+	return e.msg
+}
+
+// Is reports whether target is the package not-permitted sentinel.
+func (e *NotPermittedError) Is(target error) bool {
+	return target == errNotPermitted
+}
+
+// IsNotPermitted reports whether err indicates a hostname policy violation.
+func IsNotPermitted(err error) bool {
+	return errors.Is(err, errNotPermitted)
+}
+
+func notPermittedf(format string, args ...any) error {
+	return &NotPermittedError{msg: fmt.Sprintf(format, args...)}
+}
+
+// Validate checks the given hostnames against the policy. Hostnames have to be equal to or a subdomain of one of the
+// allowed domains and the label directly below that domain has to carry the configured prefix and suffix. Wildcard
+// hostnames are checked without their leading "*." label.
+func Validate(policy config.HostnamePolicy, hostnames ...string) error {
+	if !policy.Enabled {
+		return nil
+	}
+
+	for _, hostname := range hostnames {
+		if !allowed(policy, hostname) {
+			return notPermittedf("hostname %q is not permitted by the hostname policy, %s", hostname, describe(policy))
+		}
+	}
+
+	return nil
+}
+
+// ValidateAnnotations checks the hostnames of the ExternalDNS annotations against the policy.
+func ValidateAnnotations(policy config.HostnamePolicy, annotations map[string]string) error {
+	return validateAnnotations(policy, annotations, ExternalDNSAnnotations)
+}
+
+// ValidateIngressAnnotations checks the hostnames of the ExternalDNS and ingress-nginx server alias annotations against
+// the policy.
+func ValidateIngressAnnotations(policy config.HostnamePolicy, annotations map[string]string) error {
+	return validateAnnotations(policy, annotations, IngressAnnotations)
+}
+
+func validateAnnotations(policy config.HostnamePolicy, annotations map[string]string, keys []string) error {
+	if !policy.Enabled {
+		return nil
+	}
+
+	for _, key := range keys {
+		value, ok := annotations[key]
+		if !ok {
+			continue
+		}
+
+		err := Validate(policy, splitHostnames(value)...)
+		if err != nil {
+			return fmt.Errorf("annotation %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// splitHostnames splits a hostname annotation, ExternalDNS separates hostnames by commas and ingress-nginx server
+// aliases by commas or spaces
+func splitHostnames(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
+func allowed(policy config.HostnamePolicy, hostname string) bool {
+	hostname = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(hostname)), ".")
+	hostname = strings.TrimPrefix(hostname, "*.")
+	if hostname == "" {
+		return false
+	}
+
+	for _, domain := range policy.AllowedDomains {
+		domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if domain == "" {
+			continue
+		}
+
+		// the domain itself has no label below it, so it can only be claimed without prefix and suffix
+		if hostname == domain {
+			if policy.Prefix == "" && policy.Suffix == "" {
+				return true
+			}
+			continue
+		}
+
+		subdomain, ok := strings.CutSuffix(hostname, "."+domain)
+		if !ok {
+			continue
+		}
+
+		labels := strings.Split(subdomain, ".")
+		label := labels[len(labels)-1]
+		if strings.HasPrefix(label, strings.ToLower(policy.Prefix)) && strings.HasSuffix(label, strings.ToLower(policy.Suffix)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func describe(policy config.HostnamePolicy) string {
+	description := fmt.Sprintf("hostnames must be within %s", strings.Join(policy.AllowedDomains, ", "))
+	switch {
+	case policy.Prefix != "" && policy.Suffix != "":
+		description += fmt.Sprintf(" and the label below the domain must start with %q and end with %q", policy.Prefix, policy.Suffix)
+	case policy.Prefix != "":
+		description += fmt.Sprintf(" and the label below the domain must start with %q", policy.Prefix)
+	case policy.Suffix != "":
+		description += fmt.Sprintf(" and the label below the domain must end with %q", policy.Suffix)
+	}
+
+	return description
+}
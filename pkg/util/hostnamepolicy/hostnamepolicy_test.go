@@ -0,0 +1,148 @@
+package hostnamepolicy
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/config"
+	"gotest.tools/v3/assert"
+)
+
+func TestValidate(t *testing.T) {
+	policy := config.HostnamePolicy{
+		Enabled:        true,
+		AllowedDomains: []string{"apps.example.com", "Example.org."},
+		Prefix:         "team-a-",
+	}
+
+	testCases := []struct {
+		name     string
+		policy   config.HostnamePolicy
+		hostname string
+
+		expectAllowed bool
+	}{
+		{
+			name:          "disabled policy",
+			policy:        config.HostnamePolicy{AllowedDomains: []string{"apps.example.com"}},
+			hostname:      "shop.other.com",
+			expectAllowed: true,
+		},
+		{
+			name:          "prefixed label below allowed domain",
+			policy:        policy,
+			hostname:      "team-a-shop.apps.example.com",
+			expectAllowed: true,
+		},
+		{
+			name:          "nested subdomain of prefixed label",
+			policy:        policy,
+			hostname:      "api.team-a-shop.apps.example.com",
+			expectAllowed: true,
+		},
+		{
+			name:          "case and trailing dot are ignored",
+			policy:        policy,
+			hostname:      "Team-A-Shop.example.org.",
+			expectAllowed: true,
+		},
+		{
+			name:          "wildcard below prefixed label",
+			policy:        policy,
+			hostname:      "*.team-a-shop.apps.example.com",
+			expectAllowed: true,
+		},
+		{
+			name:     "wildcard of allowed domain with prefix",
+			policy:   policy,
+			hostname: "*.apps.example.com",
+		},
+		{
+			name:     "allowed domain itself with prefix",
+			policy:   policy,
+			hostname: "apps.example.com",
+		},
+		{
+			name:     "label of another tenant",
+			policy:   policy,
+			hostname: "team-a-shop.team-b-shop.apps.example.com",
+		},
+		{
+			name:     "missing prefix",
+			policy:   policy,
+			hostname: "shop.apps.example.com",
+		},
+		{
+			name:     "domain only sharing the suffix",
+			policy:   policy,
+			hostname: "team-a-shop.myapps.example.com",
+		},
+		{
+			name:     "other domain",
+			policy:   policy,
+			hostname: "team-a-shop.other.com",
+		},
+		{
+			name:          "wildcard of allowed domain without prefix",
+			policy:        config.HostnamePolicy{Enabled: true, AllowedDomains: []string{"apps.example.com"}},
+			hostname:      "*.apps.example.com",
+			expectAllowed: true,
+		},
+		{
+			name:          "suffixed label",
+			policy:        config.HostnamePolicy{Enabled: true, AllowedDomains: []string{"apps.example.com"}, Suffix: "-team-a"},
+			hostname:      "shop-team-a.apps.example.com",
+			expectAllowed: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := Validate(testCase.policy, testCase.hostname)
+			if testCase.expectAllowed {
+				assert.NilError(t, err)
+			} else {
+				assert.Assert(t, IsNotPermitted(err), "expected hostname %q to be rejected, got %v", testCase.hostname, err)
+			}
+		})
+	}
+}
+
+func TestValidateAnnotations(t *testing.T) {
+	policy := config.HostnamePolicy{
+		Enabled:        true,
+		AllowedDomains: []string{"apps.example.com"},
+		Suffix:         "-team-a",
+	}
+
+	assert.NilError(t, ValidateAnnotations(policy, map[string]string{
+		ExternalDNSHostnameAnnotation: "shop-team-a.apps.example.com, api-team-a.apps.example.com",
+		"other":                       "shop.other.com",
+	}))
+
+	err := ValidateAnnotations(policy, map[string]string{
+		ExternalDNSInternalHostnameAnnotation: "shop-team-a.apps.example.com,shop.apps.example.com",
+	})
+	assert.Assert(t, IsNotPermitted(err))
+	assert.ErrorContains(t, err, `annotation external-dns.alpha.kubernetes.io/internal-hostname: hostname "shop.apps.example.com" is not permitted by the hostname policy, hostnames must be within apps.example.com and the label below the domain must end with "-team-a"`)
+}
+
+func TestValidateIngressAnnotations(t *testing.T) {
+	policy := config.HostnamePolicy{
+		Enabled:        true,
+		AllowedDomains: []string{"apps.example.com"},
+		Prefix:         "team-a-",
+	}
+
+	assert.NilError(t, ValidateIngressAnnotations(policy, map[string]string{
+		NginxServerAliasAnnotation: "team-a-www.apps.example.com team-a-shop.apps.example.com",
+	}))
+
+	err := ValidateIngressAnnotations(policy, map[string]string{
+		NginxServerAliasAnnotation: "team-a-www.apps.example.com,shop.other.com",
+	})
+	assert.Assert(t, IsNotPermitted(err))
+	assert.ErrorContains(t, err, `annotation nginx.ingress.kubernetes.io/server-alias: hostname "shop.other.com" is not permitted`)
+
+	// server aliases only claim hostnames on ingresses
+	assert.NilError(t, ValidateAnnotations(policy, map[string]string{NginxServerAliasAnnotation: "shop.other.com"}))
+}
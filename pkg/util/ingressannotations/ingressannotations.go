@@ -0,0 +1,243 @@
+package ingressannotations
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/loft-sh/vcluster/config"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// TypeService is an annotation or path value that references a service by "name" or "namespace/name"
+	TypeService = "service"
+	// TypeSecret is an annotation or path value that references a secret by "name" or "namespace/name"
+	TypeSecret = "secret"
+	// TypeJSON is an annotation value that is a JSON document with references at the rule paths
+	TypeJSON = "json"
+	// TypeTraefikMiddleware is an annotation value with a comma separated list of Traefik middleware references in the
+	// "<namespace>-<name>@kubernetescrd" format
+	TypeTraefikMiddleware = "traefikMiddleware"
+)
+
+// traefikCRDProvider is the suffix of Traefik references to objects of the kubernetes CRD provider
+const traefikCRDProvider = "@kubernetescrd"
+
+// DefaultPresets are the presets used if no config is available
+var DefaultPresets = []string{"nginx"}
+
+// Presets are the built-in rules for the annotations of common ingress controllers. There is no preset for Contour, as
+// its ingress annotations don't reference other objects.
+var Presets = map[string][]config.IngressAnnotationRule{
+	"nginx": {
+		{Annotation: "nginx.ingress.kubernetes.io/auth-secret", Type: TypeSecret},
+		{Annotation: "nginx.ingress.kubernetes.io/auth-tls-secret", Type: TypeSecret},
+		{Annotation: "nginx.ingress.kubernetes.io/proxy-ssl-secret", Type: TypeSecret},
+		{Annotation: "nginx.ingress.kubernetes.io/default-backend", Type: TypeService},
+	},
+	"haproxy": {
+		{Annotation: "haproxy.org/auth-secret", Type: TypeSecret},
+		{Annotation: "haproxy.org/server-ca", Type: TypeSecret},
+		{Annotation: "haproxy.org/server-crt", Type: TypeSecret},
+	},
+	"traefik": {
+		{Annotation: "traefik.ingress.kubernetes.io/router.middlewares", Type: TypeTraefikMiddleware},
+	},
+}
+
+// Rules returns the rules of the enabled presets and the custom rules by annotation. Custom rules take precedence over
+// the presets.
+func Rules(translation config.IngressAnnotationTranslation) map[string]config.IngressAnnotationRule {
+	rules := map[string]config.IngressAnnotationRule{}
+	for _, preset := range translation.Presets {
+		for _, rule := range Presets[preset] {
+			rules[rule.Annotation] = rule
+		}
+	}
+	for _, rule := range translation.Rules {
+		rules[rule.Annotation] = rule
+	}
+
+	return rules
+}
+
+// TranslateFunc returns the host object for a reference of the given type
+type TranslateFunc func(refType string, ref types.NamespacedName) types.NamespacedName
+
+// Translate rewrites the references within the annotation value according to the rule. References without a namespace
+// are resolved in the given namespace and stay without a namespace. The value is returned unchanged if it cannot be
+// translated.
+func Translate(rule config.IngressAnnotationRule, value, namespace string, translate TranslateFunc) (string, error) {
+	if rule.Type == TypeTraefikMiddleware {
+		return translateTraefikReferences(rule.Type, value, namespace, translate), nil
+	} else if rule.Type != TypeJSON {
+		return translateReference(rule.Type, value, namespace, translate), nil
+	}
+
+	var document interface{}
+	err := json.Unmarshal([]byte(value), &document)
+	if err != nil {
+		return value, fmt.Errorf("unmarshal annotation %s: %w", rule.Annotation, err)
+	}
+
+	changed := false
+	for _, path := range rule.Paths {
+		segments, err := parsePath(path.Path)
+		if err != nil {
+			return value, err
+		}
+
+		document = walk(document, segments, func(leaf interface{}) interface{} {
+			ref, ok := leaf.(string)
+			if !ok || ref == "" {
+				return leaf
+			}
+
+			newRef := translateReference(path.Type, ref, namespace, translate)
+			changed = changed || newRef != ref
+			return newRef
+		})
+	}
+	if !changed {
+		return value, nil
+	}
+
+	out, err := json.Marshal(document)
+	if err != nil {
+		return value, fmt.Errorf("marshal annotation %s: %w", rule.Annotation, err)
+	}
+
+	return string(out), nil
+}
+
+func translateReference(refType, value, namespace string, translate TranslateFunc) string {
+	splitted := strings.Split(value, "/")
+	switch len(splitted) {
+	case 1: // only "name"
+		return translate(refType, types.NamespacedName{Namespace: namespace, Name: splitted[0]}).Name
+	case 2: // "namespace/name"
+		pName := translate(refType, types.NamespacedName{Namespace: splitted[0], Name: splitted[1]})
+		return pName.Namespace + "/" + pName.Name
+	default:
+		return value
+	}
+}
+
+// translateTraefikReferences translates a comma separated list of "<namespace>-<name>@kubernetescrd" references. As
+// namespaces may contain dashes, only references to objects in the given namespace can be split and are translated.
+func translateTraefikReferences(refType, value, namespace string, translate TranslateFunc) string {
+	refs := strings.Split(value, ",")
+	for i, ref := range refs {
+		name, ok := strings.CutSuffix(strings.TrimSpace(ref), traefikCRDProvider)
+		if !ok {
+			continue
+		}
+		name, ok = strings.CutPrefix(name, namespace+"-")
+		if !ok || name == "" {
+			continue
+		}
+
+		pName := translate(refType, types.NamespacedName{Namespace: namespace, Name: name})
+		refs[i] = pName.Namespace + "-" + pName.Name + traefikCRDProvider
+	}
+
+	return strings.Join(refs, ",")
+}
+
+type pathSegment struct {
+	key   string
+	index int
+}
+
+const (
+	// allItems is the index of a "[*]" path segment
+	allItems = -1
+	// noIndex is the index of a path segment without a list index
+	noIndex = -2
+)
+
+// ValidatePath returns an error if the path of a json annotation rule cannot be parsed
+func ValidatePath(path string) error {
+	_, err := parsePath(path)
+	return err
+}
+
+func parsePath(path string) ([]pathSegment, error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if trimmed == "" {
+		return nil, fmt.Errorf("path %q is empty", path)
+	}
+
+	segments := []pathSegment{}
+	for _, part := range strings.Split(trimmed, ".") {
+		segment := pathSegment{key: part, index: noIndex}
+		if i := strings.Index(part, "["); i >= 0 {
+			if !strings.HasSuffix(part, "]") {
+				return nil, fmt.Errorf("path %q has an unterminated index", path)
+			}
+
+			segment.key = part[:i]
+			index := part[i+1 : len(part)-1]
+			if index == "*" {
+				segment.index = allItems
+			} else {
+				n, err := strconv.Atoi(index)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("path %q has an invalid index %q, expected a number or *", path, index)
+				}
+				segment.index = n
+			}
+		}
+		if segment.key == "" && segment.index == noIndex {
+			return nil, fmt.Errorf("path %q has an empty segment", path)
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return segments, nil
+}
+
+// walk calls fn for all values at the path and replaces them with the returned value
+func walk(value interface{}, segments []pathSegment, fn func(leaf interface{}) interface{}) interface{} {
+	if len(segments) == 0 {
+		return fn(value)
+	}
+
+	segment := segments[0]
+	if segment.key != "" {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		child, ok := object[segment.key]
+		if !ok {
+			return value
+		}
+
+		object[segment.key] = walkIndex(child, segment.index, segments[1:], fn)
+		return object
+	}
+
+	return walkIndex(value, segment.index, segments[1:], fn)
+}
+
+func walkIndex(value interface{}, index int, segments []pathSegment, fn func(leaf interface{}) interface{}) interface{} {
+	if index == noIndex {
+		return walk(value, segments, fn)
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		return value
+	}
+	for i := range list {
+		if index == allItems || index == i {
+			list[i] = walk(list[i], segments, fn)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,124 @@
+package ingressannotations
+
+import (
+	"testing"
+
+	"github.com/loft-sh/vcluster/config"
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func fakeTranslate(refType string, ref types.NamespacedName) types.NamespacedName {
+	return types.NamespacedName{Namespace: "host", Name: refType + "-" + ref.Name + "-x-" + ref.Namespace}
+}
+
+func TestRules(t *testing.T) {
+	rules := Rules(config.IngressAnnotationTranslation{
+		Presets: []string{"nginx"},
+		Rules: []config.IngressAnnotationRule{
+			{Annotation: "nginx.ingress.kubernetes.io/default-backend", Type: TypeSecret},
+			{Annotation: "example.com/auth-secret", Type: TypeSecret},
+		},
+	})
+
+	assert.Equal(t, rules["nginx.ingress.kubernetes.io/auth-secret"].Type, TypeSecret)
+	assert.Equal(t, rules["nginx.ingress.kubernetes.io/default-backend"].Type, TypeSecret)
+	assert.Equal(t, rules["example.com/auth-secret"].Type, TypeSecret)
+	_, ok := rules["haproxy.org/auth-secret"]
+	assert.Assert(t, !ok)
+}
+
+func TestTranslate(t *testing.T) {
+	backends := config.IngressAnnotationRule{
+		Annotation: "example.com/backends",
+		Type:       TypeJSON,
+		Paths: []config.IngressAnnotationPath{
+			{Path: "targets[*].serviceName", Type: TypeService},
+			{Path: "$.tls.secrets[1]", Type: TypeSecret},
+		},
+	}
+
+	testCases := []struct {
+		name  string
+		rule  config.IngressAnnotationRule
+		value string
+
+		expectedValue string
+		expectedErr   string
+	}{
+		{
+			name:          "name",
+			rule:          config.IngressAnnotationRule{Type: TypeSecret},
+			value:         "auth",
+			expectedValue: "secret-auth-x-test",
+		},
+		{
+			name:          "namespace and name",
+			rule:          config.IngressAnnotationRule{Type: TypeService},
+			value:         "other/backend",
+			expectedValue: "host/service-backend-x-other",
+		},
+		{
+			name:          "unknown format",
+			rule:          config.IngressAnnotationRule{Type: TypeSecret},
+			value:         "a/b/c",
+			expectedValue: "a/b/c",
+		},
+		{
+			name:          "traefik middlewares",
+			rule:          config.IngressAnnotationRule{Type: TypeTraefikMiddleware},
+			value:         "test-auth@kubernetescrd, other-strip@kubernetescrd,compress@file",
+			expectedValue: "host-traefikMiddleware-auth-x-test@kubernetescrd, other-strip@kubernetescrd,compress@file",
+		},
+		{
+			name:          "json",
+			rule:          backends,
+			value:         `{"targets":[{"serviceName":"a","weight":1},{"serviceName":"other/b"},{"weight":2}],"tls":{"secrets":["first","second"]}}`,
+			expectedValue: `{"targets":[{"serviceName":"service-a-x-test","weight":1},{"serviceName":"host/service-b-x-other"},{"weight":2}],"tls":{"secrets":["first","secret-second-x-test"]}}`,
+		},
+		{
+			name:          "json without references",
+			rule:          backends,
+			value:         `{"targets": [], "other": true}`,
+			expectedValue: `{"targets": [], "other": true}`,
+		},
+		{
+			name:          "invalid json",
+			rule:          backends,
+			value:         "backend",
+			expectedValue: "backend",
+			expectedErr:   "unmarshal annotation example.com/backends",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			value, err := Translate(testCase.rule, testCase.value, "test", fakeTranslate)
+			if testCase.expectedErr != "" {
+				assert.ErrorContains(t, err, testCase.expectedErr)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.Equal(t, value, testCase.expectedValue)
+		})
+	}
+}
+
+func TestValidatePath(t *testing.T) {
+	for path, expectedErr := range map[string]string{
+		"forwardConfig.targetGroups[*].serviceName": "",
+		"$.secrets[0]":  "",
+		"[*].name":      "",
+		"":              `path "" is empty`,
+		"a..b":          `path "a..b" has an empty segment`,
+		"targets[*":     `path "targets[*" has an unterminated index`,
+		"targets[-1].a": `path "targets[-1].a" has an invalid index "-1", expected a number or *`,
+	} {
+		err := ValidatePath(path)
+		if expectedErr == "" {
+			assert.NilError(t, err, path)
+		} else {
+			assert.Error(t, err, expectedErr, path)
+		}
+	}
+}
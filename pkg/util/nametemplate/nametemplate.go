@@ -0,0 +1,144 @@
+package nametemplate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"text/template"
+
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// maxLength is the maximum length of generated names, so they can be used for all object kinds including services
+	maxLength = 63
+	// hashLength is the length of the hash that is appended to all names
+	hashLength = 10
+)
+
+// Values are the fields available within a name template
+type Values struct {
+	// Name is the name of the virtual object
+	Name string
+	// Namespace is the namespace of the virtual object
+	Namespace string
+	// VCluster is the name of the virtual cluster
+	VCluster string
+}
+
+// Template generates host names of namespaced objects from a Go template such as {{.VCluster}}-{{.Namespace}}-{{.Name}}
+type Template struct {
+	text     string
+	template *template.Template
+}
+
+// Parse parses and validates a name template. An empty text returns a nil template, which means the default naming
+// should be used.
+func Parse(text string) (*Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	parsed, err := template.New("name").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse name template %q: %w", text, err)
+	}
+
+	t := &Template{text: text, template: parsed}
+	err = t.validate()
+	if err != nil {
+		return nil, fmt.Errorf("name template %q: %w", text, err)
+	}
+
+	return t, nil
+}
+
+// String returns the template text
+func (t *Template) String() string {
+	return t.text
+}
+
+// Execute generates the host name for the given values. The rendered template is suffixed with a hash of the values,
+// because separators such as - can also be part of the values and {{.VCluster}}-{{.Namespace}}-{{.Name}} would
+// otherwise render a-b + c and a + b-c to the same name. The rendered template is truncated if the name would be
+// longer than 63 characters.
+func (t *Template) Execute(values Values) (string, error) {
+	buf := &bytes.Buffer{}
+	err := t.template.Execute(buf, values)
+	if err != nil {
+		return "", err
+	}
+
+	return withHash(buf.String(), values), nil
+}
+
+// validate makes sure the template uses every field and only generates valid names
+func (t *Template) validate() error {
+	// render with placeholders that can't be part of a name to find out where each field ends up
+	buf := &bytes.Buffer{}
+	err := t.template.Execute(buf, Values{Name: "\x01", Namespace: "\x02", VCluster: "\x03"})
+	if err != nil {
+		return err
+	}
+
+	rendered := buf.String()
+	for _, field := range []struct{ name, placeholder string }{
+		{name: ".Name", placeholder: "\x01"},
+		{name: ".Namespace", placeholder: "\x02"},
+		{name: ".VCluster", placeholder: "\x03"},
+	} {
+		if count := strings.Count(rendered, field.placeholder); count != 1 {
+			return fmt.Errorf("must use %s exactly once, but uses it %d times", field.name, count)
+		}
+	}
+
+	// fields without a separator in between can produce the same name for different objects, e.g. a + bc and ab + c
+	if adjacent(rendered) {
+		return fmt.Errorf("fields must be separated by at least one character")
+	}
+
+	// make sure the generated names are valid for a short and a long object name
+	for _, values := range []Values{
+		{Name: "a", Namespace: "b", VCluster: "c"},
+		{Name: strings.Repeat("a", maxLength), Namespace: strings.Repeat("b", maxLength), VCluster: strings.Repeat("c", maxLength)},
+	} {
+		name, err := t.Execute(values)
+		if err != nil {
+			return err
+		}
+
+		if errs := utilvalidation.IsDNS1123Label(name); len(errs) > 0 {
+			return fmt.Errorf("generates invalid name %q: %s", name, strings.Join(errs, ", "))
+		}
+	}
+
+	return nil
+}
+
+// adjacent returns true if two placeholders follow each other directly
+func adjacent(rendered string) bool {
+	for i := 0; i+1 < len(rendered); i++ {
+		if isPlaceholder(rendered[i]) && isPlaceholder(rendered[i+1]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isPlaceholder(c byte) bool {
+	return c == '\x01' || c == '\x02' || c == '\x03'
+}
+
+// withHash appends a hash of the values to name. The values are joined with /, which can't be part of them, so
+// different values always result in different hashes.
+func withHash(name string, values Values) string {
+	digest := sha256.Sum256([]byte(values.VCluster + "/" + values.Namespace + "/" + values.Name))
+	if len(name) > maxLength-hashLength-1 {
+		name = name[0 : maxLength-hashLength-1]
+	}
+
+	return strings.TrimRight(name, "-.") + "-" + hex.EncodeToString(digest[0:])[0:hashLength]
+}
@@ -0,0 +1,93 @@
+package nametemplate
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		template string
+
+		expectedErr string
+	}{
+		{
+			name:     "valid template",
+			template: "{{.VCluster}}-{{.Namespace}}-{{.Name}}",
+		},
+		{
+			name:        "missing vcluster",
+			template:    "{{.Namespace}}-{{.Name}}",
+			expectedErr: `name template "{{.Namespace}}-{{.Name}}": must use .VCluster exactly once, but uses it 0 times`,
+		},
+		{
+			name:        "name used twice",
+			template:    "{{.Name}}-{{.Namespace}}-{{.VCluster}}-{{.Name}}",
+			expectedErr: `name template "{{.Name}}-{{.Namespace}}-{{.VCluster}}-{{.Name}}": must use .Name exactly once, but uses it 2 times`,
+		},
+		{
+			name:        "fields without separator",
+			template:    "{{.Name}}{{.Namespace}}-{{.VCluster}}",
+			expectedErr: `name template "{{.Name}}{{.Namespace}}-{{.VCluster}}": fields must be separated by at least one character`,
+		},
+		{
+			name:        "invalid characters",
+			template:    "{{.Name}}.{{.Namespace}}.{{.VCluster}}",
+			expectedErr: `name template "{{.Name}}.{{.Namespace}}.{{.VCluster}}": generates invalid name "a.b.c-01696a08bf": must not contain dots`,
+		},
+		{
+			name:        "unknown field",
+			template:    "{{.Name}}-{{.Namespace}}-{{.VCluster}}-{{.Kind}}",
+			expectedErr: `name template "{{.Name}}-{{.Namespace}}-{{.VCluster}}-{{.Kind}}": template: name:1:41: executing "name" at <.Kind>: can't evaluate field Kind in type nametemplate.Values`,
+		},
+		{
+			name:        "invalid syntax",
+			template:    "{{.Name",
+			expectedErr: `parse name template "{{.Name": template: name:1: unclosed action`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := Parse(testCase.template)
+			if testCase.expectedErr == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, testCase.expectedErr)
+			}
+		})
+	}
+}
+
+func TestExecute(t *testing.T) {
+	template, err := Parse("{{.VCluster}}-{{.Namespace}}-{{.Name}}")
+	assert.NilError(t, err)
+
+	name, err := template.Execute(Values{Name: "web", Namespace: "shop", VCluster: "team-a"})
+	assert.NilError(t, err)
+	assert.Equal(t, name, "team-a-shop-web-5101637e57")
+
+	// separators within the values don't lead to the same name
+	name, err = template.Execute(Values{Name: "d", Namespace: "c", VCluster: "a-b"})
+	assert.NilError(t, err)
+	otherName, err := template.Execute(Values{Name: "d", Namespace: "b-c", VCluster: "a"})
+	assert.NilError(t, err)
+	assert.Equal(t, name, "a-b-c-d-4f459a7fa9")
+	assert.Equal(t, otherName, "a-b-c-d-c6d1d49e93")
+
+	// long names are truncated deterministically
+	values := Values{Name: "a-very-long-deployment-name-that-exceeds-the-length-limit", Namespace: "shop", VCluster: "team-a"}
+	name, err = template.Execute(values)
+	assert.NilError(t, err)
+	assert.Assert(t, len(name) <= 63)
+	assert.Equal(t, name, "team-a-shop-a-very-long-deployment-name-that-exceeds-213b334670")
+	otherName, err = template.Execute(Values{Name: values.Name + "-2", Namespace: "shop", VCluster: "team-a"})
+	assert.NilError(t, err)
+	assert.Assert(t, name != otherName)
+
+	empty, err := Parse("")
+	assert.NilError(t, err)
+	assert.Assert(t, empty == nil)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/loft-sh/log"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream" //nolint:staticcheck // k8s.io/client-go/transport/spdy still returns this type; migrate once upstream spdy does.
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -66,7 +67,8 @@ func StartPortForwardingWithRestart(ctx context.Context, config *rest.Config, ad
 	}
 }
 
-func StartPortForwarding(ctx context.Context, config *rest.Config, client kubernetes.Interface, address, pod, namespace, localPort, remotePort string, stdout io.Writer, stderr io.Writer, log log.Logger) (chan struct{}, error) {
+// NewPodDialer creates a dialer for the port-forward subresource of the given pod
+func NewPodDialer(config *rest.Config, client kubernetes.Interface, pod, namespace string) (httpstream.Dialer, error) {
 	execRequest := client.CoreV1().RESTClient().Post().
 		Resource("pods").
 		Name(pod).
@@ -78,11 +80,19 @@ func StartPortForwarding(ctx context.Context, config *rest.Config, client kubern
 		return nil, err
 	}
 
+	return spdy.NewDialer(upgrader, &http.Client{Transport: t}, "POST", execRequest.URL()), nil
+}
+
+func StartPortForwarding(ctx context.Context, config *rest.Config, client kubernetes.Interface, address, pod, namespace, localPort, remotePort string, stdout io.Writer, stderr io.Writer, log log.Logger) (chan struct{}, error) {
+	dialer, err := NewPodDialer(config, client, pod, namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	if address == "" {
 		address = "localhost"
 	}
 
-	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: t}, "POST", execRequest.URL())
 	errChan := make(chan error)
 	readyChan := make(chan struct{})
 	stopChan := make(chan struct{})
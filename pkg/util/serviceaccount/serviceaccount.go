@@ -16,6 +16,9 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
+// Audiences are the audiences of the service account tokens created for the virtual cluster api server
+var Audiences = []string{"https://kubernetes.default.svc.cluster.local", "https://kubernetes.default.svc", "https://kubernetes.default"}
+
 func CreateServiceAccountToken(
 	ctx context.Context,
 	vKubeClient kubernetes.Interface,
@@ -25,13 +28,48 @@ func CreateServiceAccountToken(
 	expiration int64,
 	log log.Logger,
 ) (string, error) {
-	audiences := []string{"https://kubernetes.default.svc.cluster.local", "https://kubernetes.default.svc", "https://kubernetes.default"}
+	log.Infof("Create service account token for %s/%s", serviceAccountNamespace, serviceAccount)
+	token, err := ensureServiceAccount(ctx, vKubeClient, serviceAccount, serviceAccountNamespace, clusterRole, expiration, true, log)
+	if err != nil {
+		return "", fmt.Errorf("create service account token: %w", err)
+	}
+
+	return token, nil
+}
+
+// EnsureServiceAccount waits until the service account exists without creating a token for it. If a cluster role is
+// given, the service account and its cluster role binding are created like in CreateServiceAccountToken.
+func EnsureServiceAccount(
+	ctx context.Context,
+	vKubeClient kubernetes.Interface,
+	serviceAccount,
+	serviceAccountNamespace,
+	clusterRole string,
+	log log.Logger,
+) error {
+	_, err := ensureServiceAccount(ctx, vKubeClient, serviceAccount, serviceAccountNamespace, clusterRole, 0, false, log)
+	if err != nil {
+		return fmt.Errorf("ensure service account: %w", err)
+	}
+
+	return nil
+}
+
+func ensureServiceAccount(
+	ctx context.Context,
+	vKubeClient kubernetes.Interface,
+	serviceAccount,
+	serviceAccountNamespace,
+	clusterRole string,
+	expiration int64,
+	createToken bool,
+	log log.Logger,
+) (string, error) {
 	expirationSeconds := int64(10 * 365 * 24 * 60 * 60)
 	if expiration > 0 {
 		expirationSeconds = expiration
 	}
 	token := ""
-	log.Infof("Create service account token for %s/%s", serviceAccountNamespace, serviceAccount)
 	err := wait.PollUntilContextTimeout(ctx, time.Second, time.Minute*3, false, func(ctx context.Context) (bool, error) {
 		// check if namespace exists
 		_, err := vKubeClient.CoreV1().Namespaces().Get(ctx, serviceAccountNamespace, metav1.GetOptions{})
@@ -123,9 +161,13 @@ func CreateServiceAccountToken(
 			}
 		}
 
+		if !createToken {
+			return true, nil
+		}
+
 		// create service account token
 		result, err := vKubeClient.CoreV1().ServiceAccounts(serviceAccountNamespace).CreateToken(ctx, serviceAccount, &authenticationv1.TokenRequest{Spec: authenticationv1.TokenRequestSpec{
-			Audiences:         audiences,
+			Audiences:         Audiences,
 			ExpirationSeconds: &expirationSeconds,
 		}}, metav1.CreateOptions{})
 		if err != nil {
@@ -140,7 +182,7 @@ func CreateServiceAccountToken(
 		return true, nil
 	})
 	if err != nil {
-		return "", fmt.Errorf("create service account token: %w", err)
+		return "", err
 	}
 
 	return token, nil
@@ -2,12 +2,16 @@ package translate
 
 import (
 	"maps"
+	"slices"
 	"strings"
 
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	"github.com/loft-sh/vcluster/pkg/util/stringutil"
+	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -225,6 +229,42 @@ func MergeLabelSelectors(elems ...*metav1.LabelSelector) *metav1.LabelSelector {
 	return out
 }
 
+// NamespaceSelectorToHostPodSelector returns a host pod selector that selects the pods of all virtual namespaces matched
+// by the namespace selector. An empty namespace selector matches all namespaces and therefore doesn't restrict the pods.
+func NamespaceSelectorToHostPodSelector(namespaceSelector *metav1.LabelSelector, namespaces []corev1.Namespace) *metav1.LabelSelector {
+	if namespaceSelector == nil {
+		return nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(namespaceSelector)
+	if err != nil {
+		// an invalid selector is rejected by the api server, so this shouldn't happen
+		klog.Errorf("error converting namespace selector %v: %v", namespaceSelector, err)
+		selector = labels.Nothing()
+	} else if selector.Empty() {
+		return nil
+	}
+
+	matched := []string{}
+	for _, namespace := range namespaces {
+		if selector.Matches(labels.Set(namespace.Labels)) {
+			matched = append(matched, namespace.Name)
+		}
+	}
+	slices.Sort(matched)
+
+	// all synced pods carry the namespace label, so requiring it to be absent selects no pods
+	if len(matched) == 0 {
+		return &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: NamespaceLabel, Operator: metav1.LabelSelectorOpDoesNotExist}},
+		}
+	}
+
+	return &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: NamespaceLabel, Operator: metav1.LabelSelectorOpIn, Values: matched}},
+	}
+}
+
 func AnnotationsBidirectionalUpdateFunction[T client.Object](event *synccontext.SyncEvent[T], transformFromHost, transformToHost func(key string, value interface{}) (string, interface{})) (map[string]string, map[string]string) {
 	excludeAnnotations := []string{HostNameAnnotation, HostNamespaceAnnotation, NameAnnotation, UIDAnnotation, KindAnnotation, NamespaceAnnotation, ManagedAnnotationsAnnotation, ManagedLabelsAnnotation}
 	newVirtual := maps.Clone(event.Virtual.GetAnnotations())
@@ -9,6 +9,7 @@ import (
 	"github.com/loft-sh/vcluster/pkg/scheme"
 	"github.com/loft-sh/vcluster/pkg/syncer/synccontext"
 	"github.com/loft-sh/vcluster/pkg/util/base36"
+	"github.com/loft-sh/vcluster/pkg/util/nametemplate"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
@@ -24,8 +25,18 @@ func NewSingleNamespaceTranslator(targetNamespace string) Translator {
 	}
 }
 
+// NewSingleNamespaceTranslatorWithNameTemplate creates a single namespace translator that generates host names with
+// the given template. If the template is nil, the default naming is used.
+func NewSingleNamespaceTranslatorWithNameTemplate(targetNamespace string, nameTemplate *nametemplate.Template) Translator {
+	return &singleNamespace{
+		targetNamespace: targetNamespace,
+		nameTemplate:    nameTemplate,
+	}
+}
+
 type singleNamespace struct {
 	targetNamespace string
+	nameTemplate    *nametemplate.Template
 }
 
 func (s *singleNamespace) SingleNamespaceTarget() bool {
@@ -38,16 +49,39 @@ func (s *singleNamespace) HostName(ctx *synccontext.SyncContext, vName, vNamespa
 	}
 
 	return types.NamespacedName{
-		Name:      SingleNamespaceHostName(vName, vNamespace, VClusterName),
+		Name:      s.hostName(vName, vNamespace),
 		Namespace: s.HostNamespace(ctx, vNamespace),
 	}
 }
 
+func (s *singleNamespace) hostName(vName, vNamespace string) string {
+	if s.nameTemplate == nil || vNamespace == "" {
+		return SingleNamespaceHostName(vName, vNamespace, VClusterName)
+	}
+
+	// the template was validated at startup, so this should only fail if the template is broken
+	name, err := s.nameTemplate.Execute(nametemplate.Values{Name: vName, Namespace: vNamespace, VCluster: VClusterName})
+	if err != nil {
+		klog.Errorf("error executing name template %q for %s/%s, falling back to default naming: %v", s.nameTemplate.String(), vNamespace, vName, err)
+		return SingleNamespaceHostName(vName, vNamespace, VClusterName)
+	}
+
+	return name
+}
+
 func (s *singleNamespace) HostNameShort(ctx *synccontext.SyncContext, vName, vNamespace string) types.NamespacedName {
 	if vName == "" {
 		return types.NamespacedName{}
 	}
 
+	// names generated by the template are already truncated to a valid length
+	if s.nameTemplate != nil && vNamespace != "" {
+		return types.NamespacedName{
+			Name:      s.hostName(vName, vNamespace),
+			Namespace: s.HostNamespace(ctx, vNamespace),
+		}
+	}
+
 	// we use base36 to avoid as much conflicts as possible
 	digest := sha256.Sum256([]byte(strings.Join([]string{vName, "x", vNamespace, "x", VClusterName}, "-")))
 	return types.NamespacedName{
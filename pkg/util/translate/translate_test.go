@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/loft-sh/vcluster/pkg/util/nametemplate"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -347,3 +348,16 @@ func jsonResponse(statusCode int, obj interface{}) (*http.Response, error) {
 		Body: io.NopCloser(bytes.NewReader(data)),
 	}, nil
 }
+
+func TestSingleNamespaceNameTemplate(t *testing.T) {
+	nameTemplate, err := nametemplate.Parse("{{.VCluster}}-{{.Namespace}}-{{.Name}}")
+	assert.NilError(t, err)
+
+	translator := NewSingleNamespaceTranslatorWithNameTemplate("host", nameTemplate)
+	assert.Equal(t, translator.HostName(nil, "nginx", "default").Name, "suffix-default-nginx-a807041e35")
+	assert.Equal(t, translator.HostNameShort(nil, "nginx", "default").Name, "suffix-default-nginx-a807041e35")
+
+	// cluster scoped objects keep the default naming
+	defaultTranslator := NewSingleNamespaceTranslator("host")
+	assert.Equal(t, translator.HostName(nil, "nginx", "").Name, defaultTranslator.HostName(nil, "nginx", "").Name)
+}